@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/mock_draft"
+)
+
+func main() {
+	teams := flag.Int("teams", 10, "Number of teams in the draft")
+	rounds := flag.Int("rounds", 15, "Number of rounds")
+	slot := flag.Int("slot", 1, "Your position in the draft order")
+	randomness := flag.Int("randomness", mock_draft.DefaultRandomness, "How many of the best available players a bot chooses between")
+	seed := flag.Int64("seed", 0, "Random seed for the bots (0 for a random draft)")
+	auto := flag.Bool("auto", false, "Let a bot make your picks too")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Initialize database. Players are only read to build the rankings.
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	rankings, err := mock_draft.LoadRankings(db)
+	db.Close()
+	if err != nil {
+		log.Fatalf("Failed to load player rankings: %v", err)
+	}
+
+	md, err := mock_draft.New(1, mock_draft.Settings{
+		Teams:      *teams,
+		Rounds:     *rounds,
+		UserSlot:   *slot,
+		Randomness: *randomness,
+		Seed:       *seed,
+	}, rankings)
+	if err != nil {
+		log.Fatalf("Failed to start mock draft: %v", err)
+	}
+	fmt.Printf("Mock draft: %d teams, %d rounds, you pick from slot %d (seed %d)\n\n", *teams, *rounds, *slot, md.Settings.Seed)
+
+	reader := bufio.NewReader(os.Stdin)
+	printed := 0
+	for {
+		printed = printPicks(md, printed)
+		if md.Status == models.DraftStatusCompleted {
+			break
+		}
+
+		if *auto {
+			if err := md.AutoPick(); err != nil {
+				log.Fatalf("Failed to autopick: %v", err)
+			}
+			continue
+		}
+
+		fmt.Printf("\nRound %d, pick %d. You are on the clock. Best available:\n", md.CurrentRound, md.CurrentPick)
+		for i, player := range md.Available {
+			if i == 10 {
+				break
+			}
+			fmt.Printf("  %4d  %-4s %s\n", player.PlayerID, player.Position, player.Name)
+		}
+		fmt.Print("Enter a player ID, or press enter to autopick: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			log.Fatalf("Failed to read pick: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			err = md.AutoPick()
+		} else {
+			playerID, convErr := strconv.Atoi(line)
+			if convErr != nil {
+				fmt.Println("That is not a player ID")
+				continue
+			}
+			err = md.MakePick(playerID)
+		}
+		if err != nil {
+			fmt.Printf("Pick rejected: %v\n", err)
+		}
+	}
+
+	fmt.Println("\nYour squad:")
+	for _, pick := range md.Roster(*slot) {
+		fmt.Printf("  Round %2d  %-4s %s\n", pick.Round, pick.Position, pick.Name)
+	}
+}
+
+// printPicks prints the picks made since the last call and returns how many have been printed
+func printPicks(md *mock_draft.MockDraft, printed int) int {
+	for _, pick := range md.Picks[printed:] {
+		who := fmt.Sprintf("Bot %d", pick.Slot)
+		if pick.ByUser {
+			who = "You"
+		}
+		fmt.Printf("%3d. %-6s %-4s %s\n", pick.OverallPick, who, pick.Position, pick.Name)
+	}
+	return len(md.Picks)
+}
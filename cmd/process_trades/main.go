@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/trade"
+)
+
+// Expires trade proposals nobody responded to in time and makes the accepted trades whose review
+// period has closed without a veto. This is meant to be run every few minutes.
+func main() {
+	log.Println("Starting trade processing...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	if err := trade.ExpireTrades(db, now); err != nil {
+		log.Fatalf("Failed to expire trades: %v", err)
+	}
+
+	leagueIDs, err := trade.LeaguesWithDueTrades(db, now)
+	if err != nil {
+		log.Fatalf("Failed to find leagues with trades due: %v", err)
+	}
+
+	tradeService := trade.NewTradeService(db)
+	for _, leagueID := range leagueIDs {
+		trades, err := tradeService.ProcessTrades(leagueID)
+		if err != nil {
+			log.Printf("Failed to process trades for league %d: %v", leagueID, err)
+			continue
+		}
+
+		made := 0
+		for _, processed := range trades {
+			if processed.Status == models.TradeStatusAccepted {
+				made++
+			}
+		}
+		log.Printf("League %d: %d trades processed, %d made", leagueID, len(trades), made)
+	}
+	log.Printf("Trade processing completed for %d leagues", len(leagueIDs))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/waiver"
+)
+
+// Settles the waiver claims of every league with players whose waivers have cleared. Waivers
+// clear at each league's daily processing time, so this is meant to be run every few minutes.
+func main() {
+	log.Println("Starting waiver processing...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	leagueIDs, err := waiver.LeaguesWithDueWaivers(db, time.Now())
+	if err != nil {
+		log.Fatalf("Failed to find leagues with waivers due: %v", err)
+	}
+
+	waiverService := waiver.NewWaiverService(db)
+	for _, leagueID := range leagueIDs {
+		claims, err := waiverService.ProcessWaivers(leagueID)
+		if err != nil {
+			log.Printf("Failed to process waivers for league %d: %v", leagueID, err)
+			continue
+		}
+
+		won := 0
+		for _, claim := range claims {
+			if claim.Status == models.WaiverClaimWon {
+				won++
+			}
+		}
+		log.Printf("League %d: %d claims processed, %d won", leagueID, len(claims), won)
+	}
+	log.Printf("Waiver processing completed for %d leagues", len(leagueIDs))
+}
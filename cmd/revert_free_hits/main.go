@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/services/lineup"
+)
+
+// Locks the carried-over lineups of gameweeks whose deadline has passed and puts back the squads
+// user teams had before playing a free hit once the free hit's gameweek deadline has passed. Teams
+// that do not touch their squad afterwards would otherwise keep the one-week squad, so this is
+// meant to be run every few minutes.
+func main() {
+	log.Println("Starting free hit reverts...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	lineupService := lineup.NewLineupService(db)
+	chips, err := lineupService.RevertFreeHits()
+	if err != nil {
+		log.Fatalf("Failed to revert free hits: %v", err)
+	}
+
+	for _, chip := range chips {
+		log.Printf("User team %d: squad put back after the free hit in gameweek %d", chip.UserTeamID, chip.GameweekID)
+	}
+	log.Printf("Free hit reverts completed for %d user teams", len(chips))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/external"
+	"go-app/services/fixture_sync"
+)
+
+func main() {
+	log.Println("Starting match event sync process...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	log.Println("Configuration loaded successfully")
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	matchID := flag.Int("match", 0, "Sync the events of a single match")
+	days := flag.Int("days", 3, "Sync the events of matches that kicked off in this many days")
+	flag.Parse()
+
+	// Initialize database
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	log.Println("Database connection established")
+
+	// Initialize API Football client
+	apiFootballClient := external.NewAPIFootballClient(
+		cfg.APIFootballBaseURL,
+		cfg.APIFootballAPIKey,
+		cfg.APIFootballLeagueID,
+		cfg.APIFootballSeason,
+	)
+	log.Println("API Football client initialized")
+
+	// Sync events into match incidents
+	fixtureSyncService := fixture_sync.NewFixtureSyncService(db, apiFootballClient)
+	var result *fixture_sync.EventSyncResult
+	if *matchID != 0 {
+		result, err = fixtureSyncService.SyncEvents(*matchID)
+	} else {
+		result, err = fixtureSyncService.SyncRecentEvents(time.Now().AddDate(0, 0, -*days))
+	}
+	if err != nil {
+		log.Fatalf("Failed to sync events: %v", err)
+	}
+	log.Printf("Event sync completed: %d created, %d updated, %d removed, %d skipped, %d player stats",
+		result.Created, result.Updated, result.Removed, result.Skipped, result.Stats)
+}
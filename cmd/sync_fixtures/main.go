@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/external"
+	"go-app/services/fixture_sync"
+)
+
+func main() {
+	log.Println("Starting fixture sync process...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	log.Println("Configuration loaded successfully")
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	flag.Parse()
+
+	// Initialize database
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	log.Println("Database connection established")
+
+	// Initialize API Football client
+	apiFootballClient := external.NewAPIFootballClient(
+		cfg.APIFootballBaseURL,
+		cfg.APIFootballAPIKey,
+		cfg.APIFootballLeagueID,
+		cfg.APIFootballSeason,
+	)
+	log.Println("API Football client initialized")
+
+	// Sync fixtures into matches
+	fixtureSyncService := fixture_sync.NewFixtureSyncService(db, apiFootballClient)
+	result, err := fixtureSyncService.SyncFixtures()
+	if err != nil {
+		log.Fatalf("Failed to sync fixtures: %v", err)
+	}
+	log.Printf("Fixture sync completed: %d created, %d updated (%d rescheduled, %d postponed), %d skipped",
+		result.Created, result.Updated, result.Rescheduled, result.Postponed, result.Skipped)
+}
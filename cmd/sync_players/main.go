@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/external"
+	"go-app/services/player_sync"
+)
+
+// Links players to API-Football from the squads of the synced teams, so fixture events can be
+// credited to them. Teams have to be synced first.
+func main() {
+	log.Println("Starting player sync process...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	log.Println("Configuration loaded successfully")
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	flag.Parse()
+
+	// Initialize database
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	log.Println("Database connection established")
+
+	// Initialize API Football client
+	apiFootballClient := external.NewAPIFootballClient(
+		cfg.APIFootballBaseURL,
+		cfg.APIFootballAPIKey,
+		cfg.APIFootballLeagueID,
+		cfg.APIFootballSeason,
+	)
+	log.Println("API Football client initialized")
+
+	// Sync squads into players
+	playerSyncService := player_sync.NewPlayerSyncService(db, apiFootballClient)
+	result, err := playerSyncService.SyncPlayers()
+	if err != nil {
+		log.Fatalf("Failed to sync players: %v", err)
+	}
+	log.Printf("Player sync completed: %d created, %d updated, %d linked, %d skipped",
+		result.Created, result.Updated, result.Linked, result.Skipped)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"go-app/config"
+	"go-app/database"
+	"go-app/services/pricing"
+)
+
+// Moves player prices in budget leagues based on the transfers made since each price last
+// changed. Meant to be run once a night.
+func main() {
+	defaults := pricing.DefaultSettings()
+	threshold := flag.Int("threshold", defaults.Threshold, "Net transfers needed to move a price by 0.1")
+	maxDailySteps := flag.Int("max-daily-steps", defaults.MaxDailySteps, "Most steps of 0.1 a price can move in a day")
+	minPrice := flag.Float64("min-price", defaults.MinPrice, "Lowest price a player can fall to")
+	dryRun := flag.Bool("dry-run", false, "Only print the changes that would be made")
+	flag.Parse()
+
+	log.Println("Starting price update...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.InitDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	pricingService := pricing.NewPricingServiceWithSettings(db, pricing.Settings{
+		Threshold:     *threshold,
+		MaxDailySteps: *maxDailySteps,
+		MinPrice:      *minPrice,
+	})
+
+	if *dryRun {
+		predictions, err := pricingService.PredictChanges()
+		if err != nil {
+			log.Fatalf("Failed to predict price changes: %v", err)
+		}
+		for _, prediction := range predictions {
+			if prediction.PredictedPrice != prediction.Price {
+				log.Printf("Player %d would move from %.1f to %.1f (%d net transfers)",
+					prediction.ID, prediction.Price, prediction.PredictedPrice, prediction.NetTransfers)
+			}
+		}
+		return
+	}
+
+	changes, err := pricingService.ApplyChanges()
+	if err != nil {
+		log.Fatalf("Failed to update prices: %v", err)
+	}
+	for _, change := range changes {
+		log.Printf("Player %d moved from %.1f to %.1f", change.PlayerID, change.OldPrice, change.NewPrice)
+	}
+	log.Printf("Price update completed, %d prices changed", len(changes))
+}
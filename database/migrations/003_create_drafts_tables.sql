@@ -0,0 +1,60 @@
+-- Create user_teams table
+CREATE TABLE IF NOT EXISTS user_teams (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id),
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user team belongs to the league it is drafting in
+ALTER TABLE user_teams ADD COLUMN IF NOT EXISTS league_id INTEGER REFERENCES leagues(id);
+
+-- Create user_team_players table
+CREATE TABLE IF NOT EXISTS user_team_players (
+    id SERIAL PRIMARY KEY,
+    user_team_id INTEGER REFERENCES user_teams(id),
+    player_id INTEGER REFERENCES players(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create drafts table
+CREATE TABLE IF NOT EXISTS drafts (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id),
+    type VARCHAR(50) NOT NULL DEFAULT 'snake',
+    status VARCHAR(50) NOT NULL DEFAULT 'scheduled',
+    rounds INTEGER NOT NULL,
+    current_round INTEGER NOT NULL DEFAULT 1,
+    current_pick INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create draft_order table
+CREATE TABLE IF NOT EXISTS draft_order (
+    draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (draft_id, position),
+    UNIQUE (draft_id, user_team_id)
+);
+
+-- Create draft_picks table
+CREATE TABLE IF NOT EXISTS draft_picks (
+    id SERIAL PRIMARY KEY,
+    draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+    player_id INTEGER NOT NULL REFERENCES players(id),
+    round INTEGER NOT NULL,
+    pick INTEGER NOT NULL,
+    overall_pick INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (draft_id, player_id),
+    UNIQUE (draft_id, overall_pick)
+);
+
+-- Create indexes for faster lookups
+CREATE INDEX IF NOT EXISTS idx_user_teams_league_id ON user_teams(league_id);
+CREATE INDEX IF NOT EXISTS idx_drafts_league_id ON drafts(league_id);
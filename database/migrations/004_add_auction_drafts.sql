@@ -0,0 +1,33 @@
+-- Auction settings on drafts
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS budget NUMERIC(8,1) NOT NULL DEFAULT 0;
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS min_bid NUMERIC(8,1) NOT NULL DEFAULT 0;
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS bid_time_seconds INTEGER NOT NULL DEFAULT 0;
+
+-- Record what was paid for each pick and rostered player
+ALTER TABLE draft_picks ADD COLUMN IF NOT EXISTS price NUMERIC(8,1) NOT NULL DEFAULT 0;
+ALTER TABLE user_team_players ADD COLUMN IF NOT EXISTS purchase_price NUMERIC(8,1) NOT NULL DEFAULT 0;
+
+-- Create draft_nominations table
+CREATE TABLE IF NOT EXISTS draft_nominations (
+    id SERIAL PRIMARY KEY,
+    draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id),
+    nominated_by INTEGER NOT NULL REFERENCES user_teams(id),
+    current_bid NUMERIC(8,1) NOT NULL,
+    current_bidder INTEGER NOT NULL REFERENCES user_teams(id),
+    bid_deadline TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create draft_bids table
+CREATE TABLE IF NOT EXISTS draft_bids (
+    id SERIAL PRIMARY KEY,
+    nomination_id INTEGER NOT NULL REFERENCES draft_nominations(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+    amount NUMERIC(8,1) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_draft_nominations_draft_id ON draft_nominations(draft_id);
@@ -0,0 +1,25 @@
+-- Create league_settings table
+CREATE TABLE IF NOT EXISTS league_settings (
+    league_id INTEGER PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+    pick_time_seconds INTEGER NOT NULL DEFAULT 90,
+    pause_start VARCHAR(5) NOT NULL DEFAULT '',
+    pause_end VARCHAR(5) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Deadline for the team on the clock
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS pick_deadline TIMESTAMP WITH TIME ZONE;
+
+-- Create draft_queues table
+CREATE TABLE IF NOT EXISTS draft_queues (
+    draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+    player_id INTEGER NOT NULL REFERENCES players(id),
+    rank INTEGER NOT NULL,
+    PRIMARY KEY (draft_id, user_team_id, player_id),
+    UNIQUE (draft_id, user_team_id, rank)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drafts_pick_deadline ON drafts(pick_deadline) WHERE status = 'in_progress';
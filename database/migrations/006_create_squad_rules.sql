@@ -0,0 +1,16 @@
+-- Create league_squad_rules table
+CREATE TABLE IF NOT EXISTS league_squad_rules (
+    league_id INTEGER PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+    squad_size INTEGER NOT NULL DEFAULT 15,
+    max_per_team INTEGER NOT NULL DEFAULT 3,
+    min_gk INTEGER NOT NULL DEFAULT 2,
+    max_gk INTEGER NOT NULL DEFAULT 2,
+    min_def INTEGER NOT NULL DEFAULT 5,
+    max_def INTEGER NOT NULL DEFAULT 5,
+    min_mid INTEGER NOT NULL DEFAULT 5,
+    max_mid INTEGER NOT NULL DEFAULT 5,
+    min_fwd INTEGER NOT NULL DEFAULT 3,
+    max_fwd INTEGER NOT NULL DEFAULT 3,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
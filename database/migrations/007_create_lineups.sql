@@ -0,0 +1,21 @@
+-- Create gameweeks table
+CREATE TABLE IF NOT EXISTS gameweeks (
+    id SERIAL PRIMARY KEY,
+    number INTEGER NOT NULL UNIQUE,
+    deadline TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create lineups table
+CREATE TABLE IF NOT EXISTS lineups (
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id),
+    slot INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_team_id, gameweek_id, player_id),
+    UNIQUE (user_team_id, gameweek_id, slot)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lineups_gameweek_id ON lineups(gameweek_id);
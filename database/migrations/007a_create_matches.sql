@@ -0,0 +1,54 @@
+-- Create matches table
+CREATE TABLE IF NOT EXISTS matches (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER,
+    gameweek_id INTEGER REFERENCES gameweeks(id),
+    home_team_id INTEGER REFERENCES teams(id),
+    away_team_id INTEGER REFERENCES teams(id),
+    match_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    home_score INTEGER DEFAULT 0,
+    away_score INTEGER DEFAULT 0,
+    status VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create player_stats table. Stats without a match are season totals.
+CREATE TABLE IF NOT EXISTS player_stats (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id),
+    match_id INTEGER REFERENCES matches(id),
+    goals INTEGER DEFAULT 0,
+    assists INTEGER DEFAULT 0,
+    clean_sheets INTEGER DEFAULT 0,
+    saves INTEGER DEFAULT 0,
+    yellow_cards INTEGER DEFAULT 0,
+    red_cards INTEGER DEFAULT 0,
+    minutes_played INTEGER DEFAULT 0,
+    own_goals INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create match_incidents table
+CREATE TABLE IF NOT EXISTS match_incidents (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER REFERENCES matches(id),
+    player_id INTEGER REFERENCES players(id),
+    type VARCHAR(50) NOT NULL,
+    minute INTEGER NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tables that were created before this migration get the columns they are missing
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS gameweek_id INTEGER REFERENCES gameweeks(id);
+ALTER TABLE player_stats ADD COLUMN IF NOT EXISTS match_id INTEGER REFERENCES matches(id);
+ALTER TABLE player_stats ADD COLUMN IF NOT EXISTS saves INTEGER DEFAULT 0;
+ALTER TABLE player_stats ADD COLUMN IF NOT EXISTS minutes_played INTEGER DEFAULT 0;
+ALTER TABLE player_stats ADD COLUMN IF NOT EXISTS own_goals INTEGER DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_player_stats_player_id ON player_stats(player_id);
+CREATE INDEX IF NOT EXISTS idx_player_stats_match_id ON player_stats(match_id);
+CREATE INDEX IF NOT EXISTS idx_matches_gameweek_id ON matches(gameweek_id);
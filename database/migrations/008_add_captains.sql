@@ -0,0 +1,6 @@
+-- Captain and vice-captain picks in each lineup
+ALTER TABLE lineups ADD COLUMN IF NOT EXISTS is_captain BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE lineups ADD COLUMN IF NOT EXISTS is_vice_captain BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Points multiplier for the captain
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS captain_multiplier NUMERIC(4, 2) NOT NULL DEFAULT 2;
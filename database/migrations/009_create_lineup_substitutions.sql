@@ -0,0 +1,11 @@
+-- Create lineup_substitutions table
+CREATE TABLE IF NOT EXISTS lineup_substitutions (
+    id SERIAL PRIMARY KEY,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+    player_out_id INTEGER NOT NULL REFERENCES players(id),
+    player_in_id INTEGER NOT NULL REFERENCES players(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lineup_substitutions_user_team_gameweek ON lineup_substitutions(user_team_id, gameweek_id);
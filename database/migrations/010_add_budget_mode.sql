@@ -0,0 +1,29 @@
+-- Player prices
+ALTER TABLE players ADD COLUMN IF NOT EXISTS price NUMERIC(5, 1) NOT NULL DEFAULT 0;
+
+-- Create player_prices table
+CREATE TABLE IF NOT EXISTS player_prices (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    price NUMERIC(5, 1) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_prices_player_id ON player_prices(player_id, created_at);
+
+-- Draft-exclusive or budget-based shared ownership
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS ownership_mode VARCHAR(20) NOT NULL DEFAULT 'draft';
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS budget NUMERIC(6, 1) NOT NULL DEFAULT 100.0;
+
+-- Create transfers table
+CREATE TABLE IF NOT EXISTS transfers (
+    id SERIAL PRIMARY KEY,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    player_out_id INTEGER REFERENCES players(id),
+    player_in_id INTEGER REFERENCES players(id),
+    price_out NUMERIC(5, 1) NOT NULL DEFAULT 0,
+    price_in NUMERIC(5, 1) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_user_team_id ON transfers(user_team_id);
@@ -0,0 +1,3 @@
+-- Price changes count transfers in and out of each player since their last change
+CREATE INDEX IF NOT EXISTS idx_transfers_player_in_id ON transfers(player_in_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transfers_player_out_id ON transfers(player_out_id, created_at);
@@ -0,0 +1,25 @@
+-- Create chips table
+CREATE TABLE IF NOT EXISTS chips (
+    id SERIAL PRIMARY KEY,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+    chip VARCHAR(20) NOT NULL,
+    reverted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_team_id, chip),
+    UNIQUE (user_team_id, gameweek_id)
+);
+
+-- Squads as they were before a free hit, put back once its gameweek deadline has passed
+CREATE TABLE IF NOT EXISTS free_hit_squads (
+    chip_id INTEGER NOT NULL REFERENCES chips(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id),
+    purchase_price NUMERIC(8, 1) NOT NULL DEFAULT 0,
+    PRIMARY KEY (chip_id, player_id)
+);
+
+-- Transfers count towards a gameweek's free transfers
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS gameweek_id INTEGER REFERENCES gameweeks(id);
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS cost INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transfers_user_team_gameweek ON transfers(user_team_id, gameweek_id);
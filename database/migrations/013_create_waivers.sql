@@ -0,0 +1,54 @@
+-- Waiver wire settings
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS waiver_period_hours INTEGER NOT NULL DEFAULT 48;
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS waiver_process_time VARCHAR(5) NOT NULL DEFAULT '03:00';
+
+-- Create waivers table
+CREATE TABLE IF NOT EXISTS waivers (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id),
+    clears_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A player is only on waivers once at a time in each league
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waivers_open ON waivers(league_id, player_id) WHERE processed_at IS NULL;
+
+-- Create waiver_claims table
+CREATE TABLE IF NOT EXISTS waiver_claims (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id),
+    drop_player_id INTEGER REFERENCES players(id),
+    rank INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    processed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_waiver_claims_league_status ON waiver_claims(league_id, status);
+CREATE INDEX IF NOT EXISTS idx_waiver_claims_user_team_id ON waiver_claims(user_team_id);
+
+-- Create waiver_priorities table
+CREATE TABLE IF NOT EXISTS waiver_priorities (
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    priority INTEGER NOT NULL,
+    PRIMARY KEY (league_id, user_team_id)
+);
+
+-- Create league_transactions table
+CREATE TABLE IF NOT EXISTS league_transactions (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    player_in_id INTEGER REFERENCES players(id),
+    player_out_id INTEGER REFERENCES players(id),
+    waiver_claim_id INTEGER REFERENCES waiver_claims(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_league_transactions_league_id ON league_transactions(league_id, created_at);
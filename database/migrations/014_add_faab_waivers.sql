@@ -0,0 +1,10 @@
+-- FAAB waiver settings
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS waiver_mode VARCHAR(20) NOT NULL DEFAULT 'rolling';
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS faab_budget INTEGER NOT NULL DEFAULT 100;
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS waiver_tie_break VARCHAR(20) NOT NULL DEFAULT 'priority';
+
+-- Sealed bids on waiver claims and the FAAB they spent
+ALTER TABLE waiver_claims ADD COLUMN IF NOT EXISTS bid INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE league_transactions ADD COLUMN IF NOT EXISTS amount INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_league_transactions_user_team_id ON league_transactions(user_team_id);
@@ -0,0 +1,49 @@
+-- Create trades table
+CREATE TABLE IF NOT EXISTS trades (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    proposer_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    recipient_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    counter_of_id INTEGER REFERENCES trades(id),
+    message TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trades_league_status ON trades(league_id, status);
+CREATE INDEX IF NOT EXISTS idx_trades_proposer_team_id ON trades(proposer_team_id);
+CREATE INDEX IF NOT EXISTS idx_trades_recipient_team_id ON trades(recipient_team_id);
+
+-- Create trade_items table
+CREATE TABLE IF NOT EXISTS trade_items (
+    id SERIAL PRIMARY KEY,
+    trade_id INTEGER NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+    from_user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    player_id INTEGER REFERENCES players(id),
+    draft_round INTEGER,
+    pick_original_user_team_id INTEGER REFERENCES user_teams(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_trade_items_trade_id ON trade_items(trade_id);
+CREATE INDEX IF NOT EXISTS idx_trade_items_player ON trade_items(from_user_team_id, player_id);
+
+-- Create draft_pick_owners table for picks in a league's next draft that have been traded
+CREATE TABLE IF NOT EXISTS draft_pick_owners (
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL,
+    original_user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    owner_user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    draft_id INTEGER REFERENCES drafts(id) ON DELETE CASCADE
+);
+
+-- A pick is only owned once until it is bound to a draft
+CREATE UNIQUE INDEX IF NOT EXISTS idx_draft_pick_owners_open ON draft_pick_owners(league_id, round, original_user_team_id) WHERE draft_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_draft_pick_owners_draft_id ON draft_pick_owners(draft_id);
+
+-- Trades in the league transaction history
+ALTER TABLE league_transactions ADD COLUMN IF NOT EXISTS trade_id INTEGER REFERENCES trades(id);
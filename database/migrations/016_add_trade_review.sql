@@ -0,0 +1,18 @@
+-- Trade review settings
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS trade_review VARCHAR(20) NOT NULL DEFAULT 'league_vote';
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS trade_review_hours INTEGER NOT NULL DEFAULT 24;
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS trade_veto_votes INTEGER NOT NULL DEFAULT 3;
+ALTER TABLE league_settings ADD COLUMN IF NOT EXISTS commissioner_user_team_id INTEGER REFERENCES user_teams(id) ON DELETE SET NULL;
+
+-- When the review period of an accepted trade closes
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS review_ends_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_trades_review_ends_at ON trades(review_ends_at) WHERE status = 'in_review';
+
+-- Create trade_votes table for managers voting to veto a trade
+CREATE TABLE IF NOT EXISTS trade_votes (
+    trade_id INTEGER NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+    user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (trade_id, user_team_id)
+);
@@ -0,0 +1,21 @@
+-- Create league_audit_log table, an append-only history of every roster change in a league.
+-- The teams, trades, claims and picks it refers to cannot be deleted while it does.
+CREATE TABLE IF NOT EXISTS league_audit_log (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    user_team_id INTEGER REFERENCES user_teams(id) ON DELETE RESTRICT,
+    actor_user_team_id INTEGER REFERENCES user_teams(id) ON DELETE RESTRICT,
+    action VARCHAR(30) NOT NULL,
+    players_in INTEGER[] NOT NULL DEFAULT '{}',
+    players_out INTEGER[] NOT NULL DEFAULT '{}',
+    trade_id INTEGER REFERENCES trades(id) ON DELETE RESTRICT,
+    waiver_claim_id INTEGER REFERENCES waiver_claims(id) ON DELETE RESTRICT,
+    draft_pick_id INTEGER REFERENCES draft_picks(id) ON DELETE RESTRICT,
+    roster_before INTEGER[] NOT NULL DEFAULT '{}',
+    roster_after INTEGER[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_league_audit_log_league_created ON league_audit_log(league_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_league_audit_log_user_team_id ON league_audit_log(user_team_id);
+CREATE INDEX IF NOT EXISTS idx_league_audit_log_players ON league_audit_log USING GIN (players_in, players_out);
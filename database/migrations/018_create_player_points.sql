@@ -0,0 +1,20 @@
+-- Create player_points table for the fantasy points each player scores in a gameweek
+CREATE TABLE IF NOT EXISTS player_points (
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+    points INTEGER NOT NULL DEFAULT 0,
+    minutes_played INTEGER NOT NULL DEFAULT 0,
+    goals INTEGER NOT NULL DEFAULT 0,
+    assists INTEGER NOT NULL DEFAULT 0,
+    clean_sheets INTEGER NOT NULL DEFAULT 0,
+    yellow_cards INTEGER NOT NULL DEFAULT 0,
+    red_cards INTEGER NOT NULL DEFAULT 0,
+    penalties_saved INTEGER NOT NULL DEFAULT 0,
+    penalties_missed INTEGER NOT NULL DEFAULT 0,
+    own_goals INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (player_id, gameweek_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_points_gameweek_id ON player_points(gameweek_id);
+CREATE INDEX IF NOT EXISTS idx_match_incidents_match_id ON match_incidents(match_id);
@@ -0,0 +1,18 @@
+-- Create league_scoring_rules table for the points each league scores players by
+CREATE TABLE IF NOT EXISTS league_scoring_rules (
+    league_id INTEGER PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+    preset VARCHAR(20) NOT NULL DEFAULT 'fpl',
+    rules JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Points are scored for each league under its own rules. Stored points can be worked out again
+-- from match data, so they are cleared rather than carried over.
+DELETE FROM player_points;
+ALTER TABLE player_points ADD COLUMN IF NOT EXISTS league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE;
+ALTER TABLE player_points DROP CONSTRAINT IF EXISTS player_points_pkey;
+ALTER TABLE player_points ADD PRIMARY KEY (league_id, player_id, gameweek_id);
+
+DROP INDEX IF EXISTS idx_player_points_gameweek_id;
+CREATE INDEX IF NOT EXISTS idx_player_points_league_gameweek ON player_points(league_id, gameweek_id);
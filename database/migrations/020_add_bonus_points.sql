@@ -0,0 +1,3 @@
+-- Bonus points for a match's best performers, provisional until the match is completed
+ALTER TABLE player_points ADD COLUMN IF NOT EXISTS bonus INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE player_points ADD COLUMN IF NOT EXISTS bonus_provisional BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,28 @@
+-- Create stat_corrections table for changes to the incidents of finished matches
+CREATE TABLE IF NOT EXISTS stat_corrections (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+    incident_id INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    old_player_id INTEGER REFERENCES players(id) ON DELETE SET NULL,
+    old_type VARCHAR(50),
+    old_minute INTEGER,
+    new_player_id INTEGER REFERENCES players(id) ON DELETE SET NULL,
+    new_type VARCHAR(50),
+    new_minute INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create stat_correction_points table for the points each correction moved in each league
+CREATE TABLE IF NOT EXISTS stat_correction_points (
+    correction_id INTEGER NOT NULL REFERENCES stat_corrections(id) ON DELETE CASCADE,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    old_points INTEGER NOT NULL,
+    new_points INTEGER NOT NULL,
+    PRIMARY KEY (correction_id, league_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stat_corrections_match_id ON stat_corrections(match_id);
+CREATE INDEX IF NOT EXISTS idx_stat_correction_points_league_id ON stat_correction_points(league_id);
@@ -0,0 +1,2 @@
+-- Tie matches to the API-Football fixtures they are synced from
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS external_id INTEGER UNIQUE;
@@ -0,0 +1,5 @@
+-- Tie players to API-Football and incidents to the fixture events they were synced from
+ALTER TABLE players ADD COLUMN IF NOT EXISTS external_id INTEGER UNIQUE;
+ALTER TABLE match_incidents ADD COLUMN IF NOT EXISTS external_key VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_match_incidents_external_key ON match_incidents(match_id, external_key);
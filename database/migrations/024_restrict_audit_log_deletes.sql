@@ -0,0 +1,18 @@
+-- Stop deletes from clearing the references in the league audit log, which is append-only
+ALTER TABLE league_audit_log DROP CONSTRAINT IF EXISTS league_audit_log_user_team_id_fkey;
+ALTER TABLE league_audit_log DROP CONSTRAINT IF EXISTS league_audit_log_actor_user_team_id_fkey;
+ALTER TABLE league_audit_log DROP CONSTRAINT IF EXISTS league_audit_log_trade_id_fkey;
+ALTER TABLE league_audit_log DROP CONSTRAINT IF EXISTS league_audit_log_waiver_claim_id_fkey;
+ALTER TABLE league_audit_log DROP CONSTRAINT IF EXISTS league_audit_log_draft_pick_id_fkey;
+
+ALTER TABLE league_audit_log
+    ADD CONSTRAINT league_audit_log_user_team_id_fkey
+        FOREIGN KEY (user_team_id) REFERENCES user_teams(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT league_audit_log_actor_user_team_id_fkey
+        FOREIGN KEY (actor_user_team_id) REFERENCES user_teams(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT league_audit_log_trade_id_fkey
+        FOREIGN KEY (trade_id) REFERENCES trades(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT league_audit_log_waiver_claim_id_fkey
+        FOREIGN KEY (waiver_claim_id) REFERENCES waiver_claims(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT league_audit_log_draft_pick_id_fkey
+        FOREIGN KEY (draft_pick_id) REFERENCES draft_picks(id) ON DELETE RESTRICT;
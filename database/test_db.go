@@ -130,6 +130,7 @@ func createTestTables(db *sqlx.DB) error {
 			first_name VARCHAR(255) NOT NULL,
 			last_name VARCHAR(255) NOT NULL,
 			position VARCHAR(50) NOT NULL,
+			price NUMERIC(5,1) NOT NULL DEFAULT 0,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)
@@ -143,6 +144,7 @@ func createTestTables(db *sqlx.DB) error {
 		CREATE TABLE IF NOT EXISTS user_teams (
 			id SERIAL PRIMARY KEY,
 			user_id INTEGER REFERENCES users(id),
+			league_id INTEGER REFERENCES leagues(id),
 			name VARCHAR(255) NOT NULL,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
@@ -158,7 +160,9 @@ func createTestTables(db *sqlx.DB) error {
 			id SERIAL PRIMARY KEY,
 			user_team_id INTEGER REFERENCES user_teams(id),
 			player_id INTEGER REFERENCES players(id),
-			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			purchase_price NUMERIC(8,1) NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	if err != nil {
@@ -175,6 +179,9 @@ func createTestTables(db *sqlx.DB) error {
 			yellow_cards INTEGER DEFAULT 0,
 			red_cards INTEGER DEFAULT 0,
 			clean_sheets INTEGER DEFAULT 0,
+			saves INTEGER DEFAULT 0,
+			minutes_played INTEGER DEFAULT 0,
+			own_goals INTEGER DEFAULT 0,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)
@@ -219,12 +226,557 @@ func createTestTables(db *sqlx.DB) error {
 		return fmt.Errorf("failed to create match_incidents table: %v", err)
 	}
 
+	// Create drafts table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS drafts (
+			id SERIAL PRIMARY KEY,
+			league_id INTEGER NOT NULL REFERENCES leagues(id),
+			type VARCHAR(50) NOT NULL DEFAULT 'snake',
+			status VARCHAR(50) NOT NULL DEFAULT 'scheduled',
+			rounds INTEGER NOT NULL,
+			current_round INTEGER NOT NULL DEFAULT 1,
+			current_pick INTEGER NOT NULL DEFAULT 1,
+			budget NUMERIC(8,1) NOT NULL DEFAULT 0,
+			min_bid NUMERIC(8,1) NOT NULL DEFAULT 0,
+			bid_time_seconds INTEGER NOT NULL DEFAULT 0,
+			pick_deadline TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create drafts table: %v", err)
+	}
+
+	// Create draft_order table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS draft_order (
+			draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+			position INTEGER NOT NULL,
+			PRIMARY KEY (draft_id, position),
+			UNIQUE (draft_id, user_team_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create draft_order table: %v", err)
+	}
+
+	// Create draft_picks table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS draft_picks (
+			id SERIAL PRIMARY KEY,
+			draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+			player_id INTEGER NOT NULL REFERENCES players(id),
+			round INTEGER NOT NULL,
+			pick INTEGER NOT NULL,
+			overall_pick INTEGER NOT NULL,
+			price NUMERIC(8,1) NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (draft_id, player_id),
+			UNIQUE (draft_id, overall_pick)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create draft_picks table: %v", err)
+	}
+
+	// Create draft_nominations table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS draft_nominations (
+			id SERIAL PRIMARY KEY,
+			draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+			player_id INTEGER NOT NULL REFERENCES players(id),
+			nominated_by INTEGER NOT NULL REFERENCES user_teams(id),
+			current_bid NUMERIC(8,1) NOT NULL,
+			current_bidder INTEGER NOT NULL REFERENCES user_teams(id),
+			bid_deadline TIMESTAMP NOT NULL,
+			status VARCHAR(50) NOT NULL DEFAULT 'open',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create draft_nominations table: %v", err)
+	}
+
+	// Create draft_bids table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS draft_bids (
+			id SERIAL PRIMARY KEY,
+			nomination_id INTEGER NOT NULL REFERENCES draft_nominations(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+			amount NUMERIC(8,1) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create draft_bids table: %v", err)
+	}
+
+	// Create league_settings table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS league_settings (
+			league_id INTEGER PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+			pick_time_seconds INTEGER NOT NULL DEFAULT 90,
+			pause_start VARCHAR(5) NOT NULL DEFAULT '',
+			pause_end VARCHAR(5) NOT NULL DEFAULT '',
+			timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+			captain_multiplier NUMERIC(4, 2) NOT NULL DEFAULT 2,
+			ownership_mode VARCHAR(20) NOT NULL DEFAULT 'draft',
+			budget NUMERIC(6, 1) NOT NULL DEFAULT 100.0,
+			waiver_period_hours INTEGER NOT NULL DEFAULT 48,
+			waiver_process_time VARCHAR(5) NOT NULL DEFAULT '03:00',
+			waiver_mode VARCHAR(20) NOT NULL DEFAULT 'rolling',
+			faab_budget INTEGER NOT NULL DEFAULT 100,
+			waiver_tie_break VARCHAR(20) NOT NULL DEFAULT 'priority',
+			trade_review VARCHAR(20) NOT NULL DEFAULT 'league_vote',
+			trade_review_hours INTEGER NOT NULL DEFAULT 24,
+			trade_veto_votes INTEGER NOT NULL DEFAULT 3,
+			commissioner_user_team_id INTEGER REFERENCES user_teams(id) ON DELETE SET NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create league_settings table: %v", err)
+	}
+
+	// Create draft_queues table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS draft_queues (
+			draft_id INTEGER NOT NULL REFERENCES drafts(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id),
+			player_id INTEGER NOT NULL REFERENCES players(id),
+			rank INTEGER NOT NULL,
+			PRIMARY KEY (draft_id, user_team_id, player_id),
+			UNIQUE (draft_id, user_team_id, rank)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create draft_queues table: %v", err)
+	}
+
+	// Create league_squad_rules table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS league_squad_rules (
+			league_id INTEGER PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+			squad_size INTEGER NOT NULL DEFAULT 15,
+			max_per_team INTEGER NOT NULL DEFAULT 3,
+			min_gk INTEGER NOT NULL DEFAULT 2,
+			max_gk INTEGER NOT NULL DEFAULT 2,
+			min_def INTEGER NOT NULL DEFAULT 5,
+			max_def INTEGER NOT NULL DEFAULT 5,
+			min_mid INTEGER NOT NULL DEFAULT 5,
+			max_mid INTEGER NOT NULL DEFAULT 5,
+			min_fwd INTEGER NOT NULL DEFAULT 3,
+			max_fwd INTEGER NOT NULL DEFAULT 3,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create league_squad_rules table: %v", err)
+	}
+
+	// Create gameweeks table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS gameweeks (
+			id SERIAL PRIMARY KEY,
+			number INTEGER NOT NULL UNIQUE,
+			deadline TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create gameweeks table: %v", err)
+	}
+
+	// Create lineups table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS lineups (
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+			player_id INTEGER NOT NULL REFERENCES players(id),
+			slot INTEGER NOT NULL,
+			is_captain BOOLEAN NOT NULL DEFAULT FALSE,
+			is_vice_captain BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_team_id, gameweek_id, player_id),
+			UNIQUE (user_team_id, gameweek_id, slot)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create lineups table: %v", err)
+	}
+
+	// Tie matches to a gameweek and player stats to the match they were recorded in
+	_, err = db.Exec(`
+		ALTER TABLE matches ADD COLUMN IF NOT EXISTS gameweek_id INTEGER REFERENCES gameweeks(id);
+		ALTER TABLE player_stats ADD COLUMN IF NOT EXISTS match_id INTEGER REFERENCES matches(id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add gameweek columns: %v", err)
+	}
+
+	// Create lineup_substitutions table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS lineup_substitutions (
+			id SERIAL PRIMARY KEY,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+			player_out_id INTEGER NOT NULL REFERENCES players(id),
+			player_in_id INTEGER NOT NULL REFERENCES players(id),
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create lineup_substitutions table: %v", err)
+	}
+
+	// Create player_prices table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS player_prices (
+			id SERIAL PRIMARY KEY,
+			player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+			price NUMERIC(5, 1) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create player_prices table: %v", err)
+	}
+
+	// Create transfers table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS transfers (
+			id SERIAL PRIMARY KEY,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			player_out_id INTEGER REFERENCES players(id),
+			player_in_id INTEGER REFERENCES players(id),
+			price_out NUMERIC(5, 1) NOT NULL DEFAULT 0,
+			price_in NUMERIC(5, 1) NOT NULL DEFAULT 0,
+			gameweek_id INTEGER REFERENCES gameweeks(id),
+			cost INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create transfers table: %v", err)
+	}
+
+	// Create chips table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS chips (
+			id SERIAL PRIMARY KEY,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+			chip VARCHAR(20) NOT NULL,
+			reverted_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (user_team_id, chip),
+			UNIQUE (user_team_id, gameweek_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create chips table: %v", err)
+	}
+
+	// Create free_hit_squads table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS free_hit_squads (
+			chip_id INTEGER NOT NULL REFERENCES chips(id) ON DELETE CASCADE,
+			player_id INTEGER NOT NULL REFERENCES players(id),
+			purchase_price NUMERIC(8,1) NOT NULL DEFAULT 0,
+			PRIMARY KEY (chip_id, player_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create free_hit_squads table: %v", err)
+	}
+
+	// Create waivers table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS waivers (
+			id SERIAL PRIMARY KEY,
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			player_id INTEGER NOT NULL REFERENCES players(id),
+			clears_at TIMESTAMP NOT NULL,
+			processed_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create waivers table: %v", err)
+	}
+
+	// Create waiver_claims table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS waiver_claims (
+			id SERIAL PRIMARY KEY,
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			player_id INTEGER NOT NULL REFERENCES players(id),
+			drop_player_id INTEGER REFERENCES players(id),
+			rank INTEGER NOT NULL,
+			bid INTEGER NOT NULL DEFAULT 0,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			processed_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create waiver_claims table: %v", err)
+	}
+
+	// Create waiver_priorities table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS waiver_priorities (
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			priority INTEGER NOT NULL,
+			PRIMARY KEY (league_id, user_team_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create waiver_priorities table: %v", err)
+	}
+
+	// Create trades table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS trades (
+			id SERIAL PRIMARY KEY,
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			proposer_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			recipient_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			counter_of_id INTEGER REFERENCES trades(id),
+			message TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMP NOT NULL,
+			responded_at TIMESTAMP,
+			review_ends_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create trades table: %v", err)
+	}
+
+	// Create trade_votes table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS trade_votes (
+			trade_id INTEGER NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (trade_id, user_team_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create trade_votes table: %v", err)
+	}
+
+	// Create trade_items table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS trade_items (
+			id SERIAL PRIMARY KEY,
+			trade_id INTEGER NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+			from_user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			type VARCHAR(20) NOT NULL,
+			player_id INTEGER REFERENCES players(id),
+			draft_round INTEGER,
+			pick_original_user_team_id INTEGER REFERENCES user_teams(id) ON DELETE CASCADE,
+			amount INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create trade_items table: %v", err)
+	}
+
+	// Create draft_pick_owners table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS draft_pick_owners (
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			round INTEGER NOT NULL,
+			original_user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			owner_user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			draft_id INTEGER REFERENCES drafts(id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create draft_pick_owners table: %v", err)
+	}
+
+	// Create league_transactions table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS league_transactions (
+			id SERIAL PRIMARY KEY,
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			user_team_id INTEGER NOT NULL REFERENCES user_teams(id) ON DELETE CASCADE,
+			type VARCHAR(20) NOT NULL,
+			player_in_id INTEGER REFERENCES players(id),
+			player_out_id INTEGER REFERENCES players(id),
+			waiver_claim_id INTEGER REFERENCES waiver_claims(id),
+			trade_id INTEGER REFERENCES trades(id),
+			amount INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create league_transactions table: %v", err)
+	}
+
+	// Create league_audit_log table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS league_audit_log (
+			id SERIAL PRIMARY KEY,
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			user_team_id INTEGER REFERENCES user_teams(id) ON DELETE RESTRICT,
+			actor_user_team_id INTEGER REFERENCES user_teams(id) ON DELETE RESTRICT,
+			action VARCHAR(30) NOT NULL,
+			players_in INTEGER[] NOT NULL DEFAULT '{}',
+			players_out INTEGER[] NOT NULL DEFAULT '{}',
+			trade_id INTEGER REFERENCES trades(id) ON DELETE RESTRICT,
+			waiver_claim_id INTEGER REFERENCES waiver_claims(id) ON DELETE RESTRICT,
+			draft_pick_id INTEGER REFERENCES draft_picks(id) ON DELETE RESTRICT,
+			roster_before INTEGER[] NOT NULL DEFAULT '{}',
+			roster_after INTEGER[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create league_audit_log table: %v", err)
+	}
+
+	// Create player_points table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS player_points (
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+			gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+			points INTEGER NOT NULL DEFAULT 0,
+			minutes_played INTEGER NOT NULL DEFAULT 0,
+			goals INTEGER NOT NULL DEFAULT 0,
+			assists INTEGER NOT NULL DEFAULT 0,
+			clean_sheets INTEGER NOT NULL DEFAULT 0,
+			yellow_cards INTEGER NOT NULL DEFAULT 0,
+			red_cards INTEGER NOT NULL DEFAULT 0,
+			penalties_saved INTEGER NOT NULL DEFAULT 0,
+			penalties_missed INTEGER NOT NULL DEFAULT 0,
+			own_goals INTEGER NOT NULL DEFAULT 0,
+			bonus INTEGER NOT NULL DEFAULT 0,
+			bonus_provisional BOOLEAN NOT NULL DEFAULT FALSE,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (league_id, player_id, gameweek_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create player_points table: %v", err)
+	}
+
+	// Create stat_corrections table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS stat_corrections (
+			id SERIAL PRIMARY KEY,
+			match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+			gameweek_id INTEGER NOT NULL REFERENCES gameweeks(id) ON DELETE CASCADE,
+			incident_id INTEGER NOT NULL,
+			action VARCHAR(20) NOT NULL,
+			old_player_id INTEGER REFERENCES players(id) ON DELETE SET NULL,
+			old_type VARCHAR(50),
+			old_minute INTEGER,
+			new_player_id INTEGER REFERENCES players(id) ON DELETE SET NULL,
+			new_type VARCHAR(50),
+			new_minute INTEGER,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create stat_corrections table: %v", err)
+	}
+
+	// Create stat_correction_points table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS stat_correction_points (
+			correction_id INTEGER NOT NULL REFERENCES stat_corrections(id) ON DELETE CASCADE,
+			league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+			player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+			old_points INTEGER NOT NULL,
+			new_points INTEGER NOT NULL,
+			PRIMARY KEY (correction_id, league_id, player_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create stat_correction_points table: %v", err)
+	}
+
+	// Create league_scoring_rules table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS league_scoring_rules (
+			league_id INTEGER PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+			preset VARCHAR(20) NOT NULL DEFAULT 'fpl',
+			rules JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create league_scoring_rules table: %v", err)
+	}
+
+	// Tie matches to their API-Football fixtures
+	_, err = db.Exec(`
+		ALTER TABLE matches ADD COLUMN IF NOT EXISTS external_id INTEGER UNIQUE;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add matches external_id column: %v", err)
+	}
+
+	// Tie players and incidents to API-Football
+	_, err = db.Exec(`
+		ALTER TABLE players ADD COLUMN IF NOT EXISTS external_id INTEGER UNIQUE;
+		ALTER TABLE match_incidents ADD COLUMN IF NOT EXISTS external_key VARCHAR(100);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add external id columns: %v", err)
+	}
+
 	return nil
 }
 
 // dropTestTables drops all test tables
 func dropTestTables(db *sqlx.DB) error {
 	tables := []string{
+		"stat_correction_points",
+		"stat_corrections",
+		"league_scoring_rules",
+		"player_points",
+		"league_audit_log",
+		"trade_votes",
+		"draft_pick_owners",
+		"trade_items",
+		"league_transactions",
+		"trades",
+		"waiver_priorities",
+		"waiver_claims",
+		"waivers",
+		"free_hit_squads",
+		"chips",
+		"transfers",
+		"player_prices",
+		"lineup_substitutions",
+		"lineups",
+		"gameweeks",
+		"league_squad_rules",
+		"draft_queues",
+		"league_settings",
+		"draft_bids",
+		"draft_nominations",
+		"draft_picks",
+		"draft_order",
+		"drafts",
 		"match_incidents",
 		"matches",
 		"player_stats",
@@ -249,6 +801,34 @@ func dropTestTables(db *sqlx.DB) error {
 // Clear removes all data from the test database
 func (t *TestDB) Clear() error {
 	tables := []string{
+		"stat_correction_points",
+		"stat_corrections",
+		"league_scoring_rules",
+		"player_points",
+		"league_audit_log",
+		"trade_votes",
+		"draft_pick_owners",
+		"trade_items",
+		"league_transactions",
+		"trades",
+		"waiver_priorities",
+		"waiver_claims",
+		"waivers",
+		"free_hit_squads",
+		"chips",
+		"transfers",
+		"player_prices",
+		"lineup_substitutions",
+		"lineups",
+		"gameweeks",
+		"league_squad_rules",
+		"draft_queues",
+		"league_settings",
+		"draft_bids",
+		"draft_nominations",
+		"draft_picks",
+		"draft_order",
+		"drafts",
 		"match_incidents",
 		"matches",
 		"player_stats",
@@ -14,6 +14,85 @@ import (
 type APIFootballClientInterface interface {
 	FetchTeams() ([]*models.Team, error)
 	FetchTeamByExternalID(externalID int) (*models.Team, error)
+	FetchFixtures() ([]*Fixture, error)
+	FetchFixtureEvents(fixtureID int) ([]*FixtureEvent, error)
+	FetchFixturePlayerStats(fixtureID int) ([]*FixturePlayerStats, error)
+	FetchSquad(teamExternalID int) ([]*SquadPlayer, error)
+}
+
+// FixturePlayerStats is what a player did in a fixture as API-Football reports it. Players who
+// were named in the squad but did not come on have played no minutes.
+type FixturePlayerStats struct {
+	TeamExternalID   int
+	PlayerExternalID int
+	Minutes          int
+	Saves            int
+}
+
+// SquadPlayer is a player in a team's current squad as API-Football reports it
+type SquadPlayer struct {
+	ExternalID     int
+	TeamExternalID int
+	Name           string          // as API-Football gives it, e.g. "M. Salah"
+	Position       models.Position // empty if the position is not one we know
+}
+
+// Fixture is a match as API-Football reports it. Teams are identified by their API-Football IDs.
+type Fixture struct {
+	ExternalID         int
+	LeagueID           int
+	Round              string // e.g. "Regular Season - 5"
+	HomeTeamExternalID int
+	AwayTeamExternalID int
+	Kickoff            time.Time
+	Status             string // one of the models.MatchStatus values, empty if the status is not one we know
+	APIStatus          string // the short status code API-Football gives, e.g. "FT"
+	HomeScore          int
+	AwayScore          int
+}
+
+// FixtureEvent is something that happened in a fixture as API-Football reports it, such as a goal,
+// card or substitution. Players are identified by their API-Football IDs.
+type FixtureEvent struct {
+	Minute           int // stoppage time is added on, so 45+2 is 47
+	TeamExternalID   int
+	PlayerExternalID int
+	AssistExternalID int    // the assisting player of a goal or the other player in a substitution, or zero
+	Type             string // Goal, Card, subst or Var
+	Detail           string // e.g. Normal Goal, Own Goal, Penalty, Missed Penalty, Yellow Card, Red Card
+}
+
+// squadPositions maps API-Football's squad positions to player positions
+var squadPositions = map[string]models.Position{
+	"Goalkeeper": models.PositionGK,
+	"Defender":   models.PositionDEF,
+	"Midfielder": models.PositionMID,
+	"Attacker":   models.PositionFWD,
+}
+
+// fixtureStatuses maps API-Football's short fixture statuses to match statuses. Abandoned and
+// cancelled fixtures are treated as postponed, since they have to be replayed or rearranged, and
+// fixtures awarded without being played as completed.
+var fixtureStatuses = map[string]string{
+	"TBD":  models.MatchStatusScheduled,
+	"NS":   models.MatchStatusScheduled,
+	"1H":   models.MatchStatusInProgress,
+	"HT":   models.MatchStatusInProgress,
+	"2H":   models.MatchStatusInProgress,
+	"ET":   models.MatchStatusInProgress,
+	"BT":   models.MatchStatusInProgress,
+	"P":    models.MatchStatusInProgress,
+	"SUSP": models.MatchStatusInProgress,
+	"INT":  models.MatchStatusInProgress,
+	"LIVE": models.MatchStatusInProgress,
+	"FT":   models.MatchStatusCompleted,
+	"AET":  models.MatchStatusCompleted,
+	"PEN":  models.MatchStatusCompleted,
+	"AWD":  models.MatchStatusCompleted,
+	"WO":   models.MatchStatusCompleted,
+	"PST":  models.MatchStatusPostponed,
+	"CANC": models.MatchStatusPostponed,
+	"ABD":  models.MatchStatusPostponed,
 }
 
 // APIFootballClient handles communication with the API-Football service
@@ -156,3 +235,216 @@ func (c *APIFootballClient) FetchTeamByExternalID(externalID int) (*models.Team,
 
 	return team, nil
 }
+
+// FetchFixtures retrieves the fixtures of the configured league and season from the API-Football service
+func (c *APIFootballClient) FetchFixtures() ([]*Fixture, error) {
+	url := fmt.Sprintf("%s/fixtures?league=%s&season=%s", c.BaseURL, c.LeagueID, c.Season)
+
+	var response struct {
+		Response []struct {
+			Fixture struct {
+				ID     int       `json:"id"`
+				Date   time.Time `json:"date"`
+				Status struct {
+					Short string `json:"short"`
+				} `json:"status"`
+			} `json:"fixture"`
+			League struct {
+				ID    int    `json:"id"`
+				Round string `json:"round"`
+			} `json:"league"`
+			Teams struct {
+				Home struct {
+					ID int `json:"id"`
+				} `json:"home"`
+				Away struct {
+					ID int `json:"id"`
+				} `json:"away"`
+			} `json:"teams"`
+			Goals struct {
+				Home *int `json:"home"` // null until the match kicks off
+				Away *int `json:"away"`
+			} `json:"goals"`
+		} `json:"response"`
+	}
+	if err := c.get(url, &response); err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]*Fixture, 0, len(response.Response))
+	for _, item := range response.Response {
+		fixture := &Fixture{
+			ExternalID:         item.Fixture.ID,
+			LeagueID:           item.League.ID,
+			Round:              item.League.Round,
+			HomeTeamExternalID: item.Teams.Home.ID,
+			AwayTeamExternalID: item.Teams.Away.ID,
+			Kickoff:            item.Fixture.Date.UTC(),
+			Status:             fixtureStatuses[item.Fixture.Status.Short],
+			APIStatus:          item.Fixture.Status.Short,
+		}
+		if item.Goals.Home != nil {
+			fixture.HomeScore = *item.Goals.Home
+		}
+		if item.Goals.Away != nil {
+			fixture.AwayScore = *item.Goals.Away
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+// FetchFixtureEvents retrieves the events of a fixture from the API-Football service
+func (c *APIFootballClient) FetchFixtureEvents(fixtureID int) ([]*FixtureEvent, error) {
+	url := fmt.Sprintf("%s/fixtures/events?fixture=%d", c.BaseURL, fixtureID)
+
+	var response struct {
+		Response []struct {
+			Time struct {
+				Elapsed int `json:"elapsed"`
+				Extra   int `json:"extra"`
+			} `json:"time"`
+			Team struct {
+				ID int `json:"id"`
+			} `json:"team"`
+			Player struct {
+				ID int `json:"id"`
+			} `json:"player"`
+			Assist struct {
+				ID int `json:"id"`
+			} `json:"assist"`
+			Type   string `json:"type"`
+			Detail string `json:"detail"`
+		} `json:"response"`
+	}
+	if err := c.get(url, &response); err != nil {
+		return nil, err
+	}
+
+	events := make([]*FixtureEvent, 0, len(response.Response))
+	for _, item := range response.Response {
+		events = append(events, &FixtureEvent{
+			Minute:           item.Time.Elapsed + item.Time.Extra,
+			TeamExternalID:   item.Team.ID,
+			PlayerExternalID: item.Player.ID,
+			AssistExternalID: item.Assist.ID,
+			Type:             item.Type,
+			Detail:           item.Detail,
+		})
+	}
+
+	return events, nil
+}
+
+// FetchFixturePlayerStats retrieves the statistics of every player in a fixture from the
+// API-Football service
+func (c *APIFootballClient) FetchFixturePlayerStats(fixtureID int) ([]*FixturePlayerStats, error) {
+	url := fmt.Sprintf("%s/fixtures/players?fixture=%d", c.BaseURL, fixtureID)
+
+	var response struct {
+		Response []struct {
+			Team struct {
+				ID int `json:"id"`
+			} `json:"team"`
+			Players []struct {
+				Player struct {
+					ID int `json:"id"`
+				} `json:"player"`
+				Statistics []struct {
+					Games struct {
+						Minutes *int `json:"minutes"` // null for unused substitutes
+					} `json:"games"`
+					Goals struct {
+						Saves *int `json:"saves"`
+					} `json:"goals"`
+				} `json:"statistics"`
+			} `json:"players"`
+		} `json:"response"`
+	}
+	if err := c.get(url, &response); err != nil {
+		return nil, err
+	}
+
+	var stats []*FixturePlayerStats
+	for _, team := range response.Response {
+		for _, item := range team.Players {
+			playerStats := &FixturePlayerStats{
+				TeamExternalID:   team.Team.ID,
+				PlayerExternalID: item.Player.ID,
+			}
+			for _, statistics := range item.Statistics {
+				if statistics.Games.Minutes != nil {
+					playerStats.Minutes += *statistics.Games.Minutes
+				}
+				if statistics.Goals.Saves != nil {
+					playerStats.Saves += *statistics.Goals.Saves
+				}
+			}
+			stats = append(stats, playerStats)
+		}
+	}
+
+	return stats, nil
+}
+
+// FetchSquad retrieves the current squad of a team from the API-Football service
+func (c *APIFootballClient) FetchSquad(teamExternalID int) ([]*SquadPlayer, error) {
+	url := fmt.Sprintf("%s/players/squads?team=%d", c.BaseURL, teamExternalID)
+
+	var response struct {
+		Response []struct {
+			Team struct {
+				ID int `json:"id"`
+			} `json:"team"`
+			Players []struct {
+				ID       int    `json:"id"`
+				Name     string `json:"name"`
+				Position string `json:"position"`
+			} `json:"players"`
+		} `json:"response"`
+	}
+	if err := c.get(url, &response); err != nil {
+		return nil, err
+	}
+
+	var players []*SquadPlayer
+	for _, squad := range response.Response {
+		for _, item := range squad.Players {
+			players = append(players, &SquadPlayer{
+				ExternalID:     item.ID,
+				TeamExternalID: squad.Team.ID,
+				Name:           item.Name,
+				Position:       squadPositions[item.Position],
+			})
+		}
+	}
+
+	return players, nil
+}
+
+// get sends a request to the API-Football service and decodes the JSON response into v
+func (c *APIFootballClient) get(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Add("x-rapidapi-host", "api-football-v1.p.rapidapi.com")
+	req.Header.Add("x-rapidapi-key", c.APIKey)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("API returned non-200 status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
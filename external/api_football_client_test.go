@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"go-app/models"
 )
 
 func TestNewAPIFootballClient(t *testing.T) {
@@ -196,3 +199,220 @@ func TestFetchTeamByExternalIDNotFound(t *testing.T) {
 		t.Error("Expected an error for not found team, got nil")
 	}
 }
+
+func TestFetchFixtures(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify request URL
+		expectedURL := "/fixtures?league=123&season=2023"
+		if r.URL.String() != expectedURL {
+			t.Errorf("Expected URL to be '%s', got '%s'", expectedURL, r.URL.String())
+		}
+
+		// Return a finished fixture and one still to be played
+		w.Write([]byte(`{"response": [
+			{
+				"fixture": {"id": 1001, "date": "2023-08-12T14:00:00+01:00", "status": {"short": "FT"}},
+				"league": {"id": 123, "round": "Regular Season - 1"},
+				"teams": {"home": {"id": 1}, "away": {"id": 2}},
+				"goals": {"home": 2, "away": 1}
+			},
+			{
+				"fixture": {"id": 1002, "date": "2023-08-19T15:00:00+00:00", "status": {"short": "PST"}},
+				"league": {"id": 123, "round": "Regular Season - 2"},
+				"teams": {"home": {"id": 2}, "away": {"id": 1}},
+				"goals": {"home": null, "away": null}
+			}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIFootballClient(server.URL, "test-key", "123", "2023")
+
+	fixtures, err := client.FetchFixtures()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(fixtures) != 2 {
+		t.Fatalf("Expected 2 fixtures, got %d", len(fixtures))
+	}
+
+	finished := fixtures[0]
+	if finished.ExternalID != 1001 || finished.LeagueID != 123 || finished.Round != "Regular Season - 1" {
+		t.Errorf("Unexpected fixture %+v", finished)
+	}
+	if finished.HomeTeamExternalID != 1 || finished.AwayTeamExternalID != 2 {
+		t.Errorf("Expected teams 1 and 2, got %d and %d", finished.HomeTeamExternalID, finished.AwayTeamExternalID)
+	}
+	if finished.Status != "completed" || finished.HomeScore != 2 || finished.AwayScore != 1 {
+		t.Errorf("Expected a completed 2-1, got %s %d-%d", finished.Status, finished.HomeScore, finished.AwayScore)
+	}
+	if !finished.Kickoff.Equal(time.Date(2023, 8, 12, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected kickoff at 13:00 UTC, got %s", finished.Kickoff)
+	}
+
+	postponed := fixtures[1]
+	if postponed.Status != "postponed" || postponed.HomeScore != 0 || postponed.AwayScore != 0 {
+		t.Errorf("Expected a postponed fixture without a score, got %s %d-%d", postponed.Status, postponed.HomeScore, postponed.AwayScore)
+	}
+}
+
+func TestFetchFixturesUnknownStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response": [{"fixture": {"id": 1001, "date": "2023-08-12T14:00:00+00:00", "status": {"short": "XYZ"}}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIFootballClient(server.URL, "test-key", "123", "2023")
+
+	fixtures, err := client.FetchFixtures()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The fixture is kept with no status so the sync can skip it
+	if len(fixtures) != 1 {
+		t.Fatalf("Expected 1 fixture, got %d", len(fixtures))
+	}
+	if fixtures[0].Status != "" {
+		t.Errorf("Expected an empty status, got '%s'", fixtures[0].Status)
+	}
+	if fixtures[0].APIStatus != "XYZ" {
+		t.Errorf("Expected API status to be 'XYZ', got '%s'", fixtures[0].APIStatus)
+	}
+}
+
+func TestFetchFixtureEvents(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify request URL
+		expectedURL := "/fixtures/events?fixture=1001"
+		if r.URL.String() != expectedURL {
+			t.Errorf("Expected URL to be '%s', got '%s'", expectedURL, r.URL.String())
+		}
+
+		// Return a goal in stoppage time and a booking
+		w.Write([]byte(`{"response": [
+			{
+				"time": {"elapsed": 45, "extra": 2},
+				"team": {"id": 1},
+				"player": {"id": 10},
+				"assist": {"id": 11},
+				"type": "Goal",
+				"detail": "Normal Goal"
+			},
+			{
+				"time": {"elapsed": 60, "extra": null},
+				"team": {"id": 2},
+				"player": {"id": 20},
+				"assist": {"id": null},
+				"type": "Card",
+				"detail": "Yellow Card"
+			}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIFootballClient(server.URL, "test-key", "123", "2023")
+
+	events, err := client.FetchFixtureEvents(1001)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	goal := events[0]
+	if goal.Minute != 47 {
+		t.Errorf("Expected the goal in minute 47, got %d", goal.Minute)
+	}
+	if goal.TeamExternalID != 1 || goal.PlayerExternalID != 10 || goal.AssistExternalID != 11 {
+		t.Errorf("Unexpected goal %+v", goal)
+	}
+	if goal.Type != "Goal" || goal.Detail != "Normal Goal" {
+		t.Errorf("Expected a normal goal, got %s %s", goal.Type, goal.Detail)
+	}
+
+	booking := events[1]
+	if booking.Minute != 60 || booking.PlayerExternalID != 20 || booking.AssistExternalID != 0 {
+		t.Errorf("Unexpected booking %+v", booking)
+	}
+}
+
+func TestFetchFixturePlayerStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedURL := "/fixtures/players?fixture=1001"
+		if r.URL.String() != expectedURL {
+			t.Errorf("Expected URL to be '%s', got '%s'", expectedURL, r.URL.String())
+		}
+
+		// A goalkeeper who played the whole match and a substitute who did not come on
+		w.Write([]byte(`{"response": [{
+			"team": {"id": 40},
+			"players": [
+				{"player": {"id": 280}, "statistics": [{"games": {"minutes": 90}, "goals": {"saves": 4}}]},
+				{"player": {"id": 306}, "statistics": [{"games": {"minutes": null}, "goals": {"saves": null}}]}
+			]
+		}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIFootballClient(server.URL, "test-key", "123", "2023")
+
+	stats, err := client.FetchFixturePlayerStats(1001)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 players, got %d", len(stats))
+	}
+
+	keeper := stats[0]
+	if keeper.TeamExternalID != 40 || keeper.PlayerExternalID != 280 || keeper.Minutes != 90 || keeper.Saves != 4 {
+		t.Errorf("Unexpected stats %+v", keeper)
+	}
+	if stats[1].Minutes != 0 || stats[1].Saves != 0 {
+		t.Errorf("Expected an unused substitute to have no minutes, got %+v", stats[1])
+	}
+}
+
+func TestFetchSquad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedURL := "/players/squads?team=40"
+		if r.URL.String() != expectedURL {
+			t.Errorf("Expected URL to be '%s', got '%s'", expectedURL, r.URL.String())
+		}
+
+		w.Write([]byte(`{"response": [{
+			"team": {"id": 40, "name": "Liverpool"},
+			"players": [
+				{"id": 306, "name": "M. Salah", "number": 11, "position": "Attacker"},
+				{"id": 280, "name": "Alisson Becker", "number": 1, "position": "Goalkeeper"},
+				{"id": 999, "name": "Unknown", "number": 99, "position": "Coach"}
+			]
+		}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIFootballClient(server.URL, "test-key", "123", "2023")
+
+	players, err := client.FetchSquad(40)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(players) != 3 {
+		t.Fatalf("Expected 3 players, got %d", len(players))
+	}
+
+	salah := players[0]
+	if salah.ExternalID != 306 || salah.TeamExternalID != 40 || salah.Name != "M. Salah" {
+		t.Errorf("Unexpected player %+v", salah)
+	}
+	if salah.Position != models.PositionFWD {
+		t.Errorf("Expected position to be '%s', got '%s'", models.PositionFWD, salah.Position)
+	}
+	if players[2].Position != "" {
+		t.Errorf("Expected an empty position, got '%s'", players[2].Position)
+	}
+}
@@ -45,7 +45,7 @@ func main() {
 	}
 
 	// Start the server using v1 handler
-	v1.StartServer(db)
+	v1.StartServer(db, cfg)
 }
 
 func loadEnv(env string) error {
@@ -7,8 +7,12 @@ import (
 
 // MockAPIFootballClient is a mock implementation of the APIFootballClient
 type MockAPIFootballClient struct {
-	teams []*models.Team
-	err   error
+	teams    []*models.Team
+	fixtures []*external.Fixture
+	events   map[int][]*external.FixtureEvent
+	stats    map[int][]*external.FixturePlayerStats
+	squads   map[int][]*external.SquadPlayer
+	err      error
 }
 
 // Ensure MockAPIFootballClient implements APIFootballClientInterface
@@ -22,6 +26,30 @@ func NewMockAPIFootballClient(teams []*models.Team, err error) *MockAPIFootballC
 	}
 }
 
+// WithFixtures sets the fixtures the mock returns
+func (m *MockAPIFootballClient) WithFixtures(fixtures []*external.Fixture) *MockAPIFootballClient {
+	m.fixtures = fixtures
+	return m
+}
+
+// WithEvents sets the events the mock returns for each fixture
+func (m *MockAPIFootballClient) WithEvents(events map[int][]*external.FixtureEvent) *MockAPIFootballClient {
+	m.events = events
+	return m
+}
+
+// WithPlayerStats sets the player statistics the mock returns for each fixture
+func (m *MockAPIFootballClient) WithPlayerStats(stats map[int][]*external.FixturePlayerStats) *MockAPIFootballClient {
+	m.stats = stats
+	return m
+}
+
+// WithSquads sets the squad the mock returns for each team
+func (m *MockAPIFootballClient) WithSquads(squads map[int][]*external.SquadPlayer) *MockAPIFootballClient {
+	m.squads = squads
+	return m
+}
+
 // FetchTeams returns the mock teams and error
 func (m *MockAPIFootballClient) FetchTeams() ([]*models.Team, error) {
 	return m.teams, m.err
@@ -36,3 +64,23 @@ func (m *MockAPIFootballClient) FetchTeamByExternalID(externalID int) (*models.T
 	}
 	return nil, nil
 }
+
+// FetchFixtures returns the mock fixtures and error
+func (m *MockAPIFootballClient) FetchFixtures() ([]*external.Fixture, error) {
+	return m.fixtures, m.err
+}
+
+// FetchFixtureEvents returns the mock events of a fixture and error
+func (m *MockAPIFootballClient) FetchFixtureEvents(fixtureID int) ([]*external.FixtureEvent, error) {
+	return m.events[fixtureID], m.err
+}
+
+// FetchFixturePlayerStats returns the mock player statistics of a fixture and error
+func (m *MockAPIFootballClient) FetchFixturePlayerStats(fixtureID int) ([]*external.FixturePlayerStats, error) {
+	return m.stats[fixtureID], m.err
+}
+
+// FetchSquad returns the mock squad of a team and error
+func (m *MockAPIFootballClient) FetchSquad(teamExternalID int) ([]*external.SquadPlayer, error) {
+	return m.squads[teamExternalID], m.err
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AuditAction is what was done in a league audit log entry
+type AuditAction string
+
+const (
+	AuditActionDraftPick            AuditAction = "draft_pick"            // a player was drafted or won at auction
+	AuditActionAdd                  AuditAction = "add"                   // a free agent was added
+	AuditActionDrop                 AuditAction = "drop"                  // a player was dropped without adding anyone
+	AuditActionWaiverClaim          AuditAction = "waiver_claim"          // a waiver claim was submitted
+	AuditActionWaiverAdd            AuditAction = "waiver_add"            // a waiver claim was won
+	AuditActionTrade                AuditAction = "trade"                 // players changed hands in a trade
+	AuditActionTransfer             AuditAction = "transfer"              // players were bought and sold in a budget league
+	AuditActionFreeHitRevert        AuditAction = "free_hit_revert"       // a free hit squad was put back after its gameweek
+	AuditActionCommissionerOverride AuditAction = "commissioner_override" // the commissioner overruled the managers, such as vetoing a trade
+)
+
+// AuditEntry is one entry in a league's append-only audit log. It records who did what to which
+// user team's roster, and the roster before and after, so a league's history can be replayed.
+type AuditEntry struct {
+	ID              int           `db:"id" json:"id"`
+	LeagueID        int           `db:"league_id" json:"league_id"`
+	UserTeamID      *int          `db:"user_team_id" json:"user_team_id,omitempty"`             // the team whose roster it is
+	ActorUserTeamID *int          `db:"actor_user_team_id" json:"actor_user_team_id,omitempty"` // who did it, empty when done by the system
+	Action          AuditAction   `db:"action" json:"action"`
+	PlayersIn       pq.Int64Array `db:"players_in" json:"players_in"`   // players added, or asked for by a claim
+	PlayersOut      pq.Int64Array `db:"players_out" json:"players_out"` // players dropped, or offered by a claim
+	TradeID         *int          `db:"trade_id" json:"trade_id,omitempty"`
+	WaiverClaimID   *int          `db:"waiver_claim_id" json:"waiver_claim_id,omitempty"`
+	DraftPickID     *int          `db:"draft_pick_id" json:"draft_pick_id,omitempty"`
+	RosterBefore    pq.Int64Array `db:"roster_before" json:"roster_before"`
+	RosterAfter     pq.Int64Array `db:"roster_after" json:"roster_after"`
+	CreatedAt       time.Time     `db:"created_at" json:"created_at"`
+}
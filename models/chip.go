@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ChipType is a one-off boost a user team can play in a gameweek
+type ChipType string
+
+const (
+	ChipWildcard      ChipType = "wildcard"       // unlimited free transfers for the gameweek
+	ChipBenchBoost    ChipType = "bench_boost"    // the bench scores as well as the starting XI
+	ChipTripleCaptain ChipType = "triple_captain" // the captain scores TripleCaptainMultiplier times their points
+	ChipFreeHit       ChipType = "free_hit"       // unlimited free transfers, with the squad put back after the gameweek
+)
+
+// TripleCaptainMultiplier is the captain's multiplier while the triple captain chip is active
+const TripleCaptainMultiplier = 3
+
+// Chip is a chip a user team has played. Each chip can be played once a season and only
+// one chip can be active in a gameweek.
+type Chip struct {
+	ID         int        `db:"id" json:"id"`
+	UserTeamID int        `db:"user_team_id" json:"user_team_id"`
+	GameweekID int        `db:"gameweek_id" json:"gameweek_id"`
+	Chip       ChipType   `db:"chip" json:"chip"`
+	RevertedAt *time.Time `db:"reverted_at" json:"reverted_at,omitempty"` // when the squad before a free hit was put back
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// DraftType represents the format a draft is run in
+type DraftType string
+
+const (
+	DraftTypeSnake   DraftType = "snake"
+	DraftTypeAuction DraftType = "auction"
+)
+
+// DraftStatus represents where a draft is in its lifecycle
+type DraftStatus string
+
+const (
+	DraftStatusScheduled  DraftStatus = "scheduled"
+	DraftStatusInProgress DraftStatus = "in_progress"
+	DraftStatusCompleted  DraftStatus = "completed"
+)
+
+// Draft represents a league's player draft
+type Draft struct {
+	ID             int         `db:"id" json:"id"`
+	LeagueID       int         `db:"league_id" json:"league_id"`
+	Type           DraftType   `db:"type" json:"type"`
+	Status         DraftStatus `db:"status" json:"status"`
+	Rounds         int         `db:"rounds" json:"rounds"` // roster slots per team in an auction
+	CurrentRound   int         `db:"current_round" json:"current_round"`
+	CurrentPick    int         `db:"current_pick" json:"current_pick"`         // pick within the round, or the nominating team's position in an auction
+	Budget         float64     `db:"budget" json:"budget"`                     // auction only
+	MinBid         float64     `db:"min_bid" json:"min_bid"`                   // auction only
+	BidTimeSeconds int         `db:"bid_time_seconds" json:"bid_time_seconds"` // auction only
+	PickDeadline   *time.Time  `db:"pick_deadline" json:"pick_deadline"`       // when the team on the clock is picked for
+	CreatedAt      time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+// DraftSlot represents a user team's position in the draft order
+type DraftSlot struct {
+	DraftID    int `db:"draft_id" json:"draft_id"`
+	UserTeamID int `db:"user_team_id" json:"user_team_id"`
+	Position   int `db:"position" json:"position"`
+}
+
+// DraftPick represents a player selected during a draft
+type DraftPick struct {
+	ID          int       `db:"id" json:"id"`
+	DraftID     int       `db:"draft_id" json:"draft_id"`
+	UserTeamID  int       `db:"user_team_id" json:"user_team_id"`
+	PlayerID    int       `db:"player_id" json:"player_id"`
+	Round       int       `db:"round" json:"round"`
+	Pick        int       `db:"pick" json:"pick"`
+	OverallPick int       `db:"overall_pick" json:"overall_pick"`
+	Price       float64   `db:"price" json:"price"` // winning bid in an auction
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// NominationStatus represents the state of an auction nomination
+type NominationStatus string
+
+const (
+	NominationStatusOpen NominationStatus = "open"
+	NominationStatusSold NominationStatus = "sold"
+)
+
+// DraftNomination represents a player put up for auction
+type DraftNomination struct {
+	ID            int              `db:"id" json:"id"`
+	DraftID       int              `db:"draft_id" json:"draft_id"`
+	PlayerID      int              `db:"player_id" json:"player_id"`
+	NominatedBy   int              `db:"nominated_by" json:"nominated_by"`
+	CurrentBid    float64          `db:"current_bid" json:"current_bid"`
+	CurrentBidder int              `db:"current_bidder" json:"current_bidder"`
+	BidDeadline   time.Time        `db:"bid_deadline" json:"bid_deadline"`
+	Status        NominationStatus `db:"status" json:"status"`
+	CreatedAt     time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// DraftQueueEntry represents a player in a team's ranked draft queue
+type DraftQueueEntry struct {
+	DraftID    int `db:"draft_id" json:"draft_id"`
+	UserTeamID int `db:"user_team_id" json:"user_team_id"`
+	PlayerID   int `db:"player_id" json:"player_id"`
+	Rank       int `db:"rank" json:"rank"`
+}
+
+// DraftBid represents a single bid placed on a nomination
+type DraftBid struct {
+	ID           int       `db:"id" json:"id"`
+	NominationID int       `db:"nomination_id" json:"nomination_id"`
+	UserTeamID   int       `db:"user_team_id" json:"user_team_id"`
+	Amount       float64   `db:"amount" json:"amount"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// GameweekState is where a gameweek is in its lifecycle. It follows from the clock and the
+// statuses of the gameweek's matches, so it is worked out when a gameweek is read, not stored.
+type GameweekState string
+
+const (
+	GameweekStateUpcoming  GameweekState = "upcoming"  // a later gameweek whose deadline is still to come
+	GameweekStateOpen      GameweekState = "open"      // the next deadline, so lineups and transfers are for this gameweek
+	GameweekStateLocked    GameweekState = "locked"    // the deadline has passed and no match has kicked off
+	GameweekStateLive      GameweekState = "live"      // matches are being played
+	GameweekStateFinalized GameweekState = "finalized" // every match is completed or postponed
+)
+
+// Gameweek is a round of real fixtures that user teams pick lineups for
+type Gameweek struct {
+	ID        int           `db:"id" json:"id"`
+	Number    int           `db:"number" json:"number"`
+	Deadline  time.Time     `db:"deadline" json:"deadline"` // lineups lock at the deadline
+	State     GameweekState `db:"-" json:"state,omitempty"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+}
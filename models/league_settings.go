@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// OwnershipMode is how players are shared between the user teams of a league
+type OwnershipMode string
+
+const (
+	OwnershipModeDraft  OwnershipMode = "draft"  // each player belongs to at most one team, assigned by draft
+	OwnershipModeBudget OwnershipMode = "budget" // any team can buy any player within its budget
+)
+
+// WaiverMode is how claims on players on waivers are settled in a draft league
+type WaiverMode string
+
+const (
+	WaiverModeRolling WaiverMode = "rolling" // claims go by waiver priority, with the team that claims sent to the back
+	WaiverModeFAAB    WaiverMode = "faab"    // claims are sealed bids from a free agent acquisition budget
+)
+
+// WaiverTieBreak is how equal FAAB bids on the same player are settled
+type WaiverTieBreak string
+
+const (
+	WaiverTieBreakPriority  WaiverTieBreak = "priority"  // the team with the higher waiver priority wins and goes to the back
+	WaiverTieBreakEarliest  WaiverTieBreak = "earliest"  // the bid placed first wins
+	WaiverTieBreakStandings WaiverTieBreak = "standings" // the team lower in the league standings wins
+)
+
+// TradeReview is how accepted trades are reviewed before they are made
+type TradeReview string
+
+const (
+	TradeReviewNone         TradeReview = "none"         // trades are made as soon as they are accepted
+	TradeReviewCommissioner TradeReview = "commissioner" // the commissioner can veto a trade during the review period
+	TradeReviewLeagueVote   TradeReview = "league_vote"  // enough votes from the other managers veto a trade
+)
+
+// LeagueSettings holds the configurable rules of a league
+type LeagueSettings struct {
+	LeagueID           int            `db:"league_id" json:"league_id"`
+	PickTimeSeconds    int            `db:"pick_time_seconds" json:"pick_time_seconds"` // zero turns the pick clock off
+	PauseStart         string         `db:"pause_start" json:"pause_start"`             // HH:MM the overnight pause begins, empty for none
+	PauseEnd           string         `db:"pause_end" json:"pause_end"`                 // HH:MM the overnight pause ends
+	Timezone           string         `db:"timezone" json:"timezone"`
+	CaptainMultiplier  float64        `db:"captain_multiplier" json:"captain_multiplier"` // points multiplier for the captain
+	OwnershipMode      OwnershipMode  `db:"ownership_mode" json:"ownership_mode"`
+	Budget             float64        `db:"budget" json:"budget"`                           // what a squad can cost in budget leagues
+	WaiverPeriodHours  int            `db:"waiver_period_hours" json:"waiver_period_hours"` // how long dropped players stay on waivers
+	WaiverProcessTime  string         `db:"waiver_process_time" json:"waiver_process_time"` // HH:MM waivers are processed each day, empty for as soon as they clear
+	WaiverMode         WaiverMode     `db:"waiver_mode" json:"waiver_mode"`
+	FAABBudget         int            `db:"faab_budget" json:"faab_budget"` // what each team can bid on waivers over the season
+	WaiverTieBreak     WaiverTieBreak `db:"waiver_tie_break" json:"waiver_tie_break"`
+	TradeReview        TradeReview    `db:"trade_review" json:"trade_review"`
+	TradeReviewHours   int            `db:"trade_review_hours" json:"trade_review_hours"` // how long an accepted trade can be vetoed for
+	TradeVetoVotes     int            `db:"trade_veto_votes" json:"trade_veto_votes"`     // votes that veto a trade under a league vote
+	CommissionerTeamID *int           `db:"commissioner_user_team_id" json:"commissioner_user_team_id,omitempty"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// LeagueTransactionType is how a player joined or left a user team outside of the draft
+type LeagueTransactionType string
+
+const (
+	LeagueTransactionWaiver    LeagueTransactionType = "waiver"     // a waiver claim was won
+	LeagueTransactionFreeAgent LeagueTransactionType = "free_agent" // a free agent was added
+	LeagueTransactionDrop      LeagueTransactionType = "drop"       // a player was dropped without adding anyone
+	LeagueTransactionTrade     LeagueTransactionType = "trade"      // players or FAAB changed hands in a trade
+)
+
+// LeagueTransaction is a change to a user team's roster in a draft league's transaction history
+type LeagueTransaction struct {
+	ID            int                   `db:"id" json:"id"`
+	LeagueID      int                   `db:"league_id" json:"league_id"`
+	UserTeamID    int                   `db:"user_team_id" json:"user_team_id"`
+	Type          LeagueTransactionType `db:"type" json:"type"`
+	PlayerInID    *int                  `db:"player_in_id" json:"player_in_id,omitempty"`
+	PlayerOutID   *int                  `db:"player_out_id" json:"player_out_id,omitempty"`
+	WaiverClaimID *int                  `db:"waiver_claim_id" json:"waiver_claim_id,omitempty"`
+	TradeID       *int                  `db:"trade_id" json:"trade_id,omitempty"`
+	Amount        int                   `db:"amount" json:"amount"` // FAAB spent on the move, negative when a trade brings FAAB in
+	CreatedAt     time.Time             `db:"created_at" json:"created_at"`
+}
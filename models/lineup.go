@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// StartingXISize is the number of players in a starting lineup
+const StartingXISize = 11
+
+// LineupPlayer is a player picked in a user team's lineup for a gameweek
+type LineupPlayer struct {
+	UserTeamID    int       `db:"user_team_id" json:"user_team_id"`
+	GameweekID    int       `db:"gameweek_id" json:"gameweek_id"`
+	PlayerID      int       `db:"player_id" json:"player_id"`
+	Slot          int       `db:"slot" json:"slot"` // 1-11 are the starters, the rest the bench in order
+	IsCaptain     bool      `db:"is_captain" json:"is_captain"`
+	IsViceCaptain bool      `db:"is_vice_captain" json:"is_vice_captain"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// IsStarter reports whether the player is in the starting XI
+func (p *LineupPlayer) IsStarter() bool {
+	return p.Slot <= StartingXISize
+}
+
+// Lineup is the starting XI and ordered bench a user team fields in a gameweek
+type Lineup struct {
+	UserTeamID    int      `json:"user_team_id"`
+	GameweekID    int      `json:"gameweek_id"`
+	Starters      []int    `json:"starters"`
+	Bench         []int    `json:"bench"`
+	CaptainID     int      `json:"captain_id,omitempty"`
+	ViceCaptainID int      `json:"vice_captain_id,omitempty"`
+	Locked        bool     `json:"locked"`                   // the gameweek deadline has passed
+	CarriedOver   bool     `json:"carried_over"`             // taken from an earlier gameweek as it was not changed
+	Chip          ChipType `json:"chip,omitempty"`           // the chip played in the gameweek, if any
+	InvalidReason string   `json:"invalid_reason,omitempty"` // why the lineup fielded breaks the formation rules
+
+	Substitutions []*Substitution `json:"substitutions,omitempty"` // automatic substitutions made after the gameweek
+}
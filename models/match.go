@@ -2,9 +2,19 @@ package models
 
 import "time"
 
+// Statuses a match goes through
+const (
+	MatchStatusScheduled  = "scheduled"
+	MatchStatusInProgress = "in_progress"
+	MatchStatusCompleted  = "completed"
+	MatchStatusPostponed  = "postponed"
+)
+
 type Match struct {
 	ID         int       `db:"id" json:"id"`
+	ExternalID *int      `db:"external_id" json:"external_id,omitempty"` // the API-Football fixture it was synced from
 	LeagueID   int       `db:"league_id" json:"league_id"`
+	GameweekID *int      `db:"gameweek_id" json:"gameweek_id,omitempty"`
 	HomeTeamID int       `db:"home_team_id" json:"home_team_id"`
 	AwayTeamID int       `db:"away_team_id" json:"away_team_id"`
 	MatchDate  time.Time `db:"match_date" json:"match_date"`
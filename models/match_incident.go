@@ -26,6 +26,7 @@ type MatchIncident struct {
 	Type        IncidentType `db:"type" json:"type"`
 	Minute      int          `db:"minute" json:"minute"`
 	Description string       `db:"description" json:"description"`
+	ExternalKey *string      `db:"external_key" json:"external_key,omitempty"` // identifies the API-Football event it was synced from
 	CreatedAt   time.Time    `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time    `db:"updated_at" json:"updated_at"`
 }
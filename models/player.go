@@ -13,11 +13,13 @@ const (
 )
 
 type Player struct {
-	ID        int       `db:"id" json:"id"`
-	FirstName string    `db:"first_name" json:"first_name"`
-	LastName  string    `db:"last_name" json:"last_name"`
-	Position  Position  `db:"position" json:"position"`
-	TeamID    int       `db:"team_id" json:"team_id"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID         int       `db:"id" json:"id"`
+	ExternalID *int      `db:"external_id" json:"external_id,omitempty"` // the player's API-Football ID
+	FirstName  string    `db:"first_name" json:"first_name"`
+	LastName   string    `db:"last_name" json:"last_name"`
+	Position   Position  `db:"position" json:"position"`
+	TeamID     int       `db:"team_id" json:"team_id"`
+	Price      float64   `db:"price" json:"price"` // current price in budget leagues
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PlayerPrice is a player's price from the time it was set
+type PlayerPrice struct {
+	ID        int       `db:"id" json:"id"`
+	PlayerID  int       `db:"player_id" json:"player_id"`
+	Price     float64   `db:"price" json:"price"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// PriceChange is a move in a player's price from one price to the next
+type PriceChange struct {
+	PlayerID  int       `db:"player_id" json:"player_id"`
+	OldPrice  float64   `db:"old_price" json:"old_price"`
+	NewPrice  float64   `db:"new_price" json:"new_price"`
+	ChangedAt time.Time `db:"changed_at" json:"changed_at"`
+}
@@ -5,6 +5,7 @@ import "time"
 type PlayerStats struct {
 	ID            int       `db:"id" json:"id"`
 	PlayerID      int       `db:"player_id" json:"player_id"`
+	MatchID       *int      `db:"match_id" json:"match_id,omitempty"` // nil for season totals
 	Goals         int       `db:"goals" json:"goals"`
 	Assists       int       `db:"assists" json:"assists"`
 	CleanSheets   int       `db:"clean_sheets" json:"clean_sheets"`
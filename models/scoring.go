@@ -0,0 +1,146 @@
+package models
+
+import "time"
+
+// ScoringPreset is a built-in set of scoring rules modelled on a public fantasy game
+type ScoringPreset string
+
+const (
+	ScoringPresetFPL    ScoringPreset = "fpl"    // Fantasy Premier League
+	ScoringPresetUCL    ScoringPreset = "ucl"    // UEFA Champions League Fantasy
+	ScoringPresetCustom ScoringPreset = "custom" // set by the league's commissioner
+)
+
+// AppearanceThreshold awards points to a player who plays at least a number of minutes in a match
+type AppearanceThreshold struct {
+	Minutes int `json:"minutes"`
+	Points  int `json:"points"`
+}
+
+// ScoringRules are the fantasy points a player earns in a match in a league
+type ScoringRules struct {
+	LeagueID    int                               `json:"league_id"`
+	Preset      ScoringPreset                     `json:"preset"`
+	Incidents   map[Position]map[IncidentType]int `json:"incidents"`    // points per incident by the player's position
+	Appearances []AppearanceThreshold             `json:"appearances"`  // only the highest threshold reached scores
+	BonusPoints []int                             `json:"bonus_points"` // extra points for a match's best performers, best first
+	Locked      bool                              `json:"locked"`       // the season has started, so the rules can no longer change
+	UpdatedAt   time.Time                         `json:"updated_at"`
+}
+
+// IncidentPoints returns the points a player in a position earns for an incident, or zero if
+// the incident does not score for the position
+func (r *ScoringRules) IncidentPoints(position Position, incident IncidentType) int {
+	return r.Incidents[position][incident]
+}
+
+// AppearancePoints returns the points for playing a number of minutes in a match
+func (r *ScoringRules) AppearancePoints(minutes int) int {
+	points, reached := 0, 0
+	for _, threshold := range r.Appearances {
+		if minutes >= threshold.Minutes && threshold.Minutes >= reached {
+			points, reached = threshold.Points, threshold.Minutes
+		}
+	}
+	return points
+}
+
+// BonusFor returns the bonus points for finishing a match at a rank, or zero outside the ranks
+// that earn a bonus
+func (r *ScoringRules) BonusFor(rank int) int {
+	if rank < 1 || rank > len(r.BonusPoints) {
+		return 0
+	}
+	return r.BonusPoints[rank-1]
+}
+
+// MatchBonus is how a player performed in a match and the bonus points it earned them in a
+// league. Players level on performance score share a rank. The bonus is provisional until the
+// match is completed.
+type MatchBonus struct {
+	MatchID          int  `json:"match_id"`
+	PlayerID         int  `json:"player_id"`
+	PerformanceScore int  `json:"performance_score"`
+	Rank             int  `json:"rank"`
+	Bonus            int  `json:"bonus"`
+	Provisional      bool `json:"provisional"`
+}
+
+// PlayerPoints are the fantasy points a player scored in a gameweek under a league's scoring
+// rules, with the counts they were worked out from. A penalty scored counts as a goal. Points
+// include any bonus, which is provisional while one of the matches it came from is unfinished.
+type PlayerPoints struct {
+	LeagueID         int       `db:"league_id" json:"league_id"`
+	PlayerID         int       `db:"player_id" json:"player_id"`
+	GameweekID       int       `db:"gameweek_id" json:"gameweek_id"`
+	Points           int       `db:"points" json:"points"`
+	MinutesPlayed    int       `db:"minutes_played" json:"minutes_played"`
+	Goals            int       `db:"goals" json:"goals"`
+	Assists          int       `db:"assists" json:"assists"`
+	CleanSheets      int       `db:"clean_sheets" json:"clean_sheets"`
+	YellowCards      int       `db:"yellow_cards" json:"yellow_cards"`
+	RedCards         int       `db:"red_cards" json:"red_cards"`
+	PenaltiesSaved   int       `db:"penalties_saved" json:"penalties_saved"`
+	PenaltiesMissed  int       `db:"penalties_missed" json:"penalties_missed"`
+	OwnGoals         int       `db:"own_goals" json:"own_goals"`
+	Bonus            int       `db:"bonus" json:"bonus"`
+	BonusProvisional bool      `db:"bonus_provisional" json:"bonus_provisional"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Add adds the points and counts of another match in the same gameweek
+func (p *PlayerPoints) Add(other *PlayerPoints) {
+	p.Points += other.Points
+	p.MinutesPlayed += other.MinutesPlayed
+	p.Goals += other.Goals
+	p.Assists += other.Assists
+	p.CleanSheets += other.CleanSheets
+	p.YellowCards += other.YellowCards
+	p.RedCards += other.RedCards
+	p.PenaltiesSaved += other.PenaltiesSaved
+	p.PenaltiesMissed += other.PenaltiesMissed
+	p.OwnGoals += other.OwnGoals
+	p.Bonus += other.Bonus
+	p.BonusProvisional = p.BonusProvisional || other.BonusProvisional
+}
+
+// TeamScore is what a user team scored in a gameweek: the points of the players who counted,
+// multiplied for the captain and chips, less any transfer hits
+type TeamScore struct {
+	UserTeamID   int             `json:"user_team_id"`
+	GameweekID   int             `json:"gameweek_id"`
+	Players      []*ScoredPlayer `json:"players"`
+	TransferCost int             `json:"transfer_cost"`
+	Points       float64         `json:"points"`
+}
+
+// ScoredPlayer is a player whose points counted towards a user team's gameweek score
+type ScoredPlayer struct {
+	PlayerID   int     `json:"player_id"`
+	Points     int     `json:"points"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Standing is a user team's place in its league by the points it has scored over the season
+type Standing struct {
+	Rank       int     `json:"rank"`
+	UserTeamID int     `json:"user_team_id"`
+	Name       string  `json:"name"`
+	Points     float64 `json:"points"`
+}
+
+// LiveScores are the scores of a league's user teams in a gameweek worked out from its matches
+// as they stand. They are provisional while any of the gameweek's matches is still to finish.
+type LiveScores struct {
+	LeagueID    int              `json:"league_id"`
+	GameweekID  int              `json:"gameweek_id"`
+	Provisional bool             `json:"provisional"`
+	Matches     []*Match         `json:"matches"`
+	Teams       []*LiveTeamScore `json:"teams"`
+}
+
+// LiveTeamScore is a user team's live score in a gameweek
+type LiveTeamScore struct {
+	Name string `json:"name"`
+	*TeamScore
+}
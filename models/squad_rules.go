@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// SquadRules holds the limits a league places on the make-up of each user team's squad
+type SquadRules struct {
+	LeagueID   int       `db:"league_id" json:"league_id"`
+	SquadSize  int       `db:"squad_size" json:"squad_size"`
+	MaxPerTeam int       `db:"max_per_team" json:"max_per_team"` // most players allowed from one real team
+	MinGK      int       `db:"min_gk" json:"min_gk"`
+	MaxGK      int       `db:"max_gk" json:"max_gk"`
+	MinDEF     int       `db:"min_def" json:"min_def"`
+	MaxDEF     int       `db:"max_def" json:"max_def"`
+	MinMID     int       `db:"min_mid" json:"min_mid"`
+	MaxMID     int       `db:"max_mid" json:"max_mid"`
+	MinFWD     int       `db:"min_fwd" json:"min_fwd"`
+	MaxFWD     int       `db:"max_fwd" json:"max_fwd"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// PositionLimits returns the minimum and maximum number of players allowed at a position
+func (r *SquadRules) PositionLimits(position Position) (int, int) {
+	switch position {
+	case PositionGK:
+		return r.MinGK, r.MaxGK
+	case PositionDEF:
+		return r.MinDEF, r.MaxDEF
+	case PositionMID:
+		return r.MinMID, r.MaxMID
+	case PositionFWD:
+		return r.MinFWD, r.MaxFWD
+	}
+	return 0, 0
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// CorrectionAction is how a stat correction changed a finished match's incidents
+type CorrectionAction string
+
+const (
+	CorrectionActionAdded   CorrectionAction = "added"
+	CorrectionActionChanged CorrectionAction = "changed"
+	CorrectionActionRemoved CorrectionAction = "removed"
+)
+
+// StatCorrection is a change to an incident of a match after it finished, with the incident as
+// it was and as it became and the points that moved because of it
+type StatCorrection struct {
+	ID           int              `db:"id" json:"id"`
+	MatchID      int              `db:"match_id" json:"match_id"`
+	GameweekID   int              `db:"gameweek_id" json:"gameweek_id"`
+	IncidentID   int              `db:"incident_id" json:"incident_id"`
+	Action       CorrectionAction `db:"action" json:"action"`
+	OldPlayerID  *int             `db:"old_player_id" json:"old_player_id,omitempty"` // empty for an added incident
+	OldType      *IncidentType    `db:"old_type" json:"old_type,omitempty"`
+	OldMinute    *int             `db:"old_minute" json:"old_minute,omitempty"`
+	NewPlayerID  *int             `db:"new_player_id" json:"new_player_id,omitempty"` // empty for a removed incident
+	NewType      *IncidentType    `db:"new_type" json:"new_type,omitempty"`
+	NewMinute    *int             `db:"new_minute" json:"new_minute,omitempty"`
+	PointChanges []*PointChange   `db:"-" json:"point_changes"`
+	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
+}
+
+// PointChange is how a stat correction moved a player's gameweek points in a league
+type PointChange struct {
+	CorrectionID int `db:"correction_id" json:"correction_id"`
+	LeagueID     int `db:"league_id" json:"league_id"`
+	PlayerID     int `db:"player_id" json:"player_id"`
+	OldPoints    int `db:"old_points" json:"old_points"`
+	NewPoints    int `db:"new_points" json:"new_points"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Substitution is an automatic swap of a starter who did not play for a bench player who did
+type Substitution struct {
+	ID          int       `db:"id" json:"id"`
+	UserTeamID  int       `db:"user_team_id" json:"user_team_id"`
+	GameweekID  int       `db:"gameweek_id" json:"gameweek_id"`
+	PlayerOutID int       `db:"player_out_id" json:"player_out_id"`
+	PlayerInID  int       `db:"player_in_id" json:"player_in_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// TradeStatus is where a trade proposal is in its lifecycle
+type TradeStatus string
+
+const (
+	TradeStatusPending   TradeStatus = "pending"   // waiting for the recipient to respond
+	TradeStatusInReview  TradeStatus = "in_review" // the recipient accepted and the league can veto the trade
+	TradeStatusAccepted  TradeStatus = "accepted"  // the trade was made
+	TradeStatusVetoed    TradeStatus = "vetoed"    // the commissioner or the league vetoed the trade in review
+	TradeStatusRejected  TradeStatus = "rejected"  // the recipient turned the trade down
+	TradeStatusCountered TradeStatus = "countered" // the recipient answered with a trade of their own
+	TradeStatusCancelled TradeStatus = "cancelled" // the proposer withdrew the trade
+	TradeStatusExpired   TradeStatus = "expired"   // nobody responded before the deadline
+	TradeStatusInvalid   TradeStatus = "invalid"   // a player in the trade left the roster offering them
+)
+
+// TradeItemType is what a team gives up in a trade
+type TradeItemType string
+
+const (
+	TradeItemPlayer    TradeItemType = "player"     // a player on the team's roster
+	TradeItemDraftPick TradeItemType = "draft_pick" // a pick in the league's next draft
+	TradeItemFAAB      TradeItemType = "faab"       // some of the team's FAAB balance
+)
+
+// Trade is a proposal between two user teams in a draft league to swap players, future draft
+// picks and FAAB
+type Trade struct {
+	ID              int          `db:"id" json:"id"`
+	LeagueID        int          `db:"league_id" json:"league_id"`
+	ProposerTeamID  int          `db:"proposer_team_id" json:"proposer_team_id"`
+	RecipientTeamID int          `db:"recipient_team_id" json:"recipient_team_id"`
+	Status          TradeStatus  `db:"status" json:"status"`
+	CounterOfID     *int         `db:"counter_of_id" json:"counter_of_id,omitempty"` // the trade this one answers
+	Message         string       `db:"message" json:"message"`
+	ExpiresAt       time.Time    `db:"expires_at" json:"expires_at"`
+	RespondedAt     *time.Time   `db:"responded_at" json:"responded_at,omitempty"`
+	ReviewEndsAt    *time.Time   `db:"review_ends_at" json:"review_ends_at,omitempty"` // when an accepted trade is made unless vetoed
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
+	Items           []*TradeItem `db:"-" json:"items"`
+	VetoVotes       int          `db:"-" json:"veto_votes"`
+}
+
+// TradeItem is something one side of a trade gives to the other
+type TradeItem struct {
+	ID                 int           `db:"id" json:"id"`
+	TradeID            int           `db:"trade_id" json:"trade_id"`
+	FromTeamID         int           `db:"from_user_team_id" json:"from_user_team_id"`
+	Type               TradeItemType `db:"type" json:"type"`
+	PlayerID           *int          `db:"player_id" json:"player_id,omitempty"`
+	DraftRound         *int          `db:"draft_round" json:"draft_round,omitempty"`
+	PickOriginalTeamID *int          `db:"pick_original_user_team_id" json:"pick_original_user_team_id,omitempty"` // whose pick it was to begin with
+	Amount             int           `db:"amount" json:"amount"`                                                   // FAAB only
+}
+
+// TradeVote is a manager's vote to veto a trade in review
+type TradeVote struct {
+	TradeID    int       `db:"trade_id" json:"trade_id"`
+	UserTeamID int       `db:"user_team_id" json:"user_team_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+// DraftPickOwner records who owns a pick in a league's next draft after it has been traded.
+// Picks nobody traded belong to the team they were dealt to.
+type DraftPickOwner struct {
+	LeagueID           int  `db:"league_id" json:"league_id"`
+	Round              int  `db:"round" json:"round"`
+	OriginalUserTeamID int  `db:"original_user_team_id" json:"original_user_team_id"`
+	OwnerUserTeamID    int  `db:"owner_user_team_id" json:"owner_user_team_id"`
+	DraftID            *int `db:"draft_id" json:"draft_id,omitempty"` // set once the draft the pick is in is created
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Transfer is a player bought, sold or swapped by a user team in a budget league
+type Transfer struct {
+	ID          int       `db:"id" json:"id"`
+	UserTeamID  int       `db:"user_team_id" json:"user_team_id"`
+	PlayerOutID *int      `db:"player_out_id" json:"player_out_id,omitempty"`
+	PlayerInID  *int      `db:"player_in_id" json:"player_in_id,omitempty"`
+	PriceOut    float64   `db:"price_out" json:"price_out"`               // what the player out was sold for
+	PriceIn     float64   `db:"price_in" json:"price_in"`                 // what the player in was bought for
+	GameweekID  *int      `db:"gameweek_id" json:"gameweek_id,omitempty"` // the gameweek the transfer counts towards
+	Cost        int       `db:"cost" json:"cost"`                         // points deducted for going over the free transfers
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
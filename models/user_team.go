@@ -6,6 +6,7 @@ type UserTeam struct {
 	ID        int       `db:"id" json:"id"`
 	Name      string    `db:"name" json:"name"`
 	UserID    int       `db:"user_id" json:"user_id"`
+	LeagueID  int       `db:"league_id" json:"league_id"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// WaiverClaimStatus is where a waiver claim is in processing
+type WaiverClaimStatus string
+
+const (
+	WaiverClaimPending   WaiverClaimStatus = "pending"   // waiting for the player's waivers to be processed
+	WaiverClaimWon       WaiverClaimStatus = "won"       // the player was added to the claiming team
+	WaiverClaimLost      WaiverClaimStatus = "lost"      // a team with higher priority claimed the player first
+	WaiverClaimFailed    WaiverClaimStatus = "failed"    // the claim could no longer be made, e.g. the player to drop had gone
+	WaiverClaimCancelled WaiverClaimStatus = "cancelled" // the claiming team withdrew the claim
+)
+
+// Waiver is a player on waivers in a draft league. Nobody can add them freely until their
+// waivers are processed, claims on them are settled by waiver priority instead.
+type Waiver struct {
+	ID          int        `db:"id" json:"id"`
+	LeagueID    int        `db:"league_id" json:"league_id"`
+	PlayerID    int        `db:"player_id" json:"player_id"`
+	ClearsAt    time.Time  `db:"clears_at" json:"clears_at"` // when claims on the player are processed
+	ProcessedAt *time.Time `db:"processed_at" json:"processed_at,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+}
+
+// WaiverClaim is a user team's request to add a player on waivers, optionally dropping one of
+// its own players to make room
+type WaiverClaim struct {
+	ID           int               `db:"id" json:"id"`
+	LeagueID     int               `db:"league_id" json:"league_id"`
+	UserTeamID   int               `db:"user_team_id" json:"user_team_id"`
+	PlayerID     int               `db:"player_id" json:"player_id"`
+	DropPlayerID *int              `db:"drop_player_id" json:"drop_player_id,omitempty"`
+	Rank         int               `db:"rank" json:"rank"` // the team's order of preference, 1 first
+	Bid          int               `db:"bid" json:"bid"`   // sealed FAAB bid, zero in rolling waiver leagues
+	Status       WaiverClaimStatus `db:"status" json:"status"`
+	ProcessedAt  *time.Time        `db:"processed_at" json:"processed_at,omitempty"`
+	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
+}
+
+// WaiverPriority is a user team's place in its league's waiver order, 1 claims first
+type WaiverPriority struct {
+	LeagueID   int `db:"league_id" json:"league_id"`
+	UserTeamID int `db:"user_team_id" json:"user_team_id"`
+	Priority   int `db:"priority" json:"priority"`
+}
@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditHandler struct {
+	auditService audit.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler instance
+func NewAuditHandler(db *sqlx.DB) *AuditHandler {
+	return &AuditHandler{
+		auditService: audit.NewAuditService(db),
+	}
+}
+
+// ListEntries handles GET /api/leagues/:id/audit-log with query parameters
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	query := c.Request.URL.Query()
+	filter := &audit.AuditFilter{
+		Action: models.AuditAction(query.Get("action")),
+	}
+
+	// Numeric filters and the page
+	for name, value := range map[string]*int{
+		"user_team_id":       &filter.UserTeamID,
+		"actor_user_team_id": &filter.ActorUserTeamID,
+		"player_id":          &filter.PlayerID,
+		"page":               &filter.Page,
+		"page_size":          &filter.PageSize,
+	} {
+		if param := query.Get(name); param != "" {
+			id, err := strconv.Atoi(param)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid " + name,
+				})
+				return
+			}
+			*value = id
+		}
+	}
+
+	// Time range, in RFC 3339
+	for name, value := range map[string]**time.Time{
+		"since": &filter.Since,
+		"until": &filter.Until,
+	} {
+		if param := query.Get(name); param != "" {
+			at, err := time.Parse(time.RFC3339, param)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid " + name + ", expected an RFC 3339 time",
+				})
+				return
+			}
+			*value = &at
+		}
+	}
+
+	page, err := h.auditService.ListEntries(leagueID, filter)
+	if err != nil {
+		if errors.Is(err, audit.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve audit log",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/audit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupAuditHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockAuditService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockAuditService)
+	handler := &AuditHandler{
+		auditService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/leagues/:id/audit-log", handler.ListEntries)
+
+	return router, mockService
+}
+
+func TestListEntries(t *testing.T) {
+	router, mockService := setupAuditHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		userTeamID := 3
+		mockService.On("ListEntries", 1, mock.MatchedBy(func(filter *audit.AuditFilter) bool {
+			return filter.UserTeamID == 3 && filter.PlayerID == 7 && filter.Action == models.AuditActionTrade &&
+				filter.Page == 2 && filter.PageSize == 10 && filter.Since != nil && filter.Until == nil
+		})).Return(&audit.AuditPage{
+			Entries: []*models.AuditEntry{
+				{ID: 1, LeagueID: 1, UserTeamID: &userTeamID, Action: models.AuditActionTrade, PlayersIn: audit.PlayerIDs(7)},
+			},
+			Page:     2,
+			PageSize: 10,
+			Total:    11,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET",
+			"/leagues/1/audit-log?user_team_id=3&player_id=7&action=trade&page=2&page_size=10&since=2024-08-01T00:00:00Z", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response audit.AuditPage
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, response.Total)
+		assert.Len(t, response.Entries, 1)
+	})
+
+	t.Run("invalid query parameters", func(t *testing.T) {
+		for _, query := range []string{"page=two", "player_id=x", "since=yesterday"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/leagues/1/audit-log?%s", query), nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		mockService.On("ListEntries", 2, mock.Anything).Return(nil, fmt.Errorf("%w: unknown action shuffle", audit.ErrInvalidFilter))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/2/audit-log?action=shuffle", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService.On("ListEntries", 4, mock.Anything).Return(nil, errors.New("database error"))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/4/audit-log", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,526 @@
+package draft
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/models"
+	"go-app/services/draft"
+	"go-app/services/squad"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// Notifier is told when a draft changes so live draft rooms can be updated
+type Notifier interface {
+	Refresh(draftID int)
+}
+
+type DraftHandler struct {
+	draftService draft.DraftService
+	notifier     Notifier
+}
+
+// NewDraftHandler creates a new DraftHandler instance
+func NewDraftHandler(db *sqlx.DB, notifier Notifier) *DraftHandler {
+	return &DraftHandler{
+		draftService: draft.NewDraftService(db),
+		notifier:     notifier,
+	}
+}
+
+// notify tells the notifier, if any, that a draft has changed
+func (h *DraftHandler) notify(draftID int) {
+	if h.notifier != nil {
+		h.notifier.Refresh(draftID)
+	}
+}
+
+// createDraftRequest is the request body for creating a draft
+type createDraftRequest struct {
+	Type           models.DraftType `json:"type"`
+	Rounds         int              `json:"rounds" binding:"required"`
+	UserTeamIDs    []int            `json:"user_team_ids" binding:"required"`
+	Budget         float64          `json:"budget"`
+	MinBid         float64          `json:"min_bid"`
+	BidTimeSeconds int              `json:"bid_time_seconds"`
+}
+
+// makePickRequest is the request body for making a draft pick
+type makePickRequest struct {
+	UserTeamID int `json:"user_team_id" binding:"required"`
+	PlayerID   int `json:"player_id" binding:"required"`
+}
+
+// nominateRequest is the request body for putting a player up for auction
+type nominateRequest struct {
+	UserTeamID int     `json:"user_team_id" binding:"required"`
+	PlayerID   int     `json:"player_id" binding:"required"`
+	Amount     float64 `json:"amount" binding:"required"`
+}
+
+// bidRequest is the request body for bidding on the player up for auction
+type bidRequest struct {
+	UserTeamID int     `json:"user_team_id" binding:"required"`
+	Amount     float64 `json:"amount" binding:"required"`
+}
+
+// conflictErrors are draft errors caused by the state of the draft rather than a server fault
+var conflictErrors = []error{
+	draft.ErrDraftNotInProgress,
+	draft.ErrNotYourTurn,
+	draft.ErrPlayerAlreadyDrafted,
+	draft.ErrWrongDraftType,
+	draft.ErrRosterFull,
+	draft.ErrNominationOpen,
+	draft.ErrNoOpenNomination,
+	draft.ErrBidTooLow,
+	draft.ErrBidExceedsMax,
+	draft.ErrAlreadyHighBidder,
+	draft.ErrBidClockRunning,
+	draft.ErrBidClockExpired,
+}
+
+// isConflict reports whether err was caused by the state of the draft
+func isConflict(err error) bool {
+	for _, conflict := range conflictErrors {
+		if errors.Is(err, conflict) {
+			return true
+		}
+	}
+	return false
+}
+
+// respondRuleViolation writes a 422 response naming the squad rule that err broke, if it broke one
+func respondRuleViolation(c *gin.Context, err error) bool {
+	var violation *squad.RuleViolation
+	if !errors.As(err, &violation) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":     violation.Message,
+		"violation": violation,
+	})
+	return true
+}
+
+// CreateDraft handles POST /api/leagues/:id/draft
+func (h *DraftHandler) CreateDraft(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	var req createDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	var createdDraft *models.Draft
+	switch req.Type {
+	case "", models.DraftTypeSnake:
+		createdDraft, err = h.draftService.CreateDraft(leagueID, req.Rounds, req.UserTeamIDs)
+	case models.DraftTypeAuction:
+		createdDraft, err = h.draftService.CreateAuctionDraft(leagueID, req.UserTeamIDs, draft.AuctionSettings{
+			RosterSlots:    req.Rounds,
+			Budget:         req.Budget,
+			MinBid:         req.MinBid,
+			BidTimeSeconds: req.BidTimeSeconds,
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft type. Must be one of: snake, auction",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdDraft)
+}
+
+// GetLeagueDraft handles GET /api/leagues/:id/draft
+func (h *DraftHandler) GetLeagueDraft(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	leagueDraft, err := h.draftService.GetDraftByLeague(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve draft",
+		})
+		return
+	}
+
+	if leagueDraft == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Draft not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, leagueDraft)
+}
+
+// GetDraft handles GET /api/drafts/:id
+func (h *DraftHandler) GetDraft(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	d, err := h.draftService.GetDraft(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve draft",
+		})
+		return
+	}
+
+	order, err := h.draftService.GetDraftOrder(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve draft order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"draft": d,
+		"order": order,
+	})
+}
+
+// StartDraft handles POST /api/drafts/:id/start
+func (h *DraftHandler) StartDraft(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	startedDraft, err := h.draftService.StartDraft(id)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	h.notify(id)
+	c.JSON(http.StatusOK, startedDraft)
+}
+
+// ListPicks handles GET /api/drafts/:id/picks
+func (h *DraftHandler) ListPicks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	picks, err := h.draftService.ListPicks(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve draft picks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, picks)
+}
+
+// MakePick handles POST /api/drafts/:id/picks
+func (h *DraftHandler) MakePick(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	var req makePickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	pick, err := h.draftService.MakePick(id, req.UserTeamID, req.PlayerID)
+	if err != nil {
+		if respondRuleViolation(c, err) {
+			return
+		}
+		if isConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to make pick",
+		})
+		return
+	}
+
+	h.notify(id)
+	c.JSON(http.StatusCreated, pick)
+}
+
+// Nominate handles POST /api/drafts/:id/nominations
+func (h *DraftHandler) Nominate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	var req nominateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	nomination, err := h.draftService.Nominate(id, req.UserTeamID, req.PlayerID, req.Amount)
+	if err != nil {
+		if respondRuleViolation(c, err) {
+			return
+		}
+		if isConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to nominate player",
+		})
+		return
+	}
+
+	h.notify(id)
+	c.JSON(http.StatusCreated, nomination)
+}
+
+// PlaceBid handles POST /api/drafts/:id/bids
+func (h *DraftHandler) PlaceBid(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	var req bidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	nomination, err := h.draftService.PlaceBid(id, req.UserTeamID, req.Amount)
+	if err != nil {
+		if respondRuleViolation(c, err) {
+			return
+		}
+		if isConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to place bid",
+		})
+		return
+	}
+
+	h.notify(id)
+	c.JSON(http.StatusOK, nomination)
+}
+
+// GetOpenNomination handles GET /api/drafts/:id/nomination
+func (h *DraftHandler) GetOpenNomination(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	nomination, err := h.draftService.GetOpenNomination(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve nomination",
+		})
+		return
+	}
+
+	if nomination == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No player is up for auction",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, nomination)
+}
+
+// CloseNomination handles POST /api/drafts/:id/nomination/close
+func (h *DraftHandler) CloseNomination(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	pick, err := h.draftService.CloseNomination(id)
+	if err != nil {
+		if isConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to close nomination",
+		})
+		return
+	}
+
+	h.notify(id)
+	c.JSON(http.StatusOK, pick)
+}
+
+// GetAuctionBudgets handles GET /api/drafts/:id/budgets
+func (h *DraftHandler) GetAuctionBudgets(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	budgets, err := h.draftService.GetAuctionBudgets(id)
+	if err != nil {
+		if isConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve budgets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// setQueueRequest is the request body for replacing a team's draft queue
+type setQueueRequest struct {
+	PlayerIDs []int `json:"player_ids"`
+}
+
+// GetQueue handles GET /api/drafts/:id/queues/:user_team_id
+func (h *DraftHandler) GetQueue(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	userTeamID, err := strconv.Atoi(c.Param("user_team_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	queue, err := h.draftService.GetQueue(id, userTeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve draft queue",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// SetQueue handles PUT /api/drafts/:id/queues/:user_team_id
+func (h *DraftHandler) SetQueue(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return
+	}
+
+	userTeamID, err := strconv.Atoi(c.Param("user_team_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req setQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	queue, err := h.draftService.SetQueue(id, userTeamID, req.PlayerIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
@@ -0,0 +1,337 @@
+package draft
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/draft"
+	"go-app/services/squad"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupDraftHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockDraftService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockDraftService)
+	handler := &DraftHandler{
+		draftService: mockService,
+	}
+
+	// Setup routes
+	router.POST("/leagues/:id/draft", handler.CreateDraft)
+	router.GET("/leagues/:id/draft", handler.GetLeagueDraft)
+	router.GET("/drafts/:id", handler.GetDraft)
+	router.POST("/drafts/:id/start", handler.StartDraft)
+	router.GET("/drafts/:id/picks", handler.ListPicks)
+	router.POST("/drafts/:id/picks", handler.MakePick)
+	router.POST("/drafts/:id/nominations", handler.Nominate)
+	router.POST("/drafts/:id/bids", handler.PlaceBid)
+	router.GET("/drafts/:id/nomination", handler.GetOpenNomination)
+	router.POST("/drafts/:id/nomination/close", handler.CloseNomination)
+	router.GET("/drafts/:id/budgets", handler.GetAuctionBudgets)
+	router.GET("/drafts/:id/queues/:user_team_id", handler.GetQueue)
+	router.PUT("/drafts/:id/queues/:user_team_id", handler.SetQueue)
+
+	return router, mockService
+}
+
+func TestCreateDraft(t *testing.T) {
+	router, mockService := setupDraftHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		expectedDraft := &models.Draft{
+			ID:       1,
+			LeagueID: 1,
+			Type:     models.DraftTypeSnake,
+			Status:   models.DraftStatusScheduled,
+			Rounds:   15,
+		}
+
+		mockService.On("CreateDraft", 1, 15, []int{10, 11, 12}).Return(expectedDraft, nil)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"rounds":        15,
+			"user_team_ids": []int{10, 11, 12},
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/leagues/1/draft", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.Draft
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, expectedDraft.ID, response.ID)
+		assert.Equal(t, expectedDraft.Rounds, response.Rounds)
+	})
+
+	t.Run("invalid league id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/leagues/invalid/draft", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/leagues/1/draft", bytes.NewBufferString("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetLeagueDraft(t *testing.T) {
+	router, mockService := setupDraftHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("GetDraftByLeague", 1).Return(&models.Draft{ID: 3, LeagueID: 1}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/1/draft", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.Draft
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, 3, response.ID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService.On("GetDraftByLeague", 2).Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/2/draft", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestMakePick(t *testing.T) {
+	router, mockService := setupDraftHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		expectedPick := &models.DraftPick{
+			ID:          1,
+			DraftID:     1,
+			UserTeamID:  10,
+			PlayerID:    99,
+			Round:       1,
+			Pick:        1,
+			OverallPick: 1,
+		}
+
+		mockService.On("MakePick", 1, 10, 99).Return(expectedPick, nil)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"user_team_id": 10,
+			"player_id":    99,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/drafts/1/picks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.DraftPick
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, expectedPick.PlayerID, response.PlayerID)
+	})
+
+	t.Run("player already drafted", func(t *testing.T) {
+		mockService.On("MakePick", 1, 10, 50).Return(nil, draft.ErrPlayerAlreadyDrafted)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"user_team_id": 10,
+			"player_id":    50,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/drafts/1/picks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("breaks squad rules", func(t *testing.T) {
+		mockService.On("MakePick", 1, 10, 52).Return(nil, &squad.RuleViolation{
+			Rule:     squad.RulePositionMax,
+			Position: models.PositionGK,
+			Limit:    2,
+			Message:  "squad cannot have more than 2 GK players",
+		})
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"user_team_id": 10,
+			"player_id":    52,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/drafts/1/picks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var response struct {
+			Violation squad.RuleViolation `json:"violation"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, squad.RulePositionMax, response.Violation.Rule)
+		assert.Equal(t, models.PositionGK, response.Violation.Position)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService.On("MakePick", 1, 10, 51).Return(nil, errors.New("database error"))
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"user_team_id": 10,
+			"player_id":    51,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/drafts/1/picks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestListPicks(t *testing.T) {
+	router, mockService := setupDraftHandlerTest(t)
+
+	mockService.On("ListPicks", 1).Return([]*models.DraftPick{
+		{ID: 1, DraftID: 1, PlayerID: 5, OverallPick: 1},
+		{ID: 2, DraftID: 1, PlayerID: 6, OverallPick: 2},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/drafts/1/picks", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []*models.DraftPick
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response, 2)
+}
+
+func TestCreateAuctionDraft(t *testing.T) {
+	router, mockService := setupDraftHandlerTest(t)
+
+	settings := draft.AuctionSettings{
+		RosterSlots:    15,
+		Budget:         200,
+		MinBid:         1,
+		BidTimeSeconds: 30,
+	}
+	mockService.On("CreateAuctionDraft", 1, []int{10, 11}, settings).Return(&models.Draft{
+		ID:     2,
+		Type:   models.DraftTypeAuction,
+		Budget: 200,
+	}, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"type":             "auction",
+		"rounds":           15,
+		"user_team_ids":    []int{10, 11},
+		"budget":           200,
+		"min_bid":          1,
+		"bid_time_seconds": 30,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/leagues/1/draft", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.Draft
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, models.DraftTypeAuction, response.Type)
+}
+
+func TestPlaceBid(t *testing.T) {
+	router, mockService := setupDraftHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("PlaceBid", 1, 10, 12.0).Return(&models.DraftNomination{
+			ID:            1,
+			CurrentBid:    12,
+			CurrentBidder: 10,
+		}, nil)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"user_team_id": 10,
+			"amount":       12,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/drafts/1/bids", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.DraftNomination
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, 12.0, response.CurrentBid)
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		mockService.On("PlaceBid", 1, 10, 500.0).Return(nil, draft.ErrBidExceedsMax)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"user_team_id": 10,
+			"amount":       500,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/drafts/1/bids", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestSetQueue(t *testing.T) {
+	router, mockService := setupDraftHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("SetQueue", 1, 10, []int{5, 3}).Return([]*models.DraftQueueEntry{
+			{DraftID: 1, UserTeamID: 10, PlayerID: 5, Rank: 1},
+			{DraftID: 1, UserTeamID: 10, PlayerID: 3, Rank: 2},
+		}, nil)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"player_ids": []int{5, 3},
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/drafts/1/queues/10", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []*models.DraftQueueEntry
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 2)
+		assert.Equal(t, 5, response[0].PlayerID)
+	})
+
+	t.Run("invalid user team id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/drafts/1/queues/invalid", bytes.NewBufferString("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
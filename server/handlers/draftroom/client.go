@@ -0,0 +1,154 @@
+package draftroom
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time allowed to write a message to the client
+	writeWait = 10 * time.Second
+	// Time allowed to read the next pong message from the client
+	pongWait = 60 * time.Second
+	// Send pings to the client with this period, which must be less than pongWait
+	pingPeriod = (pongWait * 9) / 10
+	// Maximum message size allowed from the client
+	maxMessageSize = 4096
+	// Number of outbound messages buffered for a client before it is dropped as too slow
+	sendBufferSize = 64
+	// Maximum length of a chat message
+	maxChatLength = 500
+)
+
+// inboundMessage is a message sent from a client to the server
+type inboundMessage struct {
+	Type     string  `json:"type"` // pick, nominate, bid or chat
+	PlayerID int     `json:"player_id"`
+	Amount   float64 `json:"amount"`
+	Message  string  `json:"message"`
+}
+
+// client is a single websocket connection in a draft room
+type client struct {
+	room       *room
+	conn       *websocket.Conn
+	userTeamID int // zero for spectators
+	outbound   chan *outboundMessage
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// send queues a message for the client without blocking the room.
+// Clients that fall too far behind are disconnected and can resync on reconnect.
+func (c *client) send(message *outboundMessage) {
+	select {
+	case <-c.done:
+	case c.outbound <- message:
+	default:
+		c.close()
+	}
+}
+
+// close shuts the client's connection down once
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// readPump reads actions from the client until the connection closes
+func (c *client) readPump() {
+	defer func() {
+		c.room.leave(c)
+		c.close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		var message inboundMessage
+		if err := c.conn.ReadJSON(&message); err != nil {
+			return
+		}
+		c.handle(&message)
+	}
+}
+
+// writePump writes queued messages and keepalive pings to the client
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case message := <-c.outbound:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handle applies an action sent by the client
+func (c *client) handle(message *inboundMessage) {
+	if c.userTeamID == 0 {
+		c.send(&outboundMessage{Type: "error", Error: "Spectators cannot take part in the draft"})
+		return
+	}
+
+	service := c.room.hub.draftService
+	switch message.Type {
+	case "pick":
+		pick, err := service.MakePick(c.room.draftID, c.userTeamID, message.PlayerID)
+		if err != nil {
+			c.send(&outboundMessage{Type: "error", Error: err.Error()})
+			return
+		}
+		c.room.broadcastPick(pick)
+	case "nominate":
+		if _, err := service.Nominate(c.room.draftID, c.userTeamID, message.PlayerID, message.Amount); err != nil {
+			c.send(&outboundMessage{Type: "error", Error: err.Error()})
+			return
+		}
+		c.room.broadcastState()
+	case "bid":
+		if _, err := service.PlaceBid(c.room.draftID, c.userTeamID, message.Amount); err != nil {
+			c.send(&outboundMessage{Type: "error", Error: err.Error()})
+			return
+		}
+		c.room.broadcastState()
+	case "chat":
+		text := strings.TrimSpace(message.Message)
+		if text == "" || len(text) > maxChatLength {
+			c.send(&outboundMessage{Type: "error", Error: "Chat messages must be between 1 and 500 characters"})
+			return
+		}
+		c.room.addChat(&ChatMessage{
+			UserTeamID: c.userTeamID,
+			Message:    text,
+			SentAt:     time.Now(),
+		})
+	default:
+		c.send(&outboundMessage{Type: "error", Error: "Unknown message type"})
+	}
+}
@@ -0,0 +1,233 @@
+package draftroom
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-app/models"
+	"go-app/services/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/jmoiron/sqlx"
+)
+
+type DraftRoomHandler struct {
+	hub         *Hub
+	userService user.UserService
+	upgrader    websocket.Upgrader
+	tickets     *tickets
+}
+
+// NewDraftRoomHandler creates a new DraftRoomHandler instance. Pages from allowedOrigins, as well
+// as from the API's own host, may open the room.
+func NewDraftRoomHandler(db *sqlx.DB, hub *Hub, allowedOrigins []string) *DraftRoomHandler {
+	return &DraftRoomHandler{
+		hub:         hub,
+		userService: user.NewUserService(db),
+		upgrader:    newUpgrader(allowedOrigins),
+		tickets:     newTickets(),
+	}
+}
+
+// newUpgrader creates a websocket upgrader that refuses pages from other sites, so a site the
+// user visits cannot open the room with their credentials
+func newUpgrader(allowedOrigins []string) websocket.Upgrader {
+	return websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				// Not a browser, so not a page acting on a user's behalf
+				return true
+			}
+			for _, allowed := range allowedOrigins {
+				if strings.EqualFold(origin, allowed) {
+					return true
+				}
+			}
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		},
+	}
+}
+
+// JoinRoom handles GET /api/drafts/:id/room
+// It upgrades the connection to a websocket. Clients joining as a team from the
+// draft order can pick, nominate, bid and chat; everyone else spectates.
+// Browsers join as a team with a ticket from CreateTicket passed as the ticket
+// query parameter. Other clients can instead pass a user_team_id with the
+// credentials of the user who owns it, sent with basic authentication.
+func (h *DraftRoomHandler) JoinRoom(c *gin.Context) {
+	d, ok := h.draftFromParam(c)
+	if !ok {
+		return
+	}
+
+	userTeamID := 0
+	if token := c.Query("ticket"); token != "" {
+		userTeamID, ok = h.tickets.redeem(token, d.ID, time.Now())
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired ticket",
+			})
+			return
+		}
+	} else if param := c.Query("user_team_id"); param != "" {
+		var err error
+		userTeamID, err = strconv.Atoi(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid user team ID",
+			})
+			return
+		}
+		if !h.authorizeTeam(c, d.ID, userTeamID) {
+			return
+		}
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Error upgrading draft room connection: %v", err)
+		return
+	}
+
+	cl := &client{
+		room:       h.hub.room(d.ID),
+		conn:       conn,
+		userTeamID: userTeamID,
+		outbound:   make(chan *outboundMessage, sendBufferSize),
+		done:       make(chan struct{}),
+	}
+	go cl.writePump()
+	cl.room.join(cl)
+	go cl.readPump()
+}
+
+// CreateTicket handles POST /api/drafts/:id/room/tickets
+// It issues a single-use ticket to join the draft room as a team within a minute.
+// The request needs the credentials of the user who owns the team, sent with
+// basic authentication.
+func (h *DraftRoomHandler) CreateTicket(c *gin.Context) {
+	d, ok := h.draftFromParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		UserTeamID int `json:"user_team_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	if !h.authorizeTeam(c, d.ID, req.UserTeamID) {
+		return
+	}
+
+	token, expiresAt, err := h.tickets.issue(d.ID, req.UserTeamID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to issue ticket",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"ticket":     token,
+		"expires_at": expiresAt,
+	})
+}
+
+// draftFromParam loads the draft named by the id path parameter, writing an error response
+// and reporting false if it cannot
+func (h *DraftRoomHandler) draftFromParam(c *gin.Context) (*models.Draft, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid draft ID",
+		})
+		return nil, false
+	}
+
+	d, err := h.hub.draftService.GetDraft(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Draft not found",
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve draft",
+		})
+		return nil, false
+	}
+	return d, true
+}
+
+// authorizeTeam checks the request's basic authentication credentials belong to the user who
+// owns a team in the draft order, writing an error response and reporting false if they do not
+func (h *DraftRoomHandler) authorizeTeam(c *gin.Context, draftID, userTeamID int) bool {
+	email, password, ok := c.Request.BasicAuth()
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required to join as a team",
+		})
+		return false
+	}
+	u, err := h.userService.Authenticate(email, password)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to authenticate",
+		})
+		return false
+	}
+	owns, err := h.userService.OwnsUserTeam(u.ID, userTeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve user team",
+		})
+		return false
+	}
+	if !owns {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "User team belongs to another user",
+		})
+		return false
+	}
+
+	order, err := h.hub.draftService.GetDraftOrder(draftID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve draft order",
+		})
+		return false
+	}
+	for _, slot := range order {
+		if slot.UserTeamID == userTeamID {
+			return true
+		}
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": "User team is not part of this draft",
+	})
+	return false
+}
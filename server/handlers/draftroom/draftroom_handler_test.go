@@ -0,0 +1,389 @@
+package draftroom
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/draft"
+	"go-app/services/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupDraftRoomHandlerTest(t *testing.T) (*httptest.Server, *mocks.MockDraftService, *Hub) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockDraftService)
+	hub := newHub(mockService)
+
+	// The manager owns every team; another user owns none
+	mockUserService := new(mocks.MockUserService)
+	mockUserService.On("Authenticate", "manager@example.com", "secret").Return(&models.User{ID: 1}, nil)
+	mockUserService.On("Authenticate", "other@example.com", "secret").Return(&models.User{ID: 2}, nil)
+	mockUserService.On("Authenticate", mock.Anything, mock.Anything).Return(nil, user.ErrInvalidCredentials)
+	mockUserService.On("OwnsUserTeam", 1, mock.Anything).Return(true, nil)
+	mockUserService.On("OwnsUserTeam", 2, mock.Anything).Return(false, nil)
+
+	handler := &DraftRoomHandler{
+		hub:         hub,
+		userService: mockUserService,
+		upgrader:    newUpgrader([]string{"https://allowed.example.com"}),
+		tickets:     newTickets(),
+	}
+
+	// Setup routes
+	router.GET("/drafts/:id/room", handler.JoinRoom)
+	router.POST("/drafts/:id/room/tickets", handler.CreateTicket)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server, mockService, hub
+}
+
+// mockSnakeDraft sets up a snake draft with the given teams in the mock service
+func mockSnakeDraft(mockService *mocks.MockDraftService, draftID int, userTeamIDs []int) {
+	order := make([]*models.DraftSlot, len(userTeamIDs))
+	for i, id := range userTeamIDs {
+		order[i] = &models.DraftSlot{DraftID: draftID, UserTeamID: id, Position: i + 1}
+	}
+
+	mockService.On("GetDraft", draftID).Return(&models.Draft{
+		ID:           draftID,
+		Type:         models.DraftTypeSnake,
+		Status:       models.DraftStatusInProgress,
+		Rounds:       2,
+		CurrentRound: 1,
+		CurrentPick:  1,
+		UpdatedAt:    time.Now(),
+	}, nil)
+	mockService.On("GetDraftOrder", draftID).Return(order, nil)
+	mockService.On("ListPicks", draftID).Return([]*models.DraftPick{}, nil)
+	mockService.On("GetTeamOnTheClock", draftID).Return(userTeamIDs[0], nil)
+}
+
+// dial connects to a room with the manager's credentials
+func dial(t *testing.T, server *httptest.Server, path string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + path
+	header := http.Header{}
+	header.Set("Authorization", basicAuth("manager@example.com", "secret"))
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// requestTicket asks for a ticket to join a room as a team with the given credentials
+func requestTicket(t *testing.T, server *httptest.Server, draftID, userTeamID int, authorization string) *http.Response {
+	body := strings.NewReader(fmt.Sprintf(`{"user_team_id": %d}`, userTeamID))
+	req, _ := http.NewRequest("POST", fmt.Sprintf("%s/drafts/%d/room/tickets", server.URL, draftID), body)
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func basicAuth(email, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+password))
+}
+
+// readUntil reads messages from conn until one of the given type arrives
+func readUntil(t *testing.T, conn *websocket.Conn, messageType string) *outboundMessage {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var message outboundMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			t.Fatalf("waiting for %q message: %v", messageType, err)
+		}
+		if message.Type == messageType {
+			return &message
+		}
+	}
+}
+
+func TestJoinRoom(t *testing.T) {
+	server, mockService, _ := setupDraftRoomHandlerTest(t)
+	mockSnakeDraft(mockService, 1, []int{10, 11})
+
+	t.Run("receives full state on connect", func(t *testing.T) {
+		conn := dial(t, server, "/drafts/1/room?user_team_id=10")
+
+		message := readUntil(t, conn, "state")
+		assert.Equal(t, 1, message.State.Draft.ID)
+		assert.Len(t, message.State.Order, 2)
+		assert.Equal(t, 10, message.State.OnTheClock)
+		assert.Contains(t, message.State.Connected, 10)
+	})
+
+	t.Run("spectators can join", func(t *testing.T) {
+		conn := dial(t, server, "/drafts/1/room")
+
+		message := readUntil(t, conn, "state")
+		assert.Equal(t, 1, message.State.Draft.ID)
+	})
+
+	t.Run("team not in draft", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", server.URL+"/drafts/1/room?user_team_id=99", nil)
+		req.Header.Set("Authorization", basicAuth("manager@example.com", "secret"))
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("joining as a team needs credentials", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/drafts/1/room?user_team_id=10")
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", server.URL+"/drafts/1/room?user_team_id=10", nil)
+		req.Header.Set("Authorization", basicAuth("manager@example.com", "wrong"))
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("team of another user", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", server.URL+"/drafts/1/room?user_team_id=10", nil)
+		req.Header.Set("Authorization", basicAuth("other@example.com", "secret"))
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("joining with a ticket needs no headers", func(t *testing.T) {
+		resp := requestTicket(t, server, 1, 11, basicAuth("manager@example.com", "secret"))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		var issued struct {
+			Ticket string `json:"ticket"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&issued))
+		assert.NotEmpty(t, issued.Ticket)
+
+		// A browser's websocket sends no Authorization header
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/drafts/1/room?ticket=" + issued.Ticket
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer conn.Close()
+		message := readUntil(t, conn, "state")
+		assert.Contains(t, message.State.Connected, 11)
+
+		// A ticket can only be used once
+		_, resp, err = websocket.DefaultDialer.Dial(url, nil)
+		assert.Error(t, err)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		}
+	})
+
+	t.Run("tickets need credentials", func(t *testing.T) {
+		resp := requestTicket(t, server, 1, 10, "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = requestTicket(t, server, 1, 10, basicAuth("other@example.com", "secret"))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = requestTicket(t, server, 1, 99, basicAuth("manager@example.com", "secret"))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("tickets only open the room of their draft", func(t *testing.T) {
+		mockSnakeDraft(mockService, 2, []int{20, 21})
+		resp := requestTicket(t, server, 2, 20, basicAuth("manager@example.com", "secret"))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		var issued struct {
+			Ticket string `json:"ticket"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&issued))
+
+		joined, err := http.Get(server.URL + "/drafts/1/room?ticket=" + issued.Ticket)
+		assert.NoError(t, err)
+		joined.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, joined.StatusCode)
+	})
+
+	t.Run("pages from other sites are refused", func(t *testing.T) {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/drafts/1/room"
+		header := http.Header{}
+		header.Set("Origin", "https://evil.example.com")
+		_, resp, err := websocket.DefaultDialer.Dial(url, header)
+		assert.Error(t, err)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		}
+
+		header.Set("Origin", "https://allowed.example.com")
+		conn, _, err := websocket.DefaultDialer.Dial(url, header)
+		if assert.NoError(t, err) {
+			conn.Close()
+		}
+	})
+
+	t.Run("invalid draft id", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/drafts/invalid/room")
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestDraftRoomBroadcasts(t *testing.T) {
+	server, mockService, _ := setupDraftRoomHandlerTest(t)
+	mockSnakeDraft(mockService, 1, []int{10, 11})
+
+	first := dial(t, server, "/drafts/1/room?user_team_id=10")
+	readUntil(t, first, "state")
+	second := dial(t, server, "/drafts/1/room?user_team_id=11")
+	readUntil(t, second, "state")
+	spectator := dial(t, server, "/drafts/1/room")
+	readUntil(t, spectator, "state")
+
+	t.Run("pick is broadcast to everyone", func(t *testing.T) {
+		mockService.On("MakePick", 1, 10, 7).Return(&models.DraftPick{
+			DraftID:     1,
+			UserTeamID:  10,
+			PlayerID:    7,
+			Round:       1,
+			Pick:        1,
+			OverallPick: 1,
+		}, nil).Once()
+
+		assert.NoError(t, first.WriteJSON(map[string]interface{}{"type": "pick", "player_id": 7}))
+
+		for _, conn := range []*websocket.Conn{first, second, spectator} {
+			message := readUntil(t, conn, "pick")
+			assert.Equal(t, 7, message.Pick.PlayerID)
+			readUntil(t, conn, "state")
+		}
+	})
+
+	t.Run("rejected pick only goes back to the sender", func(t *testing.T) {
+		mockService.On("MakePick", 1, 11, 8).Return(nil, draft.ErrNotYourTurn).Once()
+
+		assert.NoError(t, second.WriteJSON(map[string]interface{}{"type": "pick", "player_id": 8}))
+
+		message := readUntil(t, second, "error")
+		assert.Equal(t, draft.ErrNotYourTurn.Error(), message.Error)
+	})
+
+	t.Run("chat is relayed", func(t *testing.T) {
+		assert.NoError(t, second.WriteJSON(map[string]interface{}{"type": "chat", "message": "good pick"}))
+
+		for _, conn := range []*websocket.Conn{first, second, spectator} {
+			message := readUntil(t, conn, "chat")
+			assert.Equal(t, 11, message.Chat.UserTeamID)
+			assert.Equal(t, "good pick", message.Chat.Message)
+		}
+	})
+
+	t.Run("spectators cannot pick", func(t *testing.T) {
+		assert.NoError(t, spectator.WriteJSON(map[string]interface{}{"type": "pick", "player_id": 9}))
+
+		readUntil(t, spectator, "error")
+		mockService.AssertNotCalled(t, "MakePick", 1, 0, 9)
+	})
+
+	t.Run("reconnecting client gets state and chat history", func(t *testing.T) {
+		first.Close()
+
+		conn := dial(t, server, "/drafts/1/room?user_team_id=10")
+		message := readUntil(t, conn, "state")
+		if assert.Len(t, message.State.Chat, 1) {
+			assert.Equal(t, "good pick", message.State.Chat[0].Message)
+		}
+	})
+}
+
+func TestDraftRoomIsolation(t *testing.T) {
+	server, mockService, hub := setupDraftRoomHandlerTest(t)
+	mockSnakeDraft(mockService, 1, []int{10, 11})
+	mockSnakeDraft(mockService, 2, []int{20, 21})
+
+	inFirst := dial(t, server, "/drafts/1/room?user_team_id=10")
+	readUntil(t, inFirst, "state")
+	inSecond := dial(t, server, "/drafts/2/room?user_team_id=20")
+	readUntil(t, inSecond, "state")
+
+	assert.NoError(t, inFirst.WriteJSON(map[string]interface{}{"type": "chat", "message": "hello"}))
+	readUntil(t, inFirst, "chat")
+
+	// A refresh of the second draft is the next thing its room should see
+	hub.Refresh(2)
+	var message outboundMessage
+	inSecond.SetReadDeadline(time.Now().Add(2 * time.Second))
+	assert.NoError(t, inSecond.ReadJSON(&message))
+	assert.Equal(t, "state", message.Type)
+	assert.Equal(t, 2, message.State.Draft.ID)
+	assert.Empty(t, message.State.Chat)
+
+	mockService.AssertNotCalled(t, "MakePick", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunClock(t *testing.T) {
+	server, mockService, hub := setupDraftRoomHandlerTest(t)
+	mockSnakeDraft(mockService, 1, []int{10, 11})
+	mockService.On("ProcessExpiredClocks").Return([]int{1}, nil)
+
+	conn := dial(t, server, "/drafts/1/room?user_team_id=11")
+	readUntil(t, conn, "state")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.RunClock(ctx, 10*time.Millisecond)
+
+	// An autopick is broadcast as a new state
+	message := readUntil(t, conn, "state")
+	assert.Equal(t, 1, message.State.Draft.ID)
+}
+
+func TestTickets(t *testing.T) {
+	tickets := newTickets()
+	now := time.Now()
+
+	token, expiresAt, err := tickets.issue(1, 10, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(ticketTTL), expiresAt)
+
+	// Expired tickets are refused
+	_, ok := tickets.redeem(token, 1, expiresAt)
+	assert.False(t, ok)
+
+	token, _, err = tickets.issue(1, 10, now)
+	assert.NoError(t, err)
+	userTeamID, ok := tickets.redeem(token, 1, now.Add(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 10, userTeamID)
+}
@@ -0,0 +1,270 @@
+package draftroom
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go-app/models"
+	"go-app/services/draft"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// chatHistorySize is the number of chat messages a room keeps for reconnecting clients
+const chatHistorySize = 50
+
+// ChatMessage represents a chat message sent in a draft room
+type ChatMessage struct {
+	UserTeamID int       `json:"user_team_id"`
+	Message    string    `json:"message"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// Clock describes the timing of the current turn
+type Clock struct {
+	TurnStartedAt time.Time  `json:"turn_started_at"`
+	Deadline      *time.Time `json:"deadline,omitempty"`
+}
+
+// DraftState is the full snapshot of a draft sent to clients
+type DraftState struct {
+	Draft      *models.Draft           `json:"draft"`
+	Order      []*models.DraftSlot     `json:"order"`
+	Picks      []*models.DraftPick     `json:"picks"`
+	OnTheClock int                     `json:"on_the_clock,omitempty"`
+	Clock      Clock                   `json:"clock"`
+	Nomination *models.DraftNomination `json:"nomination,omitempty"`
+	Budgets    []*draft.TeamBudget     `json:"budgets,omitempty"`
+	Chat       []*ChatMessage          `json:"chat"`
+	Connected  []int                   `json:"connected"`
+}
+
+// outboundMessage is a message sent from the server to a client
+type outboundMessage struct {
+	Type  string            `json:"type"`
+	State *DraftState       `json:"state,omitempty"`
+	Pick  *models.DraftPick `json:"pick,omitempty"`
+	Chat  *ChatMessage      `json:"chat,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+// Hub keeps track of the live draft rooms in this server process
+type Hub struct {
+	draftService draft.DraftService
+	mu           sync.Mutex
+	rooms        map[int]*room
+}
+
+// NewHub creates a new Hub instance
+func NewHub(db *sqlx.DB) *Hub {
+	return newHub(draft.NewDraftService(db))
+}
+
+func newHub(draftService draft.DraftService) *Hub {
+	return &Hub{
+		draftService: draftService,
+		rooms:        make(map[int]*room),
+	}
+}
+
+// room returns the room for a draft, creating it on first use
+func (h *Hub) room(draftID int) *room {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[draftID]
+	if !ok {
+		r = &room{
+			draftID: draftID,
+			hub:     h,
+			clients: make(map[*client]bool),
+		}
+		h.rooms[draftID] = r
+	}
+	return r
+}
+
+// Refresh broadcasts the latest state of a draft to everyone in its room.
+// It is called whenever the draft changes outside of the room.
+func (h *Hub) Refresh(draftID int) {
+	h.mu.Lock()
+	r, ok := h.rooms[draftID]
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+	r.broadcastState()
+}
+
+// RunClock checks for expired pick and bid clocks every interval until ctx is done,
+// broadcasting the new state of any draft that moves on as a result
+func (h *Hub) RunClock(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			draftIDs, err := h.draftService.ProcessExpiredClocks()
+			if err != nil {
+				log.Printf("Error processing draft clocks: %v", err)
+			}
+			for _, draftID := range draftIDs {
+				h.Refresh(draftID)
+			}
+		}
+	}
+}
+
+// room is the set of clients following a single draft
+type room struct {
+	draftID int
+	hub     *Hub
+
+	// mu guards clients and chat, and serialises broadcasts so every
+	// client sees state updates in the same order
+	mu      sync.Mutex
+	clients map[*client]bool
+	chat    []*ChatMessage
+}
+
+// join adds a client to the room and sends it the full draft state
+func (r *room) join(c *client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clients[c] = true
+	state, err := r.buildState()
+	if err != nil {
+		log.Printf("Error building state for draft %d: %v", r.draftID, err)
+		c.send(&outboundMessage{Type: "error", Error: "Failed to load draft state"})
+		return
+	}
+	r.broadcastLocked(&outboundMessage{Type: "state", State: state})
+}
+
+// leave removes a client from the room, dropping the room once a finished draft has no one left in it
+func (r *room) leave(c *client) {
+	r.mu.Lock()
+	delete(r.clients, c)
+	empty := len(r.clients) == 0
+	r.mu.Unlock()
+
+	if empty {
+		d, err := r.hub.draftService.GetDraft(r.draftID)
+		if err == nil && d.Status == models.DraftStatusCompleted {
+			r.hub.mu.Lock()
+			delete(r.hub.rooms, r.draftID)
+			r.hub.mu.Unlock()
+		}
+		return
+	}
+	r.broadcastState()
+}
+
+// addChat records a chat message and relays it to the room
+func (r *room) addChat(message *ChatMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.chat = append(r.chat, message)
+	if len(r.chat) > chatHistorySize {
+		r.chat = r.chat[len(r.chat)-chatHistorySize:]
+	}
+	r.broadcastLocked(&outboundMessage{Type: "chat", Chat: message})
+}
+
+// broadcastPick announces a pick and the resulting state to the room
+func (r *room) broadcastPick(pick *models.DraftPick) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.broadcastLocked(&outboundMessage{Type: "pick", Pick: pick})
+	r.broadcastStateLocked()
+}
+
+// broadcastState sends the latest draft state to the room
+func (r *room) broadcastState() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.broadcastStateLocked()
+}
+
+func (r *room) broadcastStateLocked() {
+	state, err := r.buildState()
+	if err != nil {
+		log.Printf("Error building state for draft %d: %v", r.draftID, err)
+		return
+	}
+	r.broadcastLocked(&outboundMessage{Type: "state", State: state})
+}
+
+func (r *room) broadcastLocked(message *outboundMessage) {
+	for c := range r.clients {
+		c.send(message)
+	}
+}
+
+// buildState loads the current draft state. The caller must hold r.mu.
+func (r *room) buildState() (*DraftState, error) {
+	service := r.hub.draftService
+
+	d, err := service.GetDraft(r.draftID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := service.GetDraftOrder(r.draftID)
+	if err != nil {
+		return nil, err
+	}
+	picks, err := service.ListPicks(r.draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &DraftState{
+		Draft: d,
+		Order: order,
+		Picks: picks,
+		Clock: Clock{TurnStartedAt: d.UpdatedAt, Deadline: d.PickDeadline},
+		Chat:  append([]*ChatMessage{}, r.chat...),
+	}
+
+	if d.Status == models.DraftStatusInProgress {
+		state.OnTheClock, err = service.GetTeamOnTheClock(r.draftID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if d.Type == models.DraftTypeAuction {
+		state.Nomination, err = service.GetOpenNomination(r.draftID)
+		if err != nil {
+			return nil, err
+		}
+		if state.Nomination != nil {
+			state.Clock.TurnStartedAt = state.Nomination.CreatedAt
+			state.Clock.Deadline = &state.Nomination.BidDeadline
+		}
+		state.Budgets, err = service.GetAuctionBudgets(r.draftID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[int]bool)
+	state.Connected = []int{}
+	for c := range r.clients {
+		if c.userTeamID != 0 && !seen[c.userTeamID] {
+			seen[c.userTeamID] = true
+			state.Connected = append(state.Connected, c.userTeamID)
+		}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,68 @@
+package draftroom
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// ticketTTL is how long a ticket can be used to join a room after it is issued
+const ticketTTL = time.Minute
+
+// ticket lets whoever holds it join a draft room as a team once. Browsers cannot send
+// credentials when opening a websocket, so they ask for a ticket first and pass it in the URL.
+type ticket struct {
+	draftID    int
+	userTeamID int
+	expiresAt  time.Time
+}
+
+// tickets keeps the tickets issued by this server process that are yet to be used
+type tickets struct {
+	mu      sync.Mutex
+	byToken map[string]*ticket
+}
+
+func newTickets() *tickets {
+	return &tickets{byToken: make(map[string]*ticket)}
+}
+
+// issue creates a ticket for a team to join a draft's room and returns its token
+func (t *tickets) issue(draftID, userTeamID int, now time.Time) (string, time.Time, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(b)
+	expiresAt := now.Add(ticketTTL)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Drop the tickets nobody used
+	for key, issued := range t.byToken {
+		if !now.Before(issued.expiresAt) {
+			delete(t.byToken, key)
+		}
+	}
+	t.byToken[token] = &ticket{draftID: draftID, userTeamID: userTeamID, expiresAt: expiresAt}
+	return token, expiresAt, nil
+}
+
+// redeem uses up a ticket and returns the team it was issued to. It reports false if the
+// token is unknown, already used, expired or issued for another draft.
+func (t *tickets) redeem(token string, draftID int, now time.Time) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	issued, ok := t.byToken[token]
+	if !ok {
+		return 0, false
+	}
+	delete(t.byToken, token)
+	if issued.draftID != draftID || !now.Before(issued.expiresAt) {
+		return 0, false
+	}
+	return issued.userTeamID, true
+}
@@ -0,0 +1,151 @@
+package gameweek
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/models"
+	"go-app/services/gameweek"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type GameweekHandler struct {
+	gameweekService gameweek.GameweekService
+}
+
+// NewGameweekHandler creates a new GameweekHandler instance
+func NewGameweekHandler(db *sqlx.DB) *GameweekHandler {
+	return &GameweekHandler{
+		gameweekService: gameweek.NewGameweekService(db),
+	}
+}
+
+// ListGameweeks handles GET /api/gameweeks
+func (h *GameweekHandler) ListGameweeks(c *gin.Context) {
+	gameweeks, err := h.gameweekService.ListGameweeks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve gameweeks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gameweeks)
+}
+
+// GetGameweek handles GET /api/gameweeks/:id
+func (h *GameweekHandler) GetGameweek(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gameweek ID",
+		})
+		return
+	}
+
+	gameweek, err := h.gameweekService.GetGameweek(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve gameweek",
+		})
+		return
+	}
+	if gameweek == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Gameweek not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gameweek)
+}
+
+// GetNextGameweek handles GET /api/gameweeks/next
+func (h *GameweekHandler) GetNextGameweek(c *gin.Context) {
+	gameweek, err := h.gameweekService.GetNextGameweek()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve gameweek",
+		})
+		return
+	}
+	if gameweek == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No upcoming gameweek",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gameweek)
+}
+
+// GetCurrentGameweek handles GET /api/gameweeks/current
+func (h *GameweekHandler) GetCurrentGameweek(c *gin.Context) {
+	gameweek, err := h.gameweekService.GetCurrentGameweek()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve gameweek",
+		})
+		return
+	}
+	if gameweek == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "The season has not started",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gameweek)
+}
+
+// ListMatches handles GET /api/gameweeks/:id/matches
+func (h *GameweekHandler) ListMatches(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gameweek ID",
+		})
+		return
+	}
+
+	matches, err := h.gameweekService.ListMatches(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Gameweek not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve matches",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, matches)
+}
+
+// CreateGameweek handles POST /api/gameweeks
+func (h *GameweekHandler) CreateGameweek(c *gin.Context) {
+	var gameweek models.Gameweek
+	if err := c.ShouldBindJSON(&gameweek); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	createdGameweek, err := h.gameweekService.CreateGameweek(&gameweek)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdGameweek)
+}
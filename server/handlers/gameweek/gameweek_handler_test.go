@@ -0,0 +1,153 @@
+package gameweek
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGameweekHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockGameweekService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockGameweekService)
+	handler := &GameweekHandler{
+		gameweekService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/gameweeks", handler.ListGameweeks)
+	router.GET("/gameweeks/next", handler.GetNextGameweek)
+	router.GET("/gameweeks/current", handler.GetCurrentGameweek)
+	router.GET("/gameweeks/:id", handler.GetGameweek)
+	router.GET("/gameweeks/:id/matches", handler.ListMatches)
+	router.POST("/gameweeks", handler.CreateGameweek)
+
+	return router, mockService
+}
+
+func TestGetGameweek(t *testing.T) {
+	router, mockService := setupGameweekHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("GetGameweek", 1).Return(&models.Gameweek{ID: 1, Number: 1, Deadline: time.Now()}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gameweeks/1", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService.On("GetGameweek", 2).Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gameweeks/2", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("next", func(t *testing.T) {
+		mockService.On("GetNextGameweek").Return(&models.Gameweek{ID: 3, Number: 3, Deadline: time.Now().Add(time.Hour)}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gameweeks/next", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.Gameweek
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, response.Number)
+	})
+}
+
+func TestGetCurrentGameweek(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		router, mockService := setupGameweekHandlerTest(t)
+		mockService.On("GetCurrentGameweek").Return(&models.Gameweek{
+			ID: 2, Number: 2, Deadline: time.Now().Add(-time.Hour), State: models.GameweekStateLive,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gameweeks/current", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.Gameweek
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, response.Number)
+		assert.Equal(t, models.GameweekStateLive, response.State)
+	})
+
+	t.Run("season not started", func(t *testing.T) {
+		router, mockService := setupGameweekHandlerTest(t)
+		mockService.On("GetCurrentGameweek").Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gameweeks/current", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestListGameweekMatches(t *testing.T) {
+	router, mockService := setupGameweekHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		gameweekID := 1
+		mockService.On("ListMatches", 1).Return([]*models.Match{
+			{ID: 10, GameweekID: &gameweekID, HomeTeamID: 1, AwayTeamID: 2, Status: models.MatchStatusScheduled},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gameweeks/1/matches", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []*models.Match
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("gameweek not found", func(t *testing.T) {
+		mockService.On("ListMatches", 2).Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gameweeks/2/matches", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCreateGameweek(t *testing.T) {
+	router, mockService := setupGameweekHandlerTest(t)
+
+	deadline := time.Date(2024, 8, 16, 17, 30, 0, 0, time.UTC)
+	mockService.On("CreateGameweek", mock.Anything).Return(&models.Gameweek{ID: 1, Number: 1, Deadline: deadline}, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{"number": 1, "deadline": deadline})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/gameweeks", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
@@ -0,0 +1,192 @@
+package incident
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/models"
+	"go-app/services/incident"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type IncidentHandler struct {
+	incidentService incident.IncidentService
+}
+
+// NewIncidentHandler creates a new IncidentHandler instance
+func NewIncidentHandler(db *sqlx.DB) *IncidentHandler {
+	return &IncidentHandler{
+		incidentService: incident.NewIncidentService(db),
+	}
+}
+
+// incidentRequest is the body of a request to add or change an incident
+type incidentRequest struct {
+	PlayerID    int                 `json:"player_id" binding:"required"`
+	Type        models.IncidentType `json:"type" binding:"required"`
+	Minute      int                 `json:"minute"`
+	Description string              `json:"description"`
+}
+
+// ListIncidents handles GET /api/matches/:id/incidents
+func (h *IncidentHandler) ListIncidents(c *gin.Context) {
+	matchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid match ID",
+		})
+		return
+	}
+
+	incidents, err := h.incidentService.ListIncidents(matchID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve incidents")
+		return
+	}
+
+	c.JSON(http.StatusOK, incidents)
+}
+
+// AddIncident handles POST /api/matches/:id/incidents
+func (h *IncidentHandler) AddIncident(c *gin.Context) {
+	matchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid match ID",
+		})
+		return
+	}
+
+	var req incidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	added, err := h.incidentService.AddIncident(&models.MatchIncident{
+		MatchID:     matchID,
+		PlayerID:    req.PlayerID,
+		Type:        req.Type,
+		Minute:      req.Minute,
+		Description: req.Description,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to add incident")
+		return
+	}
+
+	c.JSON(http.StatusCreated, added)
+}
+
+// UpdateIncident handles PUT /api/matches/:id/incidents/:incident_id
+func (h *IncidentHandler) UpdateIncident(c *gin.Context) {
+	matchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid match ID",
+		})
+		return
+	}
+	incidentID, err := strconv.Atoi(c.Param("incident_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid incident ID",
+		})
+		return
+	}
+
+	var req incidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	updated, err := h.incidentService.UpdateIncident(&models.MatchIncident{
+		ID:          incidentID,
+		MatchID:     matchID,
+		PlayerID:    req.PlayerID,
+		Type:        req.Type,
+		Minute:      req.Minute,
+		Description: req.Description,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to update incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteIncident handles DELETE /api/matches/:id/incidents/:incident_id
+func (h *IncidentHandler) DeleteIncident(c *gin.Context) {
+	matchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid match ID",
+		})
+		return
+	}
+	incidentID, err := strconv.Atoi(c.Param("incident_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid incident ID",
+		})
+		return
+	}
+
+	if err := h.incidentService.DeleteIncident(matchID, incidentID); err != nil {
+		respondError(c, err, "Failed to delete incident")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListCorrections handles GET /api/leagues/:id/stat-corrections
+func (h *IncidentHandler) ListCorrections(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	corrections, err := h.incidentService.ListCorrections(leagueID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve stat corrections")
+		return
+	}
+
+	c.JSON(http.StatusOK, corrections)
+}
+
+// respondError maps an incident service error to a response
+func respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Match not found",
+		})
+	case errors.Is(err, incident.ErrIncidentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, incident.ErrInvalidIncident):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
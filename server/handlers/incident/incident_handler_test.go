@@ -0,0 +1,182 @@
+package incident
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/incident"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupIncidentHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockIncidentService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockIncidentService)
+	handler := &IncidentHandler{
+		incidentService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/matches/:id/incidents", handler.ListIncidents)
+	router.POST("/matches/:id/incidents", handler.AddIncident)
+	router.PUT("/matches/:id/incidents/:incident_id", handler.UpdateIncident)
+	router.DELETE("/matches/:id/incidents/:incident_id", handler.DeleteIncident)
+	router.GET("/leagues/:id/stat-corrections", handler.ListCorrections)
+
+	return router, mockService
+}
+
+func TestListIncidents(t *testing.T) {
+	router, mockService := setupIncidentHandlerTest(t)
+
+	mockService.On("ListIncidents", 1).Return([]*models.MatchIncident{
+		{ID: 4, MatchID: 1, PlayerID: 5, Type: models.IncidentTypeGoal, Minute: 12},
+	}, nil)
+	mockService.On("ListIncidents", 99).Return(nil, sql.ErrNoRows)
+
+	for path, code := range map[string]int{
+		"/matches/1/incidents":  http.StatusOK,
+		"/matches/99/incidents": http.StatusNotFound,
+		"/matches/x/incidents":  http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestAddIncident(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		router, mockService := setupIncidentHandlerTest(t)
+		mockService.On("AddIncident", &models.MatchIncident{MatchID: 1, PlayerID: 5, Type: models.IncidentTypeAssist, Minute: 30}).
+			Return(&models.MatchIncident{ID: 9, MatchID: 1, PlayerID: 5, Type: models.IncidentTypeAssist, Minute: 30}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/matches/1/incidents", strings.NewReader(`{"player_id": 5, "type": "assist", "minute": 30}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.MatchIncident
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, response.ID)
+	})
+
+	for name, tc := range map[string]struct {
+		err  error
+		code int
+	}{
+		"match not found":  {sql.ErrNoRows, http.StatusNotFound},
+		"invalid incident": {fmt.Errorf("%w: unknown type %q", incident.ErrInvalidIncident, "hat_trick"), http.StatusBadRequest},
+		"database error":   {errors.New("database error"), http.StatusInternalServerError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			router, mockService := setupIncidentHandlerTest(t)
+			mockService.On("AddIncident", mock.Anything).Return(nil, tc.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/matches/1/incidents", strings.NewReader(`{"player_id": 5, "type": "hat_trick"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+
+	t.Run("missing player", func(t *testing.T) {
+		router, _ := setupIncidentHandlerTest(t)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/matches/1/incidents", strings.NewReader(`{"type": "goal"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateIncident(t *testing.T) {
+	router, mockService := setupIncidentHandlerTest(t)
+
+	mockService.On("UpdateIncident", &models.MatchIncident{ID: 4, MatchID: 1, PlayerID: 6, Type: models.IncidentTypeGoal, Minute: 12}).
+		Return(&models.MatchIncident{ID: 4, MatchID: 1, PlayerID: 6, Type: models.IncidentTypeGoal, Minute: 12}, nil)
+	mockService.On("UpdateIncident", &models.MatchIncident{ID: 8, MatchID: 1, PlayerID: 6, Type: models.IncidentTypeGoal, Minute: 12}).
+		Return(nil, fmt.Errorf("%w: incident 8 in match 1", incident.ErrIncidentNotFound))
+
+	for path, code := range map[string]int{
+		"/matches/1/incidents/4": http.StatusOK,
+		"/matches/1/incidents/8": http.StatusNotFound,
+		"/matches/1/incidents/x": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", path, strings.NewReader(`{"player_id": 6, "type": "goal", "minute": 12}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestDeleteIncident(t *testing.T) {
+	router, mockService := setupIncidentHandlerTest(t)
+
+	mockService.On("DeleteIncident", 1, 4).Return(nil)
+	mockService.On("DeleteIncident", 1, 8).Return(fmt.Errorf("%w: incident 8 in match 1", incident.ErrIncidentNotFound))
+
+	for path, code := range map[string]int{
+		"/matches/1/incidents/4": http.StatusNoContent,
+		"/matches/1/incidents/8": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestListCorrections(t *testing.T) {
+	router, mockService := setupIncidentHandlerTest(t)
+
+	oldPlayer, newPlayer := 5, 6
+	mockService.On("ListCorrections", 3).Return([]*models.StatCorrection{{
+		ID:          1,
+		MatchID:     1,
+		GameweekID:  2,
+		IncidentID:  4,
+		Action:      models.CorrectionActionChanged,
+		OldPlayerID: &oldPlayer,
+		NewPlayerID: &newPlayer,
+		PointChanges: []*models.PointChange{
+			{CorrectionID: 1, LeagueID: 3, PlayerID: 5, OldPoints: 9, NewPoints: 2},
+			{CorrectionID: 1, LeagueID: 3, PlayerID: 6, OldPoints: 2, NewPoints: 9},
+		},
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/leagues/3/stat-corrections", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.StatCorrection
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response, 1) {
+		assert.Len(t, response[0].PointChanges, 2)
+	}
+}
@@ -158,3 +158,63 @@ func (h *LeagueHandler) GetLeagueByCode(c *gin.Context) {
 
 	c.JSON(http.StatusOK, league)
 }
+
+// GetSettings handles GET /api/leagues/:id/settings
+func (h *LeagueHandler) GetSettings(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	settings, err := h.leagueService.GetSettings(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve league settings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/leagues/:id/settings
+// A request without pick_time_seconds gets the default pick clock; zero turns it off.
+func (h *LeagueHandler) UpdateSettings(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	var req struct {
+		models.LeagueSettings
+		PickTimeSeconds *int `json:"pick_time_seconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	settings := req.LeagueSettings
+	settings.LeagueID = id
+	settings.PickTimeSeconds = league.DefaultPickTimeSeconds
+	if req.PickTimeSeconds != nil {
+		settings.PickTimeSeconds = *req.PickTimeSeconds
+	}
+	updatedSettings, err := h.leagueService.UpdateSettings(&settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedSettings)
+}
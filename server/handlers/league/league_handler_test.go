@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"go-app/models"
 	"go-app/server/handlers/mocks"
+	"go-app/services/league"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func setupLeagueHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockLeagueService) {
@@ -31,6 +35,8 @@ func setupLeagueHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockLeagueService
 	router.PUT("/leagues/:id", handler.UpdateLeague)
 	router.DELETE("/leagues/:id", handler.DeleteLeague)
 	router.GET("/leagues/code/:code", handler.GetLeagueByCode)
+	router.GET("/leagues/:id/settings", handler.GetSettings)
+	router.PUT("/leagues/:id/settings", handler.UpdateSettings)
 
 	return router, mockLeagueService
 }
@@ -301,3 +307,66 @@ func TestGetLeagueByCode(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 }
+
+func TestUpdateSettings(t *testing.T) {
+	router, mockLeagueService := setupLeagueHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		defer clearMockExpectations(mockLeagueService)
+		settings := &models.LeagueSettings{
+			LeagueID:        1,
+			PickTimeSeconds: 60,
+			PauseStart:      "23:00",
+			PauseEnd:        "08:00",
+			Timezone:        "UTC",
+		}
+
+		mockLeagueService.On("UpdateSettings", settings).Return(settings, nil)
+
+		body, _ := json.Marshal(settings)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/leagues/1/settings", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.LeagueSettings
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, 60, response.PickTimeSeconds)
+		assert.Equal(t, "23:00", response.PauseStart)
+	})
+
+	t.Run("pick clock", func(t *testing.T) {
+		defer clearMockExpectations(mockLeagueService)
+		mockLeagueService.On("UpdateSettings", mock.Anything).Return(&models.LeagueSettings{LeagueID: 1}, nil)
+
+		// Leaving the pick time out keeps the default clock, and zero turns it off
+		for body, pickTime := range map[string]int{
+			`{"timezone": "UTC"}`:                         league.DefaultPickTimeSeconds,
+			`{"timezone": "UTC", "pick_time_seconds": 0}`: 0,
+		} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/leagues/1/settings", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockLeagueService.AssertCalled(t, "UpdateSettings", mock.MatchedBy(func(settings *models.LeagueSettings) bool {
+				return settings.PickTimeSeconds == pickTime && settings.Timezone == "UTC"
+			}))
+		}
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		defer clearMockExpectations(mockLeagueService)
+		mockLeagueService.On("UpdateSettings", mock.Anything).Return(nil, errors.New("pick time cannot be negative"))
+
+		body, _ := json.Marshal(map[string]interface{}{"pick_time_seconds": -1})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/leagues/1/settings", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,218 @@
+package lineup
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/models"
+	"go-app/services/lineup"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type LineupHandler struct {
+	lineupService lineup.LineupService
+}
+
+// NewLineupHandler creates a new LineupHandler instance
+func NewLineupHandler(db *sqlx.DB) *LineupHandler {
+	return &LineupHandler{
+		lineupService: lineup.NewLineupService(db),
+	}
+}
+
+// setLineupRequest is the request body for picking a lineup
+type setLineupRequest struct {
+	Starters      []int `json:"starters" binding:"required"`
+	Bench         []int `json:"bench"`
+	CaptainID     int   `json:"captain_id"`
+	ViceCaptainID int   `json:"vice_captain_id"`
+}
+
+// playChipRequest is the request body for playing a chip
+type playChipRequest struct {
+	GameweekID int             `json:"gameweek_id" binding:"required"`
+	Chip       models.ChipType `json:"chip" binding:"required"`
+}
+
+// parseIDs reads the user team and gameweek IDs from the path
+func parseIDs(c *gin.Context) (int, int, bool) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return 0, 0, false
+	}
+
+	gameweekID, err := strconv.Atoi(c.Param("gameweek_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gameweek ID",
+		})
+		return 0, 0, false
+	}
+
+	return userTeamID, gameweekID, true
+}
+
+// GetLineup handles GET /api/user-teams/:id/lineups/:gameweek_id
+func (h *LineupHandler) GetLineup(c *gin.Context) {
+	userTeamID, gameweekID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	lineup, err := h.lineupService.GetLineup(userTeamID, gameweekID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve lineup")
+		return
+	}
+
+	c.JSON(http.StatusOK, lineup)
+}
+
+// SetLineup handles PUT /api/user-teams/:id/lineups/:gameweek_id
+func (h *LineupHandler) SetLineup(c *gin.Context) {
+	userTeamID, gameweekID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req setLineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	lineup, err := h.lineupService.SetLineup(&models.Lineup{
+		UserTeamID:    userTeamID,
+		GameweekID:    gameweekID,
+		Starters:      req.Starters,
+		Bench:         req.Bench,
+		CaptainID:     req.CaptainID,
+		ViceCaptainID: req.ViceCaptainID,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to save lineup")
+		return
+	}
+
+	c.JSON(http.StatusOK, lineup)
+}
+
+// ProcessAutoSubs handles POST /api/gameweeks/:id/autosubs
+func (h *LineupHandler) ProcessAutoSubs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gameweek ID",
+		})
+		return
+	}
+
+	substitutions, err := h.lineupService.ProcessAutoSubs(id)
+	if err != nil {
+		respondError(c, err, "Failed to make automatic substitutions")
+		return
+	}
+
+	c.JSON(http.StatusOK, substitutions)
+}
+
+// ListChips handles GET /api/user-teams/:id/chips
+func (h *LineupHandler) ListChips(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	chips, err := h.lineupService.ListChips(userTeamID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve chips")
+		return
+	}
+
+	c.JSON(http.StatusOK, chips)
+}
+
+// PlayChip handles POST /api/user-teams/:id/chips
+func (h *LineupHandler) PlayChip(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req playChipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	chip, err := h.lineupService.PlayChip(userTeamID, req.GameweekID, req.Chip)
+	if err != nil {
+		respondError(c, err, "Failed to play chip")
+		return
+	}
+
+	c.JSON(http.StatusCreated, chip)
+}
+
+// CancelChip handles DELETE /api/user-teams/:id/chips/:gameweek_id
+func (h *LineupHandler) CancelChip(c *gin.Context) {
+	userTeamID, gameweekID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.lineupService.CancelChip(userTeamID, gameweekID); err != nil {
+		respondError(c, err, "Failed to cancel chip")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondError maps a lineup service error to a response
+func respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, lineup.ErrGameweekNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Gameweek not found",
+		})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User team not found",
+		})
+	case errors.Is(err, lineup.ErrNoChip):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, lineup.ErrLineupLocked), errors.Is(err, lineup.ErrGameweekNotFinished),
+		errors.Is(err, lineup.ErrChipUsed), errors.Is(err, lineup.ErrGameweekHasChip):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, lineup.ErrInvalidLineup), errors.Is(err, lineup.ErrInvalidChip):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package lineup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/lineup"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupLineupHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockLineupService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockLineupService)
+	handler := &LineupHandler{
+		lineupService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/user-teams/:id/lineups/:gameweek_id", handler.GetLineup)
+	router.PUT("/user-teams/:id/lineups/:gameweek_id", handler.SetLineup)
+	router.POST("/gameweeks/:id/autosubs", handler.ProcessAutoSubs)
+	router.GET("/user-teams/:id/chips", handler.ListChips)
+	router.POST("/user-teams/:id/chips", handler.PlayChip)
+	router.DELETE("/user-teams/:id/chips/:gameweek_id", handler.CancelChip)
+
+	return router, mockService
+}
+
+func TestGetLineup(t *testing.T) {
+	router, mockService := setupLineupHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("GetLineup", 1, 2).Return(&models.Lineup{
+			UserTeamID:  1,
+			GameweekID:  2,
+			Starters:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			Bench:       []int{12, 13},
+			CarriedOver: true,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/1/lineups/2", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.Lineup
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Starters, 11)
+		assert.Equal(t, []int{12, 13}, response.Bench)
+		assert.True(t, response.CarriedOver)
+	})
+
+	t.Run("gameweek not found", func(t *testing.T) {
+		mockService.On("GetLineup", 1, 99).Return(nil, lineup.ErrGameweekNotFound)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/1/lineups/99", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid gameweek id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/1/lineups/invalid", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSetLineup(t *testing.T) {
+	router, mockService := setupLineupHandlerTest(t)
+	starters := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+
+	put := func(gameweekID int, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("/user-teams/1/lineups/%d", gameweekID), bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// lineupFor is the lineup the handler should pass on for a gameweek
+	lineupFor := func(gameweekID int) *models.Lineup {
+		return &models.Lineup{
+			UserTeamID:    1,
+			GameweekID:    gameweekID,
+			Starters:      starters,
+			Bench:         []int{12},
+			CaptainID:     9,
+			ViceCaptainID: 10,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("SetLineup", lineupFor(2)).Return(lineupFor(2), nil)
+
+		w := put(2, map[string]interface{}{"starters": starters, "bench": []int{12}, "captain_id": 9, "vice_captain_id": 10})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Lineup
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, response.CaptainID)
+		assert.Equal(t, 10, response.ViceCaptainID)
+	})
+
+	t.Run("deadline passed", func(t *testing.T) {
+		mockService.On("SetLineup", lineupFor(3)).Return(nil, lineup.ErrLineupLocked)
+
+		w := put(3, map[string]interface{}{"starters": starters, "bench": []int{12}, "captain_id": 9, "vice_captain_id": 10})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid formation", func(t *testing.T) {
+		mockService.On("SetLineup", lineupFor(4)).Return(nil, fmt.Errorf("%w: starting XI must have exactly 1 GK", lineup.ErrInvalidLineup))
+
+		w := put(4, map[string]interface{}{"starters": starters, "bench": []int{12}, "captain_id": 9, "vice_captain_id": 10})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "exactly 1 GK")
+	})
+
+	t.Run("missing starters", func(t *testing.T) {
+		w := put(2, map[string]interface{}{"bench": []int{12}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProcessAutoSubs(t *testing.T) {
+	router, mockService := setupLineupHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("ProcessAutoSubs", 1).Return([]*models.Substitution{
+			{ID: 1, UserTeamID: 3, GameweekID: 1, PlayerOutID: 4, PlayerInID: 12},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/gameweeks/1/autosubs", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []*models.Substitution
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		if assert.Len(t, response, 1) {
+			assert.Equal(t, 12, response[0].PlayerInID)
+		}
+	})
+
+	t.Run("gameweek still in progress", func(t *testing.T) {
+		mockService.On("ProcessAutoSubs", 2).Return(nil, lineup.ErrGameweekNotFinished)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/gameweeks/2/autosubs", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestListChips(t *testing.T) {
+	router, mockService := setupLineupHandlerTest(t)
+
+	mockService.On("ListChips", 1).Return([]*models.Chip{
+		{ID: 1, UserTeamID: 1, GameweekID: 3, Chip: models.ChipWildcard},
+		{ID: 2, UserTeamID: 1, GameweekID: 5, Chip: models.ChipBenchBoost},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/user-teams/1/chips", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.Chip
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response, 2) {
+		assert.Equal(t, models.ChipBenchBoost, response[1].Chip)
+	}
+}
+
+func TestPlayChip(t *testing.T) {
+	router, mockService := setupLineupHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("PlayChip", 1, 2, models.ChipTripleCaptain).Return(&models.Chip{
+			ID: 1, UserTeamID: 1, GameweekID: 2, Chip: models.ChipTripleCaptain,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/chips", bytes.NewBufferString(`{"gameweek_id": 2, "chip": "triple_captain"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("chip already used", func(t *testing.T) {
+		mockService.On("PlayChip", 1, 3, models.ChipTripleCaptain).Return(nil, lineup.ErrChipUsed)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/chips", bytes.NewBufferString(`{"gameweek_id": 3, "chip": "triple_captain"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("unknown chip", func(t *testing.T) {
+		mockService.On("PlayChip", 1, 4, models.ChipType("double_captain")).Return(nil, fmt.Errorf("%w: unknown chip", lineup.ErrInvalidChip))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/chips", bytes.NewBufferString(`{"gameweek_id": 4, "chip": "double_captain"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("missing gameweek", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/chips", bytes.NewBufferString(`{"chip": "wildcard"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCancelChip(t *testing.T) {
+	router, mockService := setupLineupHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("CancelChip", 1, 2).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/user-teams/1/chips/2", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("no chip played", func(t *testing.T) {
+		mockService.On("CancelChip", 1, 3).Return(lineup.ErrNoChip)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/user-teams/1/chips/3", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,134 @@
+package mockdraft
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/services/draft"
+	"go-app/services/mock_draft"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type MockDraftHandler struct {
+	mockDraftService mock_draft.MockDraftService
+}
+
+// NewMockDraftHandler creates a new MockDraftHandler instance
+func NewMockDraftHandler(db *sqlx.DB) *MockDraftHandler {
+	return &MockDraftHandler{
+		mockDraftService: mock_draft.NewMockDraftService(db),
+	}
+}
+
+// mockPickRequest is the request body for making a pick in a mock draft
+type mockPickRequest struct {
+	PlayerID int  `json:"player_id"`
+	Auto     bool `json:"auto"` // let a bot make the pick
+}
+
+// CreateMockDraft handles POST /api/mock-drafts
+func (h *MockDraftHandler) CreateMockDraft(c *gin.Context) {
+	var settings mock_draft.Settings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	md, err := h.mockDraftService.CreateMockDraft(settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, md)
+}
+
+// GetMockDraft handles GET /api/mock-drafts/:id
+func (h *MockDraftHandler) GetMockDraft(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid mock draft ID",
+		})
+		return
+	}
+
+	md, err := h.mockDraftService.GetMockDraft(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve mock draft",
+		})
+		return
+	}
+
+	if md == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Mock draft not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, md)
+}
+
+// MakePick handles POST /api/mock-drafts/:id/picks
+func (h *MockDraftHandler) MakePick(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid mock draft ID",
+		})
+		return
+	}
+
+	var req mockPickRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.PlayerID == 0 && !req.Auto) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	var md *mock_draft.MockDraft
+	if req.Auto {
+		md, err = h.mockDraftService.AutoPick(id)
+	} else {
+		md, err = h.mockDraftService.MakePick(id, req.PlayerID)
+	}
+	if err != nil {
+		if errors.Is(err, mock_draft.ErrPlayerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, draft.ErrDraftNotInProgress) || errors.Is(err, draft.ErrNotYourTurn) ||
+			errors.Is(err, draft.ErrPlayerAlreadyDrafted) || errors.Is(err, mock_draft.ErrPositionFull) ||
+			errors.Is(err, mock_draft.ErrPositionsNeeded) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to make pick",
+		})
+		return
+	}
+
+	if md == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Mock draft not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, md)
+}
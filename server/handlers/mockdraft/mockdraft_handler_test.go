@@ -0,0 +1,128 @@
+package mockdraft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/services/mock_draft"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMockDraftHandlerTest(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	// Mock drafts run in memory, so the real service is used with a fixed player pool
+	positions := []models.Position{models.PositionGK, models.PositionDEF, models.PositionMID, models.PositionFWD}
+	rankings := make([]*mock_draft.RankedPlayer, 40)
+	for i := range rankings {
+		rankings[i] = &mock_draft.RankedPlayer{
+			PlayerID: i + 1,
+			Name:     fmt.Sprintf("Player %d", i+1),
+			Position: positions[i%len(positions)],
+			Rank:     i + 1,
+		}
+	}
+
+	handler := &MockDraftHandler{
+		mockDraftService: mock_draft.NewMockDraftServiceWithRankings(rankings),
+	}
+
+	// Setup routes
+	router.POST("/mock-drafts", handler.CreateMockDraft)
+	router.GET("/mock-drafts/:id", handler.GetMockDraft)
+	router.POST("/mock-drafts/:id/picks", handler.MakePick)
+
+	return router
+}
+
+func TestMockDraft(t *testing.T) {
+	router := setupMockDraftHandlerTest(t)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"teams":     4,
+		"rounds":    2,
+		"user_slot": 1,
+		"seed":      5,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/mock-drafts", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created mock_draft.MockDraft
+	json.Unmarshal(w.Body.Bytes(), &created)
+	assert.Equal(t, 1, created.OnTheClock)
+
+	t.Run("make pick", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"player_id": 1})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/mock-drafts/%d/picks", created.ID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response mock_draft.MockDraft
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response.Picks, 7)
+	})
+
+	t.Run("player already taken", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"player_id": 1})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/mock-drafts/%d/picks", created.ID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("player not in the pool", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"player_id": 999})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/mock-drafts/%d/picks", created.ID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("auto pick finishes the draft", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"auto": true})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/mock-drafts/%d/picks", created.ID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response mock_draft.MockDraft
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, models.DraftStatusCompleted, response.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/mock-drafts/99", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"teams": 1, "rounds": 2, "user_slot": 1})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/mock-drafts", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -64,4 +64,20 @@ func (m *MockLeagueService) GetLeagueByCode(code string) (*models.League, error)
 	return args.Get(0).(*models.League), args.Error(1)
 }
 
+func (m *MockLeagueService) GetSettings(leagueID int) (*models.LeagueSettings, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LeagueSettings), args.Error(1)
+}
+
+func (m *MockLeagueService) UpdateSettings(settings *models.LeagueSettings) (*models.LeagueSettings, error) {
+	args := m.Called(settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LeagueSettings), args.Error(1)
+}
+
 var _ league.LeagueService = (*MockLeagueService)(nil)
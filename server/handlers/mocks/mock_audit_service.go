@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"go-app/services/audit"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) ListEntries(leagueID int, filter *audit.AuditFilter) (*audit.AuditPage, error) {
+	args := m.Called(leagueID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*audit.AuditPage), args.Error(1)
+}
+
+var _ audit.AuditService = (*MockAuditService)(nil)
@@ -0,0 +1,147 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/draft"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDraftService struct {
+	mock.Mock
+}
+
+func (m *MockDraftService) CreateDraft(leagueID int, rounds int, userTeamIDs []int) (*models.Draft, error) {
+	args := m.Called(leagueID, rounds, userTeamIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) GetDraft(id int) (*models.Draft, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) GetDraftByLeague(leagueID int) (*models.Draft, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) GetDraftOrder(draftID int) ([]*models.DraftSlot, error) {
+	args := m.Called(draftID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DraftSlot), args.Error(1)
+}
+
+func (m *MockDraftService) StartDraft(id int) (*models.Draft, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) MakePick(draftID, userTeamID, playerID int) (*models.DraftPick, error) {
+	args := m.Called(draftID, userTeamID, playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DraftPick), args.Error(1)
+}
+
+func (m *MockDraftService) ListPicks(draftID int) ([]*models.DraftPick, error) {
+	args := m.Called(draftID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DraftPick), args.Error(1)
+}
+
+func (m *MockDraftService) GetTeamOnTheClock(draftID int) (int, error) {
+	args := m.Called(draftID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDraftService) CreateAuctionDraft(leagueID int, userTeamIDs []int, settings draft.AuctionSettings) (*models.Draft, error) {
+	args := m.Called(leagueID, userTeamIDs, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *MockDraftService) Nominate(draftID, userTeamID, playerID int, openingBid float64) (*models.DraftNomination, error) {
+	args := m.Called(draftID, userTeamID, playerID, openingBid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DraftNomination), args.Error(1)
+}
+
+func (m *MockDraftService) PlaceBid(draftID, userTeamID int, amount float64) (*models.DraftNomination, error) {
+	args := m.Called(draftID, userTeamID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DraftNomination), args.Error(1)
+}
+
+func (m *MockDraftService) GetOpenNomination(draftID int) (*models.DraftNomination, error) {
+	args := m.Called(draftID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DraftNomination), args.Error(1)
+}
+
+func (m *MockDraftService) CloseNomination(draftID int) (*models.DraftPick, error) {
+	args := m.Called(draftID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DraftPick), args.Error(1)
+}
+
+func (m *MockDraftService) GetAuctionBudgets(draftID int) ([]*draft.TeamBudget, error) {
+	args := m.Called(draftID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*draft.TeamBudget), args.Error(1)
+}
+
+func (m *MockDraftService) SetQueue(draftID, userTeamID int, playerIDs []int) ([]*models.DraftQueueEntry, error) {
+	args := m.Called(draftID, userTeamID, playerIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DraftQueueEntry), args.Error(1)
+}
+
+func (m *MockDraftService) GetQueue(draftID, userTeamID int) ([]*models.DraftQueueEntry, error) {
+	args := m.Called(draftID, userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DraftQueueEntry), args.Error(1)
+}
+
+func (m *MockDraftService) ProcessExpiredClocks() ([]int, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+var _ draft.DraftService = (*MockDraftService)(nil)
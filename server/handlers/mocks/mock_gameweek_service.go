@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/gameweek"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGameweekService struct {
+	mock.Mock
+}
+
+func (m *MockGameweekService) CreateGameweek(gw *models.Gameweek) (*models.Gameweek, error) {
+	args := m.Called(gw)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Gameweek), args.Error(1)
+}
+
+func (m *MockGameweekService) GetGameweek(id int) (*models.Gameweek, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Gameweek), args.Error(1)
+}
+
+func (m *MockGameweekService) ListGameweeks() ([]*models.Gameweek, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Gameweek), args.Error(1)
+}
+
+func (m *MockGameweekService) GetNextGameweek() (*models.Gameweek, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Gameweek), args.Error(1)
+}
+
+func (m *MockGameweekService) GetCurrentGameweek() (*models.Gameweek, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Gameweek), args.Error(1)
+}
+
+func (m *MockGameweekService) ListMatches(gameweekID int) ([]*models.Match, error) {
+	args := m.Called(gameweekID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Match), args.Error(1)
+}
+
+var _ gameweek.GameweekService = (*MockGameweekService)(nil)
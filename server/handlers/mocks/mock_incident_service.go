@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/incident"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIncidentService struct {
+	mock.Mock
+}
+
+func (m *MockIncidentService) ListIncidents(matchID int) ([]*models.MatchIncident, error) {
+	args := m.Called(matchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MatchIncident), args.Error(1)
+}
+
+func (m *MockIncidentService) AddIncident(incident *models.MatchIncident) (*models.MatchIncident, error) {
+	args := m.Called(incident)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MatchIncident), args.Error(1)
+}
+
+func (m *MockIncidentService) UpdateIncident(incident *models.MatchIncident) (*models.MatchIncident, error) {
+	args := m.Called(incident)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MatchIncident), args.Error(1)
+}
+
+func (m *MockIncidentService) DeleteIncident(matchID, incidentID int) error {
+	args := m.Called(matchID, incidentID)
+	return args.Error(0)
+}
+
+func (m *MockIncidentService) ListCorrections(leagueID int) ([]*models.StatCorrection, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StatCorrection), args.Error(1)
+}
+
+var _ incident.IncidentService = (*MockIncidentService)(nil)
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/lineup"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLineupService struct {
+	mock.Mock
+}
+
+func (m *MockLineupService) GetLineup(userTeamID, gameweekID int) (*models.Lineup, error) {
+	args := m.Called(userTeamID, gameweekID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Lineup), args.Error(1)
+}
+
+func (m *MockLineupService) SetLineup(lineup *models.Lineup) (*models.Lineup, error) {
+	args := m.Called(lineup)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Lineup), args.Error(1)
+}
+
+func (m *MockLineupService) ProcessAutoSubs(gameweekID int) ([]*models.Substitution, error) {
+	args := m.Called(gameweekID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Substitution), args.Error(1)
+}
+
+func (m *MockLineupService) PlayChip(userTeamID, gameweekID int, chip models.ChipType) (*models.Chip, error) {
+	args := m.Called(userTeamID, gameweekID, chip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Chip), args.Error(1)
+}
+
+func (m *MockLineupService) CancelChip(userTeamID, gameweekID int) error {
+	args := m.Called(userTeamID, gameweekID)
+	return args.Error(0)
+}
+
+func (m *MockLineupService) ListChips(userTeamID int) ([]*models.Chip, error) {
+	args := m.Called(userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Chip), args.Error(1)
+}
+
+func (m *MockLineupService) RevertFreeHits() ([]*models.Chip, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Chip), args.Error(1)
+}
+
+var _ lineup.LineupService = (*MockLineupService)(nil)
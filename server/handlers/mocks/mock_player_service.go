@@ -67,3 +67,19 @@ func (m *MockPlayerService) ValidatePlayer(player *models.Player) error {
 	args := m.Called(player)
 	return args.Error(0)
 }
+
+func (m *MockPlayerService) UpdatePrice(playerID int, price float64) (*models.Player, error) {
+	args := m.Called(playerID, price)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), args.Error(1)
+}
+
+func (m *MockPlayerService) GetPriceHistory(playerID int) ([]*models.PlayerPrice, error) {
+	args := m.Called(playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PlayerPrice), args.Error(1)
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"time"
+
+	"go-app/models"
+	"go-app/services/pricing"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPricingService struct {
+	mock.Mock
+}
+
+func (m *MockPricingService) PredictChanges() ([]*pricing.PricePrediction, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pricing.PricePrediction), args.Error(1)
+}
+
+func (m *MockPricingService) ApplyChanges() ([]*models.PriceChange, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PriceChange), args.Error(1)
+}
+
+func (m *MockPricingService) ListPriceChanges(since time.Time) ([]*models.PriceChange, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PriceChange), args.Error(1)
+}
+
+var _ pricing.PricingService = (*MockPricingService)(nil)
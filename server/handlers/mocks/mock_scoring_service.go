@@ -0,0 +1,91 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/scoring"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScoringService struct {
+	mock.Mock
+}
+
+func (m *MockScoringService) ScoreGameweek(gameweekID int) ([]*models.PlayerPoints, error) {
+	args := m.Called(gameweekID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PlayerPoints), args.Error(1)
+}
+
+func (m *MockScoringService) ListGameweekPoints(leagueID, gameweekID int) ([]*models.PlayerPoints, error) {
+	args := m.Called(leagueID, gameweekID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PlayerPoints), args.Error(1)
+}
+
+func (m *MockScoringService) ListPlayerPoints(leagueID, playerID int) ([]*models.PlayerPoints, error) {
+	args := m.Called(leagueID, playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PlayerPoints), args.Error(1)
+}
+
+func (m *MockScoringService) GetTeamScore(userTeamID, gameweekID int) (*models.TeamScore, error) {
+	args := m.Called(userTeamID, gameweekID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamScore), args.Error(1)
+}
+
+func (m *MockScoringService) ListMatchBonus(leagueID, matchID int) ([]*models.MatchBonus, error) {
+	args := m.Called(leagueID, matchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MatchBonus), args.Error(1)
+}
+
+func (m *MockScoringService) GetStandings(leagueID int) ([]*models.Standing, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Standing), args.Error(1)
+}
+
+func (m *MockScoringService) GetLiveScores(leagueID, gameweekID int) (*models.LiveScores, error) {
+	args := m.Called(leagueID, gameweekID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LiveScores), args.Error(1)
+}
+
+func (m *MockScoringService) GetRules(leagueID int) (*models.ScoringRules, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScoringRules), args.Error(1)
+}
+
+func (m *MockScoringService) UpdateRules(rules *models.ScoringRules, userTeamID int) (*models.ScoringRules, error) {
+	args := m.Called(rules, userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScoringRules), args.Error(1)
+}
+
+func (m *MockScoringService) ListPresets() []*models.ScoringRules {
+	args := m.Called()
+	return args.Get(0).([]*models.ScoringRules)
+}
+
+var _ scoring.ScoringService = (*MockScoringService)(nil)
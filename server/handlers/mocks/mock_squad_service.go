@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/squad"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSquadService struct {
+	mock.Mock
+}
+
+func (m *MockSquadService) GetRules(leagueID int) (*models.SquadRules, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SquadRules), args.Error(1)
+}
+
+func (m *MockSquadService) UpdateRules(rules *models.SquadRules) (*models.SquadRules, error) {
+	args := m.Called(rules)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SquadRules), args.Error(1)
+}
+
+func (m *MockSquadService) ValidateRoster(userTeamID int) error {
+	args := m.Called(userTeamID)
+	return args.Error(0)
+}
+
+var _ squad.SquadService = (*MockSquadService)(nil)
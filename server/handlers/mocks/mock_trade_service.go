@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/trade"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTradeService struct {
+	mock.Mock
+}
+
+func (m *MockTradeService) ProposeTrade(proposal *models.Trade) (*models.Trade, error) {
+	args := m.Called(proposal)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) GetTrade(id int) (*models.Trade, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) ListTrades(userTeamID int) ([]*models.Trade, error) {
+	args := m.Called(userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) AcceptTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	args := m.Called(tradeID, userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) RejectTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	args := m.Called(tradeID, userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) CounterTrade(tradeID int, counter *models.Trade) (*models.Trade, error) {
+	args := m.Called(tradeID, counter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) CancelTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	args := m.Called(tradeID, userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) VetoTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	args := m.Called(tradeID, userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeService) ProcessTrades(leagueID int) ([]*models.Trade, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Trade), args.Error(1)
+}
+
+var _ trade.TradeService = (*MockTradeService)(nil)
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/transfer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockTransferService) MakeTransfers(userTeamID int, out []int, in []int) ([]*models.Transfer, error) {
+	args := m.Called(userTeamID, out, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) ListTransfers(userTeamID int) ([]*models.Transfer, error) {
+	args := m.Called(userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) GetBank(userTeamID int) (float64, error) {
+	args := m.Called(userTeamID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+var _ transfer.TransferService = (*MockTransferService)(nil)
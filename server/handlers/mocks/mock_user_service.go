@@ -48,3 +48,16 @@ func (m *MockUserService) ValidateUser(user *models.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
+
+func (m *MockUserService) Authenticate(email, password string) (*models.User, error) {
+	args := m.Called(email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserService) OwnsUserTeam(userID, userTeamID int) (bool, error) {
+	args := m.Called(userID, userTeamID)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,96 @@
+package mocks
+
+import (
+	"go-app/models"
+	"go-app/services/waiver"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWaiverService struct {
+	mock.Mock
+}
+
+func (m *MockWaiverService) ListWaivers(leagueID int) ([]*models.Waiver, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Waiver), args.Error(1)
+}
+
+func (m *MockWaiverService) GetPriorities(leagueID int) ([]*models.WaiverPriority, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.WaiverPriority), args.Error(1)
+}
+
+func (m *MockWaiverService) ListTransactions(leagueID int) ([]*models.LeagueTransaction, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LeagueTransaction), args.Error(1)
+}
+
+func (m *MockWaiverService) ListClaims(userTeamID int) ([]*models.WaiverClaim, error) {
+	args := m.Called(userTeamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.WaiverClaim), args.Error(1)
+}
+
+func (m *MockWaiverService) SubmitClaim(userTeamID, playerID int, dropPlayerID *int, bid int) (*models.WaiverClaim, error) {
+	args := m.Called(userTeamID, playerID, dropPlayerID, bid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WaiverClaim), args.Error(1)
+}
+
+func (m *MockWaiverService) ReorderClaims(userTeamID int, claimIDs []int) ([]*models.WaiverClaim, error) {
+	args := m.Called(userTeamID, claimIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.WaiverClaim), args.Error(1)
+}
+
+func (m *MockWaiverService) CancelClaim(userTeamID, claimID int) error {
+	args := m.Called(userTeamID, claimID)
+	return args.Error(0)
+}
+
+func (m *MockWaiverService) GetFAABBalance(userTeamID int) (int, error) {
+	args := m.Called(userTeamID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWaiverService) AddFreeAgent(userTeamID, playerID int, dropPlayerID *int) (*models.LeagueTransaction, error) {
+	args := m.Called(userTeamID, playerID, dropPlayerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LeagueTransaction), args.Error(1)
+}
+
+func (m *MockWaiverService) DropPlayer(userTeamID, playerID int) (*models.LeagueTransaction, error) {
+	args := m.Called(userTeamID, playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LeagueTransaction), args.Error(1)
+}
+
+func (m *MockWaiverService) ProcessWaivers(leagueID int) ([]*models.WaiverClaim, error) {
+	args := m.Called(leagueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.WaiverClaim), args.Error(1)
+}
+
+var _ waiver.WaiverService = (*MockWaiverService)(nil)
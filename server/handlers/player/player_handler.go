@@ -141,3 +141,58 @@ func (h *PlayerHandler) GetPlayerStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// updatePriceRequest is the request body for changing a player's price
+type updatePriceRequest struct {
+	Price float64 `json:"price" binding:"required"`
+}
+
+// GetPriceHistory handles GET /api/players/:id/prices
+func (h *PlayerHandler) GetPriceHistory(c *gin.Context) {
+	playerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid player ID",
+		})
+		return
+	}
+
+	prices, err := h.playerService.GetPriceHistory(playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve price history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+// UpdatePrice handles PUT /api/players/:id/price
+func (h *PlayerHandler) UpdatePrice(c *gin.Context) {
+	playerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid player ID",
+		})
+		return
+	}
+
+	var req updatePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	player, err := h.playerService.UpdatePrice(playerID, req.Price)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
@@ -1,8 +1,10 @@
 package player
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -29,6 +31,8 @@ func setupPlayerHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockPlayerService
 	router.GET("/players", handler.ListPlayers)
 	router.GET("/teams/:teamId/players", handler.GetPlayersByTeam)
 	router.GET("/players/:id/stats", handler.GetPlayerStats)
+	router.GET("/players/:id/prices", handler.GetPriceHistory)
+	router.PUT("/players/:id/price", handler.UpdatePrice)
 
 	return router, mockService
 }
@@ -226,3 +230,50 @@ func TestGetPlayerStats(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 }
+
+func TestGetPriceHistory(t *testing.T) {
+	router, mockService := setupPlayerHandlerTest(t)
+
+	mockService.On("GetPriceHistory", 1).Return([]*models.PlayerPrice{
+		{ID: 1, PlayerID: 1, Price: 7.5},
+		{ID: 2, PlayerID: 1, Price: 7.6},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/players/1/prices", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.PlayerPrice
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response, 2) {
+		assert.Equal(t, 7.6, response[1].Price)
+	}
+}
+
+func TestUpdatePrice(t *testing.T) {
+	router, mockService := setupPlayerHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("UpdatePrice", 1, 8.0).Return(&models.Player{ID: 1, Price: 8.0}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/players/1/price", bytes.NewBufferString(`{"price": 8.0}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid price", func(t *testing.T) {
+		mockService.On("UpdatePrice", 2, -1.0).Return(nil, errors.New("price cannot be negative"))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/players/2/price", bytes.NewBufferString(`{"price": -1}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,77 @@
+package pricing
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-app/services/pricing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultChangeDays is how far back price changes are listed when no range is given
+const defaultChangeDays = 7
+
+type PricingHandler struct {
+	pricingService pricing.PricingService
+}
+
+// NewPricingHandler creates a new PricingHandler instance
+func NewPricingHandler(db *sqlx.DB) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricing.NewPricingService(db),
+	}
+}
+
+// GetPredictions handles GET /api/prices/predictions
+func (h *PricingHandler) GetPredictions(c *gin.Context) {
+	predictions, err := h.pricingService.PredictChanges()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to predict price changes",
+		})
+		return
+	}
+
+	risers := make([]*pricing.PricePrediction, 0)
+	fallers := make([]*pricing.PricePrediction, 0)
+	for _, prediction := range predictions {
+		if prediction.NetTransfers > 0 {
+			risers = append(risers, prediction)
+		} else if prediction.NetTransfers < 0 {
+			fallers = append(fallers, prediction)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"risers":  risers,
+		"fallers": fallers,
+	})
+}
+
+// ListPriceChanges handles GET /api/prices/changes with an optional days query parameter
+func (h *PricingHandler) ListPriceChanges(c *gin.Context) {
+	days := defaultChangeDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid number of days",
+			})
+			return
+		}
+		days = parsed
+	}
+
+	changes, err := h.pricingService.ListPriceChanges(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve price changes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
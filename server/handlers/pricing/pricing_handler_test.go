@@ -0,0 +1,101 @@
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/pricing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupPricingHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockPricingService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockPricingService)
+	handler := &PricingHandler{
+		pricingService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/prices/predictions", handler.GetPredictions)
+	router.GET("/prices/changes", handler.ListPriceChanges)
+
+	return router, mockService
+}
+
+func TestGetPredictions(t *testing.T) {
+	router, mockService := setupPricingHandlerTest(t)
+
+	mockService.On("PredictChanges").Return([]*pricing.PricePrediction{
+		{Player: models.Player{ID: 1, Price: 6.0}, NetTransfers: 120, Progress: 2.4, PredictedPrice: 6.1},
+		{Player: models.Player{ID: 2, Price: 5.0}, NetTransfers: -30, Progress: -0.6, PredictedPrice: 5.0},
+		{Player: models.Player{ID: 3, Price: 4.5}, NetTransfers: 10, Progress: 0.2, PredictedPrice: 4.5},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/prices/predictions", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Risers  []pricing.PricePrediction `json:"risers"`
+		Fallers []pricing.PricePrediction `json:"fallers"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response.Risers, 2) {
+		assert.Equal(t, 1, response.Risers[0].ID)
+		assert.Equal(t, 6.1, response.Risers[0].PredictedPrice)
+	}
+	if assert.Len(t, response.Fallers, 1) {
+		assert.Equal(t, 2, response.Fallers[0].ID)
+	}
+}
+
+func TestListPriceChanges(t *testing.T) {
+	router, mockService := setupPricingHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("ListPriceChanges", mock.AnythingOfType("time.Time")).Return([]*models.PriceChange{
+			{PlayerID: 1, OldPrice: 6.0, NewPrice: 6.1, ChangedAt: time.Now()},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/prices/changes?days=3", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.PriceChange
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("invalid days", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/prices/changes?days=0", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService.On("ListPriceChanges", mock.AnythingOfType("time.Time")).Return(nil, errors.New("database error")).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/prices/changes", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,378 @@
+package scoring
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-app/models"
+	"go-app/services/scoring"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// liveScoresInterval is how often live scores are worked out again for a stream
+const liveScoresInterval = 10 * time.Second
+
+type ScoringHandler struct {
+	scoringService scoring.ScoringService
+	liveInterval   time.Duration
+}
+
+// NewScoringHandler creates a new ScoringHandler instance
+func NewScoringHandler(db *sqlx.DB) *ScoringHandler {
+	return &ScoringHandler{
+		scoringService: scoring.NewScoringService(db),
+		liveInterval:   liveScoresInterval,
+	}
+}
+
+// ScoreGameweek handles POST /api/gameweeks/:id/score
+func (h *ScoringHandler) ScoreGameweek(c *gin.Context) {
+	gameweekID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gameweek ID",
+		})
+		return
+	}
+
+	points, err := h.scoringService.ScoreGameweek(gameweekID)
+	if err != nil {
+		respondError(c, err, "Failed to score gameweek")
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
+
+// ListGameweekPoints handles GET /api/gameweeks/:id/points?league_id=
+func (h *ScoringHandler) ListGameweekPoints(c *gin.Context) {
+	gameweekID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gameweek ID",
+		})
+		return
+	}
+	leagueID, err := strconv.Atoi(c.Query("league_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	points, err := h.scoringService.ListGameweekPoints(leagueID, gameweekID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve points")
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
+
+// ListPlayerPoints handles GET /api/players/:id/points?league_id=
+func (h *ScoringHandler) ListPlayerPoints(c *gin.Context) {
+	playerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid player ID",
+		})
+		return
+	}
+	leagueID, err := strconv.Atoi(c.Query("league_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	points, err := h.scoringService.ListPlayerPoints(leagueID, playerID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve points")
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
+
+// GetTeamScore handles GET /api/user-teams/:id/scores/:gameweek_id
+func (h *ScoringHandler) GetTeamScore(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+	gameweekID, err := strconv.Atoi(c.Param("gameweek_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gameweek ID",
+		})
+		return
+	}
+
+	score, err := h.scoringService.GetTeamScore(userTeamID, gameweekID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve score")
+		return
+	}
+
+	c.JSON(http.StatusOK, score)
+}
+
+// ListMatchBonus handles GET /api/matches/:id/bonus?league_id=
+func (h *ScoringHandler) ListMatchBonus(c *gin.Context) {
+	matchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid match ID",
+		})
+		return
+	}
+	leagueID, err := strconv.Atoi(c.Query("league_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	bonuses, err := h.scoringService.ListMatchBonus(leagueID, matchID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Match not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve bonus points",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, bonuses)
+}
+
+// GetStandings handles GET /api/leagues/:id/standings
+func (h *ScoringHandler) GetStandings(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	standings, err := h.scoringService.GetStandings(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve standings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// GetLiveScores handles GET /api/leagues/:id/live?gameweek_id=
+func (h *ScoringHandler) GetLiveScores(c *gin.Context) {
+	leagueID, gameweekID, ok := liveParams(c)
+	if !ok {
+		return
+	}
+
+	live, err := h.scoringService.GetLiveScores(leagueID, gameweekID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve live scores")
+		return
+	}
+
+	c.JSON(http.StatusOK, live)
+}
+
+// StreamLiveScores handles GET /api/leagues/:id/live/stream?gameweek_id=
+// It streams the league's live scores as server-sent events: a "scores" event straight away and
+// again whenever the scores change. Once every match has finished the scores are sent one last
+// time as a "final" event and the stream ends.
+func (h *ScoringHandler) StreamLiveScores(c *gin.Context) {
+	leagueID, gameweekID, ok := liveParams(c)
+	if !ok {
+		return
+	}
+
+	live, err := h.scoringService.GetLiveScores(leagueID, gameweekID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve live scores")
+		return
+	}
+	// Follow the gameweek first found, even if a later one starts while streaming
+	gameweekID = live.GameweekID
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	ticker := time.NewTicker(h.liveInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		sent, err := json.Marshal(live)
+		if err != nil {
+			return
+		}
+		if !live.Provisional {
+			c.SSEvent("final", live)
+			c.Writer.Flush()
+			return
+		}
+		if string(sent) != string(last) {
+			c.SSEvent("scores", live)
+			c.Writer.Flush()
+			last = sent
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+		if live, err = h.scoringService.GetLiveScores(leagueID, gameweekID); err != nil {
+			c.SSEvent("error", gin.H{
+				"error": "Failed to retrieve live scores",
+			})
+			c.Writer.Flush()
+			return
+		}
+	}
+}
+
+// liveParams reads the league and optional gameweek of a live scores request, responding with
+// an error if either is invalid
+func liveParams(c *gin.Context) (int, int, bool) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return 0, 0, false
+	}
+	gameweekID := 0
+	if param := c.Query("gameweek_id"); param != "" {
+		if gameweekID, err = strconv.Atoi(param); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid gameweek ID",
+			})
+			return 0, 0, false
+		}
+	}
+	return leagueID, gameweekID, true
+}
+
+// GetRules handles GET /api/leagues/:id/scoring-rules
+func (h *ScoringHandler) GetRules(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	rules, err := h.scoringService.GetRules(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve scoring rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// updateRulesRequest is the body of an UpdateRules request: a preset to copy, or the league's
+// own points
+type updateRulesRequest struct {
+	UserTeamID  int                                             `json:"user_team_id" binding:"required"`
+	Preset      models.ScoringPreset                            `json:"preset"`
+	Incidents   map[models.Position]map[models.IncidentType]int `json:"incidents"`
+	Appearances []models.AppearanceThreshold                    `json:"appearances"`
+	BonusPoints []int                                           `json:"bonus_points"`
+}
+
+// UpdateRules handles PUT /api/leagues/:id/scoring-rules
+func (h *ScoringHandler) UpdateRules(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	var req updateRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	rules, err := h.scoringService.UpdateRules(&models.ScoringRules{
+		LeagueID:    leagueID,
+		Preset:      req.Preset,
+		Incidents:   req.Incidents,
+		Appearances: req.Appearances,
+		BonusPoints: req.BonusPoints,
+	}, req.UserTeamID)
+	if err != nil {
+		respondError(c, err, "Failed to update scoring rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// ListPresets handles GET /api/scoring-presets
+func (h *ScoringHandler) ListPresets(c *gin.Context) {
+	c.JSON(http.StatusOK, h.scoringService.ListPresets())
+}
+
+// respondError maps a scoring service error to a response
+func respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Gameweek or user team not found",
+		})
+	case errors.Is(err, scoring.ErrNoGameweek):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, scoring.ErrInvalidRules):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, scoring.ErrNotCommissioner):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, scoring.ErrRulesLocked):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
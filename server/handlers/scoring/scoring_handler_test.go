@@ -0,0 +1,347 @@
+package scoring
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/scoring"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupScoringHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockScoringService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockScoringService)
+	handler := &ScoringHandler{
+		scoringService: mockService,
+		liveInterval:   time.Millisecond,
+	}
+
+	// Setup routes
+	router.POST("/gameweeks/:id/score", handler.ScoreGameweek)
+	router.GET("/gameweeks/:id/points", handler.ListGameweekPoints)
+	router.GET("/players/:id/points", handler.ListPlayerPoints)
+	router.GET("/user-teams/:id/scores/:gameweek_id", handler.GetTeamScore)
+	router.GET("/leagues/:id/scoring-rules", handler.GetRules)
+	router.PUT("/leagues/:id/scoring-rules", handler.UpdateRules)
+	router.GET("/scoring-presets", handler.ListPresets)
+	router.GET("/matches/:id/bonus", handler.ListMatchBonus)
+	router.GET("/leagues/:id/standings", handler.GetStandings)
+	router.GET("/leagues/:id/live", handler.GetLiveScores)
+	router.GET("/leagues/:id/live/stream", handler.StreamLiveScores)
+
+	return router, mockService
+}
+
+func TestScoreGameweek(t *testing.T) {
+	router, mockService := setupScoringHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("ScoreGameweek", 1).Return([]*models.PlayerPoints{
+			{PlayerID: 5, GameweekID: 1, Points: 8, Goals: 1},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/gameweeks/1/score", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.PlayerPoints
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("gameweek not found", func(t *testing.T) {
+		mockService.On("ScoreGameweek", 99).Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/gameweeks/99/score", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid gameweek ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/gameweeks/abc/score", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestListPoints(t *testing.T) {
+	router, mockService := setupScoringHandlerTest(t)
+
+	mockService.On("ListGameweekPoints", 3, 1).Return([]*models.PlayerPoints{{LeagueID: 3, PlayerID: 5, GameweekID: 1, Points: 8}}, nil)
+	mockService.On("ListPlayerPoints", 3, 5).Return([]*models.PlayerPoints{{LeagueID: 3, PlayerID: 5, GameweekID: 1, Points: 8}}, nil)
+	mockService.On("ListPlayerPoints", 3, 6).Return(nil, errors.New("database error"))
+
+	for path, code := range map[string]int{
+		"/gameweeks/1/points?league_id=3": http.StatusOK,
+		"/gameweeks/1/points":             http.StatusBadRequest,
+		"/players/5/points?league_id=3":   http.StatusOK,
+		"/players/6/points?league_id=3":   http.StatusInternalServerError,
+		"/players/x/points?league_id=3":   http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestGetTeamScore(t *testing.T) {
+	router, mockService := setupScoringHandlerTest(t)
+
+	mockService.On("GetTeamScore", 2, 1).Return(&models.TeamScore{
+		UserTeamID: 2,
+		GameweekID: 1,
+		Players:    []*models.ScoredPlayer{{PlayerID: 5, Points: 8, Multiplier: 2}},
+		Points:     16,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/user-teams/2/scores/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.TeamScore
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(16), response.Points)
+}
+
+func TestListMatchBonus(t *testing.T) {
+	router, mockService := setupScoringHandlerTest(t)
+
+	mockService.On("ListMatchBonus", 3, 10).Return([]*models.MatchBonus{
+		{MatchID: 10, PlayerID: 5, PerformanceScore: 30, Rank: 1, Bonus: 3, Provisional: true},
+		{MatchID: 10, PlayerID: 6, PerformanceScore: 30, Rank: 1, Bonus: 3, Provisional: true},
+	}, nil)
+	mockService.On("ListMatchBonus", 3, 99).Return(nil, sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/matches/10/bonus?league_id=3", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.MatchBonus
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response, 2) {
+		assert.True(t, response[0].Provisional)
+	}
+
+	for path, code := range map[string]int{
+		"/matches/99/bonus?league_id=3": http.StatusNotFound,
+		"/matches/x/bonus?league_id=3":  http.StatusBadRequest,
+		"/matches/10/bonus":             http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestGetStandings(t *testing.T) {
+	router, mockService := setupScoringHandlerTest(t)
+
+	mockService.On("GetStandings", 3).Return([]*models.Standing{
+		{Rank: 1, UserTeamID: 7, Name: "Leaders", Points: 120.5},
+		{Rank: 2, UserTeamID: 8, Name: "Chasers", Points: 98},
+	}, nil)
+	mockService.On("GetStandings", 4).Return(nil, errors.New("database error"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/leagues/3/standings", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.Standing
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.Len(t, response, 2) {
+		assert.Equal(t, 120.5, response[0].Points)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/leagues/4/standings", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestLiveScores(t *testing.T) {
+	live := func(points float64, provisional bool) *models.LiveScores {
+		return &models.LiveScores{
+			LeagueID:    3,
+			GameweekID:  2,
+			Provisional: provisional,
+			Teams: []*models.LiveTeamScore{
+				{Name: "Leaders", TeamScore: &models.TeamScore{UserTeamID: 7, GameweekID: 2, Points: points}},
+			},
+		}
+	}
+
+	t.Run("snapshot", func(t *testing.T) {
+		router, mockService := setupScoringHandlerTest(t)
+		mockService.On("GetLiveScores", 3, 0).Return(live(42, true), nil)
+		mockService.On("GetLiveScores", 4, 0).Return(nil, scoring.ErrNoGameweek)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/3/live", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.LiveScores
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Provisional)
+		if assert.Len(t, response.Teams, 1) {
+			assert.Equal(t, float64(42), response.Teams[0].Points)
+			assert.Equal(t, "Leaders", response.Teams[0].Name)
+		}
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/leagues/4/live", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/leagues/3/live?gameweek_id=x", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("stream sends changes until the matches finish", func(t *testing.T) {
+		router, mockService := setupScoringHandlerTest(t)
+		// The first lookup finds the current gameweek, which the stream then follows
+		mockService.On("GetLiveScores", 3, 0).Return(live(40, true), nil).Once()
+		mockService.On("GetLiveScores", 3, 2).Return(live(40, true), nil).Once()
+		mockService.On("GetLiveScores", 3, 2).Return(live(46, true), nil).Once()
+		mockService.On("GetLiveScores", 3, 2).Return(live(48, false), nil).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/3/live/stream", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Equal(t, 2, strings.Count(body, "event:scores"))
+		assert.Equal(t, 1, strings.Count(body, "event:final"))
+		assert.Less(t, strings.Index(body, `"points":46`), strings.Index(body, "event:final"))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("stream of a missing gameweek", func(t *testing.T) {
+		router, mockService := setupScoringHandlerTest(t)
+		mockService.On("GetLiveScores", 3, 99).Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/3/live/stream?gameweek_id=99", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestScoringRules(t *testing.T) {
+	router, mockService := setupScoringHandlerTest(t)
+
+	t.Run("get rules", func(t *testing.T) {
+		mockService.On("GetRules", 3).Return(&models.ScoringRules{
+			LeagueID:    3,
+			Preset:      models.ScoringPresetFPL,
+			BonusPoints: []int{3, 2, 1},
+			Locked:      true,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/3/scoring-rules", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.ScoringRules
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Locked)
+	})
+
+	t.Run("copy a preset", func(t *testing.T) {
+		mockService.On("UpdateRules", &models.ScoringRules{LeagueID: 3, Preset: models.ScoringPresetUCL}, 7).
+			Return(&models.ScoringRules{LeagueID: 3, Preset: models.ScoringPresetUCL, BonusPoints: []int{3}}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/leagues/3/scoring-rules", strings.NewReader(`{"user_team_id": 7, "preset": "ucl"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	for name, tc := range map[string]struct {
+		err  error
+		code int
+	}{
+		"locked":            {scoring.ErrRulesLocked, http.StatusConflict},
+		"not commissioner":  {scoring.ErrNotCommissioner, http.StatusForbidden},
+		"invalid rules":     {fmt.Errorf("%w: bonus points cannot be negative", scoring.ErrInvalidRules), http.StatusBadRequest},
+		"league not joined": {sql.ErrNoRows, http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			router, mockService := setupScoringHandlerTest(t)
+			mockService.On("UpdateRules", mock.Anything, 7).Return(nil, tc.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/leagues/3/scoring-rules", strings.NewReader(`{"user_team_id": 7, "bonus_points": [3, -1]}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+
+	t.Run("missing user team", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/leagues/3/scoring-rules", strings.NewReader(`{"preset": "fpl"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("list presets", func(t *testing.T) {
+		mockService.On("ListPresets").Return([]*models.ScoringRules{
+			{Preset: models.ScoringPresetFPL},
+			{Preset: models.ScoringPresetUCL},
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/scoring-presets", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.ScoringRules
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 2)
+	})
+}
@@ -0,0 +1,113 @@
+package squad
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/models"
+	"go-app/services/squad"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type SquadHandler struct {
+	squadService squad.SquadService
+}
+
+// NewSquadHandler creates a new SquadHandler instance
+func NewSquadHandler(db *sqlx.DB) *SquadHandler {
+	return &SquadHandler{
+		squadService: squad.NewSquadService(db),
+	}
+}
+
+// GetRules handles GET /api/leagues/:id/squad-rules
+func (h *SquadHandler) GetRules(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	rules, err := h.squadService.GetRules(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve squad rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// UpdateRules handles PUT /api/leagues/:id/squad-rules
+func (h *SquadHandler) UpdateRules(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	var rules models.SquadRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	rules.LeagueID = id
+	updatedRules, err := h.squadService.UpdateRules(&rules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedRules)
+}
+
+// ValidateRoster handles GET /api/user-teams/:id/squad/validate
+func (h *SquadHandler) ValidateRoster(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	err = h.squadService.ValidateRoster(id)
+	if err != nil {
+		var violation *squad.RuleViolation
+		if errors.As(err, &violation) {
+			c.JSON(http.StatusOK, gin.H{
+				"valid":     false,
+				"violation": violation,
+			})
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User team not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to validate roster",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valid": true,
+	})
+}
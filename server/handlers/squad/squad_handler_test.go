@@ -0,0 +1,127 @@
+package squad
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/squad"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSquadHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockSquadService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockSquadService)
+	handler := &SquadHandler{
+		squadService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/leagues/:id/squad-rules", handler.GetRules)
+	router.PUT("/leagues/:id/squad-rules", handler.UpdateRules)
+	router.GET("/user-teams/:id/squad/validate", handler.ValidateRoster)
+
+	return router, mockService
+}
+
+func TestGetRules(t *testing.T) {
+	router, mockService := setupSquadHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("GetRules", 1).Return(squad.DefaultRules(1), nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/1/squad-rules", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.SquadRules
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 15, response.SquadSize)
+	})
+
+	t.Run("invalid league id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/invalid/squad-rules", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateRules(t *testing.T) {
+	router, mockService := setupSquadHandlerTest(t)
+
+	t.Run("invalid rules", func(t *testing.T) {
+		mockService.On("UpdateRules", mock.Anything).Return(nil, errors.New("squad size must be greater than zero")).Once()
+
+		body, _ := json.Marshal(map[string]interface{}{"squad_size": 0})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/leagues/1/squad-rules", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestValidateRoster(t *testing.T) {
+	router, mockService := setupSquadHandlerTest(t)
+
+	t.Run("valid roster", func(t *testing.T) {
+		mockService.On("ValidateRoster", 1).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/1/squad/validate", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"valid": true}`, w.Body.String())
+	})
+
+	t.Run("rule broken", func(t *testing.T) {
+		mockService.On("ValidateRoster", 2).Return(&squad.RuleViolation{
+			Rule:    squad.RuleTeamMax,
+			TeamID:  4,
+			Limit:   3,
+			Message: "squad cannot have more than 3 players from team 4",
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/2/squad/validate", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Valid     bool                `json:"valid"`
+			Violation squad.RuleViolation `json:"violation"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Valid)
+		assert.Equal(t, squad.RuleTeamMax, response.Violation.Rule)
+		assert.Equal(t, 4, response.Violation.TeamID)
+	})
+
+	t.Run("user team not found", func(t *testing.T) {
+		mockService.On("ValidateRoster", 3).Return(sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/3/squad/validate", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,269 @@
+package trade
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-app/models"
+	"go-app/services/squad"
+	"go-app/services/trade"
+	"go-app/services/waiver"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type TradeHandler struct {
+	tradeService trade.TradeService
+}
+
+// NewTradeHandler creates a new TradeHandler instance
+func NewTradeHandler(db *sqlx.DB) *TradeHandler {
+	return &TradeHandler{
+		tradeService: trade.NewTradeService(db),
+	}
+}
+
+// proposeTradeRequest is the request body for proposing a trade
+type proposeTradeRequest struct {
+	RecipientTeamID int                 `json:"recipient_team_id" binding:"required"`
+	Message         string              `json:"message"`
+	ExpiresAt       *time.Time          `json:"expires_at"`
+	Items           []*models.TradeItem `json:"items" binding:"required"`
+}
+
+// counterTradeRequest is the request body for countering a trade
+type counterTradeRequest struct {
+	UserTeamID int                 `json:"user_team_id" binding:"required"`
+	Message    string              `json:"message"`
+	ExpiresAt  *time.Time          `json:"expires_at"`
+	Items      []*models.TradeItem `json:"items" binding:"required"`
+}
+
+// tradeActionRequest is the request body for responding to a trade
+type tradeActionRequest struct {
+	UserTeamID int `json:"user_team_id" binding:"required"`
+}
+
+// ListTrades handles GET /api/user-teams/:id/trades
+func (h *TradeHandler) ListTrades(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	trades, err := h.tradeService.ListTrades(userTeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve trades",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, trades)
+}
+
+// ProposeTrade handles POST /api/user-teams/:id/trades
+func (h *TradeHandler) ProposeTrade(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req proposeTradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	proposal := &models.Trade{
+		ProposerTeamID:  userTeamID,
+		RecipientTeamID: req.RecipientTeamID,
+		Message:         req.Message,
+		Items:           req.Items,
+	}
+	if req.ExpiresAt != nil {
+		proposal.ExpiresAt = *req.ExpiresAt
+	}
+
+	proposal, err = h.tradeService.ProposeTrade(proposal)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User team not found",
+			})
+			return
+		}
+		respondError(c, err, "Failed to propose trade")
+		return
+	}
+
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// GetTrade handles GET /api/trades/:id
+func (h *TradeHandler) GetTrade(c *gin.Context) {
+	tradeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid trade ID",
+		})
+		return
+	}
+
+	found, err := h.tradeService.GetTrade(tradeID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve trade")
+		return
+	}
+
+	c.JSON(http.StatusOK, found)
+}
+
+// AcceptTrade handles POST /api/trades/:id/accept
+func (h *TradeHandler) AcceptTrade(c *gin.Context) {
+	h.respond(c, h.tradeService.AcceptTrade, "Failed to accept trade")
+}
+
+// RejectTrade handles POST /api/trades/:id/reject
+func (h *TradeHandler) RejectTrade(c *gin.Context) {
+	h.respond(c, h.tradeService.RejectTrade, "Failed to reject trade")
+}
+
+// CancelTrade handles POST /api/trades/:id/cancel
+func (h *TradeHandler) CancelTrade(c *gin.Context) {
+	h.respond(c, h.tradeService.CancelTrade, "Failed to cancel trade")
+}
+
+// VetoTrade handles POST /api/trades/:id/veto
+func (h *TradeHandler) VetoTrade(c *gin.Context) {
+	h.respond(c, h.tradeService.VetoTrade, "Failed to veto trade")
+}
+
+// respond runs a response to a trade by the user team in the request body
+func (h *TradeHandler) respond(c *gin.Context, action func(tradeID, userTeamID int) (*models.Trade, error), message string) {
+	tradeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid trade ID",
+		})
+		return
+	}
+
+	var req tradeActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	updated, err := action(tradeID, req.UserTeamID)
+	if err != nil {
+		respondError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// CounterTrade handles POST /api/trades/:id/counter
+func (h *TradeHandler) CounterTrade(c *gin.Context) {
+	tradeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid trade ID",
+		})
+		return
+	}
+
+	var req counterTradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	counter := &models.Trade{
+		ProposerTeamID: req.UserTeamID,
+		Message:        req.Message,
+		Items:          req.Items,
+	}
+	if req.ExpiresAt != nil {
+		counter.ExpiresAt = *req.ExpiresAt
+	}
+
+	counter, err = h.tradeService.CounterTrade(tradeID, counter)
+	if err != nil {
+		respondError(c, err, "Failed to counter trade")
+		return
+	}
+
+	c.JSON(http.StatusCreated, counter)
+}
+
+// ProcessTrades handles POST /api/leagues/:id/trades/process
+func (h *TradeHandler) ProcessTrades(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	trades, err := h.tradeService.ProcessTrades(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process trades",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, trades)
+}
+
+// respondError maps a trade service error to a response
+func respondError(c *gin.Context, err error, message string) {
+	var violation *squad.RuleViolation
+	switch {
+	case errors.As(err, &violation):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     violation.Message,
+			"violation": violation,
+		})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Trade not found",
+		})
+	case errors.Is(err, trade.ErrNotTradeParty):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, trade.ErrTradeNotPending), errors.Is(err, trade.ErrTradeNotInReview), errors.Is(err, waiver.ErrNotDraftLeague),
+		errors.Is(err, waiver.ErrDraftNotCompleted):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, trade.ErrInvalidTrade):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package trade
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/squad"
+	"go-app/services/trade"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupTradeHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockTradeService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockTradeService)
+	handler := &TradeHandler{
+		tradeService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/user-teams/:id/trades", handler.ListTrades)
+	router.POST("/user-teams/:id/trades", handler.ProposeTrade)
+	router.GET("/trades/:id", handler.GetTrade)
+	router.POST("/trades/:id/accept", handler.AcceptTrade)
+	router.POST("/trades/:id/reject", handler.RejectTrade)
+	router.POST("/trades/:id/counter", handler.CounterTrade)
+	router.POST("/trades/:id/cancel", handler.CancelTrade)
+	router.POST("/trades/:id/veto", handler.VetoTrade)
+	router.POST("/leagues/:id/trades/process", handler.ProcessTrades)
+
+	return router, mockService
+}
+
+func TestListTrades(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	mockService.On("ListTrades", 1).Return([]*models.Trade{
+		{ID: 1, ProposerTeamID: 1, RecipientTeamID: 2, Status: models.TradeStatusPending},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/user-teams/1/trades", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.Trade
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+}
+
+func TestProposeTrade(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("ProposeTrade", mock.MatchedBy(func(proposal *models.Trade) bool {
+			return proposal.ProposerTeamID == 1 && proposal.RecipientTeamID == 2 && len(proposal.Items) == 2 &&
+				proposal.ExpiresAt.IsZero()
+		})).Return(&models.Trade{ID: 1, ProposerTeamID: 1, RecipientTeamID: 2, Status: models.TradeStatusPending}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/trades", bytes.NewBufferString(`{
+			"recipient_team_id": 2,
+			"items": [
+				{"from_user_team_id": 1, "type": "player", "player_id": 5},
+				{"from_user_team_id": 2, "type": "draft_pick", "draft_round": 1, "pick_original_user_team_id": 2}
+			]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("player not on the roster", func(t *testing.T) {
+		mockService.On("ProposeTrade", mock.MatchedBy(func(proposal *models.Trade) bool {
+			return proposal.ProposerTeamID == 3
+		})).Return(nil, trade.ErrInvalidTrade)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/3/trades", bytes.NewBufferString(`{
+			"recipient_team_id": 2,
+			"items": [{"from_user_team_id": 3, "type": "player", "player_id": 9}]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("missing recipient", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/trades", bytes.NewBufferString(`{"items": []}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetTrade(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("GetTrade", 1).Return(&models.Trade{ID: 1, Status: models.TradeStatusExpired}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/trades/1", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService.On("GetTrade", 2).Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/trades/2", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAcceptTrade(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("AcceptTrade", 1, 2).Return(&models.Trade{ID: 1, Status: models.TradeStatusAccepted}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/1/accept", bytes.NewBufferString(`{"user_team_id": 2}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not the recipient", func(t *testing.T) {
+		mockService.On("AcceptTrade", 1, 1).Return(nil, trade.ErrNotTradeParty)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/1/accept", bytes.NewBufferString(`{"user_team_id": 1}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("no longer pending", func(t *testing.T) {
+		mockService.On("AcceptTrade", 2, 2).Return(nil, trade.ErrTradeNotPending)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/2/accept", bytes.NewBufferString(`{"user_team_id": 2}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("squad rule broken", func(t *testing.T) {
+		mockService.On("AcceptTrade", 3, 2).Return(nil, &squad.RuleViolation{
+			Rule:    squad.RuleSquadSize,
+			Limit:   15,
+			Message: "squad cannot have more than 15 players",
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/3/accept", bytes.NewBufferString(`{"user_team_id": 2}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "violation")
+	})
+
+	t.Run("missing user team", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/1/accept", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRejectTrade(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	mockService.On("RejectTrade", 1, 2).Return(&models.Trade{ID: 1, Status: models.TradeStatusRejected}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/trades/1/reject", bytes.NewBufferString(`{"user_team_id": 2}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCounterTrade(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	mockService.On("CounterTrade", 1, mock.MatchedBy(func(counter *models.Trade) bool {
+		return counter.ProposerTeamID == 2 && len(counter.Items) == 1 && counter.Message == "one more"
+	})).Return(&models.Trade{ID: 2, ProposerTeamID: 2, RecipientTeamID: 1, Status: models.TradeStatusPending}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/trades/1/counter", bytes.NewBufferString(`{
+		"user_team_id": 2,
+		"message": "one more",
+		"items": [{"from_user_team_id": 1, "type": "faab", "amount": 10}]
+	}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCancelTrade(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	mockService.On("CancelTrade", 1, 1).Return(&models.Trade{ID: 1, Status: models.TradeStatusCancelled}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/trades/1/cancel", bytes.NewBufferString(`{"user_team_id": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestVetoTrade(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("VetoTrade", 1, 3).Return(&models.Trade{ID: 1, Status: models.TradeStatusInReview, VetoVotes: 1}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/1/veto", bytes.NewBufferString(`{"user_team_id": 3}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.Trade
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.VetoVotes)
+	})
+
+	t.Run("not the commissioner", func(t *testing.T) {
+		mockService.On("VetoTrade", 1, 4).Return(nil, trade.ErrNotTradeParty)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/1/veto", bytes.NewBufferString(`{"user_team_id": 4}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("review closed", func(t *testing.T) {
+		mockService.On("VetoTrade", 2, 3).Return(nil, trade.ErrTradeNotInReview)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/trades/2/veto", bytes.NewBufferString(`{"user_team_id": 3}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestProcessTrades(t *testing.T) {
+	router, mockService := setupTradeHandlerTest(t)
+
+	mockService.On("ProcessTrades", 1).Return([]*models.Trade{
+		{ID: 1, Status: models.TradeStatusAccepted},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/leagues/1/trades/process", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,114 @@
+package transfer
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/services/squad"
+	"go-app/services/transfer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type TransferHandler struct {
+	transferService transfer.TransferService
+}
+
+// NewTransferHandler creates a new TransferHandler instance
+func NewTransferHandler(db *sqlx.DB) *TransferHandler {
+	return &TransferHandler{
+		transferService: transfer.NewTransferService(db),
+	}
+}
+
+// makeTransfersRequest is the request body for making transfers
+type makeTransfersRequest struct {
+	Out []int `json:"out"`
+	In  []int `json:"in"`
+}
+
+// ListTransfers handles GET /api/user-teams/:id/transfers
+func (h *TransferHandler) ListTransfers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	bank, err := h.transferService.GetBank(id)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve bank")
+		return
+	}
+
+	transfers, err := h.transferService.ListTransfers(id)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve transfers")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bank":      bank,
+		"transfers": transfers,
+	})
+}
+
+// MakeTransfers handles POST /api/user-teams/:id/transfers
+func (h *TransferHandler) MakeTransfers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req makeTransfersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	transfers, err := h.transferService.MakeTransfers(id, req.Out, req.In)
+	if err != nil {
+		respondError(c, err, "Failed to make transfers")
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfers)
+}
+
+// respondError maps a transfer service error to a response
+func respondError(c *gin.Context, err error, message string) {
+	var violation *squad.RuleViolation
+	switch {
+	case errors.As(err, &violation):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     violation.Message,
+			"violation": violation,
+		})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User team not found",
+		})
+	case errors.Is(err, transfer.ErrNotBudgetLeague):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, transfer.ErrOverBudget), errors.Is(err, transfer.ErrInvalidTransfer):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package transfer
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/squad"
+	"go-app/services/transfer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTransferHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockTransferService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockTransferService)
+	handler := &TransferHandler{
+		transferService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/user-teams/:id/transfers", handler.ListTransfers)
+	router.POST("/user-teams/:id/transfers", handler.MakeTransfers)
+
+	return router, mockService
+}
+
+func TestListTransfers(t *testing.T) {
+	router, mockService := setupTransferHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		playerInID := 5
+		mockService.On("GetBank", 1).Return(92.0, nil)
+		mockService.On("ListTransfers", 1).Return([]*models.Transfer{
+			{ID: 1, UserTeamID: 1, PlayerInID: &playerInID, PriceIn: 8.0},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/1/transfers", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Bank      float64           `json:"bank"`
+			Transfers []models.Transfer `json:"transfers"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 92.0, response.Bank)
+		assert.Len(t, response.Transfers, 1)
+	})
+
+	t.Run("draft league", func(t *testing.T) {
+		mockService.On("GetBank", 2).Return(0.0, transfer.ErrNotBudgetLeague)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/2/transfers", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("user team not found", func(t *testing.T) {
+		mockService.On("GetBank", 3).Return(0.0, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/3/transfers", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestMakeTransfers(t *testing.T) {
+	router, mockService := setupTransferHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		out, in := 4, 5
+		mockService.On("MakeTransfers", 1, []int{4}, []int{5}).Return([]*models.Transfer{
+			{ID: 1, UserTeamID: 1, PlayerOutID: &out, PlayerInID: &in, PriceOut: 7.0, PriceIn: 8.0},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/transfers", bytes.NewBufferString(`{"out": [4], "in": [5]}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		mockService.On("MakeTransfers", 2, []int(nil), []int{5}).Return(nil, transfer.ErrOverBudget)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/2/transfers", bytes.NewBufferString(`{"in": [5]}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("squad rule broken", func(t *testing.T) {
+		mockService.On("MakeTransfers", 3, []int(nil), []int{6}).Return(nil, &squad.RuleViolation{
+			Rule:    squad.RuleTeamMax,
+			TeamID:  2,
+			Limit:   3,
+			Message: "squad cannot have more than 3 players from team 2",
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/3/transfers", bytes.NewBufferString(`{"in": [6]}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "violation")
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/transfers", bytes.NewBufferString(`{"in": "x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
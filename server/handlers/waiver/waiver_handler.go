@@ -0,0 +1,343 @@
+package waiver
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-app/services/squad"
+	"go-app/services/waiver"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+type WaiverHandler struct {
+	waiverService waiver.WaiverService
+}
+
+// NewWaiverHandler creates a new WaiverHandler instance
+func NewWaiverHandler(db *sqlx.DB) *WaiverHandler {
+	return &WaiverHandler{
+		waiverService: waiver.NewWaiverService(db),
+	}
+}
+
+// submitClaimRequest is the request body for claiming a player on waivers
+type submitClaimRequest struct {
+	PlayerID     int  `json:"player_id" binding:"required"`
+	DropPlayerID *int `json:"drop_player_id"`
+	Bid          int  `json:"bid"`
+}
+
+// addPlayerRequest is the request body for adding a free agent
+type addPlayerRequest struct {
+	PlayerID     int  `json:"player_id" binding:"required"`
+	DropPlayerID *int `json:"drop_player_id"`
+}
+
+// dropPlayerRequest is the request body for dropping a player
+type dropPlayerRequest struct {
+	PlayerID int `json:"player_id" binding:"required"`
+}
+
+// reorderClaimsRequest is the request body for ranking a team's waiver claims
+type reorderClaimsRequest struct {
+	ClaimIDs []int `json:"claim_ids" binding:"required"`
+}
+
+// ListWaivers handles GET /api/leagues/:id/waivers
+func (h *WaiverHandler) ListWaivers(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	waivers, err := h.waiverService.ListWaivers(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve waivers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, waivers)
+}
+
+// GetPriorities handles GET /api/leagues/:id/waiver-priorities
+func (h *WaiverHandler) GetPriorities(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	priorities, err := h.waiverService.GetPriorities(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve waiver priorities",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, priorities)
+}
+
+// ProcessWaivers handles POST /api/leagues/:id/waivers/process
+func (h *WaiverHandler) ProcessWaivers(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	claims, err := h.waiverService.ProcessWaivers(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process waivers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// ListTransactions handles GET /api/leagues/:id/transactions
+func (h *WaiverHandler) ListTransactions(c *gin.Context) {
+	leagueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid league ID",
+		})
+		return
+	}
+
+	transactions, err := h.waiverService.ListTransactions(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve transactions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+// ListClaims handles GET /api/user-teams/:id/waiver-claims
+func (h *WaiverHandler) ListClaims(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	claims, err := h.waiverService.ListClaims(userTeamID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve waiver claims")
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// SubmitClaim handles POST /api/user-teams/:id/waiver-claims
+func (h *WaiverHandler) SubmitClaim(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req submitClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	claim, err := h.waiverService.SubmitClaim(userTeamID, req.PlayerID, req.DropPlayerID, req.Bid)
+	if err != nil {
+		respondError(c, err, "Failed to submit waiver claim")
+		return
+	}
+
+	c.JSON(http.StatusCreated, claim)
+}
+
+// ReorderClaims handles PUT /api/user-teams/:id/waiver-claims/order
+func (h *WaiverHandler) ReorderClaims(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req reorderClaimsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	claims, err := h.waiverService.ReorderClaims(userTeamID, req.ClaimIDs)
+	if err != nil {
+		respondError(c, err, "Failed to reorder waiver claims")
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// CancelClaim handles DELETE /api/user-teams/:id/waiver-claims/:claim_id
+func (h *WaiverHandler) CancelClaim(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	claimID, err := strconv.Atoi(c.Param("claim_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid claim ID",
+		})
+		return
+	}
+
+	if err := h.waiverService.CancelClaim(userTeamID, claimID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Waiver claim not found",
+			})
+			return
+		}
+		respondError(c, err, "Failed to cancel waiver claim")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFAABBalance handles GET /api/user-teams/:id/faab
+func (h *WaiverHandler) GetFAABBalance(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	balance, err := h.waiverService.GetFAABBalance(userTeamID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve FAAB balance")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_team_id": userTeamID,
+		"balance":      balance,
+	})
+}
+
+// AddFreeAgent handles POST /api/user-teams/:id/free-agents
+func (h *WaiverHandler) AddFreeAgent(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req addPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	transaction, err := h.waiverService.AddFreeAgent(userTeamID, req.PlayerID, req.DropPlayerID)
+	if err != nil {
+		respondError(c, err, "Failed to add free agent")
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// DropPlayer handles POST /api/user-teams/:id/drops
+func (h *WaiverHandler) DropPlayer(c *gin.Context) {
+	userTeamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user team ID",
+		})
+		return
+	}
+
+	var req dropPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	transaction, err := h.waiverService.DropPlayer(userTeamID, req.PlayerID)
+	if err != nil {
+		respondError(c, err, "Failed to drop player")
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// respondError maps a waiver service error to a response
+func respondError(c *gin.Context, err error, message string) {
+	var violation *squad.RuleViolation
+	switch {
+	case errors.As(err, &violation):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     violation.Message,
+			"violation": violation,
+		})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User team not found",
+		})
+	case errors.Is(err, waiver.ErrNotDraftLeague), errors.Is(err, waiver.ErrDraftNotCompleted),
+		errors.Is(err, waiver.ErrPlayerOnWaivers), errors.Is(err, waiver.ErrPlayerNotOnWaivers),
+		errors.Is(err, waiver.ErrPlayerOwned), errors.Is(err, waiver.ErrNotFAABLeague):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, waiver.ErrInvalidClaim):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
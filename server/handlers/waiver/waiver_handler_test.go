@@ -0,0 +1,317 @@
+package waiver
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-app/models"
+	"go-app/server/handlers/mocks"
+	"go-app/services/squad"
+	"go-app/services/waiver"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupWaiverHandlerTest(t *testing.T) (*gin.Engine, *mocks.MockWaiverService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := new(mocks.MockWaiverService)
+	handler := &WaiverHandler{
+		waiverService: mockService,
+	}
+
+	// Setup routes
+	router.GET("/leagues/:id/waivers", handler.ListWaivers)
+	router.GET("/leagues/:id/waiver-priorities", handler.GetPriorities)
+	router.POST("/leagues/:id/waivers/process", handler.ProcessWaivers)
+	router.GET("/leagues/:id/transactions", handler.ListTransactions)
+	router.GET("/user-teams/:id/waiver-claims", handler.ListClaims)
+	router.POST("/user-teams/:id/waiver-claims", handler.SubmitClaim)
+	router.PUT("/user-teams/:id/waiver-claims/order", handler.ReorderClaims)
+	router.DELETE("/user-teams/:id/waiver-claims/:claim_id", handler.CancelClaim)
+	router.GET("/user-teams/:id/faab", handler.GetFAABBalance)
+	router.POST("/user-teams/:id/free-agents", handler.AddFreeAgent)
+	router.POST("/user-teams/:id/drops", handler.DropPlayer)
+
+	return router, mockService
+}
+
+func TestListWaivers(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("ListWaivers", 1).Return([]*models.Waiver{
+			{ID: 1, LeagueID: 1, PlayerID: 5},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/1/waivers", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.Waiver
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("invalid league ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/leagues/abc/waivers", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetPriorities(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	mockService.On("GetPriorities", 1).Return([]*models.WaiverPriority{
+		{LeagueID: 1, UserTeamID: 3, Priority: 1},
+		{LeagueID: 1, UserTeamID: 2, Priority: 2},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/leagues/1/waiver-priorities", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.WaiverPriority
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 2)
+}
+
+func TestProcessWaivers(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	mockService.On("ProcessWaivers", 1).Return([]*models.WaiverClaim{
+		{ID: 1, UserTeamID: 3, PlayerID: 5, Status: models.WaiverClaimWon},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/leagues/1/waivers/process", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestListTransactions(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	playerInID := 5
+	mockService.On("ListTransactions", 1).Return([]*models.LeagueTransaction{
+		{ID: 1, LeagueID: 1, UserTeamID: 3, Type: models.LeagueTransactionFreeAgent, PlayerInID: &playerInID},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/leagues/1/transactions", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSubmitClaim(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		dropPlayerID := 4
+		mockService.On("SubmitClaim", 1, 5, &dropPlayerID, 12).Return(&models.WaiverClaim{
+			ID: 1, UserTeamID: 1, PlayerID: 5, DropPlayerID: &dropPlayerID, Rank: 1, Status: models.WaiverClaimPending,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/waiver-claims", bytes.NewBufferString(`{"player_id": 5, "drop_player_id": 4, "bid": 12}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("player not on waivers", func(t *testing.T) {
+		mockService.On("SubmitClaim", 2, 5, (*int)(nil), 0).Return(nil, waiver.ErrPlayerNotOnWaivers)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/2/waiver-claims", bytes.NewBufferString(`{"player_id": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("squad rule broken", func(t *testing.T) {
+		mockService.On("SubmitClaim", 3, 6, (*int)(nil), 0).Return(nil, &squad.RuleViolation{
+			Rule:    squad.RuleSquadSize,
+			Limit:   15,
+			Message: "squad cannot have more than 15 players",
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/3/waiver-claims", bytes.NewBufferString(`{"player_id": 6}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "violation")
+	})
+
+	t.Run("missing player", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/waiver-claims", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestReorderClaims(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("ReorderClaims", 1, []int{2, 1}).Return([]*models.WaiverClaim{
+			{ID: 2, UserTeamID: 1, Rank: 1},
+			{ID: 1, UserTeamID: 1, Rank: 2},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/user-teams/1/waiver-claims/order", bytes.NewBufferString(`{"claim_ids": [2, 1]}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("claims missing", func(t *testing.T) {
+		mockService.On("ReorderClaims", 2, []int{1}).Return(nil, waiver.ErrInvalidClaim)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/user-teams/2/waiver-claims/order", bytes.NewBufferString(`{"claim_ids": [1]}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+func TestCancelClaim(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("CancelClaim", 1, 2).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/user-teams/1/waiver-claims/2", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("claim not found", func(t *testing.T) {
+		mockService.On("CancelClaim", 1, 3).Return(sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/user-teams/1/waiver-claims/3", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetFAABBalance(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("GetFAABBalance", 1).Return(88, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/1/faab", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Balance int `json:"balance"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 88, response.Balance)
+	})
+
+	t.Run("rolling waiver league", func(t *testing.T) {
+		mockService.On("GetFAABBalance", 2).Return(0, waiver.ErrNotFAABLeague)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/user-teams/2/faab", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestAddFreeAgent(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		playerInID := 5
+		mockService.On("AddFreeAgent", 1, 5, (*int)(nil)).Return(&models.LeagueTransaction{
+			ID: 1, UserTeamID: 1, Type: models.LeagueTransactionFreeAgent, PlayerInID: &playerInID,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/free-agents", bytes.NewBufferString(`{"player_id": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("player on waivers", func(t *testing.T) {
+		mockService.On("AddFreeAgent", 2, 5, (*int)(nil)).Return(nil, waiver.ErrPlayerOnWaivers)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/2/free-agents", bytes.NewBufferString(`{"player_id": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestDropPlayer(t *testing.T) {
+	router, mockService := setupWaiverHandlerTest(t)
+
+	t.Run("success", func(t *testing.T) {
+		playerOutID := 5
+		mockService.On("DropPlayer", 1, 5).Return(&models.LeagueTransaction{
+			ID: 1, UserTeamID: 1, Type: models.LeagueTransactionDrop, PlayerOutID: &playerOutID,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/1/drops", bytes.NewBufferString(`{"player_id": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("user team not found", func(t *testing.T) {
+		mockService.On("DropPlayer", 2, 5).Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/user-teams/2/drops", bytes.NewBufferString(`{"player_id": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -4,9 +4,24 @@ import (
 	"fmt"
 	"time"
 
+	"go-app/config"
+	"go-app/server/handlers/audit"
+	"go-app/server/handlers/draft"
+	"go-app/server/handlers/draftroom"
+	"go-app/server/handlers/gameweek"
+	"go-app/server/handlers/incident"
+	"go-app/server/handlers/league"
+	"go-app/server/handlers/lineup"
+	"go-app/server/handlers/mockdraft"
 	"go-app/server/handlers/player"
+	"go-app/server/handlers/pricing"
+	"go-app/server/handlers/scoring"
+	"go-app/server/handlers/squad"
 	"go-app/server/handlers/team"
+	"go-app/server/handlers/trade"
+	"go-app/server/handlers/transfer"
 	"go-app/server/handlers/user"
+	"go-app/server/handlers/waiver"
 
 	//"go-app/server/middleware"
 
@@ -15,16 +30,45 @@ import (
 )
 
 type Handler struct {
-	playerHandler *player.PlayerHandler
-	teamHandler   *team.TeamHandler
-	userHandler   *user.UserHandler
+	auditHandler     *audit.AuditHandler
+	draftHandler     *draft.DraftHandler
+	draftRoomHandler *draftroom.DraftRoomHandler
+	gameweekHandler  *gameweek.GameweekHandler
+	incidentHandler  *incident.IncidentHandler
+	leagueHandler    *league.LeagueHandler
+	lineupHandler    *lineup.LineupHandler
+	mockDraftHandler *mockdraft.MockDraftHandler
+	playerHandler    *player.PlayerHandler
+	pricingHandler   *pricing.PricingHandler
+	scoringHandler   *scoring.ScoringHandler
+	squadHandler     *squad.SquadHandler
+	teamHandler      *team.TeamHandler
+	tradeHandler     *trade.TradeHandler
+	transferHandler  *transfer.TransferHandler
+	waiverHandler    *waiver.WaiverHandler
+	userHandler      *user.UserHandler
 }
 
-func NewHandler(db *sqlx.DB) *Handler {
+func NewHandler(db *sqlx.DB, cfg *config.Config) *Handler {
+	hub := draftroom.NewHub(db)
 	return &Handler{
-		playerHandler: player.NewPlayerHandler(db),
-		teamHandler:   team.NewTeamHandler(db),
-		userHandler:   user.NewUserHandler(db),
+		auditHandler:     audit.NewAuditHandler(db),
+		draftHandler:     draft.NewDraftHandler(db, hub),
+		draftRoomHandler: draftroom.NewDraftRoomHandler(db, hub, cfg.AllowedOrigins),
+		gameweekHandler:  gameweek.NewGameweekHandler(db),
+		incidentHandler:  incident.NewIncidentHandler(db),
+		leagueHandler:    league.NewLeagueHandler(db),
+		lineupHandler:    lineup.NewLineupHandler(db),
+		mockDraftHandler: mockdraft.NewMockDraftHandler(db),
+		playerHandler:    player.NewPlayerHandler(db),
+		pricingHandler:   pricing.NewPricingHandler(db),
+		scoringHandler:   scoring.NewScoringHandler(db),
+		squadHandler:     squad.NewSquadHandler(db),
+		teamHandler:      team.NewTeamHandler(db),
+		tradeHandler:     trade.NewTradeHandler(db),
+		transferHandler:  transfer.NewTransferHandler(db),
+		waiverHandler:    waiver.NewWaiverHandler(db),
+		userHandler:      user.NewUserHandler(db),
 	}
 }
 
@@ -63,9 +107,19 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 		players.GET("/:id", h.playerHandler.GetPlayer)
 		players.GET("/team/:team_id", h.playerHandler.GetPlayersByTeam)
 		players.GET("/:id/stats", h.playerHandler.GetPlayerStats)
+		players.GET("/:id/prices", h.playerHandler.GetPriceHistory)
+		players.PUT("/:id/price", h.playerHandler.UpdatePrice)
+		players.GET("/:id/points", h.scoringHandler.ListPlayerPoints)
 		//players.GET("/search", h.playerHandler.SearchPlayers) // New search endpoint
 	}
 
+	// Price routes
+	prices := r.Group("/prices")
+	{
+		prices.GET("/predictions", h.pricingHandler.GetPredictions)
+		prices.GET("/changes", h.pricingHandler.ListPriceChanges)
+	}
+
 	// Team routes with enhanced features
 	teams := r.Group("/teams")
 	{
@@ -87,4 +141,115 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 		users.DELETE("/:id", h.userHandler.DeleteUser)
 		//users.GET("/me", h.userHandler.GetCurrentUser) // New current user endpoint
 	}
+
+	// Draft routes
+	leagues := r.Group("/leagues")
+	{
+		leagues.GET("/:id/settings", h.leagueHandler.GetSettings)
+		leagues.PUT("/:id/settings", h.leagueHandler.UpdateSettings)
+		leagues.GET("/:id/squad-rules", h.squadHandler.GetRules)
+		leagues.PUT("/:id/squad-rules", h.squadHandler.UpdateRules)
+		leagues.GET("/:id/scoring-rules", h.scoringHandler.GetRules)
+		leagues.PUT("/:id/scoring-rules", h.scoringHandler.UpdateRules)
+		leagues.GET("/:id/standings", h.scoringHandler.GetStandings)
+		leagues.GET("/:id/live", h.scoringHandler.GetLiveScores)
+		leagues.GET("/:id/live/stream", h.scoringHandler.StreamLiveScores)
+		leagues.GET("/:id/stat-corrections", h.incidentHandler.ListCorrections)
+		leagues.POST("/:id/draft", h.draftHandler.CreateDraft)
+		leagues.GET("/:id/draft", h.draftHandler.GetLeagueDraft)
+		leagues.GET("/:id/waivers", h.waiverHandler.ListWaivers)
+		leagues.POST("/:id/waivers/process", h.waiverHandler.ProcessWaivers)
+		leagues.GET("/:id/waiver-priorities", h.waiverHandler.GetPriorities)
+		leagues.GET("/:id/transactions", h.waiverHandler.ListTransactions)
+		leagues.GET("/:id/audit-log", h.auditHandler.ListEntries)
+		leagues.POST("/:id/trades/process", h.tradeHandler.ProcessTrades)
+	}
+	drafts := r.Group("/drafts")
+	{
+		drafts.GET("/:id", h.draftHandler.GetDraft)
+		drafts.POST("/:id/start", h.draftHandler.StartDraft)
+		drafts.GET("/:id/picks", h.draftHandler.ListPicks)
+		drafts.POST("/:id/picks", h.draftHandler.MakePick)
+		drafts.POST("/:id/nominations", h.draftHandler.Nominate)
+		drafts.POST("/:id/bids", h.draftHandler.PlaceBid)
+		drafts.GET("/:id/nomination", h.draftHandler.GetOpenNomination)
+		drafts.POST("/:id/nomination/close", h.draftHandler.CloseNomination)
+		drafts.GET("/:id/budgets", h.draftHandler.GetAuctionBudgets)
+		drafts.GET("/:id/queues/:user_team_id", h.draftHandler.GetQueue)
+		drafts.PUT("/:id/queues/:user_team_id", h.draftHandler.SetQueue)
+		drafts.GET("/:id/room", h.draftRoomHandler.JoinRoom)
+		drafts.POST("/:id/room/tickets", h.draftRoomHandler.CreateTicket)
+	}
+
+	// User team routes
+	userTeams := r.Group("/user-teams")
+	{
+		userTeams.GET("/:id/squad/validate", h.squadHandler.ValidateRoster)
+		userTeams.GET("/:id/lineups/:gameweek_id", h.lineupHandler.GetLineup)
+		userTeams.PUT("/:id/lineups/:gameweek_id", h.lineupHandler.SetLineup)
+		userTeams.GET("/:id/chips", h.lineupHandler.ListChips)
+		userTeams.POST("/:id/chips", h.lineupHandler.PlayChip)
+		userTeams.DELETE("/:id/chips/:gameweek_id", h.lineupHandler.CancelChip)
+		userTeams.GET("/:id/scores/:gameweek_id", h.scoringHandler.GetTeamScore)
+		userTeams.GET("/:id/transfers", h.transferHandler.ListTransfers)
+		userTeams.POST("/:id/transfers", h.transferHandler.MakeTransfers)
+		userTeams.GET("/:id/waiver-claims", h.waiverHandler.ListClaims)
+		userTeams.POST("/:id/waiver-claims", h.waiverHandler.SubmitClaim)
+		userTeams.PUT("/:id/waiver-claims/order", h.waiverHandler.ReorderClaims)
+		userTeams.DELETE("/:id/waiver-claims/:claim_id", h.waiverHandler.CancelClaim)
+		userTeams.GET("/:id/faab", h.waiverHandler.GetFAABBalance)
+		userTeams.POST("/:id/free-agents", h.waiverHandler.AddFreeAgent)
+		userTeams.POST("/:id/drops", h.waiverHandler.DropPlayer)
+		userTeams.GET("/:id/trades", h.tradeHandler.ListTrades)
+		userTeams.POST("/:id/trades", h.tradeHandler.ProposeTrade)
+	}
+
+	// Trade routes
+	trades := r.Group("/trades")
+	{
+		trades.GET("/:id", h.tradeHandler.GetTrade)
+		trades.POST("/:id/accept", h.tradeHandler.AcceptTrade)
+		trades.POST("/:id/reject", h.tradeHandler.RejectTrade)
+		trades.POST("/:id/counter", h.tradeHandler.CounterTrade)
+		trades.POST("/:id/cancel", h.tradeHandler.CancelTrade)
+		trades.POST("/:id/veto", h.tradeHandler.VetoTrade)
+	}
+
+	// Gameweek routes
+	gameweeks := r.Group("/gameweeks")
+	{
+		gameweeks.GET("", h.gameweekHandler.ListGameweeks)
+		gameweeks.GET("/next", h.gameweekHandler.GetNextGameweek)
+		gameweeks.GET("/current", h.gameweekHandler.GetCurrentGameweek)
+		gameweeks.GET("/:id", h.gameweekHandler.GetGameweek)
+		gameweeks.GET("/:id/matches", h.gameweekHandler.ListMatches)
+		gameweeks.POST("", h.gameweekHandler.CreateGameweek)
+		gameweeks.POST("/:id/autosubs", h.lineupHandler.ProcessAutoSubs)
+		gameweeks.POST("/:id/score", h.scoringHandler.ScoreGameweek)
+		gameweeks.GET("/:id/points", h.scoringHandler.ListGameweekPoints)
+	}
+
+	// Match routes
+	matches := r.Group("/matches")
+	{
+		matches.GET("/:id/bonus", h.scoringHandler.ListMatchBonus)
+		matches.GET("/:id/incidents", h.incidentHandler.ListIncidents)
+		matches.POST("/:id/incidents", h.incidentHandler.AddIncident)
+		matches.PUT("/:id/incidents/:incident_id", h.incidentHandler.UpdateIncident)
+		matches.DELETE("/:id/incidents/:incident_id", h.incidentHandler.DeleteIncident)
+	}
+
+	// Scoring preset routes
+	scoringPresets := r.Group("/scoring-presets")
+	{
+		scoringPresets.GET("", h.scoringHandler.ListPresets)
+	}
+
+	// Mock draft routes
+	mockDrafts := r.Group("/mock-drafts")
+	{
+		mockDrafts.POST("", h.mockDraftHandler.CreateMockDraft)
+		mockDrafts.GET("/:id", h.mockDraftHandler.GetMockDraft)
+		mockDrafts.POST("/:id/picks", h.mockDraftHandler.MakePick)
+	}
 }
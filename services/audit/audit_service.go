@@ -0,0 +1,235 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	// DefaultPageSize is how many audit log entries are listed on a page when no size is asked for
+	DefaultPageSize = 50
+	// MaxPageSize is the most audit log entries listed on one page
+	MaxPageSize = 200
+)
+
+// ErrInvalidFilter is returned when an audit log is listed with a filter that cannot match anything
+var ErrInvalidFilter = errors.New("invalid audit log filter")
+
+// actions are the actions an audit log can be filtered by
+var actions = map[models.AuditAction]bool{
+	models.AuditActionDraftPick:            true,
+	models.AuditActionAdd:                  true,
+	models.AuditActionDrop:                 true,
+	models.AuditActionWaiverClaim:          true,
+	models.AuditActionWaiverAdd:            true,
+	models.AuditActionTrade:                true,
+	models.AuditActionTransfer:             true,
+	models.AuditActionFreeHitRevert:        true,
+	models.AuditActionCommissionerOverride: true,
+}
+
+// AuditService defines the interface for reading a league's audit log. Entries are only ever
+// added by the services that change rosters, through Record.
+type AuditService interface {
+	ListEntries(leagueID int, filter *AuditFilter) (*AuditPage, error)
+}
+
+// AuditFilter represents the filter criteria and page for listing a league's audit log
+type AuditFilter struct {
+	UserTeamID      int
+	ActorUserTeamID int
+	Action          models.AuditAction
+	PlayerID        int // entries that moved the player in or out
+	Since           *time.Time
+	Until           *time.Time
+	Page            int
+	PageSize        int
+}
+
+// AuditPage is one page of a league's audit log, latest first
+type AuditPage struct {
+	Entries  []*models.AuditEntry `json:"entries"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int                  `json:"total"`
+}
+
+// Implementation of the AuditService interface
+type auditServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewAuditService creates a new AuditService instance
+func NewAuditService(db *sqlx.DB) AuditService {
+	return &auditServiceImpl{db: db}
+}
+
+// RosterOf returns the players on a user team's roster, in player order. Callers take it before
+// changing a roster so the entry they record can show what it was.
+func RosterOf(q sqlx.Queryer, userTeamID int) (pq.Int64Array, error) {
+	roster := pq.Int64Array{}
+	err := sqlx.Select(q, &roster, "SELECT player_id FROM user_team_players WHERE user_team_id = $1 ORDER BY player_id", userTeamID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading roster: %w", err)
+	}
+	return roster, nil
+}
+
+// PlayerIDs returns the given players as an audit log player list
+func PlayerIDs(ids ...int) pq.Int64Array {
+	players := pq.Int64Array{}
+	for _, id := range ids {
+		players = append(players, int64(id))
+	}
+	return players
+}
+
+// OptionalPlayer returns an audit log player list of the player, or an empty list if there is none
+func OptionalPlayer(id *int) pq.Int64Array {
+	if id == nil {
+		return PlayerIDs()
+	}
+	return PlayerIDs(*id)
+}
+
+// Record appends an entry to its league's audit log. The roster after the change is read from the
+// user team, so Record is called once the change has been made. The league is taken from the user
+// team when it is not given, and the players moved are worked out from the rosters when neither
+// list is given.
+func Record(tx *sqlx.Tx, entry *models.AuditEntry) error {
+	if entry.UserTeamID != nil {
+		if entry.LeagueID == 0 {
+			if err := tx.QueryRow("SELECT league_id FROM user_teams WHERE id = $1", *entry.UserTeamID).Scan(&entry.LeagueID); err != nil {
+				return err
+			}
+		}
+		after, err := RosterOf(tx, *entry.UserTeamID)
+		if err != nil {
+			return err
+		}
+		entry.RosterAfter = after
+	}
+	if entry.RosterBefore == nil {
+		entry.RosterBefore = entry.RosterAfter
+	}
+	if entry.PlayersIn == nil && entry.PlayersOut == nil {
+		entry.PlayersIn = missingFrom(entry.RosterAfter, entry.RosterBefore)
+		entry.PlayersOut = missingFrom(entry.RosterBefore, entry.RosterAfter)
+	}
+	for _, list := range []*pq.Int64Array{&entry.PlayersIn, &entry.PlayersOut, &entry.RosterBefore, &entry.RosterAfter} {
+		if *list == nil {
+			*list = pq.Int64Array{}
+		}
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	err := tx.QueryRow(`
+		INSERT INTO league_audit_log (league_id, user_team_id, actor_user_team_id, action, players_in, players_out,
+			trade_id, waiver_claim_id, draft_pick_id, roster_before, roster_after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`, entry.LeagueID, entry.UserTeamID, entry.ActorUserTeamID, entry.Action, entry.PlayersIn, entry.PlayersOut,
+		entry.TradeID, entry.WaiverClaimID, entry.DraftPickID, entry.RosterBefore, entry.RosterAfter,
+		entry.CreatedAt).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("error recording audit entry: %w", err)
+	}
+	return nil
+}
+
+// missingFrom returns the players in a roster that are not in another
+func missingFrom(roster, other pq.Int64Array) pq.Int64Array {
+	in := make(map[int64]bool, len(other))
+	for _, id := range other {
+		in[id] = true
+	}
+	missing := pq.Int64Array{}
+	for _, id := range roster {
+		if !in[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// ListEntries retrieves a page of a league's audit log, latest first, with the total number of
+// entries that match the filter
+func (s *auditServiceImpl) ListEntries(leagueID int, filter *AuditFilter) (*AuditPage, error) {
+	if filter == nil {
+		filter = &AuditFilter{}
+	}
+	if filter.Page < 0 || filter.PageSize < 0 {
+		return nil, fmt.Errorf("%w: page and page size cannot be negative", ErrInvalidFilter)
+	}
+	if filter.Action != "" && !actions[filter.Action] {
+		return nil, fmt.Errorf("%w: unknown action %s", ErrInvalidFilter, filter.Action)
+	}
+	if filter.Since != nil && filter.Until != nil && filter.Until.Before(*filter.Since) {
+		return nil, fmt.Errorf("%w: until is before since", ErrInvalidFilter)
+	}
+	page := &AuditPage{Page: filter.Page, PageSize: filter.PageSize, Entries: []*models.AuditEntry{}}
+	if page.Page == 0 {
+		page.Page = 1
+	}
+	if page.PageSize == 0 {
+		page.PageSize = DefaultPageSize
+	}
+	if page.PageSize > MaxPageSize {
+		page.PageSize = MaxPageSize
+	}
+
+	where := " WHERE league_id = $1"
+	args := []interface{}{leagueID}
+	argCount := 2
+
+	if filter.UserTeamID != 0 {
+		where += fmt.Sprintf(" AND user_team_id = $%d", argCount)
+		args = append(args, filter.UserTeamID)
+		argCount++
+	}
+	if filter.ActorUserTeamID != 0 {
+		where += fmt.Sprintf(" AND actor_user_team_id = $%d", argCount)
+		args = append(args, filter.ActorUserTeamID)
+		argCount++
+	}
+	if filter.Action != "" {
+		where += fmt.Sprintf(" AND action = $%d", argCount)
+		args = append(args, filter.Action)
+		argCount++
+	}
+	if filter.PlayerID != 0 {
+		where += fmt.Sprintf(" AND ($%d = ANY(players_in) OR $%d = ANY(players_out))", argCount, argCount)
+		args = append(args, filter.PlayerID)
+		argCount++
+	}
+	if filter.Since != nil {
+		where += fmt.Sprintf(" AND created_at >= $%d", argCount)
+		args = append(args, *filter.Since)
+		argCount++
+	}
+	if filter.Until != nil {
+		where += fmt.Sprintf(" AND created_at < $%d", argCount)
+		args = append(args, *filter.Until)
+		argCount++
+	}
+
+	if err := s.db.Get(&page.Total, "SELECT COUNT(*) FROM league_audit_log"+where, args...); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT * FROM league_audit_log" + where +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	args = append(args, page.PageSize, (page.Page-1)*page.PageSize)
+	if err := s.db.Select(&page.Entries, query, args...); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB       *database.TestDB
+	auditService AuditService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	auditService = NewAuditService(testDB.GetDB())
+	m.Run()
+}
+
+func TestAuditService(t *testing.T) {
+	db := testDB.GetDB()
+
+	// createLeague inserts a league with two user teams
+	createLeague := func() (int, []int) {
+		now := time.Now()
+		var leagueID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("AUDIT%d", now.UnixNano()), "Audit League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+
+		userTeamIDs := make([]int, 2)
+		for i := range userTeamIDs {
+			var userID int
+			err := db.QueryRow(`
+				INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, "Audit", "Manager", fmt.Sprintf("audit_%d_%d@example.com", now.UnixNano(), i), "password", now, now).Scan(&userID)
+			assert.NoError(t, err)
+			err = db.QueryRow(`
+				INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, userID, leagueID, fmt.Sprintf("Audit Team %d", i), now, now).Scan(&userTeamIDs[i])
+			assert.NoError(t, err)
+		}
+		return leagueID, userTeamIDs
+	}
+
+	// createPlayers inserts forwards on separate teams
+	createPlayers := func(count int) []int {
+		now := time.Now()
+		ids := make([]int, count)
+		for i := range ids {
+			var teamID int
+			err := db.QueryRow(`
+				INSERT INTO teams (name, external_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id
+			`, fmt.Sprintf("Audit Club %d", i), now.UnixNano()%100000+int64(i), now, now).Scan(&teamID)
+			assert.NoError(t, err)
+			err = db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", i), models.PositionFWD, now, now).Scan(&ids[i])
+			assert.NoError(t, err)
+		}
+		return ids
+	}
+
+	// add adds a player to a user team and records it at the given time
+	add := func(userTeamID, playerID int, at time.Time) {
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		before, err := RosterOf(tx, userTeamID)
+		assert.NoError(t, err)
+		_, err = tx.Exec(`
+			INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+		`, userTeamID, playerID, at)
+		assert.NoError(t, err)
+		err = Record(tx, &models.AuditEntry{
+			UserTeamID:      &userTeamID,
+			ActorUserTeamID: &userTeamID,
+			Action:          models.AuditActionAdd,
+			RosterBefore:    before,
+			CreatedAt:       at,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+	}
+
+	t.Run("entries record the rosters either side of a change", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague()
+		playerIDs := createPlayers(2)
+		now := time.Now()
+		add(teams[0], playerIDs[0], now)
+		add(teams[0], playerIDs[1], now.Add(time.Minute))
+
+		page, err := auditService.ListEntries(leagueID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
+		if assert.Len(t, page.Entries, 2) {
+			latest := page.Entries[0]
+			assert.Equal(t, leagueID, latest.LeagueID)
+			assert.Equal(t, PlayerIDs(playerIDs[1]), latest.PlayersIn)
+			assert.Equal(t, PlayerIDs(), latest.PlayersOut)
+			assert.Equal(t, PlayerIDs(playerIDs[0]), latest.RosterBefore)
+			assert.Equal(t, PlayerIDs(playerIDs[0], playerIDs[1]), latest.RosterAfter)
+			assert.Equal(t, PlayerIDs(), page.Entries[1].RosterBefore)
+		}
+	})
+
+	t.Run("entries are filtered and paged", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague()
+		playerIDs := createPlayers(5)
+		start := time.Now().Add(-time.Hour)
+		for i, playerID := range playerIDs {
+			add(teams[i%2], playerID, start.Add(time.Duration(i)*time.Minute))
+		}
+
+		page, err := auditService.ListEntries(leagueID, &AuditFilter{Page: 2, PageSize: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, page.Total)
+		if assert.Len(t, page.Entries, 2) {
+			assert.Equal(t, PlayerIDs(playerIDs[2]), page.Entries[0].PlayersIn)
+			assert.Equal(t, PlayerIDs(playerIDs[1]), page.Entries[1].PlayersIn)
+		}
+
+		page, err = auditService.ListEntries(leagueID, &AuditFilter{UserTeamID: teams[1]})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
+
+		page, err = auditService.ListEntries(leagueID, &AuditFilter{PlayerID: playerIDs[4]})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+
+		since := start.Add(90 * time.Second)
+		until := start.Add(210 * time.Second)
+		page, err = auditService.ListEntries(leagueID, &AuditFilter{Since: &since, Until: &until})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
+
+		page, err = auditService.ListEntries(leagueID, &AuditFilter{Action: models.AuditActionTrade})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, page.Total)
+		assert.Empty(t, page.Entries)
+
+		_, err = auditService.ListEntries(leagueID, &AuditFilter{Since: &until, Until: &since})
+		assert.ErrorIs(t, err, ErrInvalidFilter)
+	})
+}
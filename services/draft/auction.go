@@ -0,0 +1,488 @@
+package draft
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/squad"
+	"go-app/services/waiver"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrNominationOpen is returned when a player is nominated while another is still up for auction
+	ErrNominationOpen = errors.New("another player is already up for auction")
+	// ErrNoOpenNomination is returned when bidding or closing without a player up for auction
+	ErrNoOpenNomination = errors.New("no player is up for auction")
+	// ErrBidTooLow is returned when a bid does not beat the current bid or the minimum bid
+	ErrBidTooLow = errors.New("bid is too low")
+	// ErrBidExceedsMax is returned when a bid would leave a team unable to fill its remaining slots
+	ErrBidExceedsMax = errors.New("bid exceeds the maximum this team can afford")
+	// ErrAlreadyHighBidder is returned when a team bids against itself
+	ErrAlreadyHighBidder = errors.New("team already holds the highest bid")
+	// ErrBidClockRunning is returned when closing a nomination before its bid clock has run out
+	ErrBidClockRunning = errors.New("bid clock has not run out")
+	// ErrBidClockExpired is returned when bidding after the bid clock has run out
+	ErrBidClockExpired = errors.New("bid clock has run out")
+)
+
+// AuctionSettings holds the rules an auction draft is run with
+type AuctionSettings struct {
+	RosterSlots    int
+	Budget         float64
+	MinBid         float64
+	BidTimeSeconds int
+}
+
+// TeamBudget summarises a team's spending during an auction draft
+type TeamBudget struct {
+	UserTeamID     int     `db:"user_team_id" json:"user_team_id"`
+	Spent          float64 `db:"spent" json:"spent"`
+	SlotsFilled    int     `db:"slots_filled" json:"slots_filled"`
+	Remaining      float64 `db:"-" json:"remaining"`
+	SlotsRemaining int     `db:"-" json:"slots_remaining"`
+	MaxBid         float64 `db:"-" json:"max_bid"`
+}
+
+// roundPrice rounds a price to the single decimal place prices are stored with
+func roundPrice(price float64) float64 {
+	return math.Round(price*10) / 10
+}
+
+// CreateAuctionDraft creates a new auction draft for a league with the given nomination order
+func (s *draftServiceImpl) CreateAuctionDraft(leagueID int, userTeamIDs []int, settings AuctionSettings) (*models.Draft, error) {
+	if settings.Budget <= 0 {
+		return nil, fmt.Errorf("budget must be greater than zero")
+	}
+	if settings.MinBid <= 0 {
+		return nil, fmt.Errorf("minimum bid must be greater than zero")
+	}
+	if settings.BidTimeSeconds <= 0 {
+		return nil, fmt.Errorf("bid time must be greater than zero")
+	}
+	if settings.MinBid*float64(settings.RosterSlots) > settings.Budget {
+		return nil, fmt.Errorf("budget cannot fill %d roster slots at the minimum bid", settings.RosterSlots)
+	}
+
+	now := time.Now()
+	draft := &models.Draft{
+		LeagueID:       leagueID,
+		Type:           models.DraftTypeAuction,
+		Status:         models.DraftStatusScheduled,
+		Rounds:         settings.RosterSlots,
+		CurrentRound:   1,
+		CurrentPick:    1,
+		Budget:         roundPrice(settings.Budget),
+		MinBid:         roundPrice(settings.MinBid),
+		BidTimeSeconds: settings.BidTimeSeconds,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.createDraft(draft, userTeamIDs); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// Nominate puts a player up for auction with an opening bid from the nominating team
+func (s *draftServiceImpl) Nominate(draftID, userTeamID, playerID int, openingBid float64) (*models.DraftNomination, error) {
+	openingBid = roundPrice(openingBid)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draft, err := lockDraft(tx, draftID, models.DraftTypeAuction)
+	if err != nil {
+		return nil, err
+	}
+
+	nomination, err := nominate(tx, draft, userTeamID, playerID, openingBid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing nomination: %w", err)
+	}
+
+	return nomination, nil
+}
+
+// nominate puts a player up for auction in a draft locked by the caller
+func nominate(tx *sqlx.Tx, draft *models.Draft, userTeamID, playerID int, openingBid float64) (*models.DraftNomination, error) {
+	var order []int
+	if err := tx.Select(&order, "SELECT user_team_id FROM draft_order WHERE draft_id = $1 ORDER BY position", draft.ID); err != nil {
+		return nil, err
+	}
+	if teamOnTheClock(draft, order) != userTeamID {
+		return nil, ErrNotYourTurn
+	}
+
+	var count int
+	err := tx.QueryRow("SELECT COUNT(*) FROM draft_nominations WHERE draft_id = $1 AND status = $2", draft.ID, models.NominationStatusOpen).Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrNominationOpen
+	}
+
+	if err := checkPlayerAvailable(tx, draft.LeagueID, playerID); err != nil {
+		return nil, err
+	}
+	if err := squad.ValidateRosterChange(tx, userTeamID, []int{playerID}, nil); err != nil {
+		return nil, err
+	}
+
+	budget, err := teamBudget(tx, draft, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	if openingBid < draft.MinBid {
+		return nil, ErrBidTooLow
+	}
+	if openingBid > budget.MaxBid {
+		return nil, ErrBidExceedsMax
+	}
+
+	now := time.Now()
+	nomination := &models.DraftNomination{
+		DraftID:       draft.ID,
+		PlayerID:      playerID,
+		NominatedBy:   userTeamID,
+		CurrentBid:    openingBid,
+		CurrentBidder: userTeamID,
+		BidDeadline:   now.Add(time.Duration(draft.BidTimeSeconds) * time.Second),
+		Status:        models.NominationStatusOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO draft_nominations (draft_id, player_id, nominated_by, current_bid, current_bidder, bid_deadline, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, nomination.DraftID, nomination.PlayerID, nomination.NominatedBy, nomination.CurrentBid, nomination.CurrentBidder,
+		nomination.BidDeadline, nomination.Status, nomination.CreatedAt, nomination.UpdatedAt).Scan(&nomination.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating nomination: %w", err)
+	}
+
+	if err := insertBid(tx, nomination.ID, userTeamID, openingBid, now); err != nil {
+		return nil, err
+	}
+
+	// The bid clock takes over from the nominating team's pick clock
+	_, err = tx.Exec("UPDATE drafts SET pick_deadline = NULL, updated_at = $1 WHERE id = $2", now, draft.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error stopping pick clock: %w", err)
+	}
+
+	return nomination, nil
+}
+
+// PlaceBid raises the bid on the player currently up for auction and restarts the bid clock
+func (s *draftServiceImpl) PlaceBid(draftID, userTeamID int, amount float64) (*models.DraftNomination, error) {
+	amount = roundPrice(amount)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draft, err := lockDraft(tx, draftID, models.DraftTypeAuction)
+	if err != nil {
+		return nil, err
+	}
+
+	nomination, err := openNomination(tx, draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !now.Before(nomination.BidDeadline) {
+		return nil, ErrBidClockExpired
+	}
+	if nomination.CurrentBidder == userTeamID {
+		return nil, ErrAlreadyHighBidder
+	}
+	if amount <= nomination.CurrentBid || amount < draft.MinBid {
+		return nil, ErrBidTooLow
+	}
+	if err := squad.ValidateRosterChange(tx, userTeamID, []int{nomination.PlayerID}, nil); err != nil {
+		return nil, err
+	}
+
+	budget, err := teamBudget(tx, draft, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	if amount > budget.MaxBid {
+		return nil, ErrBidExceedsMax
+	}
+
+	nomination.CurrentBid = amount
+	nomination.CurrentBidder = userTeamID
+	nomination.BidDeadline = now.Add(time.Duration(draft.BidTimeSeconds) * time.Second)
+	nomination.UpdatedAt = now
+
+	_, err = tx.Exec(`
+		UPDATE draft_nominations
+		SET current_bid = $1, current_bidder = $2, bid_deadline = $3, updated_at = $4
+		WHERE id = $5
+	`, nomination.CurrentBid, nomination.CurrentBidder, nomination.BidDeadline, nomination.UpdatedAt, nomination.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error updating nomination: %w", err)
+	}
+
+	if err := insertBid(tx, nomination.ID, userTeamID, amount, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing bid: %w", err)
+	}
+
+	return nomination, nil
+}
+
+// GetOpenNomination retrieves the player currently up for auction, or nil if there is none
+func (s *draftServiceImpl) GetOpenNomination(draftID int) (*models.DraftNomination, error) {
+	nomination := &models.DraftNomination{}
+	err := s.db.Get(nomination, "SELECT * FROM draft_nominations WHERE draft_id = $1 AND status = $2", draftID, models.NominationStatusOpen)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return nomination, nil
+}
+
+// CloseNomination awards the player up for auction to the highest bidder once the bid clock has run out
+func (s *draftServiceImpl) CloseNomination(draftID int) (*models.DraftPick, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draft, err := lockDraft(tx, draftID, models.DraftTypeAuction)
+	if err != nil {
+		return nil, err
+	}
+
+	nomination, err := openNomination(tx, draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Before(nomination.BidDeadline) {
+		return nil, ErrBidClockRunning
+	}
+
+	budgets, err := teamBudgets(tx, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	var sold int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM draft_picks WHERE draft_id = $1", draftID).Scan(&sold); err != nil {
+		return nil, err
+	}
+
+	var winner *TeamBudget
+	for _, budget := range budgets {
+		if budget.UserTeamID == nomination.CurrentBidder {
+			winner = budget
+		}
+	}
+	if winner == nil {
+		return nil, fmt.Errorf("user team %d is not part of draft %d", nomination.CurrentBidder, draftID)
+	}
+
+	before, err := audit.RosterOf(tx, nomination.CurrentBidder)
+	if err != nil {
+		return nil, err
+	}
+	pick := &models.DraftPick{
+		DraftID:     draftID,
+		UserTeamID:  nomination.CurrentBidder,
+		PlayerID:    nomination.PlayerID,
+		Round:       winner.SlotsFilled + 1,
+		Pick:        draft.CurrentPick,
+		OverallPick: sold + 1,
+		Price:       nomination.CurrentBid,
+		CreatedAt:   now,
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO draft_picks (draft_id, user_team_id, player_id, round, pick, overall_pick, price, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, pick.DraftID, pick.UserTeamID, pick.PlayerID, pick.Round, pick.Pick, pick.OverallPick, pick.Price, pick.CreatedAt).Scan(&pick.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error recording pick: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO user_team_players (user_team_id, player_id, purchase_price, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, pick.UserTeamID, pick.PlayerID, pick.Price, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("error adding player to roster: %w", err)
+	}
+	// The winning bid is the team's own, so it is the actor
+	err = audit.Record(tx, &models.AuditEntry{
+		LeagueID:        draft.LeagueID,
+		UserTeamID:      &pick.UserTeamID,
+		ActorUserTeamID: &pick.UserTeamID,
+		Action:          models.AuditActionDraftPick,
+		PlayersIn:       audit.PlayerIDs(pick.PlayerID),
+		DraftPickID:     &pick.ID,
+		RosterBefore:    before,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE draft_nominations
+		SET status = $1, updated_at = $2
+		WHERE id = $3
+	`, models.NominationStatusSold, now, nomination.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error closing nomination: %w", err)
+	}
+
+	// Pass the nomination to the next team that still has slots to fill
+	winner.SlotsFilled++
+	draft.Status = models.DraftStatusCompleted
+	for i := 1; i <= len(budgets); i++ {
+		next := budgets[(draft.CurrentPick-1+i)%len(budgets)]
+		if next.SlotsFilled < draft.Rounds {
+			draft.CurrentPick = (draft.CurrentPick-1+i)%len(budgets) + 1
+			draft.Status = models.DraftStatusInProgress
+			break
+		}
+	}
+
+	draft.PickDeadline = nil
+	if draft.Status == models.DraftStatusInProgress {
+		draft.PickDeadline, err = nextPickDeadline(tx, draft.LeagueID, now)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := waiver.PlaceUndraftedOnWaivers(tx, draft.LeagueID, now); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE drafts
+		SET status = $1, current_pick = $2, pick_deadline = $3, updated_at = $4
+		WHERE id = $5
+	`, draft.Status, draft.CurrentPick, draft.PickDeadline, now, draft.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error advancing draft: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing sale: %w", err)
+	}
+
+	return pick, nil
+}
+
+// GetAuctionBudgets retrieves the remaining budget and maximum bid of every team in an auction draft
+func (s *draftServiceImpl) GetAuctionBudgets(draftID int) ([]*TeamBudget, error) {
+	draft, err := s.GetDraft(draftID)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Type != models.DraftTypeAuction {
+		return nil, ErrWrongDraftType
+	}
+	return teamBudgets(s.db, draft)
+}
+
+// openNomination locks the open nomination of a draft
+func openNomination(tx *sqlx.Tx, draftID int) (*models.DraftNomination, error) {
+	nomination := &models.DraftNomination{}
+	err := tx.Get(nomination, "SELECT * FROM draft_nominations WHERE draft_id = $1 AND status = $2 FOR UPDATE", draftID, models.NominationStatusOpen)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoOpenNomination
+		}
+		return nil, err
+	}
+	return nomination, nil
+}
+
+// insertBid records a bid in the nomination's bid history
+func insertBid(tx *sqlx.Tx, nominationID, userTeamID int, amount float64, placedAt time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO draft_bids (nomination_id, user_team_id, amount, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, nominationID, userTeamID, amount, placedAt)
+	if err != nil {
+		return fmt.Errorf("error recording bid: %w", err)
+	}
+	return nil
+}
+
+// teamBudgets calculates the spending position of every team in draft order
+func teamBudgets(q sqlx.Queryer, draft *models.Draft) ([]*TeamBudget, error) {
+	var budgets []*TeamBudget
+	err := sqlx.Select(q, &budgets, `
+		SELECT o.user_team_id, COALESCE(SUM(p.price), 0) AS spent, COUNT(p.id) AS slots_filled
+		FROM draft_order o
+		LEFT JOIN draft_picks p ON p.draft_id = o.draft_id AND p.user_team_id = o.user_team_id
+		WHERE o.draft_id = $1
+		GROUP BY o.user_team_id, o.position
+		ORDER BY o.position
+	`, draft.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, budget := range budgets {
+		budget.Remaining = roundPrice(draft.Budget - budget.Spent)
+		budget.SlotsRemaining = draft.Rounds - budget.SlotsFilled
+		if budget.SlotsRemaining > 0 {
+			// Keep back the minimum bid for every other slot still to fill
+			budget.MaxBid = roundPrice(budget.Remaining - draft.MinBid*float64(budget.SlotsRemaining-1))
+		}
+	}
+	return budgets, nil
+}
+
+// teamBudget calculates the spending position of a single team in a draft
+func teamBudget(q sqlx.Queryer, draft *models.Draft, userTeamID int) (*TeamBudget, error) {
+	budgets, err := teamBudgets(q, draft)
+	if err != nil {
+		return nil, err
+	}
+	for _, budget := range budgets {
+		if budget.UserTeamID == userTeamID {
+			if budget.SlotsRemaining <= 0 {
+				return nil, ErrRosterFull
+			}
+			return budget, nil
+		}
+	}
+	return nil, fmt.Errorf("user team %d is not part of draft %d", userTeamID, draft.ID)
+}
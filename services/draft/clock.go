@@ -0,0 +1,314 @@
+package draft
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+	"go-app/services/league"
+	"go-app/services/squad"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultRankingScore is the SQL expression players are ranked by when a team has no
+// queue to draft from. It scores each player's output so far from the player_stats table
+// aliased as ps.
+const DefaultRankingScore = "COALESCE(SUM(ps.goals * 5 + ps.assists * 3 + ps.clean_sheets * 4 - ps.yellow_cards - ps.red_cards * 3), 0)"
+
+// ErrNoPlayersAvailable is returned when an autopick finds no undrafted players left
+var ErrNoPlayersAvailable = errors.New("no players are left to pick")
+
+// PickDeadline returns when a pick clock started at start runs out under the league's settings.
+// The clock does not run during the overnight pause. A nil deadline means the league has no pick clock.
+func PickDeadline(start time.Time, settings *models.LeagueSettings) (*time.Time, error) {
+	if settings.PickTimeSeconds <= 0 {
+		return nil, nil
+	}
+	remaining := time.Duration(settings.PickTimeSeconds) * time.Second
+
+	if settings.PauseStart == "" {
+		deadline := start.Add(remaining)
+		return &deadline, nil
+	}
+
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %s", settings.Timezone)
+	}
+	pauseStart, err := league.ParseClockTime(settings.PauseStart)
+	if err != nil {
+		return nil, err
+	}
+	pauseEnd, err := league.ParseClockTime(settings.PauseEnd)
+	if err != nil {
+		return nil, err
+	}
+	pauseLength := pauseEnd - pauseStart
+	if pauseLength <= 0 {
+		// The pause runs past midnight
+		pauseLength += 24 * time.Hour
+	}
+
+	// pauseOn returns the pause that starts on the given day offset from t
+	pauseOn := func(t time.Time, days int) (time.Time, time.Time) {
+		year, month, day := t.In(loc).Date()
+		begin := time.Date(year, month, day+days, 0, 0, 0, 0, loc).Add(pauseStart)
+		return begin, begin.Add(pauseLength)
+	}
+
+	t := start
+	for {
+		// Skip to the end of any pause the clock is currently in
+		for _, days := range []int{-1, 0} {
+			begin, end := pauseOn(t, days)
+			if !t.Before(begin) && t.Before(end) {
+				t = end
+			}
+		}
+
+		next, _ := pauseOn(t, 0)
+		if !next.After(t) {
+			next, _ = pauseOn(t, 1)
+		}
+
+		if !t.Add(remaining).After(next) {
+			deadline := t.Add(remaining)
+			return &deadline, nil
+		}
+		remaining -= next.Sub(t)
+		t = next
+	}
+}
+
+// nextPickDeadline returns the deadline of a pick clock started now in the given league
+func nextPickDeadline(q sqlx.Queryer, leagueID int, now time.Time) (*time.Time, error) {
+	settings, err := league.LoadSettings(q, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading league settings: %w", err)
+	}
+	return PickDeadline(now, settings)
+}
+
+// SetQueue replaces a team's ranked draft queue with the given players, best first
+func (s *draftServiceImpl) SetQueue(draftID, userTeamID int, playerIDs []int) ([]*models.DraftQueueEntry, error) {
+	seen := make(map[int]bool, len(playerIDs))
+	for _, id := range playerIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("player %d appears more than once in the queue", id)
+		}
+		seen[id] = true
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	err = tx.QueryRow("SELECT COUNT(*) FROM draft_order WHERE draft_id = $1 AND user_team_id = $2", draftID, userTeamID).Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("user team %d is not part of draft %d", userTeamID, draftID)
+	}
+
+	_, err = tx.Exec("DELETE FROM draft_queues WHERE draft_id = $1 AND user_team_id = $2", draftID, userTeamID)
+	if err != nil {
+		return nil, fmt.Errorf("error clearing draft queue: %w", err)
+	}
+
+	for i, playerID := range playerIDs {
+		_, err := tx.Exec(`
+			INSERT INTO draft_queues (draft_id, user_team_id, player_id, rank)
+			VALUES ($1, $2, $3, $4)
+		`, draftID, userTeamID, playerID, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("error adding player %d to draft queue: %w", playerID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing draft queue: %w", err)
+	}
+
+	return s.GetQueue(draftID, userTeamID)
+}
+
+// GetQueue retrieves a team's ranked draft queue
+func (s *draftServiceImpl) GetQueue(draftID, userTeamID int) ([]*models.DraftQueueEntry, error) {
+	queue := []*models.DraftQueueEntry{}
+	err := s.db.Select(&queue, `
+		SELECT * FROM draft_queues
+		WHERE draft_id = $1 AND user_team_id = $2
+		ORDER BY rank
+	`, draftID, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// ProcessExpiredClocks acts for every team whose clock has run out. Teams out of time on
+// a pick are given the best available player from their queue, and auction bid clocks that
+// have run out are closed. It returns the drafts that moved on.
+func (s *draftServiceImpl) ProcessExpiredClocks() ([]int, error) {
+	now := time.Now()
+
+	var draftIDs []int
+	err := s.db.Select(&draftIDs, `
+		SELECT id FROM drafts
+		WHERE status = $1 AND pick_deadline <= $2
+		UNION
+		SELECT n.draft_id FROM draft_nominations n
+		JOIN drafts d ON d.id = n.draft_id
+		WHERE d.status = $1 AND n.status = $3 AND n.bid_deadline <= $2
+		ORDER BY 1
+	`, models.DraftStatusInProgress, now, models.NominationStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	var processed []int
+	var errs []error
+	for _, draftID := range draftIDs {
+		moved, err := s.expireClock(draftID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("draft %d: %w", draftID, err))
+			continue
+		}
+		if moved {
+			processed = append(processed, draftID)
+		}
+	}
+
+	return processed, errors.Join(errs...)
+}
+
+// expireClock makes the autopick or closes the auction for a draft whose clock has run out
+func (s *draftServiceImpl) expireClock(draftID int, now time.Time) (bool, error) {
+	nomination, err := s.GetOpenNomination(draftID)
+	if err != nil {
+		return false, err
+	}
+	if nomination != nil {
+		_, err := s.CloseNomination(draftID)
+		if errors.Is(err, ErrBidClockRunning) || errors.Is(err, ErrNoOpenNomination) {
+			// Someone bid or the nomination was closed since we looked
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Check the clock again now the draft is locked, as the pick may have been made since we looked
+	draft := &models.Draft{}
+	if err := tx.Get(draft, "SELECT * FROM drafts WHERE id = $1 FOR UPDATE", draftID); err != nil {
+		return false, err
+	}
+	if draft.Status != models.DraftStatusInProgress || draft.PickDeadline == nil || now.Before(*draft.PickDeadline) {
+		return false, nil
+	}
+
+	var order []int
+	if err := tx.Select(&order, "SELECT user_team_id FROM draft_order WHERE draft_id = $1 ORDER BY position", draft.ID); err != nil {
+		return false, err
+	}
+	userTeamID, err := pickingTeam(tx, draft, order)
+	if err != nil {
+		return false, err
+	}
+
+	playerID, err := bestAvailablePlayer(tx, draft, userTeamID)
+	if err != nil {
+		return false, err
+	}
+
+	if draft.Type == models.DraftTypeAuction {
+		_, err = nominate(tx, draft, userTeamID, playerID, draft.MinBid)
+	} else {
+		_, err = makePick(tx, draft, userTeamID, playerID, nil)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing autopick: %w", err)
+	}
+
+	return true, nil
+}
+
+// bestAvailablePlayer returns the highest ranked undrafted player in a team's queue that fits
+// the league's squad rules, falling back to the default ranking when nothing in the queue does
+func bestAvailablePlayer(tx *sqlx.Tx, draft *models.Draft, userTeamID int) (int, error) {
+	rules, roster, err := squad.LoadSquad(tx, userTeamID)
+	if err != nil {
+		return 0, err
+	}
+
+	var queued []*models.Player
+	err = tx.Select(&queued, `
+		SELECT p.*
+		FROM draft_queues q
+		JOIN players p ON p.id = q.player_id
+		WHERE q.draft_id = $1 AND q.user_team_id = $2
+		AND NOT EXISTS (
+			SELECT 1
+			FROM user_team_players utp
+			JOIN user_teams ut ON ut.id = utp.user_team_id
+			WHERE ut.league_id = $3 AND utp.player_id = q.player_id
+		)
+		ORDER BY q.rank
+	`, draft.ID, userTeamID, draft.LeagueID)
+	if err != nil {
+		return 0, err
+	}
+	if player := firstFitting(rules, roster, queued); player != nil {
+		return player.ID, nil
+	}
+
+	var ranked []*models.Player
+	err = tx.Select(&ranked, `
+		SELECT p.*
+		FROM players p
+		LEFT JOIN player_stats ps ON ps.player_id = p.id
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM user_team_players utp
+			JOIN user_teams ut ON ut.id = utp.user_team_id
+			WHERE ut.league_id = $1 AND utp.player_id = p.id
+		)
+		GROUP BY p.id
+		ORDER BY `+DefaultRankingScore+` DESC, p.id
+	`, draft.LeagueID)
+	if err != nil {
+		return 0, err
+	}
+	if player := firstFitting(rules, roster, ranked); player != nil {
+		return player.ID, nil
+	}
+	return 0, ErrNoPlayersAvailable
+}
+
+// firstFitting returns the first candidate that can join the roster without breaking the squad rules
+func firstFitting(rules *models.SquadRules, roster []*models.Player, candidates []*models.Player) *models.Player {
+	squadWith := make([]*models.Player, len(roster)+1)
+	copy(squadWith, roster)
+	for _, candidate := range candidates {
+		squadWith[len(roster)] = candidate
+		if squad.CheckSquad(rules, squadWith) == nil {
+			return candidate
+		}
+	}
+	return nil
+}
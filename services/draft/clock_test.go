@@ -0,0 +1,170 @@
+package draft
+
+import (
+	"testing"
+	"time"
+
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickDeadline(t *testing.T) {
+	start := time.Date(2024, 8, 10, 20, 0, 0, 0, time.UTC)
+
+	t.Run("no pick clock", func(t *testing.T) {
+		deadline, err := PickDeadline(start, &models.LeagueSettings{Timezone: "UTC"})
+		assert.NoError(t, err)
+		assert.Nil(t, deadline)
+	})
+
+	t.Run("no pause", func(t *testing.T) {
+		deadline, err := PickDeadline(start, &models.LeagueSettings{PickTimeSeconds: 90, Timezone: "UTC"})
+		assert.NoError(t, err)
+		assert.Equal(t, start.Add(90*time.Second), *deadline)
+	})
+
+	settings := &models.LeagueSettings{
+		PickTimeSeconds: 3600,
+		PauseStart:      "23:00",
+		PauseEnd:        "08:00",
+		Timezone:        "UTC",
+	}
+
+	t.Run("clock finishes before the pause", func(t *testing.T) {
+		deadline, err := PickDeadline(start, settings)
+		assert.NoError(t, err)
+		assert.Equal(t, start.Add(time.Hour), *deadline)
+	})
+
+	t.Run("clock stops overnight", func(t *testing.T) {
+		deadline, err := PickDeadline(time.Date(2024, 8, 10, 22, 30, 0, 0, time.UTC), settings)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 8, 11, 8, 30, 0, 0, time.UTC), *deadline)
+	})
+
+	t.Run("clock starts during the pause", func(t *testing.T) {
+		deadline, err := PickDeadline(time.Date(2024, 8, 11, 2, 0, 0, 0, time.UTC), settings)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 8, 11, 9, 0, 0, 0, time.UTC), *deadline)
+	})
+
+	t.Run("daytime pause", func(t *testing.T) {
+		deadline, err := PickDeadline(start, &models.LeagueSettings{
+			PickTimeSeconds: 7200,
+			PauseStart:      "21:00",
+			PauseEnd:        "21:30",
+			Timezone:        "UTC",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, start.Add(150*time.Minute), *deadline)
+	})
+}
+
+func TestDraftClock(t *testing.T) {
+	// expireClock winds a draft's pick clock back so it has already run out
+	expireClock := func(draftID int) {
+		_, err := testDB.GetDB().Exec("UPDATE drafts SET pick_deadline = $1 WHERE id = $2", time.Now().Add(-time.Second), draftID)
+		assert.NoError(t, err)
+	}
+
+	t.Run("StartDraft starts the pick clock", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		draft, err := draftService.CreateDraft(leagueID, 1, userTeamIDs)
+		assert.NoError(t, err)
+
+		started, err := draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, started.PickDeadline) {
+			assert.WithinDuration(t, time.Now().Add(90*time.Second), *started.PickDeadline, 5*time.Second)
+		}
+
+		// Nothing happens while the clock is still running
+		processed, err := draftService.ProcessExpiredClocks()
+		assert.NoError(t, err)
+		assert.Empty(t, processed)
+	})
+
+	t.Run("Autopick takes from the queue", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		playerIDs := createPlayers(t, 3)
+
+		draft, err := draftService.CreateDraft(leagueID, 1, userTeamIDs)
+		assert.NoError(t, err)
+		_, err = draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+
+		queue, err := draftService.SetQueue(draft.ID, userTeamIDs[0], []int{playerIDs[2], playerIDs[1]})
+		assert.NoError(t, err)
+		assert.Len(t, queue, 2)
+
+		expireClock(draft.ID)
+		processed, err := draftService.ProcessExpiredClocks()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{draft.ID}, processed)
+
+		picks, err := draftService.ListPicks(draft.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, picks, 1) {
+			assert.Equal(t, userTeamIDs[0], picks[0].UserTeamID)
+			assert.Equal(t, playerIDs[2], picks[0].PlayerID)
+		}
+
+		// The next team's clock has started
+		next, err := draftService.GetDraft(draft.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, next.PickDeadline)
+	})
+
+	t.Run("Autopick falls back to the default ranking", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		playerIDs := createPlayers(t, 3)
+
+		_, err := testDB.GetDB().Exec(`
+			INSERT INTO player_stats (player_id, goals, assists)
+			VALUES ($1, 10, 2)
+		`, playerIDs[1])
+		assert.NoError(t, err)
+
+		draft, err := draftService.CreateDraft(leagueID, 1, userTeamIDs)
+		assert.NoError(t, err)
+		_, err = draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+
+		// The only queued player has already gone
+		_, err = draftService.SetQueue(draft.ID, userTeamIDs[1], []int{playerIDs[0]})
+		assert.NoError(t, err)
+		_, err = draftService.MakePick(draft.ID, userTeamIDs[0], playerIDs[0])
+		assert.NoError(t, err)
+
+		expireClock(draft.ID)
+		_, err = draftService.ProcessExpiredClocks()
+		assert.NoError(t, err)
+
+		picks, err := draftService.ListPicks(draft.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, picks, 2) {
+			assert.Equal(t, playerIDs[1], picks[1].PlayerID)
+		}
+	})
+
+	t.Run("League without a pick clock", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		_, err := testDB.GetDB().Exec("INSERT INTO league_settings (league_id, pick_time_seconds) VALUES ($1, 0)", leagueID)
+		assert.NoError(t, err)
+
+		draft, err := draftService.CreateDraft(leagueID, 1, userTeamIDs)
+		assert.NoError(t, err)
+		started, err := draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, started.PickDeadline)
+	})
+}
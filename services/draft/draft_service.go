@@ -0,0 +1,466 @@
+package draft
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/league"
+	"go-app/services/squad"
+	"go-app/services/waiver"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrDraftNotInProgress is returned when a pick is made outside of a running draft
+	ErrDraftNotInProgress = errors.New("draft is not in progress")
+	// ErrNotYourTurn is returned when a team picks out of turn
+	ErrNotYourTurn = errors.New("it is not this team's turn to pick")
+	// ErrPlayerAlreadyDrafted is returned when a player is already owned in the league
+	ErrPlayerAlreadyDrafted = errors.New("player has already been drafted in this league")
+	// ErrWrongDraftType is returned when an operation does not apply to the draft's type
+	ErrWrongDraftType = errors.New("operation is not supported for this draft type")
+	// ErrRosterFull is returned when a team has no roster slots left to fill
+	ErrRosterFull = errors.New("team has no roster slots left to fill")
+	// ErrBudgetLeague is returned when a draft is created for a league that buys players from a budget
+	ErrBudgetLeague = errors.New("league uses budget ownership and does not draft players")
+)
+
+// DraftService defines the interface for draft-related operations
+type DraftService interface {
+	CreateDraft(leagueID int, rounds int, userTeamIDs []int) (*models.Draft, error)
+	GetDraft(id int) (*models.Draft, error)
+	GetDraftByLeague(leagueID int) (*models.Draft, error)
+	GetDraftOrder(draftID int) ([]*models.DraftSlot, error)
+	StartDraft(id int) (*models.Draft, error)
+	MakePick(draftID, userTeamID, playerID int) (*models.DraftPick, error)
+	ListPicks(draftID int) ([]*models.DraftPick, error)
+	GetTeamOnTheClock(draftID int) (int, error)
+	CreateAuctionDraft(leagueID int, userTeamIDs []int, settings AuctionSettings) (*models.Draft, error)
+	Nominate(draftID, userTeamID, playerID int, openingBid float64) (*models.DraftNomination, error)
+	PlaceBid(draftID, userTeamID int, amount float64) (*models.DraftNomination, error)
+	GetOpenNomination(draftID int) (*models.DraftNomination, error)
+	CloseNomination(draftID int) (*models.DraftPick, error)
+	GetAuctionBudgets(draftID int) ([]*TeamBudget, error)
+	SetQueue(draftID, userTeamID int, playerIDs []int) ([]*models.DraftQueueEntry, error)
+	GetQueue(draftID, userTeamID int) ([]*models.DraftQueueEntry, error)
+	ProcessExpiredClocks() ([]int, error)
+}
+
+// Implementation of the DraftService interface
+type draftServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewDraftService creates a new DraftService instance
+func NewDraftService(db *sqlx.DB) DraftService {
+	return &draftServiceImpl{db: db}
+}
+
+// SnakeOrderIndex returns the index into the draft order of the team picking at the
+// given round and pick. Odd rounds run in draft order, even rounds run in reverse.
+func SnakeOrderIndex(round, pick, teams int) int {
+	if round%2 == 0 {
+		return teams - pick
+	}
+	return pick - 1
+}
+
+// CreateDraft creates a new snake draft for a league with the given pick order
+func (s *draftServiceImpl) CreateDraft(leagueID int, rounds int, userTeamIDs []int) (*models.Draft, error) {
+	now := time.Now()
+	draft := &models.Draft{
+		LeagueID:     leagueID,
+		Type:         models.DraftTypeSnake,
+		Status:       models.DraftStatusScheduled,
+		Rounds:       rounds,
+		CurrentRound: 1,
+		CurrentPick:  1,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.createDraft(draft, userTeamIDs); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// createDraft validates and inserts a draft along with its order
+func (s *draftServiceImpl) createDraft(draft *models.Draft, userTeamIDs []int) error {
+	if draft.Rounds <= 0 {
+		return fmt.Errorf("rounds must be greater than zero")
+	}
+	if len(userTeamIDs) < 2 {
+		return fmt.Errorf("a draft needs at least two teams")
+	}
+
+	seen := make(map[int]bool, len(userTeamIDs))
+	for _, id := range userTeamIDs {
+		if seen[id] {
+			return fmt.Errorf("user team %d appears more than once in the draft order", id)
+		}
+		seen[id] = true
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Budget leagues share players between teams, so there is nothing to draft
+	settings, err := league.LoadSettings(tx, draft.LeagueID)
+	if err != nil {
+		return err
+	}
+	if settings.OwnershipMode == models.OwnershipModeBudget {
+		return ErrBudgetLeague
+	}
+
+	// Every team in the order must belong to the league
+	var count int
+	query, args, err := sqlx.In("SELECT COUNT(*) FROM user_teams WHERE league_id = ? AND id IN (?)", draft.LeagueID, userTeamIDs)
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRow(tx.Rebind(query), args...).Scan(&count); err != nil {
+		return err
+	}
+	if count != len(userTeamIDs) {
+		return fmt.Errorf("all teams in the draft order must belong to league %d", draft.LeagueID)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO drafts (league_id, type, status, rounds, current_round, current_pick, budget, min_bid, bid_time_seconds, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, draft.LeagueID, draft.Type, draft.Status, draft.Rounds, draft.CurrentRound, draft.CurrentPick,
+		draft.Budget, draft.MinBid, draft.BidTimeSeconds, draft.CreatedAt, draft.UpdatedAt).Scan(&draft.ID)
+	if err != nil {
+		return fmt.Errorf("error creating draft: %w", err)
+	}
+
+	for i, userTeamID := range userTeamIDs {
+		_, err := tx.Exec(`
+			INSERT INTO draft_order (draft_id, user_team_id, position)
+			VALUES ($1, $2, $3)
+		`, draft.ID, userTeamID, i+1)
+		if err != nil {
+			return fmt.Errorf("error creating draft order: %w", err)
+		}
+	}
+
+	// Picks traded ahead of the league's next draft are made in this one
+	if draft.Type == models.DraftTypeSnake {
+		_, err := tx.Exec(`
+			UPDATE draft_pick_owners
+			SET draft_id = $1
+			WHERE league_id = $2 AND draft_id IS NULL AND round <= $3
+		`, draft.ID, draft.LeagueID, draft.Rounds)
+		if err != nil {
+			return fmt.Errorf("error assigning traded picks: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing draft: %w", err)
+	}
+
+	return nil
+}
+
+// GetDraft retrieves a draft by ID
+func (s *draftServiceImpl) GetDraft(id int) (*models.Draft, error) {
+	draft := &models.Draft{}
+	err := s.db.Get(draft, "SELECT * FROM drafts WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// GetDraftByLeague retrieves the most recent draft for a league
+func (s *draftServiceImpl) GetDraftByLeague(leagueID int) (*models.Draft, error) {
+	draft := &models.Draft{}
+	err := s.db.Get(draft, "SELECT * FROM drafts WHERE league_id = $1 ORDER BY id DESC LIMIT 1", leagueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return draft, nil
+}
+
+// GetDraftOrder retrieves the pick order for a draft
+func (s *draftServiceImpl) GetDraftOrder(draftID int) ([]*models.DraftSlot, error) {
+	var order []*models.DraftSlot
+	err := s.db.Select(&order, "SELECT * FROM draft_order WHERE draft_id = $1 ORDER BY position", draftID)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// StartDraft moves a scheduled draft into progress
+func (s *draftServiceImpl) StartDraft(id int) (*models.Draft, error) {
+	draft, err := s.GetDraft(id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status != models.DraftStatusScheduled {
+		return nil, fmt.Errorf("draft %d has already been started", id)
+	}
+
+	draft.Status = models.DraftStatusInProgress
+	draft.UpdatedAt = time.Now()
+	draft.PickDeadline, err = nextPickDeadline(s.db, draft.LeagueID, draft.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE drafts
+		SET status = $1, pick_deadline = $2, updated_at = $3
+		WHERE id = $4
+	`, draft.Status, draft.PickDeadline, draft.UpdatedAt, draft.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// MakePick records a pick for the team on the clock and adds the player to its roster
+func (s *draftServiceImpl) MakePick(draftID, userTeamID, playerID int) (*models.DraftPick, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draft, err := lockDraft(tx, draftID, models.DraftTypeSnake)
+	if err != nil {
+		return nil, err
+	}
+
+	pick, err := makePick(tx, draft, userTeamID, playerID, &userTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing pick: %w", err)
+	}
+
+	return pick, nil
+}
+
+// makePick records a pick in a snake draft locked by the caller and moves the draft on to the next
+// pick. The actor is the team that made the pick, or nil when it was made for the team.
+func makePick(tx *sqlx.Tx, draft *models.Draft, userTeamID, playerID int, actor *int) (*models.DraftPick, error) {
+	var order []int
+	if err := tx.Select(&order, "SELECT user_team_id FROM draft_order WHERE draft_id = $1 ORDER BY position", draft.ID); err != nil {
+		return nil, err
+	}
+	picking, err := pickingTeam(tx, draft, order)
+	if err != nil {
+		return nil, err
+	}
+	if picking != userTeamID {
+		return nil, ErrNotYourTurn
+	}
+
+	if err := checkPlayerAvailable(tx, draft.LeagueID, playerID); err != nil {
+		return nil, err
+	}
+	if err := squad.ValidateRosterChange(tx, userTeamID, []int{playerID}, nil); err != nil {
+		return nil, err
+	}
+
+	before, err := audit.RosterOf(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	pick := &models.DraftPick{
+		DraftID:     draft.ID,
+		UserTeamID:  userTeamID,
+		PlayerID:    playerID,
+		Round:       draft.CurrentRound,
+		Pick:        draft.CurrentPick,
+		OverallPick: (draft.CurrentRound-1)*len(order) + draft.CurrentPick,
+		CreatedAt:   now,
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO draft_picks (draft_id, user_team_id, player_id, round, pick, overall_pick, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, pick.DraftID, pick.UserTeamID, pick.PlayerID, pick.Round, pick.Pick, pick.OverallPick, pick.CreatedAt).Scan(&pick.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error recording pick: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+	`, userTeamID, playerID, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("error adding player to roster: %w", err)
+	}
+	err = audit.Record(tx, &models.AuditEntry{
+		LeagueID:        draft.LeagueID,
+		UserTeamID:      &userTeamID,
+		ActorUserTeamID: actor,
+		Action:          models.AuditActionDraftPick,
+		PlayersIn:       audit.PlayerIDs(playerID),
+		DraftPickID:     &pick.ID,
+		RosterBefore:    before,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Advance to the next pick, completing the draft after the final round
+	draft.CurrentPick++
+	if draft.CurrentPick > len(order) {
+		draft.CurrentPick = 1
+		draft.CurrentRound++
+	}
+	draft.PickDeadline = nil
+	if draft.CurrentRound > draft.Rounds {
+		draft.Status = models.DraftStatusCompleted
+		// Players nobody drafted go through waivers before anyone can add them
+		if err := waiver.PlaceUndraftedOnWaivers(tx, draft.LeagueID, now); err != nil {
+			return nil, err
+		}
+	} else {
+		draft.PickDeadline, err = nextPickDeadline(tx, draft.LeagueID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE drafts
+		SET status = $1, current_round = $2, current_pick = $3, pick_deadline = $4, updated_at = $5
+		WHERE id = $6
+	`, draft.Status, draft.CurrentRound, draft.CurrentPick, draft.PickDeadline, now, draft.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error advancing draft: %w", err)
+	}
+
+	return pick, nil
+}
+
+// ListPicks retrieves every pick made in a draft in pick order
+func (s *draftServiceImpl) ListPicks(draftID int) ([]*models.DraftPick, error) {
+	var picks []*models.DraftPick
+	err := s.db.Select(&picks, "SELECT * FROM draft_picks WHERE draft_id = $1 ORDER BY overall_pick", draftID)
+	if err != nil {
+		return nil, err
+	}
+	return picks, nil
+}
+
+// GetTeamOnTheClock returns the user team due to make the current pick
+func (s *draftServiceImpl) GetTeamOnTheClock(draftID int) (int, error) {
+	draft, err := s.GetDraft(draftID)
+	if err != nil {
+		return 0, err
+	}
+	if draft.Status != models.DraftStatusInProgress {
+		return 0, ErrDraftNotInProgress
+	}
+
+	order, err := s.GetDraftOrder(draftID)
+	if err != nil {
+		return 0, err
+	}
+
+	userTeamIDs := make([]int, len(order))
+	for i, slot := range order {
+		userTeamIDs[i] = slot.UserTeamID
+	}
+	return pickingTeam(s.db, draft, userTeamIDs)
+}
+
+// teamOnTheClock returns the team due to pick, or to nominate in an auction, from the draft order
+func teamOnTheClock(draft *models.Draft, order []int) int {
+	// Auction drafts track the nominating team rather than a snake pick
+	if draft.Type == models.DraftTypeAuction {
+		return order[draft.CurrentPick-1]
+	}
+	return order[SnakeOrderIndex(draft.CurrentRound, draft.CurrentPick, len(order))]
+}
+
+// pickingTeam returns the team due to make the current pick. In a snake draft that is whoever
+// owns the pick, which is not the team on the clock in the draft order if it was traded away.
+func pickingTeam(q sqlx.Queryer, draft *models.Draft, order []int) (int, error) {
+	userTeamID := teamOnTheClock(draft, order)
+	if draft.Type == models.DraftTypeAuction {
+		return userTeamID, nil
+	}
+
+	var owner int
+	err := q.QueryRowx(`
+		SELECT owner_user_team_id
+		FROM draft_pick_owners
+		WHERE draft_id = $1 AND round = $2 AND original_user_team_id = $3
+	`, draft.ID, draft.CurrentRound, userTeamID).Scan(&owner)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return userTeamID, nil
+		}
+		return 0, err
+	}
+	return owner, nil
+}
+
+// lockDraft locks a running draft of the given type for the rest of the transaction
+func lockDraft(tx *sqlx.Tx, draftID int, draftType models.DraftType) (*models.Draft, error) {
+	draft := &models.Draft{}
+	if err := tx.Get(draft, "SELECT * FROM drafts WHERE id = $1 FOR UPDATE", draftID); err != nil {
+		return nil, err
+	}
+	if draft.Type != draftType {
+		return nil, ErrWrongDraftType
+	}
+	if draft.Status != models.DraftStatusInProgress {
+		return nil, ErrDraftNotInProgress
+	}
+	return draft, nil
+}
+
+// checkPlayerAvailable ensures a player exists and is not already on a roster in the league
+func checkPlayerAvailable(tx *sqlx.Tx, leagueID, playerID int) error {
+	var count int
+	err := tx.QueryRow("SELECT COUNT(*) FROM players WHERE id = $1", playerID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("player with ID %d not found", playerID)
+	}
+
+	err = tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM user_team_players utp
+		JOIN user_teams ut ON ut.id = utp.user_team_id
+		WHERE ut.league_id = $1 AND utp.player_id = $2
+	`, leagueID, playerID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPlayerAlreadyDrafted
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package draft
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB       *database.TestDB
+	draftService DraftService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	draftService = NewDraftService(testDB.GetDB())
+	m.Run()
+}
+
+// createLeagueWithTeams inserts a league with the given number of user teams
+func createLeagueWithTeams(t *testing.T, teams int) (int, []int) {
+	db := testDB.GetDB()
+	now := time.Now()
+
+	var leagueID int
+	err := db.QueryRow(`
+		INSERT INTO leagues (code, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, fmt.Sprintf("DRAFT%d", now.UnixNano()), "Draft League", now, now).Scan(&leagueID)
+	assert.NoError(t, err)
+
+	userTeamIDs := make([]int, 0, teams)
+	for i := 0; i < teams; i++ {
+		var userID int
+		err := db.QueryRow(`
+			INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, "Manager", fmt.Sprintf("%d", i), fmt.Sprintf("manager%d_%d@example.com", i, now.UnixNano()), "password", now, now).Scan(&userID)
+		assert.NoError(t, err)
+
+		var userTeamID int
+		err = db.QueryRow(`
+			INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, userID, leagueID, fmt.Sprintf("Team %d", i), now, now).Scan(&userTeamID)
+		assert.NoError(t, err)
+		userTeamIDs = append(userTeamIDs, userTeamID)
+	}
+
+	return leagueID, userTeamIDs
+}
+
+// createPlayers inserts the given number of players on a single team
+func createPlayers(t *testing.T, count int) []int {
+	db := testDB.GetDB()
+	now := time.Now()
+
+	var teamID int
+	err := db.QueryRow(`
+		INSERT INTO teams (name, external_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, "Player Team", 1, now, now).Scan(&teamID)
+	assert.NoError(t, err)
+
+	playerIDs := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		var playerID int
+		err := db.QueryRow(`
+			INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, teamID, "Player", fmt.Sprintf("%d", i), models.PositionMID, now, now).Scan(&playerID)
+		assert.NoError(t, err)
+		playerIDs = append(playerIDs, playerID)
+	}
+
+	return playerIDs
+}
+
+func TestSnakeOrderIndex(t *testing.T) {
+	// Round 1 runs forwards, round 2 runs backwards
+	assert.Equal(t, 0, SnakeOrderIndex(1, 1, 3))
+	assert.Equal(t, 2, SnakeOrderIndex(1, 3, 3))
+	assert.Equal(t, 2, SnakeOrderIndex(2, 1, 3))
+	assert.Equal(t, 0, SnakeOrderIndex(2, 3, 3))
+	assert.Equal(t, 0, SnakeOrderIndex(3, 1, 3))
+}
+
+func TestDraftService(t *testing.T) {
+	t.Run("CreateDraft", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 3)
+
+		draft, err := draftService.CreateDraft(leagueID, 2, userTeamIDs)
+		assert.NoError(t, err)
+		assert.NotZero(t, draft.ID)
+		assert.Equal(t, models.DraftStatusScheduled, draft.Status)
+
+		order, err := draftService.GetDraftOrder(draft.ID)
+		assert.NoError(t, err)
+		assert.Len(t, order, 3)
+		assert.Equal(t, userTeamIDs[0], order[0].UserTeamID)
+
+		// Teams from another league are rejected
+		_, otherTeams := createLeagueWithTeams(t, 2)
+		_, err = draftService.CreateDraft(leagueID, 2, append(userTeamIDs, otherTeams[0]))
+		assert.Error(t, err)
+	})
+
+	t.Run("MakePick follows snake order", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		playerIDs := createPlayers(t, 4)
+
+		draft, err := draftService.CreateDraft(leagueID, 2, userTeamIDs)
+		assert.NoError(t, err)
+
+		// Picks are rejected before the draft starts
+		_, err = draftService.MakePick(draft.ID, userTeamIDs[0], playerIDs[0])
+		assert.ErrorIs(t, err, ErrDraftNotInProgress)
+
+		_, err = draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+
+		// Out of turn
+		_, err = draftService.MakePick(draft.ID, userTeamIDs[1], playerIDs[0])
+		assert.ErrorIs(t, err, ErrNotYourTurn)
+
+		expectedTeams := []int{userTeamIDs[0], userTeamIDs[1], userTeamIDs[1], userTeamIDs[0]}
+		for i, userTeamID := range expectedTeams {
+			onTheClock, err := draftService.GetTeamOnTheClock(draft.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, userTeamID, onTheClock)
+
+			pick, err := draftService.MakePick(draft.ID, userTeamID, playerIDs[i])
+			assert.NoError(t, err)
+			assert.Equal(t, i+1, pick.OverallPick)
+		}
+
+		completed, err := draftService.GetDraft(draft.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.DraftStatusCompleted, completed.Status)
+
+		picks, err := draftService.ListPicks(draft.ID)
+		assert.NoError(t, err)
+		assert.Len(t, picks, 4)
+
+		// Every pick is persisted to the drafting team's roster
+		var rosterCount int
+		err = testDB.GetDB().QueryRow("SELECT COUNT(*) FROM user_team_players WHERE user_team_id = $1", userTeamIDs[0]).Scan(&rosterCount)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, rosterCount)
+	})
+
+	t.Run("traded picks are made by their owner", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		playerIDs := createPlayers(t, 2)
+
+		// Team 0 traded its first round pick to team 1 before the draft
+		_, err := testDB.GetDB().Exec(`
+			INSERT INTO draft_pick_owners (league_id, round, original_user_team_id, owner_user_team_id)
+			VALUES ($1, $2, $3, $4)
+		`, leagueID, 1, userTeamIDs[0], userTeamIDs[1])
+		assert.NoError(t, err)
+
+		draft, err := draftService.CreateDraft(leagueID, 1, userTeamIDs)
+		assert.NoError(t, err)
+		_, err = draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+
+		onTheClock, err := draftService.GetTeamOnTheClock(draft.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, userTeamIDs[1], onTheClock)
+
+		_, err = draftService.MakePick(draft.ID, userTeamIDs[0], playerIDs[0])
+		assert.ErrorIs(t, err, ErrNotYourTurn)
+		for _, playerID := range playerIDs {
+			pick, err := draftService.MakePick(draft.ID, userTeamIDs[1], playerID)
+			assert.NoError(t, err)
+			assert.Equal(t, userTeamIDs[1], pick.UserTeamID)
+		}
+	})
+
+	t.Run("MakePick rejects drafted player", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		playerIDs := createPlayers(t, 1)
+
+		draft, err := draftService.CreateDraft(leagueID, 1, userTeamIDs)
+		assert.NoError(t, err)
+		_, err = draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+
+		_, err = draftService.MakePick(draft.ID, userTeamIDs[0], playerIDs[0])
+		assert.NoError(t, err)
+
+		_, err = draftService.MakePick(draft.ID, userTeamIDs[1], playerIDs[0])
+		assert.ErrorIs(t, err, ErrPlayerAlreadyDrafted)
+	})
+}
+
+func TestAuctionDraft(t *testing.T) {
+	t.Run("Bidding enforces budgets", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamIDs := createLeagueWithTeams(t, 2)
+		playerIDs := createPlayers(t, 3)
+
+		draft, err := draftService.CreateAuctionDraft(leagueID, userTeamIDs, AuctionSettings{
+			RosterSlots:    2,
+			Budget:         10,
+			MinBid:         1,
+			BidTimeSeconds: 30,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, models.DraftTypeAuction, draft.Type)
+
+		_, err = draftService.StartDraft(draft.ID)
+		assert.NoError(t, err)
+
+		// Snake picks are not allowed in an auction
+		_, err = draftService.MakePick(draft.ID, userTeamIDs[0], playerIDs[0])
+		assert.ErrorIs(t, err, ErrWrongDraftType)
+
+		// Only the team on the clock can nominate
+		_, err = draftService.Nominate(draft.ID, userTeamIDs[1], playerIDs[0], 1)
+		assert.ErrorIs(t, err, ErrNotYourTurn)
+
+		nomination, err := draftService.Nominate(draft.ID, userTeamIDs[0], playerIDs[0], 1)
+		assert.NoError(t, err)
+		assert.Equal(t, userTeamIDs[0], nomination.CurrentBidder)
+
+		_, err = draftService.Nominate(draft.ID, userTeamIDs[0], playerIDs[1], 1)
+		assert.ErrorIs(t, err, ErrNominationOpen)
+
+		// A team must keep the minimum bid back for its last slot
+		_, err = draftService.PlaceBid(draft.ID, userTeamIDs[1], 9.5)
+		assert.ErrorIs(t, err, ErrBidExceedsMax)
+
+		nomination, err = draftService.PlaceBid(draft.ID, userTeamIDs[1], 9)
+		assert.NoError(t, err)
+		assert.Equal(t, 9.0, nomination.CurrentBid)
+
+		_, err = draftService.PlaceBid(draft.ID, userTeamIDs[0], 8)
+		assert.ErrorIs(t, err, ErrBidTooLow)
+
+		// The player cannot be sold while the clock is running
+		_, err = draftService.CloseNomination(draft.ID)
+		assert.ErrorIs(t, err, ErrBidClockRunning)
+
+		_, err = testDB.GetDB().Exec("UPDATE draft_nominations SET bid_deadline = $1 WHERE id = $2", time.Now().Add(-time.Minute), nomination.ID)
+		assert.NoError(t, err)
+
+		pick, err := draftService.CloseNomination(draft.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, userTeamIDs[1], pick.UserTeamID)
+		assert.Equal(t, 9.0, pick.Price)
+
+		// The winning bid is recorded against the rostered player
+		var purchasePrice float64
+		err = testDB.GetDB().QueryRow("SELECT purchase_price FROM user_team_players WHERE user_team_id = $1 AND player_id = $2", userTeamIDs[1], playerIDs[0]).Scan(&purchasePrice)
+		assert.NoError(t, err)
+		assert.Equal(t, 9.0, purchasePrice)
+
+		budgets, err := draftService.GetAuctionBudgets(draft.ID)
+		assert.NoError(t, err)
+		assert.Len(t, budgets, 2)
+		assert.Equal(t, 1.0, budgets[1].Remaining)
+		assert.Equal(t, 1.0, budgets[1].MaxBid)
+		assert.Equal(t, 9.0, budgets[0].MaxBid)
+
+		// Nomination passes to the next team
+		onTheClock, err := draftService.GetTeamOnTheClock(draft.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, userTeamIDs[1], onTheClock)
+	})
+}
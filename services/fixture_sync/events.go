@@ -0,0 +1,418 @@
+package fixture_sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-app/external"
+	"go-app/models"
+	"go-app/services/incident"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// EventSyncResult counts the incidents an event sync created, changed and removed
+type EventSyncResult struct {
+	Created int
+	Updated int
+	Removed int
+	Skipped int // incidents of players who are not linked to API-Football
+	Stats   int // players whose minutes and saves were recorded
+}
+
+// add adds the counts of another sync
+func (r *EventSyncResult) add(other *EventSyncResult) {
+	r.Created += other.Created
+	r.Updated += other.Updated
+	r.Removed += other.Removed
+	r.Skipped += other.Skipped
+	r.Stats += other.Stats
+}
+
+// EventIncident is an incident a fixture event stands for, with the player it is about
+type EventIncident struct {
+	PlayerExternalID int
+	Type             models.IncidentType
+	Minute           int
+	Description      string
+}
+
+// CleanSheetMinutes is how long a player has to have played in a match to keep a clean sheet
+const CleanSheetMinutes = 60
+
+// EventIncidents turns a fixture event into the incidents it stands for. A goal also credits
+// the assist, a substitution is recorded for both players, as both played, and a second yellow
+// card counts as a red card. VAR reviews and unknown events stand for nothing.
+func EventIncidents(event *external.FixtureEvent) []*EventIncident {
+	credit := func(playerExternalID int, incidentType models.IncidentType) *EventIncident {
+		return &EventIncident{
+			PlayerExternalID: playerExternalID,
+			Type:             incidentType,
+			Minute:           event.Minute,
+			Description:      event.Detail,
+		}
+	}
+
+	var incidents []*EventIncident
+	switch strings.ToLower(event.Type) {
+	case "goal":
+		switch strings.ToLower(event.Detail) {
+		case "normal goal":
+			incidents = append(incidents, credit(event.PlayerExternalID, models.IncidentTypeGoal))
+			if event.AssistExternalID != 0 {
+				incidents = append(incidents, credit(event.AssistExternalID, models.IncidentTypeAssist))
+			}
+		case "penalty":
+			incidents = append(incidents, credit(event.PlayerExternalID, models.IncidentTypePenaltyScored))
+		case "missed penalty":
+			incidents = append(incidents, credit(event.PlayerExternalID, models.IncidentTypePenaltyMissed))
+		case "own goal":
+			incidents = append(incidents, credit(event.PlayerExternalID, models.IncidentTypeOwnGoal))
+		}
+	case "card":
+		switch strings.ToLower(event.Detail) {
+		case "yellow card":
+			incidents = append(incidents, credit(event.PlayerExternalID, models.IncidentTypeYellowCard))
+		case "red card", "second yellow card":
+			incidents = append(incidents, credit(event.PlayerExternalID, models.IncidentTypeRedCard))
+		}
+	case "subst":
+		incidents = append(incidents, credit(event.PlayerExternalID, models.IncidentTypeSubstitution))
+		if event.AssistExternalID != 0 {
+			incidents = append(incidents, credit(event.AssistExternalID, models.IncidentTypeSubstitution))
+		}
+	}
+
+	// Events without a player cannot be credited to anyone
+	credited := incidents[:0]
+	for _, eventIncident := range incidents {
+		if eventIncident.PlayerExternalID != 0 {
+			credited = append(credited, eventIncident)
+		}
+	}
+	return credited
+}
+
+// SyncEvents fetches the events of a match's fixture from the API-Football service and brings the
+// match's synced incidents in line with them. Each incident is keyed by its type, player and
+// minute, so running the sync again changes nothing. An incident whose player or minute the
+// provider has since changed is updated, and one whose event has gone is removed. Changes to a
+// finished match are corrections, as when they are made by hand. Incidents added by hand are
+// left alone. The minutes and saves of each linked player are recorded in their player stats for
+// the match first. API-Football has no clean sheet events, so once a match is completed a clean
+// sheet is also synced for each linked player who did not concede, going by the final score and
+// those minutes.
+func (s *FixtureSyncService) SyncEvents(matchID int) (*EventSyncResult, error) {
+	var externalID *int
+	if err := s.db.Get(&externalID, "SELECT external_id FROM matches WHERE id = $1", matchID); err != nil {
+		return nil, err
+	}
+	if externalID == nil {
+		return nil, fmt.Errorf("match %d was not synced from API-Football", matchID)
+	}
+
+	events, err := s.apiFootballClient.FetchFixtureEvents(*externalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events from API-Football: %w", err)
+	}
+	stats, err := s.apiFootballClient.FetchFixturePlayerStats(*externalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player statistics from API-Football: %w", err)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the match so its incidents change one sync or edit at a time
+	match := &models.Match{}
+	if err := tx.Get(match, "SELECT * FROM matches WHERE id = $1 FOR UPDATE", matchID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	recorded, err := syncPlayerStats(tx, match, stats, now)
+	if err != nil {
+		return nil, err
+	}
+	result, err := syncIncidents(tx, match, events, now)
+	if err != nil {
+		return nil, err
+	}
+	result.Stats = recorded
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing incidents: %w", err)
+	}
+
+	log.Printf("Synced events of fixture %d: %d created, %d updated, %d removed, %d skipped, %d player stats",
+		*externalID, result.Created, result.Updated, result.Removed, result.Skipped, result.Stats)
+	return result, nil
+}
+
+// SyncRecentEvents syncs the events of every synced match that has kicked off since a time and is
+// in progress or completed. A match that fails to sync is logged and skipped.
+func (s *FixtureSyncService) SyncRecentEvents(since time.Time) (*EventSyncResult, error) {
+	var matchIDs []int
+	err := s.db.Select(&matchIDs, `
+		SELECT id FROM matches
+		WHERE external_id IS NOT NULL AND status IN ($1, $2) AND match_date >= $3
+		ORDER BY match_date, id
+	`, models.MatchStatusInProgress, models.MatchStatusCompleted, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EventSyncResult{}
+	for _, matchID := range matchIDs {
+		synced, err := s.SyncEvents(matchID)
+		if err != nil {
+			log.Printf("Error syncing events of match %d: %v", matchID, err)
+			continue
+		}
+		result.add(synced)
+	}
+	return result, nil
+}
+
+// syncPlayerStats records the minutes and saves of each linked player in a match's player stats,
+// updating the row already there for the match if there is one. Players who are not linked are
+// left out, and it returns how many players were recorded.
+func syncPlayerStats(tx *sqlx.Tx, match *models.Match, stats []*external.FixturePlayerStats, now time.Time) (int, error) {
+	playerIDs, err := playersByExternalID(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, playerStats := range stats {
+		playerID, ok := playerIDs[playerStats.PlayerExternalID]
+		if !ok {
+			continue
+		}
+		updated, err := tx.Exec(`
+			UPDATE player_stats SET minutes_played = $1, saves = $2, updated_at = $3
+			WHERE player_id = $4 AND match_id = $5
+		`, playerStats.Minutes, playerStats.Saves, now, playerID, match.ID)
+		if err != nil {
+			return 0, fmt.Errorf("error updating player stats: %w", err)
+		}
+		rows, err := updated.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if rows == 0 {
+			_, err := tx.Exec(`
+				INSERT INTO player_stats (player_id, match_id, minutes_played, saves, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $5)
+			`, playerID, match.ID, playerStats.Minutes, playerStats.Saves, now)
+			if err != nil {
+				return 0, fmt.Errorf("error recording player stats: %w", err)
+			}
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+// syncIncidents brings the synced incidents of a match in line with its fixture's events
+func syncIncidents(tx *sqlx.Tx, match *models.Match, events []*external.FixtureEvent, now time.Time) (*EventSyncResult, error) {
+	playerIDs, err := playersByExternalID(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EventSyncResult{}
+	var wanted []*models.MatchIncident
+	occurrences := make(map[string]int)
+	for _, event := range events {
+		for _, eventIncident := range EventIncidents(event) {
+			playerID, ok := playerIDs[eventIncident.PlayerExternalID]
+			if !ok {
+				log.Printf("Skipping %s in fixture %d: player %d is not linked", eventIncident.Type,
+					*match.ExternalID, eventIncident.PlayerExternalID)
+				result.Skipped++
+				continue
+			}
+			// The same player can be booked twice in a minute, so repeats are numbered
+			key := fmt.Sprintf("%s:%d:%d", eventIncident.Type, eventIncident.PlayerExternalID, eventIncident.Minute)
+			occurrences[key]++
+			key = fmt.Sprintf("%s:%d", key, occurrences[key])
+			wanted = append(wanted, &models.MatchIncident{
+				MatchID:     match.ID,
+				PlayerID:    playerID,
+				Type:        eventIncident.Type,
+				Minute:      eventIncident.Minute,
+				Description: eventIncident.Description,
+				ExternalKey: &key,
+			})
+		}
+	}
+
+	if match.Status == models.MatchStatusCompleted {
+		cleanSheets, err := cleanSheetIncidents(tx, match)
+		if err != nil {
+			return nil, err
+		}
+		wanted = append(wanted, cleanSheets...)
+	}
+
+	var existing []*models.MatchIncident
+	err = tx.Select(&existing, `
+		SELECT id, match_id, player_id, type, minute, COALESCE(description, '') AS description, external_key, created_at, updated_at
+		FROM match_incidents
+		WHERE match_id = $1 AND external_key IS NOT NULL
+		ORDER BY id
+	`, match.ID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*models.MatchIncident, len(existing))
+	for _, before := range existing {
+		byKey[*before.ExternalKey] = before
+	}
+
+	// Events already synced are left as they are
+	var added []*models.MatchIncident
+	for _, after := range wanted {
+		if _, ok := byKey[*after.ExternalKey]; ok {
+			delete(byKey, *after.ExternalKey)
+			continue
+		}
+		added = append(added, after)
+	}
+	var gone []*models.MatchIncident
+	for _, before := range existing {
+		if _, ok := byKey[*before.ExternalKey]; ok {
+			gone = append(gone, before)
+		}
+	}
+
+	// A new event of the same type as a vanished one in the same minute, or for the same player,
+	// is the provider correcting the player or the minute
+	for _, sameMinute := range []bool{true, false} {
+		remaining := added[:0]
+		for _, after := range added {
+			i := changedFrom(after, gone, sameMinute)
+			if i < 0 {
+				remaining = append(remaining, after)
+				continue
+			}
+			before := gone[i]
+			gone = append(gone[:i], gone[i+1:]...)
+
+			// Update keeps the incident's key, so the new key is stored separately
+			key := after.ExternalKey
+			after.ID = before.ID
+			if err := incident.Update(tx, after, now); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec("UPDATE match_incidents SET external_key = $1 WHERE id = $2", key, after.ID); err != nil {
+				return nil, fmt.Errorf("error updating incident key: %w", err)
+			}
+			after.ExternalKey = key
+			if _, err := incident.Correct(tx, match, before, after, now); err != nil {
+				return nil, err
+			}
+			result.Updated++
+		}
+		added = remaining
+	}
+
+	for _, before := range gone {
+		if err := incident.Delete(tx, before); err != nil {
+			return nil, err
+		}
+		if _, err := incident.Correct(tx, match, before, nil, now); err != nil {
+			return nil, err
+		}
+		result.Removed++
+	}
+	for _, after := range added {
+		if err := incident.Insert(tx, after, now); err != nil {
+			return nil, err
+		}
+		if _, err := incident.Correct(tx, match, nil, after, now); err != nil {
+			return nil, err
+		}
+		result.Created++
+	}
+	return result, nil
+}
+
+// cleanSheetIncidents returns a clean sheet for every linked goalkeeper, defender and midfielder
+// who played at least CleanSheetMinutes in a completed match for a team that did not concede.
+// Players with a clean sheet added by hand are left out so it is not credited twice.
+func cleanSheetIncidents(q sqlx.Queryer, match *models.Match) ([]*models.MatchIncident, error) {
+	var rows []struct {
+		PlayerID   int `db:"player_id"`
+		ExternalID int `db:"external_id"`
+		TeamID     int `db:"team_id"`
+	}
+	err := sqlx.Select(q, &rows, `
+		SELECT DISTINCT p.id AS player_id, p.external_id, p.team_id
+		FROM player_stats ps
+		JOIN players p ON p.id = ps.player_id
+		WHERE ps.match_id = $1 AND ps.minutes_played >= $2 AND p.external_id IS NOT NULL AND p.position <> $3
+			AND NOT EXISTS (
+				SELECT 1 FROM match_incidents mi
+				WHERE mi.match_id = ps.match_id AND mi.player_id = p.id AND mi.type = $4 AND mi.external_key IS NULL
+			)
+		ORDER BY p.id
+	`, match.ID, CleanSheetMinutes, models.PositionFWD, models.IncidentTypeCleanSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var incidents []*models.MatchIncident
+	for _, row := range rows {
+		kept := row.TeamID == match.HomeTeamID && match.AwayScore == 0 ||
+			row.TeamID == match.AwayTeamID && match.HomeScore == 0
+		if !kept {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d:90:1", models.IncidentTypeCleanSheet, row.ExternalID)
+		incidents = append(incidents, &models.MatchIncident{
+			MatchID:     match.ID,
+			PlayerID:    row.PlayerID,
+			Type:        models.IncidentTypeCleanSheet,
+			Minute:      90,
+			Description: "Clean sheet",
+			ExternalKey: &key,
+		})
+	}
+	return incidents, nil
+}
+
+// changedFrom finds the vanished incident a new one changes: one of the same type in the same
+// minute, or for the same player. It returns -1 if there is none.
+func changedFrom(after *models.MatchIncident, gone []*models.MatchIncident, sameMinute bool) int {
+	for i, before := range gone {
+		if before.Type != after.Type {
+			continue
+		}
+		if sameMinute && before.Minute == after.Minute || !sameMinute && before.PlayerID == after.PlayerID {
+			return i
+		}
+	}
+	return -1
+}
+
+// playersByExternalID maps the API-Football IDs of linked players to their IDs
+func playersByExternalID(q sqlx.Queryer) (map[int]int, error) {
+	var rows []struct {
+		ID         int `db:"id"`
+		ExternalID int `db:"external_id"`
+	}
+	if err := sqlx.Select(q, &rows, "SELECT id, external_id FROM players WHERE external_id IS NOT NULL"); err != nil {
+		return nil, err
+	}
+	playerIDs := make(map[int]int, len(rows))
+	for _, row := range rows {
+		playerIDs[row.ExternalID] = row.ID
+	}
+	return playerIDs, nil
+}
@@ -0,0 +1,223 @@
+package fixture_sync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/external"
+	"go-app/mocks"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventIncidents(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *external.FixtureEvent
+		want  []*EventIncident
+	}{
+		{
+			name:  "goal with an assist",
+			event: &external.FixtureEvent{Minute: 12, PlayerExternalID: 10, AssistExternalID: 11, Type: "Goal", Detail: "Normal Goal"},
+			want: []*EventIncident{
+				{PlayerExternalID: 10, Type: models.IncidentTypeGoal, Minute: 12, Description: "Normal Goal"},
+				{PlayerExternalID: 11, Type: models.IncidentTypeAssist, Minute: 12, Description: "Normal Goal"},
+			},
+		},
+		{
+			name:  "penalty",
+			event: &external.FixtureEvent{Minute: 30, PlayerExternalID: 10, Type: "Goal", Detail: "Penalty"},
+			want:  []*EventIncident{{PlayerExternalID: 10, Type: models.IncidentTypePenaltyScored, Minute: 30, Description: "Penalty"}},
+		},
+		{
+			name:  "missed penalty",
+			event: &external.FixtureEvent{Minute: 30, PlayerExternalID: 10, Type: "Goal", Detail: "Missed Penalty"},
+			want:  []*EventIncident{{PlayerExternalID: 10, Type: models.IncidentTypePenaltyMissed, Minute: 30, Description: "Missed Penalty"}},
+		},
+		{
+			name:  "own goal",
+			event: &external.FixtureEvent{Minute: 50, PlayerExternalID: 20, Type: "Goal", Detail: "Own Goal"},
+			want:  []*EventIncident{{PlayerExternalID: 20, Type: models.IncidentTypeOwnGoal, Minute: 50, Description: "Own Goal"}},
+		},
+		{
+			name:  "yellow card",
+			event: &external.FixtureEvent{Minute: 60, PlayerExternalID: 20, Type: "Card", Detail: "Yellow Card"},
+			want:  []*EventIncident{{PlayerExternalID: 20, Type: models.IncidentTypeYellowCard, Minute: 60, Description: "Yellow Card"}},
+		},
+		{
+			name:  "second yellow card",
+			event: &external.FixtureEvent{Minute: 70, PlayerExternalID: 20, Type: "Card", Detail: "Second Yellow card"},
+			want:  []*EventIncident{{PlayerExternalID: 20, Type: models.IncidentTypeRedCard, Minute: 70, Description: "Second Yellow card"}},
+		},
+		{
+			name:  "substitution",
+			event: &external.FixtureEvent{Minute: 75, PlayerExternalID: 10, AssistExternalID: 12, Type: "subst", Detail: "Substitution 1"},
+			want: []*EventIncident{
+				{PlayerExternalID: 10, Type: models.IncidentTypeSubstitution, Minute: 75, Description: "Substitution 1"},
+				{PlayerExternalID: 12, Type: models.IncidentTypeSubstitution, Minute: 75, Description: "Substitution 1"},
+			},
+		},
+		{
+			name:  "VAR review",
+			event: &external.FixtureEvent{Minute: 80, PlayerExternalID: 10, Type: "Var", Detail: "Goal cancelled"},
+			want:  nil,
+		},
+		{
+			name:  "card without a player",
+			event: &external.FixtureEvent{Minute: 85, Type: "Card", Detail: "Yellow Card"},
+			want:  []*EventIncident{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EventIncidents(tt.event))
+		})
+	}
+}
+
+func TestSyncEvents(t *testing.T) {
+	defer testDB.Clear()
+	db := testDB.GetDB()
+	now := time.Now()
+
+	var teamID, matchID int
+	err := db.QueryRow(`
+		INSERT INTO teams (name, external_id, created_at, updated_at)
+		VALUES ('Event Club', 1, $1, $1)
+		RETURNING id
+	`, now).Scan(&teamID)
+	assert.NoError(t, err)
+	playerIDs := make(map[int]int)
+	for _, externalID := range []int{10, 11, 12} {
+		var playerID int
+		err := db.QueryRow(`
+			INSERT INTO players (team_id, first_name, last_name, position, external_id, created_at, updated_at)
+			VALUES ($1, 'Player', $2, $3, $4, $5, $5)
+			RETURNING id
+		`, teamID, fmt.Sprintf("%d", externalID), models.PositionFWD, externalID, now).Scan(&playerID)
+		assert.NoError(t, err)
+		playerIDs[externalID] = playerID
+	}
+	err = db.QueryRow(`
+		INSERT INTO matches (external_id, league_id, home_team_id, away_team_id, match_date, status)
+		VALUES (1001, 39, $1, $1, $2, $3)
+		RETURNING id
+	`, teamID, now, models.MatchStatusInProgress).Scan(&matchID)
+	assert.NoError(t, err)
+
+	// An incident added by hand is not the sync's to change
+	_, err = db.Exec(`
+		INSERT INTO match_incidents (match_id, player_id, type, minute, created_at, updated_at)
+		VALUES ($1, $2, $3, 5, $4, $4)
+	`, matchID, playerIDs[12], models.IncidentTypeCleanSheet, now)
+	assert.NoError(t, err)
+
+	events := map[int][]*external.FixtureEvent{
+		1001: {
+			{Minute: 12, PlayerExternalID: 10, AssistExternalID: 11, Type: "Goal", Detail: "Normal Goal"},
+			{Minute: 40, PlayerExternalID: 11, Type: "Card", Detail: "Yellow Card"},
+			// Player 99 is not linked
+			{Minute: 60, PlayerExternalID: 99, Type: "Card", Detail: "Yellow Card"},
+		},
+	}
+	stats := map[int][]*external.FixturePlayerStats{}
+	client := mocks.NewMockAPIFootballClient(nil, nil).WithEvents(events).WithPlayerStats(stats)
+	fixtureSyncService := NewFixtureSyncService(db, client)
+
+	incidentsOf := func() map[models.IncidentType][]*models.MatchIncident {
+		var incidents []*models.MatchIncident
+		err := db.Select(&incidents, `
+			SELECT id, match_id, player_id, type, minute, external_key, created_at, updated_at
+			FROM match_incidents
+			WHERE match_id = $1
+		`, matchID)
+		assert.NoError(t, err)
+		byType := make(map[models.IncidentType][]*models.MatchIncident)
+		for _, incident := range incidents {
+			byType[incident.Type] = append(byType[incident.Type], incident)
+		}
+		return byType
+	}
+
+	result, err := fixtureSyncService.SyncEvents(matchID)
+	assert.NoError(t, err)
+	assert.Equal(t, &EventSyncResult{Created: 3, Skipped: 1}, result)
+	incidents := incidentsOf()
+	if assert.Len(t, incidents[models.IncidentTypeGoal], 1) {
+		assert.Equal(t, playerIDs[10], incidents[models.IncidentTypeGoal][0].PlayerID)
+		assert.Equal(t, 12, incidents[models.IncidentTypeGoal][0].Minute)
+	}
+	assert.Len(t, incidents[models.IncidentTypeAssist], 1)
+	assert.Len(t, incidents[models.IncidentTypeYellowCard], 1)
+	assert.Len(t, incidents[models.IncidentTypeCleanSheet], 1)
+	goalID := incidents[models.IncidentTypeGoal][0].ID
+
+	// Running it again changes nothing
+	result, err = fixtureSyncService.SyncEvents(matchID)
+	assert.NoError(t, err)
+	assert.Equal(t, &EventSyncResult{Skipped: 1}, result)
+
+	// The provider credits the goal to another player, moves the booking and drops the assist
+	events[1001][0].PlayerExternalID = 12
+	events[1001][0].AssistExternalID = 0
+	events[1001][1].Minute = 42
+	result, err = fixtureSyncService.SyncEvents(matchID)
+	assert.NoError(t, err)
+	assert.Equal(t, &EventSyncResult{Updated: 2, Removed: 1, Skipped: 1}, result)
+	incidents = incidentsOf()
+	if assert.Len(t, incidents[models.IncidentTypeGoal], 1) {
+		assert.Equal(t, goalID, incidents[models.IncidentTypeGoal][0].ID)
+		assert.Equal(t, playerIDs[12], incidents[models.IncidentTypeGoal][0].PlayerID)
+		assert.Equal(t, "goal:12:12:1", *incidents[models.IncidentTypeGoal][0].ExternalKey)
+	}
+	assert.Empty(t, incidents[models.IncidentTypeAssist])
+	if assert.Len(t, incidents[models.IncidentTypeYellowCard], 1) {
+		assert.Equal(t, 42, incidents[models.IncidentTypeYellowCard][0].Minute)
+	}
+	assert.Len(t, incidents[models.IncidentTypeCleanSheet], 1)
+
+	// And the changes are themselves synced
+	result, err = fixtureSyncService.SyncEvents(matchID)
+	assert.NoError(t, err)
+	assert.Equal(t, &EventSyncResult{Skipped: 1}, result)
+
+	// The match ends goalless. Player 11 is a defender who played the hour for a clean sheet,
+	// player 10 is a forward and player 12 already has one added by hand.
+	_, err = db.Exec("UPDATE players SET position = $1 WHERE id = $2", models.PositionDEF, playerIDs[11])
+	assert.NoError(t, err)
+	stats[1001] = []*external.FixturePlayerStats{
+		{TeamExternalID: 1, PlayerExternalID: 10, Minutes: 90},
+		{TeamExternalID: 1, PlayerExternalID: 11, Minutes: 90, Saves: 1},
+		{TeamExternalID: 1, PlayerExternalID: 12, Minutes: 90},
+		{TeamExternalID: 1, PlayerExternalID: 99, Minutes: 90},
+	}
+	_, err = db.Exec("UPDATE matches SET status = $1, home_score = 0, away_score = 0 WHERE id = $2",
+		models.MatchStatusCompleted, matchID)
+	assert.NoError(t, err)
+	result, err = fixtureSyncService.SyncEvents(matchID)
+	assert.NoError(t, err)
+	assert.Equal(t, &EventSyncResult{Created: 1, Skipped: 1, Stats: 3}, result)
+
+	var recorded []*models.PlayerStats
+	err = db.Select(&recorded, "SELECT player_id, minutes_played, saves FROM player_stats WHERE match_id = $1 ORDER BY player_id", matchID)
+	assert.NoError(t, err)
+	if assert.Len(t, recorded, 3) {
+		assert.Equal(t, 90, recorded[1].MinutesPlayed)
+		assert.Equal(t, 1, recorded[1].Saves)
+	}
+	incidents = incidentsOf()
+	if assert.Len(t, incidents[models.IncidentTypeCleanSheet], 2) {
+		assert.ElementsMatch(t, []int{playerIDs[11], playerIDs[12]}, []int{
+			incidents[models.IncidentTypeCleanSheet][0].PlayerID, incidents[models.IncidentTypeCleanSheet][1].PlayerID,
+		})
+	}
+
+	// Goals corrected onto the final score take the clean sheet away
+	_, err = db.Exec("UPDATE matches SET home_score = 1, away_score = 1 WHERE id = $1", matchID)
+	assert.NoError(t, err)
+	result, err = fixtureSyncService.SyncEvents(matchID)
+	assert.NoError(t, err)
+	assert.Equal(t, &EventSyncResult{Removed: 1, Skipped: 1, Stats: 3}, result)
+}
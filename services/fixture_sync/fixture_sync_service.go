@@ -0,0 +1,226 @@
+package fixture_sync
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-app/external"
+	"go-app/models"
+	"go-app/services/team_sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// FixtureSyncService handles synchronizing matches from API-Football fixtures
+type FixtureSyncService struct {
+	db                *sqlx.DB
+	apiFootballClient external.APIFootballClientInterface
+}
+
+// NewFixtureSyncService creates a new FixtureSyncService instance
+func NewFixtureSyncService(db *sqlx.DB, apiFootballClient external.APIFootballClientInterface) *FixtureSyncService {
+	return &FixtureSyncService{
+		db:                db,
+		apiFootballClient: apiFootballClient,
+	}
+}
+
+// SyncResult counts the matches a fixture sync created and changed. A rescheduled or postponed
+// match is also counted as updated.
+type SyncResult struct {
+	Created     int
+	Updated     int
+	Rescheduled int
+	Postponed   int
+	Skipped     int // fixtures between teams that have not been synced or with an unknown status
+}
+
+// SyncFixtures fetches the fixtures of the configured league and season from the API-Football
+// service and creates or updates a match for each. Teams are matched by their external IDs, so
+// they have to be synced first. A match is put in the gameweek numbered after its round, if
+// there is one, and keeps that gameweek from then on, unless it is rearranged: a match given a
+// new kickoff moves to the gameweek the kickoff falls in, so a postponed match that is replayed
+// weeks later scores in the gameweek it is played in.
+func (s *FixtureSyncService) SyncFixtures() (*SyncResult, error) {
+	log.Println("Starting fixture sync from API-Football")
+
+	fixtures, err := s.apiFootballClient.FetchFixtures()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fixtures from API-Football: %w", err)
+	}
+
+	log.Printf("Fetched %d fixtures from API-Football", len(fixtures))
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	teamIDs, err := team_sync.TeamsByExternalID(tx)
+	if err != nil {
+		return nil, err
+	}
+	var gameweeks []*models.Gameweek
+	if err := tx.Select(&gameweeks, "SELECT * FROM gameweeks ORDER BY number"); err != nil {
+		return nil, err
+	}
+	gameweekIDs := make(map[int]int, len(gameweeks))
+	for _, gameweek := range gameweeks {
+		gameweekIDs[gameweek.Number] = gameweek.ID
+	}
+
+	now := time.Now()
+	result := &SyncResult{}
+	for _, fixture := range fixtures {
+		if fixture.Status == "" {
+			log.Printf("Skipping fixture %d: unknown status %q", fixture.ExternalID, fixture.APIStatus)
+			result.Skipped++
+			continue
+		}
+		homeTeamID, home := teamIDs[fixture.HomeTeamExternalID]
+		awayTeamID, away := teamIDs[fixture.AwayTeamExternalID]
+		if !home || !away {
+			log.Printf("Skipping fixture %d: its teams have not been synced", fixture.ExternalID)
+			result.Skipped++
+			continue
+		}
+
+		match := &models.Match{}
+		err := tx.Get(match, "SELECT * FROM matches WHERE external_id = $1 FOR UPDATE", fixture.ExternalID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		created := err == sql.ErrNoRows
+		before := *match
+
+		externalID := fixture.ExternalID
+		match.ExternalID = &externalID
+		match.LeagueID = fixture.LeagueID
+		match.HomeTeamID = homeTeamID
+		match.AwayTeamID = awayTeamID
+		match.MatchDate = fixture.Kickoff
+		match.HomeScore = fixture.HomeScore
+		match.AwayScore = fixture.AwayScore
+		match.Status = fixture.Status
+		moved := !created && !before.MatchDate.Equal(match.MatchDate)
+		if match.GameweekID == nil {
+			if number, ok := RoundNumber(fixture.Round); ok {
+				if gameweekID, ok := gameweekIDs[number]; ok {
+					match.GameweekID = &gameweekID
+				}
+			}
+		} else if moved && match.Status != models.MatchStatusPostponed {
+			// A rearranged fixture is played in the gameweek its new kickoff falls in, not its round's
+			if gameweekID, ok := GameweekAt(gameweeks, match.MatchDate); ok {
+				match.GameweekID = &gameweekID
+			}
+		}
+
+		if created {
+			if err := insertMatch(tx, match, now); err != nil {
+				return nil, err
+			}
+			log.Printf("Created match for fixture %d", fixture.ExternalID)
+			result.Created++
+			continue
+		}
+
+		if !changed(&before, match) {
+			continue
+		}
+		if moved {
+			log.Printf("Fixture %d moved from %s to %s", fixture.ExternalID, before.MatchDate, match.MatchDate)
+			result.Rescheduled++
+		}
+		if match.Status == models.MatchStatusPostponed && before.Status != models.MatchStatusPostponed {
+			log.Printf("Fixture %d was postponed", fixture.ExternalID)
+			result.Postponed++
+		}
+		if err := updateMatch(tx, match, now); err != nil {
+			return nil, err
+		}
+		result.Updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing fixtures: %w", err)
+	}
+
+	log.Printf("Fixture sync completed: %d created, %d updated, %d skipped", result.Created, result.Updated, result.Skipped)
+	return result, nil
+}
+
+// RoundNumber reads the number at the end of an API-Football round, such as 5 from
+// "Regular Season - 5". Rounds without a number, such as cup finals, report false.
+func RoundNumber(round string) (int, bool) {
+	i := strings.LastIndex(round, "-")
+	number, err := strconv.Atoi(strings.TrimSpace(round[i+1:]))
+	if err != nil || number <= 0 {
+		return 0, false
+	}
+	return number, true
+}
+
+// GameweekAt returns the gameweek a kickoff falls in: the last, of gameweeks in order, whose
+// deadline is not after it. A kickoff before the first deadline reports false.
+func GameweekAt(gameweeks []*models.Gameweek, kickoff time.Time) (int, bool) {
+	id, found := 0, false
+	for _, gameweek := range gameweeks {
+		if !gameweek.Deadline.After(kickoff) {
+			id, found = gameweek.ID, true
+		}
+	}
+	return id, found
+}
+
+// changed reports whether a sync changed anything about a match
+func changed(before, after *models.Match) bool {
+	sameGameweek := (before.GameweekID == nil) == (after.GameweekID == nil) &&
+		(before.GameweekID == nil || *before.GameweekID == *after.GameweekID)
+	return !sameGameweek ||
+		before.LeagueID != after.LeagueID ||
+		before.HomeTeamID != after.HomeTeamID ||
+		before.AwayTeamID != after.AwayTeamID ||
+		!before.MatchDate.Equal(after.MatchDate) ||
+		before.HomeScore != after.HomeScore ||
+		before.AwayScore != after.AwayScore ||
+		before.Status != after.Status
+}
+
+// insertMatch stores a new match
+func insertMatch(tx *sqlx.Tx, match *models.Match, now time.Time) error {
+	match.CreatedAt = now
+	match.UpdatedAt = now
+	err := tx.QueryRow(`
+		INSERT INTO matches (external_id, league_id, gameweek_id, home_team_id, away_team_id, match_date,
+			home_score, away_score, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		RETURNING id
+	`, match.ExternalID, match.LeagueID, match.GameweekID, match.HomeTeamID, match.AwayTeamID, match.MatchDate,
+		match.HomeScore, match.AwayScore, match.Status, now).Scan(&match.ID)
+	if err != nil {
+		return fmt.Errorf("error creating match: %w", err)
+	}
+	return nil
+}
+
+// updateMatch stores the changes to an existing match
+func updateMatch(tx *sqlx.Tx, match *models.Match, now time.Time) error {
+	match.UpdatedAt = now
+	_, err := tx.Exec(`
+		UPDATE matches
+		SET league_id = $1, gameweek_id = $2, home_team_id = $3, away_team_id = $4, match_date = $5,
+			home_score = $6, away_score = $7, status = $8, updated_at = $9
+		WHERE id = $10
+	`, match.LeagueID, match.GameweekID, match.HomeTeamID, match.AwayTeamID, match.MatchDate,
+		match.HomeScore, match.AwayScore, match.Status, now, match.ID)
+	if err != nil {
+		return fmt.Errorf("error updating match: %w", err)
+	}
+	return nil
+}
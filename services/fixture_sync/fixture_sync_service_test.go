@@ -0,0 +1,239 @@
+package fixture_sync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/external"
+	"go-app/mocks"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDB *database.TestDB
+
+func TestMain(m *testing.M) {
+	// Initialize test database
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	// Run tests
+	m.Run()
+}
+
+func TestFixtureSyncService(t *testing.T) {
+	t.Run("SyncFixtures", func(t *testing.T) {
+		defer testDB.Clear()
+		db := testDB.GetDB()
+		now := time.Now()
+
+		teamIDs := make(map[int]int)
+		for _, externalID := range []int{1, 2} {
+			var teamID int
+			err := db.QueryRow(`
+				INSERT INTO teams (name, external_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $3)
+				RETURNING id
+			`, fmt.Sprintf("Team %d", externalID), externalID, now).Scan(&teamID)
+			assert.NoError(t, err)
+			teamIDs[externalID] = teamID
+		}
+		var gameweekID int
+		err := db.QueryRow(`
+			INSERT INTO gameweeks (number, deadline, created_at, updated_at)
+			VALUES (1, $1, $2, $2)
+			RETURNING id
+		`, now.Add(-time.Hour), now).Scan(&gameweekID)
+		assert.NoError(t, err)
+
+		kickoff := time.Date(2023, 8, 12, 14, 0, 0, 0, time.UTC)
+		fixtures := []*external.Fixture{
+			{ExternalID: 1001, LeagueID: 39, Round: "Regular Season - 1", HomeTeamExternalID: 1, AwayTeamExternalID: 2,
+				Kickoff: kickoff, Status: models.MatchStatusScheduled},
+			{ExternalID: 1002, LeagueID: 39, Round: "Regular Season - 2", HomeTeamExternalID: 2, AwayTeamExternalID: 1,
+				Kickoff: kickoff.Add(7 * 24 * time.Hour), Status: models.MatchStatusScheduled},
+			// Team 3 has not been synced
+			{ExternalID: 1003, LeagueID: 39, Round: "Regular Season - 1", HomeTeamExternalID: 3, AwayTeamExternalID: 1,
+				Kickoff: kickoff, Status: models.MatchStatusScheduled},
+			// API-Football gave a status that is not mapped
+			{ExternalID: 1004, LeagueID: 39, Round: "Regular Season - 1", HomeTeamExternalID: 1, AwayTeamExternalID: 2,
+				Kickoff: kickoff, APIStatus: "XYZ"},
+		}
+		client := mocks.NewMockAPIFootballClient(nil, nil).WithFixtures(fixtures)
+		fixtureSyncService := NewFixtureSyncService(db, client)
+
+		result, err := fixtureSyncService.SyncFixtures()
+		assert.NoError(t, err)
+		assert.Equal(t, &SyncResult{Created: 2, Skipped: 2}, result)
+
+		match := &models.Match{}
+		err = db.Get(match, "SELECT * FROM matches WHERE external_id = 1001")
+		assert.NoError(t, err)
+		assert.Equal(t, teamIDs[1], match.HomeTeamID)
+		assert.Equal(t, teamIDs[2], match.AwayTeamID)
+		assert.Equal(t, 39, match.LeagueID)
+		if assert.NotNil(t, match.GameweekID) {
+			assert.Equal(t, gameweekID, *match.GameweekID)
+		}
+		// Gameweek 2 has not been created yet
+		err = db.Get(match, "SELECT * FROM matches WHERE external_id = 1002")
+		assert.NoError(t, err)
+		assert.Nil(t, match.GameweekID)
+
+		// Syncing again without changes leaves the matches alone
+		result, err = fixtureSyncService.SyncFixtures()
+		assert.NoError(t, err)
+		assert.Equal(t, &SyncResult{Skipped: 2}, result)
+
+		// The first fixture is played and the second postponed after being moved
+		fixtures[0].Status = models.MatchStatusCompleted
+		fixtures[0].HomeScore = 2
+		fixtures[0].AwayScore = 1
+		fixtures[1].Kickoff = kickoff.Add(9 * 24 * time.Hour)
+		fixtures[1].Status = models.MatchStatusPostponed
+		result, err = fixtureSyncService.SyncFixtures()
+		assert.NoError(t, err)
+		assert.Equal(t, &SyncResult{Updated: 2, Rescheduled: 1, Postponed: 1, Skipped: 2}, result)
+
+		err = db.Get(match, "SELECT * FROM matches WHERE external_id = 1001")
+		assert.NoError(t, err)
+		assert.Equal(t, models.MatchStatusCompleted, match.Status)
+		assert.Equal(t, 2, match.HomeScore)
+		assert.Equal(t, 1, match.AwayScore)
+		err = db.Get(match, "SELECT * FROM matches WHERE external_id = 1002")
+		assert.NoError(t, err)
+		assert.Equal(t, models.MatchStatusPostponed, match.Status)
+		assert.True(t, match.MatchDate.Equal(kickoff.Add(9*24*time.Hour)))
+
+		var count int
+		err = db.Get(&count, "SELECT COUNT(*) FROM matches")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Rearranged fixtures move to the gameweek of their new kickoff", func(t *testing.T) {
+		defer testDB.Clear()
+		db := testDB.GetDB()
+		now := time.Now()
+
+		for _, externalID := range []int{1, 2} {
+			_, err := db.Exec(`
+				INSERT INTO teams (name, external_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $3)
+			`, fmt.Sprintf("Team %d", externalID), externalID, now)
+			assert.NoError(t, err)
+		}
+		kickoff := time.Date(2023, 8, 12, 14, 0, 0, 0, time.UTC)
+		gameweekIDs := make(map[int]int)
+		for number := 1; number <= 4; number++ {
+			var gameweekID int
+			err := db.QueryRow(`
+				INSERT INTO gameweeks (number, deadline, created_at, updated_at)
+				VALUES ($1, $2, $3, $3)
+				RETURNING id
+			`, number, kickoff.Add(time.Duration(number-1)*7*24*time.Hour-2*time.Hour), now).Scan(&gameweekID)
+			assert.NoError(t, err)
+			gameweekIDs[number] = gameweekID
+		}
+
+		fixtures := []*external.Fixture{
+			{ExternalID: 1001, LeagueID: 39, Round: "Regular Season - 1", HomeTeamExternalID: 1, AwayTeamExternalID: 2,
+				Kickoff: kickoff, Status: models.MatchStatusScheduled},
+		}
+		client := mocks.NewMockAPIFootballClient(nil, nil).WithFixtures(fixtures)
+		fixtureSyncService := NewFixtureSyncService(db, client)
+		_, err := fixtureSyncService.SyncFixtures()
+		assert.NoError(t, err)
+
+		gameweekOf := func() int {
+			match := &models.Match{}
+			err := db.Get(match, "SELECT * FROM matches WHERE external_id = 1001")
+			assert.NoError(t, err)
+			if !assert.NotNil(t, match.GameweekID) {
+				return 0
+			}
+			return *match.GameweekID
+		}
+		assert.Equal(t, gameweekIDs[1], gameweekOf())
+
+		// Postponed without a new date, the match stays where it was
+		fixtures[0].Kickoff = kickoff.Add(time.Hour)
+		fixtures[0].Status = models.MatchStatusPostponed
+		_, err = fixtureSyncService.SyncFixtures()
+		assert.NoError(t, err)
+		assert.Equal(t, gameweekIDs[1], gameweekOf())
+
+		// Replayed in the third gameweek, it is scored there although its round is still 1
+		fixtures[0].Kickoff = kickoff.Add(15 * 24 * time.Hour)
+		fixtures[0].Status = models.MatchStatusScheduled
+		result, err := fixtureSyncService.SyncFixtures()
+		assert.NoError(t, err)
+		assert.Equal(t, &SyncResult{Updated: 1, Rescheduled: 1}, result)
+		assert.Equal(t, gameweekIDs[3], gameweekOf())
+
+		// Syncing again does not put it back in its round's gameweek
+		_, err = fixtureSyncService.SyncFixtures()
+		assert.NoError(t, err)
+		assert.Equal(t, gameweekIDs[3], gameweekOf())
+	})
+}
+
+func TestGameweekAt(t *testing.T) {
+	start := time.Date(2023, 8, 11, 17, 30, 0, 0, time.UTC)
+	gameweeks := []*models.Gameweek{
+		{ID: 11, Number: 1, Deadline: start},
+		{ID: 12, Number: 2, Deadline: start.Add(7 * 24 * time.Hour)},
+		{ID: 13, Number: 3, Deadline: start.Add(14 * 24 * time.Hour)},
+	}
+	tests := []struct {
+		name    string
+		kickoff time.Time
+		id      int
+		ok      bool
+	}{
+		{"before the first deadline", start.Add(-time.Hour), 0, false},
+		{"on a deadline", start.Add(7 * 24 * time.Hour), 12, true},
+		{"between deadlines", start.Add(10 * 24 * time.Hour), 12, true},
+		{"after the last deadline", start.Add(30 * 24 * time.Hour), 13, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := GameweekAt(gameweeks, tt.kickoff)
+			assert.Equal(t, tt.id, id)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestRoundNumber(t *testing.T) {
+	tests := []struct {
+		round  string
+		number int
+		ok     bool
+	}{
+		{"Regular Season - 5", 5, true},
+		{"Regular Season - 38", 38, true},
+		{"12", 12, true},
+		{"Final", 0, false},
+		{"Round of 16", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.round, func(t *testing.T) {
+			number, ok := RoundNumber(tt.round)
+			assert.Equal(t, tt.number, number)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package gameweek
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GameweekService defines the interface for gameweek operations
+type GameweekService interface {
+	CreateGameweek(gameweek *models.Gameweek) (*models.Gameweek, error)
+	GetGameweek(id int) (*models.Gameweek, error)
+	ListGameweeks() ([]*models.Gameweek, error)
+	GetNextGameweek() (*models.Gameweek, error)
+	GetCurrentGameweek() (*models.Gameweek, error)
+	ListMatches(gameweekID int) ([]*models.Match, error)
+}
+
+// Implementation of the GameweekService interface
+type gameweekServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewGameweekService creates a new GameweekService instance
+func NewGameweekService(db *sqlx.DB) GameweekService {
+	return &gameweekServiceImpl{db: db}
+}
+
+// CreateGameweek creates a new gameweek
+func (s *gameweekServiceImpl) CreateGameweek(gameweek *models.Gameweek) (*models.Gameweek, error) {
+	if gameweek.Number <= 0 {
+		return nil, fmt.Errorf("gameweek number must be greater than zero")
+	}
+	if gameweek.Deadline.IsZero() {
+		return nil, fmt.Errorf("deadline is required")
+	}
+
+	now := time.Now()
+	gameweek.CreatedAt = now
+	gameweek.UpdatedAt = now
+
+	err := s.db.QueryRow(`
+		INSERT INTO gameweeks (number, deadline, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, gameweek.Number, gameweek.Deadline, gameweek.CreatedAt, gameweek.UpdatedAt).Scan(&gameweek.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating gameweek: %w", err)
+	}
+
+	if err := LoadStates(s.db, now, gameweek); err != nil {
+		return nil, err
+	}
+	return gameweek, nil
+}
+
+// GetGameweek retrieves a gameweek by ID, or nil if it does not exist
+func (s *gameweekServiceImpl) GetGameweek(id int) (*models.Gameweek, error) {
+	gameweek := &models.Gameweek{}
+	err := s.db.Get(gameweek, "SELECT * FROM gameweeks WHERE id = $1", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := LoadStates(s.db, time.Now(), gameweek); err != nil {
+		return nil, err
+	}
+	return gameweek, nil
+}
+
+// ListGameweeks retrieves every gameweek in order
+func (s *gameweekServiceImpl) ListGameweeks() ([]*models.Gameweek, error) {
+	var gameweeks []*models.Gameweek
+	err := s.db.Select(&gameweeks, "SELECT * FROM gameweeks ORDER BY number")
+	if err != nil {
+		return nil, err
+	}
+	if err := LoadStates(s.db, time.Now(), gameweeks...); err != nil {
+		return nil, err
+	}
+	return gameweeks, nil
+}
+
+// GetNextGameweek retrieves the first gameweek whose deadline has not yet passed, or nil if there is none
+func (s *gameweekServiceImpl) GetNextGameweek() (*models.Gameweek, error) {
+	now := time.Now()
+	gameweek, err := LoadNextGameweek(s.db, now)
+	if err != nil || gameweek == nil {
+		return nil, err
+	}
+	if err := LoadStates(s.db, now, gameweek); err != nil {
+		return nil, err
+	}
+	return gameweek, nil
+}
+
+// GetCurrentGameweek retrieves the gameweek being played or last played, or nil if the first
+// deadline has not yet passed
+func (s *gameweekServiceImpl) GetCurrentGameweek() (*models.Gameweek, error) {
+	now := time.Now()
+	gameweek, err := LoadCurrentGameweek(s.db, now)
+	if err != nil || gameweek == nil {
+		return nil, err
+	}
+	if err := LoadStates(s.db, now, gameweek); err != nil {
+		return nil, err
+	}
+	return gameweek, nil
+}
+
+// ListMatches retrieves the matches in a gameweek in kick-off order
+func (s *gameweekServiceImpl) ListMatches(gameweekID int) ([]*models.Match, error) {
+	var id int
+	if err := s.db.QueryRow("SELECT id FROM gameweeks WHERE id = $1", gameweekID).Scan(&id); err != nil {
+		return nil, err
+	}
+	matches := make([]*models.Match, 0)
+	err := s.db.Select(&matches, "SELECT * FROM matches WHERE gameweek_id = $1 ORDER BY match_date, id", gameweekID)
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// LoadNextGameweek retrieves the first gameweek whose deadline is after now, or nil if there is none
+func LoadNextGameweek(q sqlx.Queryer, now time.Time) (*models.Gameweek, error) {
+	gameweek := &models.Gameweek{}
+	err := sqlx.Get(q, gameweek, "SELECT * FROM gameweeks WHERE deadline > $1 ORDER BY number LIMIT 1", now)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return gameweek, nil
+}
+
+// LoadCurrentGameweek retrieves the last gameweek whose deadline is at or before now, which is
+// the one being played or last played, or nil if the season has not started
+func LoadCurrentGameweek(q sqlx.Queryer, now time.Time) (*models.Gameweek, error) {
+	gameweek := &models.Gameweek{}
+	err := sqlx.Get(q, gameweek, "SELECT * FROM gameweeks WHERE deadline <= $1 ORDER BY number DESC LIMIT 1", now)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return gameweek, nil
+}
+
+// State works out where a gameweek is in its lifecycle at a time from the statuses of its
+// matches. open says whether the gameweek has the next deadline. Once the deadline passes a
+// gameweek is locked until one of its matches kicks off, then live until every match is
+// completed or postponed. A gameweek without matches stays locked, as there is nothing to play.
+func State(gameweek *models.Gameweek, open bool, statuses []string, now time.Time) models.GameweekState {
+	if now.Before(gameweek.Deadline) {
+		if open {
+			return models.GameweekStateOpen
+		}
+		return models.GameweekStateUpcoming
+	}
+
+	started, finished := false, len(statuses) > 0
+	for _, status := range statuses {
+		switch status {
+		case models.MatchStatusInProgress:
+			started, finished = true, false
+		case models.MatchStatusCompleted:
+			started = true
+		case models.MatchStatusScheduled:
+			finished = false
+		}
+	}
+	switch {
+	case finished:
+		return models.GameweekStateFinalized
+	case started:
+		return models.GameweekStateLive
+	default:
+		return models.GameweekStateLocked
+	}
+}
+
+// LoadStates sets the state of each gameweek at a time
+func LoadStates(q sqlx.Queryer, now time.Time, gameweeks ...*models.Gameweek) error {
+	if len(gameweeks) == 0 {
+		return nil
+	}
+	next, err := LoadNextGameweek(q, now)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, len(gameweeks))
+	for i, gameweek := range gameweeks {
+		ids[i] = gameweek.ID
+	}
+	query, args, err := sqlx.In("SELECT gameweek_id, status FROM matches WHERE gameweek_id IN (?)", ids)
+	if err != nil {
+		return err
+	}
+	var rows []struct {
+		GameweekID int    `db:"gameweek_id"`
+		Status     string `db:"status"`
+	}
+	if err := sqlx.Select(q, &rows, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return err
+	}
+	statuses := make(map[int][]string)
+	for _, row := range rows {
+		statuses[row.GameweekID] = append(statuses[row.GameweekID], row.Status)
+	}
+
+	for _, gameweek := range gameweeks {
+		open := next != nil && next.ID == gameweek.ID
+		gameweek.State = State(gameweek, open, statuses[gameweek.ID], now)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package gameweek
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB          *database.TestDB
+	gameweekService GameweekService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	gameweekService = NewGameweekService(testDB.GetDB())
+	m.Run()
+}
+
+func TestGameweekService(t *testing.T) {
+	t.Run("CreateGameweek", func(t *testing.T) {
+		defer testDB.Clear()
+
+		created, err := gameweekService.CreateGameweek(&models.Gameweek{Number: 1, Deadline: time.Now()})
+		assert.NoError(t, err)
+		assert.NotZero(t, created.ID)
+
+		// Duplicate number
+		_, err = gameweekService.CreateGameweek(&models.Gameweek{Number: 1, Deadline: time.Now()})
+		assert.Error(t, err)
+
+		// Missing deadline
+		_, err = gameweekService.CreateGameweek(&models.Gameweek{Number: 2})
+		assert.Error(t, err)
+	})
+
+	t.Run("GetNextGameweek", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, err := gameweekService.CreateGameweek(&models.Gameweek{Number: 1, Deadline: time.Now().Add(-time.Hour)})
+		assert.NoError(t, err)
+		second, err := gameweekService.CreateGameweek(&models.Gameweek{Number: 2, Deadline: time.Now().Add(time.Hour)})
+		assert.NoError(t, err)
+
+		next, err := gameweekService.GetNextGameweek()
+		assert.NoError(t, err)
+		if assert.NotNil(t, next) {
+			assert.Equal(t, second.ID, next.ID)
+		}
+
+		missing, err := gameweekService.GetGameweek(second.ID + 100)
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+	t.Run("states follow the clock and match statuses", func(t *testing.T) {
+		defer testDB.Clear()
+		db := testDB.GetDB()
+		now := time.Now()
+
+		finished, err := gameweekService.CreateGameweek(&models.Gameweek{Number: 1, Deadline: now.Add(-48 * time.Hour)})
+		assert.NoError(t, err)
+		current, err := gameweekService.CreateGameweek(&models.Gameweek{Number: 2, Deadline: now.Add(-time.Hour)})
+		assert.NoError(t, err)
+		next, err := gameweekService.CreateGameweek(&models.Gameweek{Number: 3, Deadline: now.Add(time.Hour)})
+		assert.NoError(t, err)
+		later, err := gameweekService.CreateGameweek(&models.Gameweek{Number: 4, Deadline: now.Add(48 * time.Hour)})
+		assert.NoError(t, err)
+
+		var teamID int
+		err = db.QueryRow(`
+			INSERT INTO teams (name, external_id, created_at, updated_at)
+			VALUES ('Gameweek Club', 1, $1, $1)
+			RETURNING id
+		`, now).Scan(&teamID)
+		assert.NoError(t, err)
+		for _, match := range []struct {
+			gameweekID int
+			status     string
+		}{
+			{finished.ID, models.MatchStatusCompleted},
+			{finished.ID, models.MatchStatusPostponed},
+			{current.ID, models.MatchStatusCompleted},
+			{current.ID, models.MatchStatusScheduled},
+		} {
+			_, err := db.Exec(`
+				INSERT INTO matches (home_team_id, away_team_id, match_date, status, gameweek_id)
+				VALUES ($1, $1, $2, $3, $4)
+			`, teamID, now, match.status, match.gameweekID)
+			assert.NoError(t, err)
+		}
+
+		gameweeks, err := gameweekService.ListGameweeks()
+		assert.NoError(t, err)
+		states := make(map[int]models.GameweekState)
+		for _, gameweek := range gameweeks {
+			states[gameweek.ID] = gameweek.State
+		}
+		assert.Equal(t, models.GameweekStateFinalized, states[finished.ID])
+		assert.Equal(t, models.GameweekStateLive, states[current.ID])
+		assert.Equal(t, models.GameweekStateOpen, states[next.ID])
+		assert.Equal(t, models.GameweekStateUpcoming, states[later.ID])
+
+		got, err := gameweekService.GetCurrentGameweek()
+		assert.NoError(t, err)
+		if assert.NotNil(t, got) {
+			assert.Equal(t, current.ID, got.ID)
+			assert.Equal(t, models.GameweekStateLive, got.State)
+		}
+
+		matches, err := gameweekService.ListMatches(current.ID)
+		assert.NoError(t, err)
+		assert.Len(t, matches, 2)
+		matches, err = gameweekService.ListMatches(later.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, matches)
+		_, err = gameweekService.ListMatches(later.ID + 100)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestState(t *testing.T) {
+	now := time.Now()
+	past := &models.Gameweek{Deadline: now.Add(-time.Hour)}
+	future := &models.Gameweek{Deadline: now.Add(time.Hour)}
+
+	tests := []struct {
+		name     string
+		gameweek *models.Gameweek
+		open     bool
+		statuses []string
+		want     models.GameweekState
+	}{
+		{"next deadline", future, true, nil, models.GameweekStateOpen},
+		{"later deadline", future, false, nil, models.GameweekStateUpcoming},
+		{"deadline passed before kick-off", past, false, []string{"scheduled", "scheduled"}, models.GameweekStateLocked},
+		{"no matches", past, false, nil, models.GameweekStateLocked},
+		{"match in progress", past, false, []string{"in_progress", "scheduled"}, models.GameweekStateLive},
+		{"between matches", past, false, []string{"completed", "scheduled"}, models.GameweekStateLive},
+		{"every match over", past, false, []string{"completed", "postponed"}, models.GameweekStateFinalized},
+		{"every match postponed", past, false, []string{"postponed"}, models.GameweekStateFinalized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, State(tt.gameweek, tt.open, tt.statuses, now))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package incident
+
+import (
+	"fmt"
+	"time"
+
+	"go-app/models"
+	"go-app/services/scoring"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// pointsKey identifies a player's gameweek points in a league
+type pointsKey struct {
+	leagueID int
+	playerID int
+}
+
+// Correct handles an incident of a match being added, changed or removed after the match has
+// finished. It rescores the match's gameweek, which team scores and standings are worked out
+// from, and logs the correction with the points of the players it moved: the players of the
+// incident, and players in the match whose bonus changed as a result. Movement from other matches
+// of the gameweek that had not been rescored yet is not put down to the correction. A gameweek
+// that has not been scored yet is left to be scored as usual. before is nil for an added
+// incident and after nil for a removed one. Nothing is done for a match that is not finished or
+// not in a gameweek, as its points are still to be scored, and nil is returned.
+func Correct(tx *sqlx.Tx, match *models.Match, before, after *models.MatchIncident, now time.Time) (*models.StatCorrection, error) {
+	if match.Status != models.MatchStatusCompleted || match.GameweekID == nil {
+		return nil, nil
+	}
+
+	correction := &models.StatCorrection{
+		MatchID:    match.ID,
+		GameweekID: *match.GameweekID,
+		CreatedAt:  now,
+	}
+	switch {
+	case before == nil:
+		correction.Action = models.CorrectionActionAdded
+	case after == nil:
+		correction.Action = models.CorrectionActionRemoved
+	default:
+		correction.Action = models.CorrectionActionChanged
+	}
+	if before != nil {
+		correction.IncidentID = before.ID
+		correction.OldPlayerID = &before.PlayerID
+		correction.OldType = &before.Type
+		correction.OldMinute = &before.Minute
+	}
+	if after != nil {
+		correction.IncidentID = after.ID
+		correction.NewPlayerID = &after.PlayerID
+		correction.NewType = &after.Type
+		correction.NewMinute = &after.Minute
+	}
+
+	var previous []*models.PlayerPoints
+	if err := tx.Select(&previous, "SELECT * FROM player_points WHERE gameweek_id = $1", correction.GameweekID); err != nil {
+		return nil, err
+	}
+	var rescored []*models.PlayerPoints
+	if len(previous) > 0 {
+		var err error
+		if rescored, err = scoring.ScoreGameweek(tx, correction.GameweekID, now); err != nil {
+			return nil, fmt.Errorf("error rescoring gameweek: %w", err)
+		}
+	}
+
+	involved := make(map[int]bool)
+	for _, incident := range []*models.MatchIncident{before, after} {
+		if incident != nil {
+			involved[incident.PlayerID] = true
+		}
+	}
+	var inMatch []int
+	err := tx.Select(&inMatch, `
+		SELECT player_id FROM player_stats WHERE match_id = $1
+		UNION
+		SELECT player_id FROM match_incidents WHERE match_id = $1
+	`, match.ID)
+	if err != nil {
+		return nil, err
+	}
+	played := make(map[int]bool, len(inMatch))
+	for _, playerID := range inMatch {
+		played[playerID] = true
+	}
+
+	// Players can drop out of the points altogether, or come into them, as well as move
+	changes := make(map[pointsKey]*models.PointChange)
+	oldBonus := make(map[pointsKey]int)
+	newBonus := make(map[pointsKey]int)
+	var order []pointsKey
+	change := func(p *models.PlayerPoints) *models.PointChange {
+		key := pointsKey{leagueID: p.LeagueID, playerID: p.PlayerID}
+		c, ok := changes[key]
+		if !ok {
+			c = &models.PointChange{LeagueID: p.LeagueID, PlayerID: p.PlayerID}
+			changes[key] = c
+			order = append(order, key)
+		}
+		return c
+	}
+	for _, p := range previous {
+		change(p).OldPoints = p.Points
+		oldBonus[pointsKey{leagueID: p.LeagueID, playerID: p.PlayerID}] = p.Bonus
+	}
+	for _, p := range rescored {
+		change(p).NewPoints = p.Points
+		newBonus[pointsKey{leagueID: p.LeagueID, playerID: p.PlayerID}] = p.Bonus
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stat_corrections (match_id, gameweek_id, incident_id, action, old_player_id, old_type,
+			old_minute, new_player_id, new_type, new_minute, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, correction.MatchID, correction.GameweekID, correction.IncidentID, correction.Action, correction.OldPlayerID,
+		correction.OldType, correction.OldMinute, correction.NewPlayerID, correction.NewType, correction.NewMinute,
+		now).Scan(&correction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error logging correction: %w", err)
+	}
+
+	correction.PointChanges = []*models.PointChange{}
+	for _, key := range order {
+		c := changes[key]
+		if c.OldPoints == c.NewPoints {
+			continue
+		}
+		bonusMoved := played[key.playerID] && oldBonus[key] != newBonus[key]
+		if !involved[key.playerID] && !bonusMoved {
+			continue
+		}
+		c.CorrectionID = correction.ID
+		_, err := tx.NamedExec(`
+			INSERT INTO stat_correction_points (correction_id, league_id, player_id, old_points, new_points)
+			VALUES (:correction_id, :league_id, :player_id, :old_points, :new_points)
+		`, c)
+		if err != nil {
+			return nil, fmt.Errorf("error logging point change: %w", err)
+		}
+		correction.PointChanges = append(correction.PointChanges, c)
+	}
+	return correction, nil
+}
+
+// ListCorrections retrieves the corrections that moved points in a league, the latest first,
+// each with the league's point changes
+func (s *incidentServiceImpl) ListCorrections(leagueID int) ([]*models.StatCorrection, error) {
+	corrections := make([]*models.StatCorrection, 0)
+	err := s.db.Select(&corrections, `
+		SELECT c.*
+		FROM stat_corrections c
+		WHERE EXISTS (SELECT 1 FROM stat_correction_points p WHERE p.correction_id = c.id AND p.league_id = $1)
+		ORDER BY c.created_at DESC, c.id DESC
+	`, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	if len(corrections) == 0 {
+		return corrections, nil
+	}
+
+	byID := make(map[int]*models.StatCorrection, len(corrections))
+	ids := make([]int, len(corrections))
+	for i, correction := range corrections {
+		correction.PointChanges = []*models.PointChange{}
+		byID[correction.ID] = correction
+		ids[i] = correction.ID
+	}
+	query, args, err := sqlx.In(`
+		SELECT * FROM stat_correction_points
+		WHERE league_id = ? AND correction_id IN (?)
+		ORDER BY player_id
+	`, leagueID, ids)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*models.PointChange
+	if err := s.db.Select(&changes, s.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		byID[change.CorrectionID].PointChanges = append(byID[change.CorrectionID].PointChanges, change)
+	}
+	return corrections, nil
+}
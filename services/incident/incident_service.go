@@ -0,0 +1,250 @@
+package incident
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrIncidentNotFound is returned when an incident does not exist in the given match
+	ErrIncidentNotFound = errors.New("incident not found")
+	// ErrInvalidIncident is returned when an incident has an unknown type, player or minute
+	ErrInvalidIncident = errors.New("invalid incident")
+)
+
+// validTypes are the incident types a match can record
+var validTypes = map[models.IncidentType]bool{
+	models.IncidentTypeGoal:          true,
+	models.IncidentTypeAssist:        true,
+	models.IncidentTypeYellowCard:    true,
+	models.IncidentTypeRedCard:       true,
+	models.IncidentTypeSubstitution:  true,
+	models.IncidentTypeCleanSheet:    true,
+	models.IncidentTypePenaltyScored: true,
+	models.IncidentTypePenaltyMissed: true,
+	models.IncidentTypePenaltySaved:  true,
+	models.IncidentTypeOwnGoal:       true,
+}
+
+// incidentColumns selects an incident with a missing description read as empty
+const incidentColumns = `id, match_id, player_id, type, minute, COALESCE(description, '') AS description, external_key, created_at, updated_at`
+
+// IncidentService defines the interface for recording what happens in matches
+type IncidentService interface {
+	ListIncidents(matchID int) ([]*models.MatchIncident, error)
+	AddIncident(incident *models.MatchIncident) (*models.MatchIncident, error)
+	UpdateIncident(incident *models.MatchIncident) (*models.MatchIncident, error)
+	DeleteIncident(matchID, incidentID int) error
+	ListCorrections(leagueID int) ([]*models.StatCorrection, error)
+}
+
+// Implementation of the IncidentService interface
+type incidentServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewIncidentService creates a new IncidentService instance
+func NewIncidentService(db *sqlx.DB) IncidentService {
+	return &incidentServiceImpl{db: db}
+}
+
+// ListIncidents retrieves the incidents of a match in the order they happened
+func (s *incidentServiceImpl) ListIncidents(matchID int) ([]*models.MatchIncident, error) {
+	if err := s.db.QueryRow("SELECT id FROM matches WHERE id = $1", matchID).Scan(&matchID); err != nil {
+		return nil, err
+	}
+	incidents := make([]*models.MatchIncident, 0)
+	err := s.db.Select(&incidents, `
+		SELECT `+incidentColumns+`
+		FROM match_incidents
+		WHERE match_id = $1
+		ORDER BY minute, id
+	`, matchID)
+	if err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// AddIncident records an incident in a match. Adding one to a finished match is a correction.
+func (s *incidentServiceImpl) AddIncident(incident *models.MatchIncident) (*models.MatchIncident, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	match, err := lockMatch(tx, incident.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := Insert(tx, incident, now); err != nil {
+		return nil, err
+	}
+	if _, err := Correct(tx, match, nil, incident, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+// UpdateIncident changes the player, type, minute or description of an incident. Changing one in
+// a finished match is a correction.
+func (s *incidentServiceImpl) UpdateIncident(incident *models.MatchIncident) (*models.MatchIncident, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	match, err := lockMatch(tx, incident.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	before, err := loadIncident(tx, incident.MatchID, incident.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := Update(tx, incident, now); err != nil {
+		return nil, err
+	}
+	if _, err := Correct(tx, match, before, incident, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+// DeleteIncident removes an incident from a match. Removing one from a finished match is a
+// correction.
+func (s *incidentServiceImpl) DeleteIncident(matchID, incidentID int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	match, err := lockMatch(tx, matchID)
+	if err != nil {
+		return err
+	}
+	before, err := loadIncident(tx, matchID, incidentID)
+	if err != nil {
+		return err
+	}
+	if err := Delete(tx, before); err != nil {
+		return err
+	}
+	if _, err := Correct(tx, match, before, nil, time.Now()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing incident: %w", err)
+	}
+
+	return nil
+}
+
+// lockMatch retrieves a match and locks it until the transaction ends, so its incidents change
+// one at a time
+func lockMatch(tx *sqlx.Tx, matchID int) (*models.Match, error) {
+	match := &models.Match{}
+	if err := tx.Get(match, "SELECT * FROM matches WHERE id = $1 FOR UPDATE", matchID); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// loadIncident retrieves an incident of a match
+func loadIncident(q sqlx.Queryer, matchID, incidentID int) (*models.MatchIncident, error) {
+	incident := &models.MatchIncident{}
+	err := sqlx.Get(q, incident, "SELECT "+incidentColumns+" FROM match_incidents WHERE id = $1 AND match_id = $2", incidentID, matchID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: incident %d in match %d", ErrIncidentNotFound, incidentID, matchID)
+		}
+		return nil, err
+	}
+	return incident, nil
+}
+
+// validate checks that an incident has a known type, an existing player and a sensible minute
+func validate(q sqlx.Queryer, incident *models.MatchIncident) error {
+	if !validTypes[incident.Type] {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidIncident, incident.Type)
+	}
+	if incident.Minute < 0 {
+		return fmt.Errorf("%w: the minute cannot be negative", ErrInvalidIncident)
+	}
+	var count int
+	if err := q.QueryRowx("SELECT COUNT(*) FROM players WHERE id = $1", incident.PlayerID).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: player %d not found", ErrInvalidIncident, incident.PlayerID)
+	}
+	return nil
+}
+
+// Insert validates and stores a new incident
+func Insert(tx *sqlx.Tx, incident *models.MatchIncident, now time.Time) error {
+	if err := validate(tx, incident); err != nil {
+		return err
+	}
+	incident.CreatedAt = now
+	incident.UpdatedAt = now
+	err := tx.QueryRow(`
+		INSERT INTO match_incidents (match_id, player_id, type, minute, description, external_key, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		RETURNING id
+	`, incident.MatchID, incident.PlayerID, incident.Type, incident.Minute, incident.Description, incident.ExternalKey,
+		now).Scan(&incident.ID)
+	if err != nil {
+		return fmt.Errorf("error creating incident: %w", err)
+	}
+	return nil
+}
+
+// Update validates and stores the changes to an existing incident. An incident synced from
+// API-Football keeps its external key, so a manual edit is not undone by the next sync.
+func Update(tx *sqlx.Tx, incident *models.MatchIncident, now time.Time) error {
+	if err := validate(tx, incident); err != nil {
+		return err
+	}
+	incident.UpdatedAt = now
+	err := tx.QueryRow(`
+		UPDATE match_incidents
+		SET player_id = $1, type = $2, minute = $3, description = $4, updated_at = $5
+		WHERE id = $6 AND match_id = $7
+		RETURNING created_at, external_key
+	`, incident.PlayerID, incident.Type, incident.Minute, incident.Description, now, incident.ID, incident.MatchID).Scan(&incident.CreatedAt, &incident.ExternalKey)
+	if err != nil {
+		return fmt.Errorf("error updating incident: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an incident
+func Delete(tx *sqlx.Tx, incident *models.MatchIncident) error {
+	if _, err := tx.Exec("DELETE FROM match_incidents WHERE id = $1", incident.ID); err != nil {
+		return fmt.Errorf("error deleting incident: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package incident
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/scoring"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB          *database.TestDB
+	incidentService IncidentService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	incidentService = NewIncidentService(testDB.GetDB())
+	m.Run()
+}
+
+func TestIncidentService(t *testing.T) {
+	db := testDB.GetDB()
+	scoringService := scoring.NewScoringService(db)
+
+	// setup creates a gameweek with a match between the clubs of a forward and a midfielder, both
+	// fielded by a user team with the forward as captain
+	setup := func(status string) (leagueID, userTeamID, gameweekID, matchID int, playerIDs []int) {
+		now := time.Now()
+		err := db.QueryRow(`
+			INSERT INTO gameweeks (number, deadline, created_at, updated_at)
+			VALUES (1, $1, $2, $2)
+			RETURNING id
+		`, now.Add(-time.Hour), now).Scan(&gameweekID)
+		assert.NoError(t, err)
+
+		var teamID int
+		err = db.QueryRow(`
+			INSERT INTO teams (name, external_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, "Incident Club", now.UnixNano()%100000, now, now).Scan(&teamID)
+		assert.NoError(t, err)
+		playerIDs = make([]int, 2)
+		for i, position := range []models.Position{models.PositionFWD, models.PositionMID} {
+			err := db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", i), position, now, now).Scan(&playerIDs[i])
+			assert.NoError(t, err)
+		}
+
+		err = db.QueryRow(`
+			INSERT INTO matches (home_team_id, away_team_id, match_date, status, gameweek_id)
+			VALUES ($1, $1, $2, $3, $4)
+			RETURNING id
+		`, teamID, now, status, gameweekID).Scan(&matchID)
+		assert.NoError(t, err)
+		for _, playerID := range playerIDs {
+			_, err := db.Exec("INSERT INTO player_stats (player_id, match_id, minutes_played) VALUES ($1, $2, 90)", playerID, matchID)
+			assert.NoError(t, err)
+		}
+
+		var userID int
+		err = db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("FIX%d", now.UnixNano()), "Correction League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, "Correction", "Manager", fmt.Sprintf("correction_%d@example.com", now.UnixNano()), "password", now, now).Scan(&userID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, userID, leagueID, "Correction Team", now, now).Scan(&userTeamID)
+		assert.NoError(t, err)
+		for i, playerID := range playerIDs {
+			_, err := db.Exec(`
+				INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $3)
+			`, userTeamID, playerID, now)
+			assert.NoError(t, err)
+			_, err = db.Exec(`
+				INSERT INTO lineups (user_team_id, gameweek_id, player_id, slot, is_captain, is_vice_captain)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, userTeamID, gameweekID, playerID, i+1, i == 0, i == 1)
+			assert.NoError(t, err)
+		}
+		return
+	}
+
+	points := func(leagueID, gameweekID int) map[int]int {
+		stored, err := scoringService.ListGameweekPoints(leagueID, gameweekID)
+		assert.NoError(t, err)
+		byPlayer := make(map[int]int, len(stored))
+		for _, p := range stored {
+			byPlayer[p.PlayerID] = p.Points
+		}
+		return byPlayer
+	}
+
+	standing := func(leagueID int) float64 {
+		standings, err := scoringService.GetStandings(leagueID)
+		assert.NoError(t, err)
+		if assert.Len(t, standings, 1) {
+			return standings[0].Points
+		}
+		return 0
+	}
+
+	t.Run("corrections to a finished match move points through to the standings", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, _, gameweekID, matchID, playerIDs := setup(models.MatchStatusCompleted)
+		goal, err := incidentService.AddIncident(&models.MatchIncident{
+			MatchID:  matchID,
+			PlayerID: playerIDs[0],
+			Type:     models.IncidentTypeGoal,
+			Minute:   20,
+		})
+		assert.NoError(t, err)
+
+		// Nothing has been scored yet, so there is nothing to correct
+		corrections, err := incidentService.ListCorrections(leagueID)
+		assert.NoError(t, err)
+		assert.Empty(t, corrections)
+
+		_, err = scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+		// The forward's goal and top bonus doubled for the captain, and the midfielder's second bonus
+		assert.Equal(t, map[int]int{playerIDs[0]: 9, playerIDs[1]: 4}, points(leagueID, gameweekID))
+		assert.Equal(t, float64(22), standing(leagueID))
+
+		// The goal is given to the midfielder
+		goal.PlayerID = playerIDs[1]
+		_, err = incidentService.UpdateIncident(goal)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]int{playerIDs[0]: 4, playerIDs[1]: 10}, points(leagueID, gameweekID))
+		assert.Equal(t, float64(18), standing(leagueID))
+
+		corrections, err = incidentService.ListCorrections(leagueID)
+		assert.NoError(t, err)
+		if assert.Len(t, corrections, 1) {
+			correction := corrections[0]
+			assert.Equal(t, models.CorrectionActionChanged, correction.Action)
+			assert.Equal(t, goal.ID, correction.IncidentID)
+			assert.Equal(t, playerIDs[0], *correction.OldPlayerID)
+			assert.Equal(t, playerIDs[1], *correction.NewPlayerID)
+			assert.Equal(t, []*models.PointChange{
+				{CorrectionID: correction.ID, LeagueID: leagueID, PlayerID: playerIDs[0], OldPoints: 9, NewPoints: 4},
+				{CorrectionID: correction.ID, LeagueID: leagueID, PlayerID: playerIDs[1], OldPoints: 4, NewPoints: 10},
+			}, correction.PointChanges)
+		}
+
+		// With the goal gone the two share the top bonus
+		assert.NoError(t, incidentService.DeleteIncident(matchID, goal.ID))
+		assert.Equal(t, map[int]int{playerIDs[0]: 5, playerIDs[1]: 5}, points(leagueID, gameweekID))
+		assert.Equal(t, float64(15), standing(leagueID))
+
+		corrections, err = incidentService.ListCorrections(leagueID)
+		assert.NoError(t, err)
+		if assert.Len(t, corrections, 2) {
+			assert.Equal(t, models.CorrectionActionRemoved, corrections[0].Action)
+			assert.Nil(t, corrections[0].NewPlayerID)
+		}
+
+		err = incidentService.DeleteIncident(matchID, goal.ID)
+		assert.ErrorIs(t, err, ErrIncidentNotFound)
+	})
+
+	t.Run("points moved by other matches are not put down to a correction", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamID, gameweekID, matchID, playerIDs := setup(models.MatchStatusCompleted)
+		_, err := scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+
+		// Another match of the gameweek kicks off after the gameweek was scored
+		now := time.Now()
+		var teamID, otherMatchID, otherPlayerID int
+		err = db.QueryRow(`
+			INSERT INTO teams (name, external_id, created_at, updated_at)
+			VALUES ('Other Club', $1, $2, $2)
+			RETURNING id
+		`, now.UnixNano()%100000+1, now).Scan(&teamID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+			VALUES ($1, 'Other', 'Player', $2, $3, $3)
+			RETURNING id
+		`, teamID, models.PositionDEF, now).Scan(&otherPlayerID)
+		assert.NoError(t, err)
+		_, err = db.Exec(`
+			INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+		`, userTeamID, otherPlayerID, now)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO matches (home_team_id, away_team_id, match_date, status, gameweek_id)
+			VALUES ($1, $1, $2, $3, $4)
+			RETURNING id
+		`, teamID, now, models.MatchStatusInProgress, gameweekID).Scan(&otherMatchID)
+		assert.NoError(t, err)
+		_, err = db.Exec("INSERT INTO player_stats (player_id, match_id, minutes_played) VALUES ($1, $2, 90)", otherPlayerID, otherMatchID)
+		assert.NoError(t, err)
+
+		_, err = incidentService.AddIncident(&models.MatchIncident{
+			MatchID:  matchID,
+			PlayerID: playerIDs[0],
+			Type:     models.IncidentTypeYellowCard,
+			Minute:   30,
+		})
+		assert.NoError(t, err)
+
+		corrections, err := incidentService.ListCorrections(leagueID)
+		assert.NoError(t, err)
+		if assert.Len(t, corrections, 1) {
+			changed := make(map[int]bool)
+			for _, change := range corrections[0].PointChanges {
+				changed[change.PlayerID] = true
+			}
+			assert.True(t, changed[playerIDs[0]])
+			assert.False(t, changed[otherPlayerID])
+		}
+		// The other match is still scored along with it
+		assert.Contains(t, points(leagueID, gameweekID), otherPlayerID)
+	})
+
+	t.Run("incidents in a match still being played are not corrections", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, _, gameweekID, matchID, playerIDs := setup(models.MatchStatusInProgress)
+		_, err := scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+
+		_, err = incidentService.AddIncident(&models.MatchIncident{
+			MatchID:  matchID,
+			PlayerID: playerIDs[1],
+			Type:     models.IncidentTypeYellowCard,
+			Minute:   55,
+		})
+		assert.NoError(t, err)
+
+		incidents, err := incidentService.ListIncidents(matchID)
+		assert.NoError(t, err)
+		assert.Len(t, incidents, 1)
+		corrections, err := incidentService.ListCorrections(leagueID)
+		assert.NoError(t, err)
+		assert.Empty(t, corrections)
+
+		_, err = incidentService.AddIncident(&models.MatchIncident{
+			MatchID:  matchID,
+			PlayerID: playerIDs[1],
+			Type:     "hat_trick",
+		})
+		assert.ErrorIs(t, err, ErrInvalidIncident)
+	})
+}
@@ -19,6 +19,8 @@ type LeagueService interface {
 	ListLeagues() ([]*models.League, error)
 	ValidateLeague(league *models.League) error
 	GetLeagueByCode(code string) (*models.League, error)
+	GetSettings(leagueID int) (*models.LeagueSettings, error)
+	UpdateSettings(settings *models.LeagueSettings) (*models.LeagueSettings, error)
 }
 
 // Implementation of the LeagueService interface
@@ -213,3 +213,77 @@ func TestLeagueService(t *testing.T) {
 		assert.Nil(t, nonExistentLeague)
 	})
 }
+
+func TestLeagueSettings(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		defer testDB.Clear()
+
+		league, err := leagueService.CreateLeague(&models.League{Name: "Settings League", Code: "SET1"})
+		assert.NoError(t, err)
+
+		settings, err := leagueService.GetSettings(league.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultPickTimeSeconds, settings.PickTimeSeconds)
+		assert.Empty(t, settings.PauseStart)
+		assert.Equal(t, float64(DefaultCaptainMultiplier), settings.CaptainMultiplier)
+		assert.Equal(t, DefaultWaiverPeriodHours, settings.WaiverPeriodHours)
+		assert.Equal(t, DefaultWaiverProcessTime, settings.WaiverProcessTime)
+		assert.Equal(t, models.WaiverModeRolling, settings.WaiverMode)
+		assert.Equal(t, models.TradeReviewLeagueVote, settings.TradeReview)
+		assert.Equal(t, DefaultTradeReviewHours, settings.TradeReviewHours)
+		assert.Equal(t, DefaultTradeVetoVotes, settings.TradeVetoVotes)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		defer testDB.Clear()
+
+		league, err := leagueService.CreateLeague(&models.League{Name: "Settings League", Code: "SET2"})
+		assert.NoError(t, err)
+
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{
+			LeagueID:          league.ID,
+			PickTimeSeconds:   120,
+			PauseStart:        "23:00",
+			PauseEnd:          "07:30",
+			CaptainMultiplier: 3,
+		})
+		assert.NoError(t, err)
+
+		settings, err := leagueService.GetSettings(league.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 120, settings.PickTimeSeconds)
+		assert.Equal(t, "23:00", settings.PauseStart)
+		assert.Equal(t, "UTC", settings.Timezone)
+		assert.Equal(t, float64(3), settings.CaptainMultiplier)
+
+		// A pause needs both ends
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, PauseStart: "23:00"})
+		assert.Error(t, err)
+
+		// The captain cannot score less than other players
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, CaptainMultiplier: 0.5})
+		assert.Error(t, err)
+
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, WaiverPeriodHours: -1})
+		assert.Error(t, err)
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, WaiverProcessTime: "3am"})
+		assert.Error(t, err)
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, WaiverMode: "auction"})
+		assert.Error(t, err)
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, WaiverTieBreak: "coin_toss"})
+		assert.Error(t, err)
+
+		// Commissioner review needs a commissioner from the league
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, TradeReview: models.TradeReviewCommissioner})
+		assert.Error(t, err)
+		commissionerID := 999999
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{
+			LeagueID:           league.ID,
+			TradeReview:        models.TradeReviewCommissioner,
+			CommissionerTeamID: &commissionerID,
+		})
+		assert.Error(t, err)
+		_, err = leagueService.UpdateSettings(&models.LeagueSettings{LeagueID: league.ID, TradeVetoVotes: -1})
+		assert.Error(t, err)
+	})
+}
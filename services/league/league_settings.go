@@ -0,0 +1,245 @@
+package league
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultPickTimeSeconds is the pick clock used by leagues that have not configured one
+const DefaultPickTimeSeconds = 90
+
+// DefaultCaptainMultiplier is the captain's points multiplier in leagues that have not configured one
+const DefaultCaptainMultiplier = 2
+
+// DefaultBudget is what a squad can cost in budget leagues that have not configured a budget
+const DefaultBudget = 100.0
+
+// DefaultWaiverPeriodHours is how long dropped players stay on waivers in leagues that have not configured it
+const DefaultWaiverPeriodHours = 48
+
+// DefaultWaiverProcessTime is when waivers are processed each day in leagues that have not configured it
+const DefaultWaiverProcessTime = "03:00"
+
+// DefaultFAABBudget is what each team can bid on waivers in FAAB leagues that have not configured a budget
+const DefaultFAABBudget = 100
+
+// DefaultTradeReviewHours is how long accepted trades can be vetoed for in leagues that have not configured it
+const DefaultTradeReviewHours = 24
+
+// DefaultTradeVetoVotes is how many managers have to vote against a trade to veto it in leagues that have not configured it
+const DefaultTradeVetoVotes = 3
+
+// DefaultSettings returns the settings a league uses until they are changed
+func DefaultSettings(leagueID int) *models.LeagueSettings {
+	return &models.LeagueSettings{
+		LeagueID:          leagueID,
+		PickTimeSeconds:   DefaultPickTimeSeconds,
+		Timezone:          "UTC",
+		CaptainMultiplier: DefaultCaptainMultiplier,
+		OwnershipMode:     models.OwnershipModeDraft,
+		Budget:            DefaultBudget,
+		WaiverPeriodHours: DefaultWaiverPeriodHours,
+		WaiverProcessTime: DefaultWaiverProcessTime,
+		WaiverMode:        models.WaiverModeRolling,
+		FAABBudget:        DefaultFAABBudget,
+		WaiverTieBreak:    models.WaiverTieBreakPriority,
+		TradeReview:       models.TradeReviewLeagueVote,
+		TradeReviewHours:  DefaultTradeReviewHours,
+		TradeVetoVotes:    DefaultTradeVetoVotes,
+	}
+}
+
+// LoadSettings retrieves a league's settings, falling back to the defaults if none are stored
+func LoadSettings(q sqlx.Queryer, leagueID int) (*models.LeagueSettings, error) {
+	settings := &models.LeagueSettings{}
+	err := sqlx.Get(q, settings, "SELECT * FROM league_settings WHERE league_id = $1", leagueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultSettings(leagueID), nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetSettings retrieves the settings of a league
+func (s *leagueServiceImpl) GetSettings(leagueID int) (*models.LeagueSettings, error) {
+	return LoadSettings(s.db, leagueID)
+}
+
+// UpdateSettings stores the settings of a league
+func (s *leagueServiceImpl) UpdateSettings(settings *models.LeagueSettings) (*models.LeagueSettings, error) {
+	if err := ValidateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetLeague(settings.LeagueID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("league with ID %d not found", settings.LeagueID)
+		}
+		return nil, err
+	}
+
+	if settings.CommissionerTeamID != nil {
+		var count int
+		err := s.db.QueryRow("SELECT COUNT(*) FROM user_teams WHERE id = $1 AND league_id = $2",
+			*settings.CommissionerTeamID, settings.LeagueID).Scan(&count)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("commissioner must be a user team in league %d", settings.LeagueID)
+		}
+	}
+
+	now := time.Now()
+	settings.UpdatedAt = now
+
+	err := s.db.QueryRow(`
+		INSERT INTO league_settings (league_id, pick_time_seconds, pause_start, pause_end, timezone, captain_multiplier,
+			ownership_mode, budget, waiver_period_hours, waiver_process_time, waiver_mode, faab_budget, waiver_tie_break,
+			trade_review, trade_review_hours, trade_veto_votes, commissioner_user_team_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $18)
+		ON CONFLICT (league_id) DO UPDATE
+		SET pick_time_seconds = EXCLUDED.pick_time_seconds,
+			pause_start = EXCLUDED.pause_start,
+			pause_end = EXCLUDED.pause_end,
+			timezone = EXCLUDED.timezone,
+			captain_multiplier = EXCLUDED.captain_multiplier,
+			ownership_mode = EXCLUDED.ownership_mode,
+			budget = EXCLUDED.budget,
+			waiver_period_hours = EXCLUDED.waiver_period_hours,
+			waiver_process_time = EXCLUDED.waiver_process_time,
+			waiver_mode = EXCLUDED.waiver_mode,
+			faab_budget = EXCLUDED.faab_budget,
+			waiver_tie_break = EXCLUDED.waiver_tie_break,
+			trade_review = EXCLUDED.trade_review,
+			trade_review_hours = EXCLUDED.trade_review_hours,
+			trade_veto_votes = EXCLUDED.trade_veto_votes,
+			commissioner_user_team_id = EXCLUDED.commissioner_user_team_id,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`, settings.LeagueID, settings.PickTimeSeconds, settings.PauseStart, settings.PauseEnd, settings.Timezone,
+		settings.CaptainMultiplier, settings.OwnershipMode, settings.Budget, settings.WaiverPeriodHours,
+		settings.WaiverProcessTime, settings.WaiverMode, settings.FAABBudget, settings.WaiverTieBreak, settings.TradeReview,
+		settings.TradeReviewHours, settings.TradeVetoVotes, settings.CommissionerTeamID, now).Scan(&settings.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error updating league settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// ValidateSettings validates league settings
+func ValidateSettings(settings *models.LeagueSettings) error {
+	if settings.PickTimeSeconds < 0 {
+		return fmt.Errorf("pick time cannot be negative")
+	}
+	if settings.CaptainMultiplier == 0 {
+		settings.CaptainMultiplier = DefaultCaptainMultiplier
+	}
+	if settings.CaptainMultiplier < 1 {
+		return fmt.Errorf("captain multiplier cannot be less than 1")
+	}
+	switch settings.OwnershipMode {
+	case "":
+		settings.OwnershipMode = models.OwnershipModeDraft
+	case models.OwnershipModeDraft, models.OwnershipModeBudget:
+	default:
+		return fmt.Errorf("invalid ownership mode: %s", settings.OwnershipMode)
+	}
+	if settings.Budget == 0 {
+		settings.Budget = DefaultBudget
+	}
+	if settings.Budget < 0 {
+		return fmt.Errorf("budget cannot be negative")
+	}
+	if settings.WaiverPeriodHours < 0 {
+		return fmt.Errorf("waiver period cannot be negative")
+	}
+	if settings.WaiverProcessTime != "" {
+		if _, err := ParseClockTime(settings.WaiverProcessTime); err != nil {
+			return err
+		}
+	}
+	switch settings.WaiverMode {
+	case "":
+		settings.WaiverMode = models.WaiverModeRolling
+	case models.WaiverModeRolling, models.WaiverModeFAAB:
+	default:
+		return fmt.Errorf("invalid waiver mode: %s", settings.WaiverMode)
+	}
+	if settings.FAABBudget == 0 {
+		settings.FAABBudget = DefaultFAABBudget
+	}
+	if settings.FAABBudget < 0 {
+		return fmt.Errorf("FAAB budget cannot be negative")
+	}
+	switch settings.WaiverTieBreak {
+	case "":
+		settings.WaiverTieBreak = models.WaiverTieBreakPriority
+	case models.WaiverTieBreakPriority, models.WaiverTieBreakEarliest, models.WaiverTieBreakStandings:
+	default:
+		return fmt.Errorf("invalid waiver tie-break: %s", settings.WaiverTieBreak)
+	}
+	switch settings.TradeReview {
+	case "":
+		settings.TradeReview = models.TradeReviewLeagueVote
+	case models.TradeReviewNone, models.TradeReviewLeagueVote:
+	case models.TradeReviewCommissioner:
+		if settings.CommissionerTeamID == nil {
+			return fmt.Errorf("commissioner trade review needs a commissioner")
+		}
+	default:
+		return fmt.Errorf("invalid trade review: %s", settings.TradeReview)
+	}
+	if settings.TradeReviewHours == 0 {
+		settings.TradeReviewHours = DefaultTradeReviewHours
+	}
+	if settings.TradeReviewHours < 0 {
+		return fmt.Errorf("trade review period cannot be negative")
+	}
+	if settings.TradeVetoVotes == 0 {
+		settings.TradeVetoVotes = DefaultTradeVetoVotes
+	}
+	if settings.TradeVetoVotes < 0 {
+		return fmt.Errorf("trade veto votes cannot be negative")
+	}
+	if settings.Timezone == "" {
+		settings.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %s", settings.Timezone)
+	}
+	if (settings.PauseStart == "") != (settings.PauseEnd == "") {
+		return fmt.Errorf("overnight pause needs both a start and an end")
+	}
+	if settings.PauseStart != "" {
+		start, err := ParseClockTime(settings.PauseStart)
+		if err != nil {
+			return err
+		}
+		end, err := ParseClockTime(settings.PauseEnd)
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return fmt.Errorf("overnight pause cannot start and end at the same time")
+		}
+	}
+	return nil
+}
+
+// ParseClockTime parses an HH:MM time of day into the duration since midnight
+func ParseClockTime(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
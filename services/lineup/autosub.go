@@ -0,0 +1,205 @@
+package lineup
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrGameweekNotFinished is returned when substitutions are processed before every match in the gameweek is over
+var ErrGameweekNotFinished = errors.New("gameweek has matches still to finish")
+
+// AutoSubstitute swaps each starter who did not play for the first bench player, in bench order,
+// who did play and keeps the starting XI in an allowed formation
+func AutoSubstitute(lineup *models.Lineup, positions map[int]models.Position, played map[int]bool) []*models.Substitution {
+	counts := make(map[models.Position]int)
+	for _, playerID := range lineup.Starters {
+		counts[positions[playerID]]++
+	}
+
+	used := make([]bool, len(lineup.Bench))
+	var substitutions []*models.Substitution
+	for _, out := range lineup.Starters {
+		if played[out] {
+			continue
+		}
+		for i, in := range lineup.Bench {
+			if used[i] || !played[in] {
+				continue
+			}
+
+			counts[positions[out]]--
+			counts[positions[in]]++
+			if CheckFormation(counts) == nil {
+				used[i] = true
+				substitutions = append(substitutions, &models.Substitution{
+					UserTeamID:  lineup.UserTeamID,
+					GameweekID:  lineup.GameweekID,
+					PlayerOutID: out,
+					PlayerInID:  in,
+				})
+				break
+			}
+			counts[positions[out]]++
+			counts[positions[in]]--
+		}
+	}
+	return substitutions
+}
+
+// ApplySubstitutions returns a copy of a lineup with the substitutions made
+func ApplySubstitutions(lineup *models.Lineup, substitutions []*models.Substitution) *models.Lineup {
+	applied := *lineup
+	applied.Starters = append([]int{}, lineup.Starters...)
+	applied.Bench = append([]int{}, lineup.Bench...)
+	for _, substitution := range substitutions {
+		for i, playerID := range applied.Starters {
+			if playerID == substitution.PlayerOutID {
+				applied.Starters[i] = substitution.PlayerInID
+			}
+		}
+		for i, playerID := range applied.Bench {
+			if playerID == substitution.PlayerInID {
+				applied.Bench[i] = substitution.PlayerOutID
+			}
+		}
+	}
+	return &applied
+}
+
+// FieldedLineup returns the lineup a user team fielded in a gameweek, with the automatic
+// substitutions made to it
+func FieldedLineup(q sqlx.Queryer, userTeamID int, gameweek *models.Gameweek) (*models.Lineup, error) {
+	lineup, err := LoadLineup(q, userTeamID, gameweek)
+	if err != nil {
+		return nil, err
+	}
+	substitutions, err := loadSubstitutions(q, userTeamID, gameweek.ID)
+	if err != nil {
+		return nil, err
+	}
+	return ApplySubstitutions(lineup, substitutions), nil
+}
+
+// loadSubstitutions retrieves the automatic substitutions made for a user team in a gameweek
+func loadSubstitutions(q sqlx.Queryer, userTeamID, gameweekID int) ([]*models.Substitution, error) {
+	var substitutions []*models.Substitution
+	err := sqlx.Select(q, &substitutions, `
+		SELECT * FROM lineup_substitutions
+		WHERE user_team_id = $1 AND gameweek_id = $2
+		ORDER BY id
+	`, userTeamID, gameweekID)
+	if err != nil {
+		return nil, err
+	}
+	return substitutions, nil
+}
+
+// ProcessAutoSubs makes the automatic substitutions for every user team once all of a gameweek's
+// matches are over. Running it again replaces the substitutions made last time, so it can be
+// rerun after match data is corrected.
+func (s *lineupServiceImpl) ProcessAutoSubs(gameweekID int) ([]*models.Substitution, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	gameweek, err := getGameweek(tx, gameweekID)
+	if err != nil {
+		return nil, err
+	}
+
+	var unfinished int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM matches
+		WHERE gameweek_id = $1 AND status IN ('scheduled', 'in_progress')
+	`, gameweekID).Scan(&unfinished)
+	if err != nil {
+		return nil, err
+	}
+	if unfinished > 0 || time.Now().Before(gameweek.Deadline) {
+		return nil, ErrGameweekNotFinished
+	}
+
+	// Free hit lineups are stored as they are reverted, so the one-week squads are put back
+	// before lineups are read rather than carried into the next gameweek
+	if _, err := RevertDueFreeHits(tx, 0, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM lineup_substitutions WHERE gameweek_id = $1", gameweekID); err != nil {
+		return nil, fmt.Errorf("error clearing substitutions: %w", err)
+	}
+
+	var userTeamIDs []int
+	if err := tx.Select(&userTeamIDs, "SELECT id FROM user_teams ORDER BY id"); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	made := []*models.Substitution{}
+	for _, userTeamID := range userTeamIDs {
+		lineup, err := LoadLineup(tx, userTeamID, gameweek)
+		if err != nil {
+			return nil, err
+		}
+
+		// With a bench boost the whole squad scores, so there is nobody to bring on
+		playerIDs := append(append([]int{}, lineup.Starters...), lineup.Bench...)
+		if len(playerIDs) == 0 || lineup.Chip == models.ChipBenchBoost {
+			continue
+		}
+		played, err := PlayersWhoPlayed(tx, gameweekID, playerIDs)
+		if err != nil {
+			return nil, err
+		}
+		positions, err := playerPositions(tx, playerIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, substitution := range AutoSubstitute(lineup, positions, played) {
+			substitution.CreatedAt = now
+			err := tx.QueryRow(`
+				INSERT INTO lineup_substitutions (user_team_id, gameweek_id, player_out_id, player_in_id, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, substitution.UserTeamID, substitution.GameweekID, substitution.PlayerOutID, substitution.PlayerInID,
+				substitution.CreatedAt).Scan(&substitution.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error recording substitution: %w", err)
+			}
+			made = append(made, substitution)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing substitutions: %w", err)
+	}
+
+	return made, nil
+}
+
+// playerPositions looks up the position of each of the given players
+func playerPositions(q sqlx.Queryer, playerIDs []int) (map[int]models.Position, error) {
+	query, args, err := sqlx.In("SELECT * FROM players WHERE id IN (?)", playerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var players []*models.Player
+	if err := sqlx.Select(q, &players, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return nil, err
+	}
+
+	positions := make(map[int]models.Position, len(players))
+	for _, player := range players {
+		positions[player.ID] = player.Position
+	}
+	return positions, nil
+}
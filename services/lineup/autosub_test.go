@@ -0,0 +1,93 @@
+package lineup
+
+import (
+	"testing"
+
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoSubstitute(t *testing.T) {
+	positions := make(map[int]models.Position)
+	for _, player := range rosterOf() {
+		positions[player.ID] = player.Position
+	}
+
+	// A 4-4-2 with the second goalkeeper first on the bench, then a defender, midfielder and forward
+	lineup := &models.Lineup{
+		UserTeamID: 1,
+		GameweekID: 1,
+		Starters:   []int{1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14},
+		Bench:      []int{2, 7, 12, 15},
+	}
+	// everyoneBut returns a played map in which only the given players did not play
+	everyoneBut := func(ids ...int) map[int]bool {
+		played := make(map[int]bool)
+		for id := range positions {
+			played[id] = true
+		}
+		for _, id := range ids {
+			played[id] = false
+		}
+		return played
+	}
+
+	t.Run("everyone played", func(t *testing.T) {
+		assert.Empty(t, AutoSubstitute(lineup, positions, everyoneBut()))
+	})
+
+	t.Run("first eligible bench player comes on", func(t *testing.T) {
+		// The goalkeeper on the bench cannot replace a midfielder, so the defender comes on
+		substitutions := AutoSubstitute(lineup, positions, everyoneBut(8))
+		if assert.Len(t, substitutions, 1) {
+			assert.Equal(t, 8, substitutions[0].PlayerOutID)
+			assert.Equal(t, 7, substitutions[0].PlayerInID)
+		}
+	})
+
+	t.Run("goalkeeper is replaced by goalkeeper", func(t *testing.T) {
+		substitutions := AutoSubstitute(lineup, positions, everyoneBut(1))
+		if assert.Len(t, substitutions, 1) {
+			assert.Equal(t, 2, substitutions[0].PlayerInID)
+		}
+	})
+
+	t.Run("bench players who did not play are skipped", func(t *testing.T) {
+		substitutions := AutoSubstitute(lineup, positions, everyoneBut(13, 7))
+		if assert.Len(t, substitutions, 1) {
+			assert.Equal(t, 13, substitutions[0].PlayerOutID)
+			assert.Equal(t, 12, substitutions[0].PlayerInID)
+		}
+	})
+
+	t.Run("swap that breaks the formation is not made", func(t *testing.T) {
+		// A 3-5-2 losing a defender can only bring on the defender from the bench
+		threeFiveTwo := &models.Lineup{
+			Starters: []int{1, 3, 4, 5, 8, 9, 10, 11, 12, 13, 14},
+			Bench:    []int{2, 15, 6, 7},
+		}
+		substitutions := AutoSubstitute(threeFiveTwo, positions, everyoneBut(3))
+		if assert.Len(t, substitutions, 1) {
+			assert.Equal(t, 6, substitutions[0].PlayerInID)
+		}
+	})
+
+	t.Run("each bench player comes on once", func(t *testing.T) {
+		substitutions := AutoSubstitute(lineup, positions, everyoneBut(3, 4, 12, 15))
+		if assert.Len(t, substitutions, 1) {
+			assert.Equal(t, 3, substitutions[0].PlayerOutID)
+			assert.Equal(t, 7, substitutions[0].PlayerInID)
+		}
+	})
+
+	t.Run("substitutions applied to the lineup", func(t *testing.T) {
+		applied := ApplySubstitutions(lineup, AutoSubstitute(lineup, positions, everyoneBut(8)))
+		assert.Contains(t, applied.Starters, 7)
+		assert.NotContains(t, applied.Starters, 8)
+		assert.Equal(t, []int{2, 8, 12, 15}, applied.Bench)
+
+		// The picked lineup is left as it was
+		assert.Contains(t, lineup.Starters, 8)
+	})
+}
@@ -0,0 +1,79 @@
+package lineup
+
+import (
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PlayersWhoPlayed reports which of the given players took part in a match in a gameweek,
+// going by their minutes in the match stats or any match incident they were involved in
+func PlayersWhoPlayed(q sqlx.Queryer, gameweekID int, playerIDs []int) (map[int]bool, error) {
+	played := make(map[int]bool, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return played, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT ps.player_id
+		FROM player_stats ps
+		JOIN matches m ON m.id = ps.match_id
+		WHERE m.gameweek_id = ? AND ps.player_id IN (?) AND ps.minutes_played > 0
+		UNION
+		SELECT mi.player_id
+		FROM match_incidents mi
+		JOIN matches m ON m.id = mi.match_id
+		WHERE m.gameweek_id = ? AND mi.player_id IN (?)
+	`, gameweekID, playerIDs, gameweekID, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	if err := sqlx.Select(q, &ids, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		played[id] = true
+	}
+	return played, nil
+}
+
+// ActiveCaptain returns the player who takes the captain's multiplier: the captain, or the
+// vice-captain if the captain did not play. It returns zero when neither of them played.
+func ActiveCaptain(lineup *models.Lineup, played map[int]bool) int {
+	if lineup.CaptainID != 0 && played[lineup.CaptainID] {
+		return lineup.CaptainID
+	}
+	if lineup.ViceCaptainID != 0 && played[lineup.ViceCaptainID] {
+		return lineup.ViceCaptainID
+	}
+	return 0
+}
+
+// Multipliers returns what each starter's points are multiplied by in a gameweek. Bench
+// players are left out as they do not score, unless the lineup's bench boost is active. A
+// triple captain replaces the league's captain multiplier.
+func Multipliers(q sqlx.Queryer, lineup *models.Lineup, captainMultiplier float64) (map[int]float64, error) {
+	played, err := PlayersWhoPlayed(q, lineup.GameweekID, []int{lineup.CaptainID, lineup.ViceCaptainID})
+	if err != nil {
+		return nil, err
+	}
+
+	multipliers := make(map[int]float64, len(lineup.Starters)+len(lineup.Bench))
+	for _, playerID := range lineup.Starters {
+		multipliers[playerID] = 1
+	}
+	if lineup.Chip == models.ChipBenchBoost {
+		for _, playerID := range lineup.Bench {
+			multipliers[playerID] = 1
+		}
+	}
+	if lineup.Chip == models.ChipTripleCaptain {
+		captainMultiplier = models.TripleCaptainMultiplier
+	}
+	if captain := ActiveCaptain(lineup, played); captain != 0 {
+		multipliers[captain] = captainMultiplier
+	}
+	return multipliers, nil
+}
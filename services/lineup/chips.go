@@ -0,0 +1,269 @@
+package lineup
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/league"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrChipUsed        = errors.New("chip has already been played this season")
+	ErrGameweekHasChip = errors.New("a chip has already been played in this gameweek")
+	ErrInvalidChip     = errors.New("invalid chip")
+	ErrNoChip          = errors.New("no chip has been played in this gameweek")
+)
+
+// chipTypes are the chips a user team can play
+var chipTypes = map[models.ChipType]bool{
+	models.ChipWildcard:      true,
+	models.ChipBenchBoost:    true,
+	models.ChipTripleCaptain: true,
+	models.ChipFreeHit:       true,
+}
+
+// ActiveChip returns the chip a user team has played in a gameweek, or an empty chip type if
+// it has not played one
+func ActiveChip(q sqlx.Queryer, userTeamID, gameweekID int) (models.ChipType, error) {
+	var chip models.ChipType
+	err := q.QueryRowx("SELECT chip FROM chips WHERE user_team_id = $1 AND gameweek_id = $2", userTeamID, gameweekID).Scan(&chip)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return chip, nil
+}
+
+// PlayChip plays a chip for a user team in a gameweek before its deadline. Wildcards and free
+// hits are only available in budget leagues, as they are about transfers.
+func (s *lineupServiceImpl) PlayChip(userTeamID, gameweekID int, chip models.ChipType) (*models.Chip, error) {
+	if !chipTypes[chip] {
+		return nil, fmt.Errorf("%w: unknown chip %q", ErrInvalidChip, chip)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the team so the same chip cannot be played twice at once
+	var leagueID int
+	if err := tx.QueryRow("SELECT league_id FROM user_teams WHERE id = $1 FOR UPDATE", userTeamID).Scan(&leagueID); err != nil {
+		return nil, err
+	}
+
+	gameweek, err := getGameweek(tx, gameweekID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !now.Before(gameweek.Deadline) {
+		return nil, ErrLineupLocked
+	}
+
+	var used []*models.Chip
+	if err := tx.Select(&used, "SELECT * FROM chips WHERE user_team_id = $1", userTeamID); err != nil {
+		return nil, err
+	}
+	for _, played := range used {
+		if played.Chip == chip {
+			return nil, ErrChipUsed
+		}
+		if played.GameweekID == gameweekID {
+			return nil, ErrGameweekHasChip
+		}
+	}
+
+	if chip == models.ChipWildcard || chip == models.ChipFreeHit {
+		settings, err := league.LoadSettings(tx, leagueID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading league settings: %w", err)
+		}
+		if settings.OwnershipMode != models.OwnershipModeBudget {
+			return nil, fmt.Errorf("%w: %s is only available in budget leagues", ErrInvalidChip, chip)
+		}
+	}
+
+	if chip == models.ChipFreeHit {
+		// The squad to put back afterwards must be the one from before the gameweek's transfers
+		var transfers int
+		err := tx.QueryRow("SELECT COUNT(*) FROM transfers WHERE user_team_id = $1 AND gameweek_id = $2", userTeamID, gameweekID).Scan(&transfers)
+		if err != nil {
+			return nil, err
+		}
+		if transfers > 0 {
+			return nil, fmt.Errorf("%w: play a free hit before making the gameweek's transfers", ErrInvalidChip)
+		}
+	}
+
+	played := &models.Chip{
+		UserTeamID: userTeamID,
+		GameweekID: gameweekID,
+		Chip:       chip,
+		CreatedAt:  now,
+	}
+	err = tx.QueryRow(`
+		INSERT INTO chips (user_team_id, gameweek_id, chip, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, played.UserTeamID, played.GameweekID, played.Chip, played.CreatedAt).Scan(&played.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error playing chip: %w", err)
+	}
+
+	switch chip {
+	case models.ChipWildcard:
+		// Transfers already made in the gameweek become free too
+		_, err := tx.Exec("UPDATE transfers SET cost = 0 WHERE user_team_id = $1 AND gameweek_id = $2", userTeamID, gameweekID)
+		if err != nil {
+			return nil, fmt.Errorf("error refunding transfers: %w", err)
+		}
+	case models.ChipFreeHit:
+		_, err := tx.Exec(`
+			INSERT INTO free_hit_squads (chip_id, player_id, purchase_price)
+			SELECT $1, player_id, purchase_price
+			FROM user_team_players
+			WHERE user_team_id = $2
+		`, played.ID, userTeamID)
+		if err != nil {
+			return nil, fmt.Errorf("error saving squad: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing chip: %w", err)
+	}
+
+	return played, nil
+}
+
+// CancelChip takes back a bench boost or triple captain before the gameweek deadline. Wildcards
+// and free hits cannot be taken back once played.
+func (s *lineupServiceImpl) CancelChip(userTeamID, gameweekID int) error {
+	gameweek, err := getGameweek(s.db, gameweekID)
+	if err != nil {
+		return err
+	}
+	if !time.Now().Before(gameweek.Deadline) {
+		return ErrLineupLocked
+	}
+
+	chip, err := ActiveChip(s.db, userTeamID, gameweekID)
+	if err != nil {
+		return err
+	}
+	if chip == "" {
+		return ErrNoChip
+	}
+	if chip == models.ChipWildcard || chip == models.ChipFreeHit {
+		return fmt.Errorf("%w: %s cannot be cancelled once played", ErrInvalidChip, chip)
+	}
+
+	_, err = s.db.Exec("DELETE FROM chips WHERE user_team_id = $1 AND gameweek_id = $2", userTeamID, gameweekID)
+	if err != nil {
+		return fmt.Errorf("error cancelling chip: %w", err)
+	}
+	return nil
+}
+
+// ListChips retrieves the chips a user team has played, in gameweek order
+func (s *lineupServiceImpl) ListChips(userTeamID int) ([]*models.Chip, error) {
+	var chips []*models.Chip
+	err := s.db.Select(&chips, `
+		SELECT c.*
+		FROM chips c
+		JOIN gameweeks g ON g.id = c.gameweek_id
+		WHERE c.user_team_id = $1
+		ORDER BY g.number
+	`, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	return chips, nil
+}
+
+// RevertFreeHits locks the lineups of gameweeks whose deadline has passed and puts back the squads
+// of every user team whose free hit gameweek deadline has passed
+func (s *lineupServiceImpl) RevertFreeHits() ([]*models.Chip, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reverted, err := RevertDueFreeHits(tx, 0, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing free hits: %w", err)
+	}
+
+	return reverted, nil
+}
+
+// RevertDueFreeHits puts back the squad a user team had before playing a free hit once the free
+// hit's gameweek deadline has passed, or does so for every user team when userTeamID is zero.
+// Due lineups are locked first so the gameweek still shows the team that was fielded.
+func RevertDueFreeHits(tx *sqlx.Tx, userTeamID int, now time.Time) ([]*models.Chip, error) {
+	if err := LockDueLineups(tx, userTeamID, now); err != nil {
+		return nil, err
+	}
+
+	var chips []*models.Chip
+	err := tx.Select(&chips, `
+		SELECT c.*
+		FROM chips c
+		JOIN gameweeks g ON g.id = c.gameweek_id
+		WHERE c.chip = $1 AND c.reverted_at IS NULL AND g.deadline <= $2 AND ($3 = 0 OR c.user_team_id = $3)
+		ORDER BY c.id
+		FOR UPDATE OF c
+	`, models.ChipFreeHit, now, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chip := range chips {
+		before, err := audit.RosterOf(tx, chip.UserTeamID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec("DELETE FROM user_team_players WHERE user_team_id = $1", chip.UserTeamID); err != nil {
+			return nil, fmt.Errorf("error clearing free hit squad: %w", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO user_team_players (user_team_id, player_id, purchase_price, created_at, updated_at)
+			SELECT $1, player_id, purchase_price, $2, $2
+			FROM free_hit_squads
+			WHERE chip_id = $3
+		`, chip.UserTeamID, now, chip.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error putting back squad: %w", err)
+		}
+		err = audit.Record(tx, &models.AuditEntry{
+			UserTeamID:   &chip.UserTeamID,
+			Action:       models.AuditActionFreeHitRevert,
+			RosterBefore: before,
+			CreatedAt:    now,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec("UPDATE chips SET reverted_at = $1 WHERE id = $2", now, chip.ID); err != nil {
+			return nil, fmt.Errorf("error marking free hit as reverted: %w", err)
+		}
+		chip.RevertedAt = &now
+	}
+	return chips, nil
+}
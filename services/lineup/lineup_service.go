@@ -0,0 +1,443 @@
+package lineup
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrGameweekNotFound = errors.New("gameweek not found")
+	ErrLineupLocked     = errors.New("lineup is locked as the gameweek deadline has passed")
+	ErrInvalidLineup    = errors.New("invalid lineup")
+)
+
+// PositionRange is the fewest and most players of a position allowed in a starting XI
+type PositionRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// FormationLimits are the formations a starting XI can line up in
+var FormationLimits = map[models.Position]PositionRange{
+	models.PositionGK:  {Min: 1, Max: 1},
+	models.PositionDEF: {Min: 3, Max: 5},
+	models.PositionMID: {Min: 2, Max: 5},
+	models.PositionFWD: {Min: 1, Max: 3},
+}
+
+// LineupService defines the interface for lineup operations
+type LineupService interface {
+	GetLineup(userTeamID, gameweekID int) (*models.Lineup, error)
+	SetLineup(lineup *models.Lineup) (*models.Lineup, error)
+	ProcessAutoSubs(gameweekID int) ([]*models.Substitution, error)
+	PlayChip(userTeamID, gameweekID int, chip models.ChipType) (*models.Chip, error)
+	CancelChip(userTeamID, gameweekID int) error
+	ListChips(userTeamID int) ([]*models.Chip, error)
+	RevertFreeHits() ([]*models.Chip, error)
+}
+
+// Implementation of the LineupService interface
+type lineupServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewLineupService creates a new LineupService instance
+func NewLineupService(db *sqlx.DB) LineupService {
+	return &lineupServiceImpl{db: db}
+}
+
+// getGameweek retrieves a gameweek, returning ErrGameweekNotFound if it does not exist
+func getGameweek(q sqlx.Queryer, id int) (*models.Gameweek, error) {
+	gameweek := &models.Gameweek{}
+	err := sqlx.Get(q, gameweek, "SELECT * FROM gameweeks WHERE id = $1", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGameweekNotFound
+		}
+		return nil, err
+	}
+	return gameweek, nil
+}
+
+// GetLineup retrieves the lineup a user team picked for a gameweek along with any automatic
+// substitutions made to it
+func (s *lineupServiceImpl) GetLineup(userTeamID, gameweekID int) (*models.Lineup, error) {
+	gameweek, err := getGameweek(s.db, gameweekID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineup, err := LoadLineup(s.db, userTeamID, gameweek)
+	if err != nil {
+		return nil, err
+	}
+	lineup.Substitutions, err = loadSubstitutions(s.db, userTeamID, gameweekID)
+	if err != nil {
+		return nil, err
+	}
+	return lineup, nil
+}
+
+// LoadLineup retrieves the lineup a user team fields in a gameweek. A team that has not changed
+// its lineup for the gameweek fields the one from its latest earlier gameweek, less any players
+// that have since left the squad, with the gaps filled from the bench. Lineups picked for a free
+// hit are never carried over. Once the deadline has passed the lineup only ever comes from stored
+// rows, so later roster moves cannot change who started: LockDueLineups stores carried-over
+// lineups as they stood at the deadline.
+func LoadLineup(q sqlx.Queryer, userTeamID int, gameweek *models.Gameweek) (*models.Lineup, error) {
+	return loadLineup(q, userTeamID, gameweek, !time.Now().Before(gameweek.Deadline))
+}
+
+// loadLineup retrieves the lineup a user team fields in a gameweek, either as it is stored once
+// locked or as it stands before the deadline
+func loadLineup(q sqlx.Queryer, userTeamID int, gameweek *models.Gameweek, locked bool) (*models.Lineup, error) {
+	lineup := &models.Lineup{
+		UserTeamID: userTeamID,
+		GameweekID: gameweek.ID,
+		Starters:   []int{},
+		Bench:      []int{},
+		Locked:     locked,
+	}
+
+	var err error
+	lineup.Chip, err = ActiveChip(q, userTeamID, gameweek.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromGameweekID int
+	err = q.QueryRowx(`
+		SELECT l.gameweek_id
+		FROM lineups l
+		JOIN gameweeks g ON g.id = l.gameweek_id
+		WHERE l.user_team_id = $1 AND g.number <= $2
+			AND (l.gameweek_id = $3 OR NOT EXISTS (
+				SELECT 1 FROM chips c
+				WHERE c.user_team_id = l.user_team_id AND c.gameweek_id = l.gameweek_id AND c.chip = $4
+			))
+		ORDER BY g.number DESC
+		LIMIT 1
+	`, userTeamID, gameweek.Number, gameweek.ID, models.ChipFreeHit).Scan(&fromGameweekID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return lineup, nil
+		}
+		return nil, err
+	}
+	lineup.CarriedOver = fromGameweekID != gameweek.ID
+
+	query := `
+		SELECT l.*
+		FROM lineups l
+		JOIN user_team_players utp ON utp.user_team_id = l.user_team_id AND utp.player_id = l.player_id
+		WHERE l.user_team_id = $1 AND l.gameweek_id = $2
+		ORDER BY l.slot
+	`
+	if lineup.Locked {
+		query = `
+			SELECT l.*
+			FROM lineups l
+			WHERE l.user_team_id = $1 AND l.gameweek_id = $2
+			ORDER BY l.slot
+		`
+	}
+
+	var players []*models.LineupPlayer
+	if err := sqlx.Select(q, &players, query, userTeamID, fromGameweekID); err != nil {
+		return nil, err
+	}
+
+	for _, player := range players {
+		if player.IsStarter() {
+			lineup.Starters = append(lineup.Starters, player.PlayerID)
+		} else {
+			lineup.Bench = append(lineup.Bench, player.PlayerID)
+		}
+		if player.IsCaptain {
+			lineup.CaptainID = player.PlayerID
+		}
+		if player.IsViceCaptain {
+			lineup.ViceCaptainID = player.PlayerID
+		}
+	}
+
+	if lineup.Locked {
+		if len(players) > 0 {
+			positions, err := playerPositions(q, append(append([]int{}, lineup.Starters...), lineup.Bench...))
+			if err != nil {
+				return nil, err
+			}
+			flagLineup(lineup, positions)
+		}
+		return lineup, nil
+	}
+
+	var roster []*models.Player
+	err = sqlx.Select(q, &roster, `
+		SELECT p.*
+		FROM players p
+		JOIN user_team_players utp ON utp.player_id = p.id
+		WHERE utp.user_team_id = $1
+		ORDER BY p.id
+	`, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	fillLineup(lineup, roster)
+	return lineup, nil
+}
+
+// LockDueLineups stores the carried-over lineup of a user team, or of every user team when
+// userTeamID is zero, for each gameweek whose deadline has passed and that has no lineup stored
+// yet. The lineup is worked out from the squad as it is now, so this has to run before any roster
+// move made after a deadline, and is also run regularly by a job.
+func LockDueLineups(tx *sqlx.Tx, userTeamID int, now time.Time) error {
+	var due []struct {
+		UserTeamID int `db:"user_team_id"`
+		GameweekID int `db:"gameweek_id"`
+	}
+	err := tx.Select(&due, `
+		SELECT ut.id AS user_team_id, g.id AS gameweek_id
+		FROM user_teams ut
+		CROSS JOIN gameweeks g
+		WHERE g.deadline <= $1 AND ($2 = 0 OR ut.id = $2)
+			AND NOT EXISTS (SELECT 1 FROM lineups l WHERE l.user_team_id = ut.id AND l.gameweek_id = g.id)
+			AND EXISTS (
+				SELECT 1 FROM lineups l
+				JOIN gameweeks lg ON lg.id = l.gameweek_id
+				WHERE l.user_team_id = ut.id AND lg.number < g.number
+			)
+		ORDER BY ut.id, g.number
+	`, now, userTeamID)
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range due {
+		gameweek, err := getGameweek(tx, pair.GameweekID)
+		if err != nil {
+			return err
+		}
+		// Loaded as it stood before the deadline, from the squad it still has
+		lineup, err := loadLineup(tx, pair.UserTeamID, gameweek, false)
+		if err != nil {
+			return err
+		}
+		if len(lineup.Starters) == 0 && len(lineup.Bench) == 0 {
+			continue
+		}
+		if err := storeLineup(tx, lineup, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillLineup fills the gaps players who have left the squad leave in a lineup. Squad players
+// missing from it join the end of the bench. Bench players then come into the starting XI in
+// bench order, first at positions short of the fewest a formation allows and then at any
+// position with room. A lineup that still breaks the formation rules is flagged.
+func fillLineup(lineup *models.Lineup, roster []*models.Player) {
+	positions := make(map[int]models.Position, len(roster))
+	for _, player := range roster {
+		positions[player.ID] = player.Position
+	}
+	picked := make(map[int]bool)
+	counts := make(map[models.Position]int)
+	for _, playerID := range lineup.Starters {
+		picked[playerID] = true
+		counts[positions[playerID]]++
+	}
+	for _, playerID := range lineup.Bench {
+		picked[playerID] = true
+	}
+	for _, player := range roster {
+		if !picked[player.ID] {
+			lineup.Bench = append(lineup.Bench, player.ID)
+		}
+	}
+
+	for len(lineup.Starters) < models.StartingXISize {
+		index := -1
+		for _, toMinimum := range []bool{true, false} {
+			for i, playerID := range lineup.Bench {
+				limits := FormationLimits[positions[playerID]]
+				if toMinimum && counts[positions[playerID]] < limits.Min || !toMinimum && counts[positions[playerID]] < limits.Max {
+					index = i
+					break
+				}
+			}
+			if index >= 0 {
+				break
+			}
+		}
+		if index < 0 {
+			break
+		}
+		playerID := lineup.Bench[index]
+		lineup.Starters = append(lineup.Starters, playerID)
+		lineup.Bench = append(lineup.Bench[:index], lineup.Bench[index+1:]...)
+		counts[positions[playerID]]++
+	}
+
+	flagLineup(lineup, positions)
+}
+
+// flagLineup flags a lineup whose starters break the formation rules
+func flagLineup(lineup *models.Lineup, positions map[int]models.Position) {
+	counts := make(map[models.Position]int)
+	for _, playerID := range lineup.Starters {
+		counts[positions[playerID]]++
+	}
+	if len(lineup.Starters) != models.StartingXISize {
+		lineup.InvalidReason = fmt.Sprintf("the squad is %d short of a starting XI", models.StartingXISize-len(lineup.Starters))
+	} else if err := CheckFormation(counts); err != nil {
+		lineup.InvalidReason = err.Error()
+	}
+}
+
+// SetLineup picks a user team's starting XI, ordered bench and captains for a gameweek
+func (s *lineupServiceImpl) SetLineup(lineup *models.Lineup) (*models.Lineup, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	gameweek, err := getGameweek(tx, lineup.GameweekID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !now.Before(gameweek.Deadline) {
+		return nil, ErrLineupLocked
+	}
+
+	// A free hit squad from an earlier gameweek has to be put back before picking from it
+	if _, err := RevertDueFreeHits(tx, lineup.UserTeamID, now); err != nil {
+		return nil, err
+	}
+
+	var roster []*models.Player
+	err = tx.Select(&roster, `
+		SELECT p.*
+		FROM players p
+		JOIN user_team_players utp ON utp.player_id = p.id
+		WHERE utp.user_team_id = $1
+	`, lineup.UserTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateLineup(roster, lineup); err != nil {
+		return nil, err
+	}
+	if lineup.Bench == nil {
+		lineup.Bench = []int{}
+	}
+
+	if err := storeLineup(tx, lineup, now); err != nil {
+		return nil, err
+	}
+
+	lineup.Chip, err = ActiveChip(tx, lineup.UserTeamID, lineup.GameweekID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing lineup: %w", err)
+	}
+
+	lineup.Locked = false
+	lineup.CarriedOver = false
+	return lineup, nil
+}
+
+// storeLineup replaces the lineup a user team has stored for a gameweek
+func storeLineup(tx *sqlx.Tx, lineup *models.Lineup, now time.Time) error {
+	_, err := tx.Exec("DELETE FROM lineups WHERE user_team_id = $1 AND gameweek_id = $2", lineup.UserTeamID, lineup.GameweekID)
+	if err != nil {
+		return fmt.Errorf("error clearing lineup: %w", err)
+	}
+
+	for i, playerID := range append(append([]int{}, lineup.Starters...), lineup.Bench...) {
+		_, err := tx.Exec(`
+			INSERT INTO lineups (user_team_id, gameweek_id, player_id, slot, is_captain, is_vice_captain, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, lineup.UserTeamID, lineup.GameweekID, playerID, i+1, playerID == lineup.CaptainID, playerID == lineup.ViceCaptainID, now)
+		if err != nil {
+			return fmt.Errorf("error saving lineup: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateLineup checks that a lineup is picked from the roster, that the starters line up
+// in an allowed formation and that the captain and vice-captain are two different starters
+func ValidateLineup(roster []*models.Player, lineup *models.Lineup) error {
+	starters, bench := lineup.Starters, lineup.Bench
+	if len(starters) != models.StartingXISize {
+		return fmt.Errorf("%w: pick exactly %d starters", ErrInvalidLineup, models.StartingXISize)
+	}
+
+	positions := make(map[int]models.Position, len(roster))
+	for _, player := range roster {
+		positions[player.ID] = player.Position
+	}
+
+	picked := make(map[int]bool, len(starters)+len(bench))
+	for _, playerID := range append(append([]int{}, starters...), bench...) {
+		if _, ok := positions[playerID]; !ok {
+			return fmt.Errorf("%w: player %d is not in the squad", ErrInvalidLineup, playerID)
+		}
+		if picked[playerID] {
+			return fmt.Errorf("%w: player %d is picked more than once", ErrInvalidLineup, playerID)
+		}
+		picked[playerID] = true
+	}
+
+	counts := make(map[models.Position]int)
+	isStarter := make(map[int]bool, len(starters))
+	for _, playerID := range starters {
+		counts[positions[playerID]]++
+		isStarter[playerID] = true
+	}
+	if err := CheckFormation(counts); err != nil {
+		return err
+	}
+
+	if lineup.ViceCaptainID != 0 && lineup.CaptainID == 0 {
+		return fmt.Errorf("%w: pick a captain before a vice-captain", ErrInvalidLineup)
+	}
+	if lineup.CaptainID != 0 && !isStarter[lineup.CaptainID] {
+		return fmt.Errorf("%w: captain must be in the starting XI", ErrInvalidLineup)
+	}
+	if lineup.ViceCaptainID != 0 && !isStarter[lineup.ViceCaptainID] {
+		return fmt.Errorf("%w: vice-captain must be in the starting XI", ErrInvalidLineup)
+	}
+	if lineup.CaptainID != 0 && lineup.CaptainID == lineup.ViceCaptainID {
+		return fmt.Errorf("%w: captain and vice-captain must be different players", ErrInvalidLineup)
+	}
+	return nil
+}
+
+// CheckFormation checks the number of starters at each position against FormationLimits
+func CheckFormation(counts map[models.Position]int) error {
+	for _, position := range []models.Position{models.PositionGK, models.PositionDEF, models.PositionMID, models.PositionFWD} {
+		limits := FormationLimits[position]
+		if counts[position] < limits.Min || counts[position] > limits.Max {
+			if limits.Min == limits.Max {
+				return fmt.Errorf("%w: starting XI must have exactly %d %s", ErrInvalidLineup, limits.Min, position)
+			}
+			return fmt.Errorf("%w: starting XI must have between %d and %d %s", ErrInvalidLineup, limits.Min, limits.Max, position)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,495 @@
+package lineup
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB        *database.TestDB
+	lineupService LineupService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	lineupService = NewLineupService(testDB.GetDB())
+	m.Run()
+}
+
+// rosterOf builds a 15 player roster of 2 GK, 5 DEF, 5 MID and 3 FWD with IDs 1 to 15
+func rosterOf() []*models.Player {
+	var roster []*models.Player
+	for _, group := range []struct {
+		position models.Position
+		count    int
+	}{
+		{models.PositionGK, 2},
+		{models.PositionDEF, 5},
+		{models.PositionMID, 5},
+		{models.PositionFWD, 3},
+	} {
+		for i := 0; i < group.count; i++ {
+			roster = append(roster, &models.Player{ID: len(roster) + 1, Position: group.position})
+		}
+	}
+	return roster
+}
+
+func TestValidateLineup(t *testing.T) {
+	roster := rosterOf()
+
+	t.Run("4-4-2", func(t *testing.T) {
+		starters := []int{1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14}
+		assert.NoError(t, ValidateLineup(roster, &models.Lineup{Starters: starters, Bench: []int{2, 7, 12, 15}}))
+	})
+
+	t.Run("two goalkeepers", func(t *testing.T) {
+		starters := []int{1, 2, 3, 4, 5, 8, 9, 10, 11, 13, 14}
+		assert.True(t, errors.Is(ValidateLineup(roster, &models.Lineup{Starters: starters}), ErrInvalidLineup))
+	})
+
+	t.Run("too few defenders", func(t *testing.T) {
+		starters := []int{1, 3, 4, 8, 9, 10, 11, 12, 13, 14, 15}
+		err := ValidateLineup(roster, &models.Lineup{Starters: starters})
+		assert.True(t, errors.Is(err, ErrInvalidLineup))
+		assert.Contains(t, err.Error(), "DEF")
+	})
+
+	t.Run("wrong number of starters", func(t *testing.T) {
+		assert.True(t, errors.Is(ValidateLineup(roster, &models.Lineup{Starters: []int{1, 3, 4, 5}}), ErrInvalidLineup))
+	})
+
+	t.Run("player not in squad", func(t *testing.T) {
+		starters := []int{1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 99}
+		assert.True(t, errors.Is(ValidateLineup(roster, &models.Lineup{Starters: starters}), ErrInvalidLineup))
+	})
+
+	t.Run("player on bench and in starting XI", func(t *testing.T) {
+		starters := []int{1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14}
+		assert.True(t, errors.Is(ValidateLineup(roster, &models.Lineup{Starters: starters, Bench: []int{2, 14}}), ErrInvalidLineup))
+	})
+
+	t.Run("captains", func(t *testing.T) {
+		starters := []int{1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14}
+		lineup := func(captainID, viceCaptainID int) *models.Lineup {
+			return &models.Lineup{Starters: starters, Bench: []int{2}, CaptainID: captainID, ViceCaptainID: viceCaptainID}
+		}
+
+		assert.NoError(t, ValidateLineup(roster, lineup(13, 9)))
+		assert.NoError(t, ValidateLineup(roster, lineup(13, 0)))
+
+		// Captain on the bench
+		assert.True(t, errors.Is(ValidateLineup(roster, lineup(2, 9)), ErrInvalidLineup))
+		// Same player twice
+		assert.True(t, errors.Is(ValidateLineup(roster, lineup(13, 13)), ErrInvalidLineup))
+		// Vice-captain without a captain
+		assert.True(t, errors.Is(ValidateLineup(roster, lineup(0, 9)), ErrInvalidLineup))
+	})
+}
+
+func TestFillLineup(t *testing.T) {
+	// without returns the roster less the given players
+	without := func(ids ...int) []*models.Player {
+		gone := make(map[int]bool)
+		for _, id := range ids {
+			gone[id] = true
+		}
+		var roster []*models.Player
+		for _, player := range rosterOf() {
+			if !gone[player.ID] {
+				roster = append(roster, player)
+			}
+		}
+		return roster
+	}
+
+	t.Run("goalkeeper who left is replaced from the bench", func(t *testing.T) {
+		// The 4-4-2 from TestAutoSubstitute without its goalkeeper
+		lineup := &models.Lineup{
+			Starters: []int{3, 4, 5, 6, 8, 9, 10, 11, 13, 14},
+			Bench:    []int{7, 2, 12, 15},
+		}
+		fillLineup(lineup, without(1))
+		assert.Equal(t, []int{3, 4, 5, 6, 8, 9, 10, 11, 13, 14, 2}, lineup.Starters)
+		assert.Equal(t, []int{7, 12, 15}, lineup.Bench)
+		assert.Empty(t, lineup.InvalidReason)
+	})
+
+	t.Run("new signings join the bench", func(t *testing.T) {
+		lineup := &models.Lineup{
+			Starters: []int{1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14},
+			Bench:    []int{2, 7},
+		}
+		fillLineup(lineup, rosterOf())
+		assert.Len(t, lineup.Starters, models.StartingXISize)
+		assert.Equal(t, []int{2, 7, 12, 15}, lineup.Bench)
+		assert.Empty(t, lineup.InvalidReason)
+	})
+
+	t.Run("lineup that cannot be fixed is flagged", func(t *testing.T) {
+		// Only a goalkeeper and a forward are left to replace a defender
+		lineup := &models.Lineup{
+			Starters: []int{1, 3, 4, 8, 9, 10, 11, 12, 13, 14},
+			Bench:    []int{2, 15},
+		}
+		fillLineup(lineup, without(5, 6, 7))
+		assert.Contains(t, lineup.Starters, 15)
+		assert.Contains(t, lineup.InvalidReason, "DEF")
+
+		short := &models.Lineup{Starters: []int{1, 3, 4, 5}}
+		fillLineup(short, without(2, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15))
+		assert.Contains(t, short.InvalidReason, "short")
+	})
+}
+
+func TestActiveCaptain(t *testing.T) {
+	lineup := &models.Lineup{CaptainID: 9, ViceCaptainID: 10}
+
+	assert.Equal(t, 9, ActiveCaptain(lineup, map[int]bool{9: true, 10: true}))
+	assert.Equal(t, 10, ActiveCaptain(lineup, map[int]bool{10: true}))
+	assert.Equal(t, 0, ActiveCaptain(lineup, map[int]bool{}))
+	assert.Equal(t, 0, ActiveCaptain(&models.Lineup{}, map[int]bool{9: true}))
+}
+
+func TestLineupService(t *testing.T) {
+	db := testDB.GetDB()
+
+	// setup inserts a user team with a full squad and returns its ID and player IDs
+	setup := func() (int, []int) {
+		now := time.Now()
+		var leagueID, userID, userTeamID, teamID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("LINEUP%d", now.UnixNano()), "Lineup League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, "Lineup", "Manager", fmt.Sprintf("lineup_%d@example.com", now.UnixNano()), "password", now, now).Scan(&userID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, userID, leagueID, "Lineup Team", now, now).Scan(&userTeamID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO teams (name, external_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, "Lineup FC", 1, now, now).Scan(&teamID)
+		assert.NoError(t, err)
+
+		var playerIDs []int
+		for _, player := range rosterOf() {
+			var playerID int
+			err := db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", player.ID), player.Position, now, now).Scan(&playerID)
+			assert.NoError(t, err)
+			_, err = db.Exec(`
+				INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $4)
+			`, userTeamID, playerID, now, now)
+			assert.NoError(t, err)
+			playerIDs = append(playerIDs, playerID)
+		}
+		return userTeamID, playerIDs
+	}
+
+	createGameweek := func(number int, deadline time.Time) int {
+		var id int
+		err := db.QueryRow(`
+			INSERT INTO gameweeks (number, deadline, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+			RETURNING id
+		`, number, deadline, time.Now()).Scan(&id)
+		assert.NoError(t, err)
+		return id
+	}
+
+	// pick returns the player IDs at the given 1-based roster positions
+	pick := func(playerIDs []int, positions ...int) []int {
+		ids := make([]int, len(positions))
+		for i, position := range positions {
+			ids[i] = playerIDs[position-1]
+		}
+		return ids
+	}
+
+	t.Run("SetLineup and carry over", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID, playerIDs := setup()
+		first := createGameweek(1, time.Now().Add(time.Hour))
+		second := createGameweek(2, time.Now().Add(24*time.Hour))
+
+		starters := pick(playerIDs, 1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14)
+		bench := pick(playerIDs, 2, 7, 12, 15)
+		_, err := lineupService.SetLineup(&models.Lineup{
+			UserTeamID:    userTeamID,
+			GameweekID:    first,
+			Starters:      starters,
+			Bench:         bench,
+			CaptainID:     starters[9],
+			ViceCaptainID: starters[8],
+		})
+		assert.NoError(t, err)
+
+		lineup, err := lineupService.GetLineup(userTeamID, first)
+		assert.NoError(t, err)
+		assert.Equal(t, starters, lineup.Starters)
+		assert.Equal(t, bench, lineup.Bench)
+		assert.False(t, lineup.CarriedOver)
+
+		// The next gameweek uses the same lineup until it is changed
+		carried, err := lineupService.GetLineup(userTeamID, second)
+		assert.NoError(t, err)
+		assert.Equal(t, starters, carried.Starters)
+		assert.Equal(t, starters[9], carried.CaptainID)
+		assert.Equal(t, starters[8], carried.ViceCaptainID)
+		assert.True(t, carried.CarriedOver)
+	})
+
+	t.Run("Locked lineups ignore later roster moves", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID, playerIDs := setup()
+		first := createGameweek(1, time.Now().Add(time.Hour))
+		second := createGameweek(2, time.Now().Add(2*time.Hour))
+		third := createGameweek(3, time.Now().Add(24*time.Hour))
+
+		starters := pick(playerIDs, 1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14)
+		bench := pick(playerIDs, 2, 7, 12, 15)
+		_, err := lineupService.SetLineup(&models.Lineup{UserTeamID: userTeamID, GameweekID: first, Starters: starters, Bench: bench})
+		assert.NoError(t, err)
+
+		// Both deadlines pass and the carried-over lineup is locked
+		_, err = db.Exec("UPDATE gameweeks SET deadline = $1 WHERE id IN ($2, $3)", time.Now().Add(-time.Hour), first, second)
+		assert.NoError(t, err)
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+		assert.NoError(t, LockDueLineups(tx, 0, time.Now()))
+		assert.NoError(t, tx.Commit())
+
+		// The goalkeeper is sold afterwards
+		_, err = db.Exec("DELETE FROM user_team_players WHERE user_team_id = $1 AND player_id = $2", userTeamID, starters[0])
+		assert.NoError(t, err)
+
+		locked, err := lineupService.GetLineup(userTeamID, second)
+		assert.NoError(t, err)
+		assert.True(t, locked.Locked)
+		assert.Equal(t, starters, locked.Starters)
+		assert.Equal(t, bench, locked.Bench)
+
+		// The next gameweek is still open, so the reserve goalkeeper comes in
+		open, err := lineupService.GetLineup(userTeamID, third)
+		assert.NoError(t, err)
+		assert.True(t, open.CarriedOver)
+		assert.Contains(t, open.Starters, bench[0])
+		assert.NotContains(t, open.Starters, starters[0])
+		assert.Empty(t, open.InvalidReason)
+	})
+
+	t.Run("SetLineup after the deadline", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID, playerIDs := setup()
+		gameweekID := createGameweek(1, time.Now().Add(-time.Hour))
+
+		starters := pick(playerIDs, 1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14)
+		_, err := lineupService.SetLineup(&models.Lineup{UserTeamID: userTeamID, GameweekID: gameweekID, Starters: starters})
+		assert.ErrorIs(t, err, ErrLineupLocked)
+
+		lineup, err := lineupService.GetLineup(userTeamID, gameweekID)
+		assert.NoError(t, err)
+		assert.True(t, lineup.Locked)
+		assert.Empty(t, lineup.Starters)
+	})
+
+	t.Run("Vice-captain takes the multiplier when the captain does not play", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, playerIDs := setup()
+		gameweekID := createGameweek(1, time.Now().Add(-time.Hour))
+		starters := pick(playerIDs, 1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14)
+		lineup := &models.Lineup{GameweekID: gameweekID, Starters: starters, CaptainID: starters[9], ViceCaptainID: starters[8]}
+
+		var teamID, matchID int
+		err := db.QueryRow("SELECT team_id FROM players WHERE id = $1", starters[0]).Scan(&teamID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO matches (home_team_id, away_team_id, match_date, status, gameweek_id)
+			VALUES ($1, $1, $2, 'completed', $3)
+			RETURNING id
+		`, teamID, time.Now(), gameweekID).Scan(&matchID)
+		assert.NoError(t, err)
+
+		// The captain sat on the bench for the whole match
+		_, err = db.Exec(`
+			INSERT INTO player_stats (player_id, match_id, minutes_played)
+			VALUES ($1, $2, 0), ($3, $2, 90)
+		`, starters[9], matchID, starters[8])
+		assert.NoError(t, err)
+
+		multipliers, err := Multipliers(db, lineup, 2)
+		assert.NoError(t, err)
+		assert.Len(t, multipliers, 11)
+		assert.Equal(t, float64(1), multipliers[starters[9]])
+		assert.Equal(t, float64(2), multipliers[starters[8]])
+
+		// A match incident counts as playing, even without minutes in the stats
+		_, err = db.Exec(`
+			INSERT INTO match_incidents (match_id, player_id, type, minute)
+			VALUES ($1, $2, $3, 85)
+		`, matchID, starters[9], models.IncidentTypeSubstitution)
+		assert.NoError(t, err)
+
+		multipliers, err = Multipliers(db, lineup, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(2), multipliers[starters[9]])
+		assert.Equal(t, float64(1), multipliers[starters[8]])
+
+		// Chips change who scores and by how much
+		lineup.Chip = models.ChipTripleCaptain
+		multipliers, err = Multipliers(db, lineup, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(3), multipliers[starters[9]])
+
+		lineup.Chip = models.ChipBenchBoost
+		lineup.Bench = pick(playerIDs, 2, 7, 12, 15)
+		multipliers, err = Multipliers(db, lineup, 2)
+		assert.NoError(t, err)
+		assert.Len(t, multipliers, 15)
+		assert.Equal(t, float64(2), multipliers[starters[9]])
+	})
+
+	t.Run("Chips", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID, _ := setup()
+		first := createGameweek(1, time.Now().Add(time.Hour))
+		second := createGameweek(2, time.Now().Add(24*time.Hour))
+		past := createGameweek(3, time.Now().Add(-time.Hour))
+
+		chip, err := lineupService.PlayChip(userTeamID, first, models.ChipTripleCaptain)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ChipTripleCaptain, chip.Chip)
+
+		lineup, err := lineupService.GetLineup(userTeamID, first)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ChipTripleCaptain, lineup.Chip)
+
+		// Each chip once a season and one chip a gameweek
+		_, err = lineupService.PlayChip(userTeamID, second, models.ChipTripleCaptain)
+		assert.ErrorIs(t, err, ErrChipUsed)
+		_, err = lineupService.PlayChip(userTeamID, first, models.ChipBenchBoost)
+		assert.ErrorIs(t, err, ErrGameweekHasChip)
+		_, err = lineupService.PlayChip(userTeamID, past, models.ChipBenchBoost)
+		assert.ErrorIs(t, err, ErrLineupLocked)
+		_, err = lineupService.PlayChip(userTeamID, second, models.ChipType("double_captain"))
+		assert.ErrorIs(t, err, ErrInvalidChip)
+
+		// Wildcards are about transfers, which draft leagues do not make
+		_, err = lineupService.PlayChip(userTeamID, second, models.ChipWildcard)
+		assert.ErrorIs(t, err, ErrInvalidChip)
+
+		_, err = lineupService.PlayChip(userTeamID, second, models.ChipBenchBoost)
+		assert.NoError(t, err)
+		chips, err := lineupService.ListChips(userTeamID)
+		assert.NoError(t, err)
+		if assert.Len(t, chips, 2) {
+			assert.Equal(t, first, chips[0].GameweekID)
+			assert.Equal(t, second, chips[1].GameweekID)
+		}
+
+		assert.NoError(t, lineupService.CancelChip(userTeamID, first))
+		assert.ErrorIs(t, lineupService.CancelChip(userTeamID, first), ErrNoChip)
+
+		// A cancelled chip can be played again
+		_, err = lineupService.PlayChip(userTeamID, first, models.ChipTripleCaptain)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ProcessAutoSubs", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID, playerIDs := setup()
+		gameweekID := createGameweek(1, time.Now().Add(time.Hour))
+		starters := pick(playerIDs, 1, 3, 4, 5, 6, 8, 9, 10, 11, 13, 14)
+		bench := pick(playerIDs, 2, 7, 12, 15)
+		_, err := lineupService.SetLineup(&models.Lineup{UserTeamID: userTeamID, GameweekID: gameweekID, Starters: starters, Bench: bench})
+		assert.NoError(t, err)
+
+		var teamID, matchID int
+		err = db.QueryRow("SELECT team_id FROM players WHERE id = $1", starters[0]).Scan(&teamID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO matches (home_team_id, away_team_id, match_date, status, gameweek_id)
+			VALUES ($1, $1, $2, 'in_progress', $3)
+			RETURNING id
+		`, teamID, time.Now(), gameweekID).Scan(&matchID)
+		assert.NoError(t, err)
+
+		// Everyone but the fifth starter, a midfielder, played
+		for _, playerID := range playerIDs {
+			minutes := 90
+			if playerID == starters[5] {
+				minutes = 0
+			}
+			_, err := db.Exec("INSERT INTO player_stats (player_id, match_id, minutes_played) VALUES ($1, $2, $3)", playerID, matchID, minutes)
+			assert.NoError(t, err)
+		}
+
+		// Not before the match is over
+		_, err = lineupService.ProcessAutoSubs(gameweekID)
+		assert.ErrorIs(t, err, ErrGameweekNotFinished)
+
+		_, err = db.Exec("UPDATE matches SET status = 'completed' WHERE id = $1", matchID)
+		assert.NoError(t, err)
+		_, err = db.Exec("UPDATE gameweeks SET deadline = $1 WHERE id = $2", time.Now().Add(-time.Hour), gameweekID)
+		assert.NoError(t, err)
+
+		for run := 0; run < 2; run++ {
+			substitutions, err := lineupService.ProcessAutoSubs(gameweekID)
+			assert.NoError(t, err)
+			assert.Len(t, substitutions, 1)
+		}
+
+		lineup, err := lineupService.GetLineup(userTeamID, gameweekID)
+		assert.NoError(t, err)
+		if assert.Len(t, lineup.Substitutions, 1) {
+			assert.Equal(t, starters[5], lineup.Substitutions[0].PlayerOutID)
+			assert.Equal(t, bench[1], lineup.Substitutions[0].PlayerInID)
+		}
+	})
+
+	t.Run("GetLineup for a missing gameweek", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, err := lineupService.GetLineup(1, 999)
+		assert.ErrorIs(t, err, ErrGameweekNotFound)
+	})
+}
@@ -0,0 +1,327 @@
+package mock_draft
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go-app/models"
+	"go-app/services/draft"
+)
+
+var (
+	// ErrPositionFull is returned when a squad already holds the most players allowed at a position
+	ErrPositionFull = errors.New("squad already has the maximum number of players in that position")
+	// ErrPositionsNeeded is returned when a squad's picks left are all needed for positions it is short of
+	ErrPositionsNeeded = errors.New("squad's remaining picks are needed for positions below their minimum")
+	// ErrPlayerNotFound is returned when a player is not in the mock draft's pool
+	ErrPlayerNotFound = errors.New("player not found in the mock draft pool")
+)
+
+// DefaultPositionLimits caps how many players of each position a squad can hold
+var DefaultPositionLimits = map[models.Position]int{
+	models.PositionGK:  2,
+	models.PositionDEF: 5,
+	models.PositionMID: 5,
+	models.PositionFWD: 3,
+}
+
+// DefaultPositionMinimums is how many players of each position a squad needs to field a starting XI
+var DefaultPositionMinimums = map[models.Position]int{
+	models.PositionGK:  1,
+	models.PositionDEF: 3,
+	models.PositionMID: 2,
+	models.PositionFWD: 1,
+}
+
+// DefaultRandomness is how many of the best available players a bot chooses between
+const DefaultRandomness = 3
+
+// RankedPlayer is a player in the mock draft pool
+type RankedPlayer struct {
+	PlayerID int             `db:"id" json:"player_id"`
+	Name     string          `db:"name" json:"name"`
+	Position models.Position `db:"position" json:"position"`
+	Rank     int             `db:"rank" json:"rank"`
+}
+
+// Settings holds the setup of a mock draft
+type Settings struct {
+	Teams      int   `json:"teams"`
+	Rounds     int   `json:"rounds"`
+	UserSlot   int   `json:"user_slot"`  // the user's position in the draft order
+	Randomness int   `json:"randomness"` // how many of the best available players a bot chooses between
+	Seed       int64 `json:"seed"`       // zero picks a random seed
+}
+
+// Pick represents a player taken in a mock draft
+type Pick struct {
+	Round       int             `json:"round"`
+	Pick        int             `json:"pick"`
+	OverallPick int             `json:"overall_pick"`
+	Slot        int             `json:"slot"`
+	PlayerID    int             `json:"player_id"`
+	Name        string          `json:"name"`
+	Position    models.Position `json:"position"`
+	ByUser      bool            `json:"by_user"`
+}
+
+// MockDraft is a snake draft run in memory between a user and bot managers
+type MockDraft struct {
+	ID           int                `json:"id"`
+	Settings     Settings           `json:"settings"`
+	Status       models.DraftStatus `json:"status"`
+	CurrentRound int                `json:"current_round"`
+	CurrentPick  int                `json:"current_pick"`
+	OnTheClock   int                `json:"on_the_clock,omitempty"` // slot due to pick
+	Picks        []*Pick            `json:"picks"`
+	Available    []*RankedPlayer    `json:"available"`
+	CreatedAt    time.Time          `json:"created_at"`
+
+	rng    *rand.Rand
+	counts []map[models.Position]int // players taken at each position, by slot
+}
+
+// New sets up a mock draft from the given player rankings and runs the bots up to the user's first pick
+func New(id int, settings Settings, rankings []*RankedPlayer) (*MockDraft, error) {
+	if settings.Teams < 2 {
+		return nil, fmt.Errorf("a mock draft needs at least two teams")
+	}
+	if settings.Rounds <= 0 {
+		return nil, fmt.Errorf("rounds must be greater than zero")
+	}
+	if settings.UserSlot < 1 || settings.UserSlot > settings.Teams {
+		return nil, fmt.Errorf("user slot must be between 1 and %d", settings.Teams)
+	}
+	squadSize := 0
+	for _, limit := range DefaultPositionLimits {
+		squadSize += limit
+	}
+	if settings.Rounds > squadSize {
+		return nil, fmt.Errorf("rounds cannot exceed the squad size of %d", squadSize)
+	}
+	if len(rankings) < settings.Teams*settings.Rounds {
+		return nil, fmt.Errorf("not enough players to run %d rounds with %d teams", settings.Rounds, settings.Teams)
+	}
+	if settings.Randomness <= 0 {
+		settings.Randomness = DefaultRandomness
+	}
+	if settings.Seed == 0 {
+		settings.Seed = time.Now().UnixNano()
+	}
+
+	available := make([]*RankedPlayer, len(rankings))
+	copy(available, rankings)
+	sort.SliceStable(available, func(i, j int) bool {
+		return available[i].Rank < available[j].Rank
+	})
+
+	md := &MockDraft{
+		ID:           id,
+		Settings:     settings,
+		Status:       models.DraftStatusInProgress,
+		CurrentRound: 1,
+		CurrentPick:  1,
+		Picks:        []*Pick{},
+		Available:    available,
+		CreatedAt:    time.Now(),
+		rng:          rand.New(rand.NewSource(settings.Seed)),
+		counts:       make([]map[models.Position]int, settings.Teams),
+	}
+	for i := range md.counts {
+		md.counts[i] = make(map[models.Position]int)
+	}
+
+	md.runBots()
+	return md, nil
+}
+
+// slotOnTheClock returns the draft slot due to make the current pick
+func (md *MockDraft) slotOnTheClock() int {
+	return draft.SnakeOrderIndex(md.CurrentRound, md.CurrentPick, md.Settings.Teams) + 1
+}
+
+// MakePick takes a player for the user and runs the bots up to the user's next pick
+func (md *MockDraft) MakePick(playerID int) error {
+	if md.Status != models.DraftStatusInProgress {
+		return draft.ErrDraftNotInProgress
+	}
+	if md.slotOnTheClock() != md.Settings.UserSlot {
+		return draft.ErrNotYourTurn
+	}
+
+	index := -1
+	for i, player := range md.Available {
+		if player.PlayerID == playerID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		for _, pick := range md.Picks {
+			if pick.PlayerID == playerID {
+				return draft.ErrPlayerAlreadyDrafted
+			}
+		}
+		return ErrPlayerNotFound
+	}
+	if err := md.canTake(md.Settings.UserSlot, md.Available[index].Position); err != nil {
+		return err
+	}
+
+	md.take(index, true)
+	md.runBots()
+	return nil
+}
+
+// AutoPick lets a bot make the user's pick and runs the bots up to the user's next pick.
+// The pick is passed if no player left in the pool fits the user's squad.
+func (md *MockDraft) AutoPick() error {
+	if md.Status != models.DraftStatusInProgress {
+		return draft.ErrDraftNotInProgress
+	}
+	if md.slotOnTheClock() != md.Settings.UserSlot {
+		return draft.ErrNotYourTurn
+	}
+
+	if index := md.botChoice(md.Settings.UserSlot); index >= 0 {
+		md.take(index, true)
+	} else {
+		md.advance()
+	}
+	md.runBots()
+	return nil
+}
+
+// Roster returns the players taken by a draft slot
+func (md *MockDraft) Roster(slot int) []*Pick {
+	var roster []*Pick
+	for _, pick := range md.Picks {
+		if pick.Slot == slot {
+			roster = append(roster, pick)
+		}
+	}
+	return roster
+}
+
+// runBots makes bot picks until it is the user's turn or the draft is over. A bot passes
+// its pick if no player left in the pool fits its squad.
+func (md *MockDraft) runBots() {
+	for md.Status == models.DraftStatusInProgress {
+		slot := md.slotOnTheClock()
+		if slot == md.Settings.UserSlot {
+			md.OnTheClock = slot
+			return
+		}
+		if index := md.botChoice(slot); index >= 0 {
+			md.take(index, false)
+		} else {
+			md.advance()
+		}
+	}
+	md.OnTheClock = 0
+}
+
+// botChoice picks one of the best available players the slot can take, favouring the
+// higher ranked ones. It returns -1 if none of them fits.
+func (md *MockDraft) botChoice(slot int) int {
+	var candidates []int
+	for i, player := range md.Available {
+		if md.canTake(slot, player.Position) == nil {
+			candidates = append(candidates, i)
+			if len(candidates) == md.Settings.Randomness {
+				break
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		// The pool has run out of players at every position the slot needs
+		return -1
+	}
+
+	// Weight the candidates n, n-1, ..., 1 from best to worst
+	total := len(candidates) * (len(candidates) + 1) / 2
+	roll := md.rng.Intn(total)
+	for i, index := range candidates {
+		roll -= len(candidates) - i
+		if roll < 0 {
+			return index
+		}
+	}
+	return candidates[0]
+}
+
+// canTake checks whether the slot on the clock can take a player at a position. The slot must
+// have room for them, and once its picks left are only enough to bring every position up to its
+// minimum, the player has to fill one of those positions.
+func (md *MockDraft) canTake(slot int, position models.Position) error {
+	counts := md.counts[slot-1]
+	if counts[position] >= DefaultPositionLimits[position] {
+		return ErrPositionFull
+	}
+	if counts[position] >= DefaultPositionMinimums[position] && md.shortfall(slot) >= md.picksLeft() {
+		return ErrPositionsNeeded
+	}
+	return nil
+}
+
+// shortfall counts the players a slot still needs to bring every position up to its minimum
+func (md *MockDraft) shortfall(slot int) int {
+	short := 0
+	for position, minimum := range DefaultPositionMinimums {
+		if count := md.counts[slot-1][position]; count < minimum {
+			short += minimum - count
+		}
+	}
+	return short
+}
+
+// picksLeft counts the picks the slot on the clock has left, including the current one
+func (md *MockDraft) picksLeft() int {
+	return md.Settings.Rounds - md.CurrentRound + 1
+}
+
+// take gives the available player at index to the slot on the clock and moves to the next pick
+func (md *MockDraft) take(index int, byUser bool) {
+	player := md.Available[index]
+	slot := md.slotOnTheClock()
+
+	md.Picks = append(md.Picks, &Pick{
+		Round:       md.CurrentRound,
+		Pick:        md.CurrentPick,
+		OverallPick: len(md.Picks) + 1,
+		Slot:        slot,
+		PlayerID:    player.PlayerID,
+		Name:        player.Name,
+		Position:    player.Position,
+		ByUser:      byUser,
+	})
+	md.counts[slot-1][player.Position]++
+	md.Available = append(md.Available[:index], md.Available[index+1:]...)
+	md.advance()
+}
+
+// advance moves to the next pick, completing the draft after the last one
+func (md *MockDraft) advance() {
+	md.CurrentPick++
+	if md.CurrentPick > md.Settings.Teams {
+		md.CurrentPick = 1
+		md.CurrentRound++
+	}
+	if md.CurrentRound > md.Settings.Rounds {
+		md.Status = models.DraftStatusCompleted
+	}
+}
+
+// snapshot returns a copy of the draft that is safe to read while the original changes
+func (md *MockDraft) snapshot() *MockDraft {
+	copied := *md
+	copied.Picks = append([]*Pick{}, md.Picks...)
+	copied.Available = append([]*RankedPlayer{}, md.Available...)
+	copied.rng = nil
+	copied.counts = nil
+	return &copied
+}
@@ -0,0 +1,141 @@
+package mock_draft
+
+import (
+	"sync"
+	"time"
+
+	"go-app/services/draft"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// mockDraftTTL is how long a mock draft is kept in memory after it was created
+const mockDraftTTL = 24 * time.Hour
+
+// MockDraftService defines the interface for running mock drafts
+type MockDraftService interface {
+	CreateMockDraft(settings Settings) (*MockDraft, error)
+	GetMockDraft(id int) (*MockDraft, error)
+	MakePick(id, playerID int) (*MockDraft, error)
+	AutoPick(id int) (*MockDraft, error)
+}
+
+// Implementation of the MockDraftService interface. Mock drafts are only ever held in memory.
+type mockDraftServiceImpl struct {
+	loadRankings func() ([]*RankedPlayer, error)
+
+	mu     sync.Mutex
+	nextID int
+	drafts map[int]*MockDraft
+}
+
+// NewMockDraftService creates a new MockDraftService instance that ranks players from the database
+func NewMockDraftService(db *sqlx.DB) MockDraftService {
+	return &mockDraftServiceImpl{
+		loadRankings: func() ([]*RankedPlayer, error) {
+			return LoadRankings(db)
+		},
+		drafts: make(map[int]*MockDraft),
+	}
+}
+
+// NewMockDraftServiceWithRankings creates a new MockDraftService instance that drafts from the given rankings
+func NewMockDraftServiceWithRankings(rankings []*RankedPlayer) MockDraftService {
+	return &mockDraftServiceImpl{
+		loadRankings: func() ([]*RankedPlayer, error) {
+			return rankings, nil
+		},
+		drafts: make(map[int]*MockDraft),
+	}
+}
+
+// LoadRankings ranks every player by the same default ranking the real draft's autopick uses.
+// It only reads from the database.
+func LoadRankings(db sqlx.Queryer) ([]*RankedPlayer, error) {
+	var rankings []*RankedPlayer
+	err := sqlx.Select(db, &rankings, `
+		SELECT p.id, p.first_name || ' ' || p.last_name AS name, p.position,
+			ROW_NUMBER() OVER (ORDER BY `+draft.DefaultRankingScore+` DESC, p.id) AS rank
+		FROM players p
+		LEFT JOIN player_stats ps ON ps.player_id = p.id
+		GROUP BY p.id
+		ORDER BY rank
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return rankings, nil
+}
+
+// CreateMockDraft starts a new mock draft and runs the bots up to the user's first pick
+func (s *mockDraftServiceImpl) CreateMockDraft(settings Settings) (*MockDraft, error) {
+	rankings, err := s.loadRankings()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	s.nextID++
+	md, err := New(s.nextID, settings, rankings)
+	if err != nil {
+		return nil, err
+	}
+	s.drafts[md.ID] = md
+
+	return md.snapshot(), nil
+}
+
+// GetMockDraft retrieves a mock draft, or nil if it does not exist
+func (s *mockDraftServiceImpl) GetMockDraft(id int) (*MockDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	md, ok := s.drafts[id]
+	if !ok {
+		return nil, nil
+	}
+	return md.snapshot(), nil
+}
+
+// MakePick takes a player for the user in a mock draft
+func (s *mockDraftServiceImpl) MakePick(id, playerID int) (*MockDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	md, ok := s.drafts[id]
+	if !ok {
+		return nil, nil
+	}
+	if err := md.MakePick(playerID); err != nil {
+		return nil, err
+	}
+	return md.snapshot(), nil
+}
+
+// AutoPick lets a bot make the user's pick in a mock draft
+func (s *mockDraftServiceImpl) AutoPick(id int) (*MockDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	md, ok := s.drafts[id]
+	if !ok {
+		return nil, nil
+	}
+	if err := md.AutoPick(); err != nil {
+		return nil, err
+	}
+	return md.snapshot(), nil
+}
+
+// evictExpired drops mock drafts older than mockDraftTTL. The caller must hold s.mu.
+func (s *mockDraftServiceImpl) evictExpired() {
+	cutoff := time.Now().Add(-mockDraftTTL)
+	for id, md := range s.drafts {
+		if md.CreatedAt.Before(cutoff) {
+			delete(s.drafts, id)
+		}
+	}
+}
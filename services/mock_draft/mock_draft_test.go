@@ -0,0 +1,223 @@
+package mock_draft
+
+import (
+	"fmt"
+	"testing"
+
+	"go-app/models"
+	"go-app/services/draft"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createRankings builds a ranked player pool with an even spread of positions
+func createRankings(count int) []*RankedPlayer {
+	positions := []models.Position{models.PositionGK, models.PositionDEF, models.PositionMID, models.PositionFWD}
+	rankings := make([]*RankedPlayer, count)
+	for i := range rankings {
+		rankings[i] = &RankedPlayer{
+			PlayerID: i + 1,
+			Name:     fmt.Sprintf("Player %d", i+1),
+			Position: positions[i%len(positions)],
+			Rank:     i + 1,
+		}
+	}
+	return rankings
+}
+
+func TestNew(t *testing.T) {
+	t.Run("bots pick up to the user's turn", func(t *testing.T) {
+		md, err := New(1, Settings{Teams: 4, Rounds: 3, UserSlot: 3, Seed: 1}, createRankings(40))
+		assert.NoError(t, err)
+		assert.Len(t, md.Picks, 2)
+		assert.Equal(t, 3, md.OnTheClock)
+		for _, pick := range md.Picks {
+			assert.False(t, pick.ByUser)
+		}
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		_, err := New(1, Settings{Teams: 1, Rounds: 3, UserSlot: 1}, createRankings(40))
+		assert.Error(t, err)
+
+		_, err = New(1, Settings{Teams: 4, Rounds: 3, UserSlot: 5}, createRankings(40))
+		assert.Error(t, err)
+
+		_, err = New(1, Settings{Teams: 4, Rounds: 16, UserSlot: 1}, createRankings(100))
+		assert.Error(t, err)
+
+		_, err = New(1, Settings{Teams: 4, Rounds: 3, UserSlot: 1}, createRankings(10))
+		assert.Error(t, err)
+	})
+
+	t.Run("same seed gives the same draft", func(t *testing.T) {
+		settings := Settings{Teams: 6, Rounds: 5, UserSlot: 6, Seed: 42}
+		first, err := New(1, settings, createRankings(60))
+		assert.NoError(t, err)
+		second, err := New(2, settings, createRankings(60))
+		assert.NoError(t, err)
+
+		for i := range first.Picks {
+			assert.Equal(t, first.Picks[i].PlayerID, second.Picks[i].PlayerID)
+		}
+	})
+}
+
+func TestMakePick(t *testing.T) {
+	md, err := New(1, Settings{Teams: 3, Rounds: 2, UserSlot: 1, Seed: 7}, createRankings(20))
+	assert.NoError(t, err)
+	assert.Empty(t, md.Picks)
+
+	// A player who is not in the pool
+	assert.ErrorIs(t, md.MakePick(999), ErrPlayerNotFound)
+
+	assert.NoError(t, md.MakePick(1))
+	assert.ErrorIs(t, md.MakePick(1), draft.ErrPlayerAlreadyDrafted)
+
+	// The bots have taken their picks and it is the user's turn again at the end of the next round
+	assert.Len(t, md.Picks, 5)
+	assert.Equal(t, 1, md.OnTheClock)
+	assert.True(t, md.Picks[0].ByUser)
+
+	assert.NoError(t, md.AutoPick())
+	assert.Equal(t, models.DraftStatusCompleted, md.Status)
+	assert.Len(t, md.Picks, 6)
+	assert.Len(t, md.Roster(1), 2)
+
+	assert.ErrorIs(t, md.MakePick(5), draft.ErrDraftNotInProgress)
+}
+
+func TestPositionLimits(t *testing.T) {
+	t.Run("bots fill a full squad within the limits", func(t *testing.T) {
+		for seed := int64(1); seed <= 20; seed++ {
+			md, err := New(1, Settings{Teams: 4, Rounds: 15, UserSlot: 1, Randomness: 5, Seed: seed}, createRankings(200))
+			assert.NoError(t, err)
+
+			for md.Status == models.DraftStatusInProgress {
+				assert.NoError(t, md.AutoPick())
+			}
+
+			for slot := 1; slot <= 4; slot++ {
+				counts := make(map[models.Position]int)
+				for _, pick := range md.Roster(slot) {
+					counts[pick.Position]++
+				}
+				assert.Equal(t, DefaultPositionLimits, counts)
+			}
+		}
+	})
+
+	t.Run("user cannot overfill a position", func(t *testing.T) {
+		md, err := New(1, Settings{Teams: 2, Rounds: 15, UserSlot: 1, Seed: 3}, createRankings(40))
+		assert.NoError(t, err)
+
+		// bestGoalkeeper returns the highest ranked goalkeeper left in the pool
+		bestGoalkeeper := func() int {
+			for _, player := range md.Available {
+				if player.Position == models.PositionGK {
+					return player.PlayerID
+				}
+			}
+			return 0
+		}
+
+		assert.NoError(t, md.MakePick(bestGoalkeeper()))
+		assert.NoError(t, md.MakePick(bestGoalkeeper()))
+		assert.ErrorIs(t, md.MakePick(bestGoalkeeper()), ErrPositionFull)
+	})
+
+	t.Run("picks are passed once nobody left fits", func(t *testing.T) {
+		// A pool of goalkeepers only runs out of players who fit after two rounds
+		rankings := createRankings(50)
+		for _, player := range rankings {
+			player.Position = models.PositionGK
+		}
+		md, err := New(1, Settings{Teams: 3, Rounds: 15, UserSlot: 2, Seed: 5}, rankings)
+		assert.NoError(t, err)
+
+		for md.Status == models.DraftStatusInProgress {
+			assert.NoError(t, md.AutoPick())
+		}
+
+		assert.Len(t, md.Picks, 6)
+		for slot := 1; slot <= 3; slot++ {
+			assert.Len(t, md.Roster(slot), DefaultPositionLimits[models.PositionGK])
+		}
+	})
+}
+
+func TestPositionMinimums(t *testing.T) {
+	// lowRankedGoalkeepers builds a pool where every goalkeeper is ranked below every outfield player
+	lowRankedGoalkeepers := func() []*RankedPlayer {
+		outfield := []models.Position{models.PositionDEF, models.PositionMID, models.PositionFWD}
+		rankings := createRankings(200)
+		for i, player := range rankings {
+			if i < 150 {
+				player.Position = outfield[i%len(outfield)]
+			} else {
+				player.Position = models.PositionGK
+			}
+		}
+		return rankings
+	}
+
+	t.Run("bots keep picks for positions they are short of", func(t *testing.T) {
+		for seed := int64(1); seed <= 20; seed++ {
+			md, err := New(1, Settings{Teams: 4, Rounds: 8, UserSlot: 1, Randomness: 5, Seed: seed}, lowRankedGoalkeepers())
+			assert.NoError(t, err)
+
+			for md.Status == models.DraftStatusInProgress {
+				assert.NoError(t, md.AutoPick())
+			}
+
+			for slot := 1; slot <= 4; slot++ {
+				counts := make(map[models.Position]int)
+				for _, pick := range md.Roster(slot) {
+					counts[pick.Position]++
+				}
+				for position, minimum := range DefaultPositionMinimums {
+					assert.GreaterOrEqual(t, counts[position], minimum, "slot %d %s", slot, position)
+				}
+			}
+		}
+	})
+
+	t.Run("user cannot spend picks needed for other positions", func(t *testing.T) {
+		md, err := New(1, Settings{Teams: 2, Rounds: 7, UserSlot: 1, Seed: 3}, lowRankedGoalkeepers())
+		assert.NoError(t, err)
+
+		// bestAt returns the highest ranked player left in the pool at a position
+		bestAt := func(position models.Position) int {
+			for _, player := range md.Available {
+				if player.Position == position {
+					return player.PlayerID
+				}
+			}
+			return 0
+		}
+
+		// Seven rounds only just cover the minimums, so every pick has to go towards them
+		assert.NoError(t, md.MakePick(bestAt(models.PositionFWD)))
+		assert.ErrorIs(t, md.MakePick(bestAt(models.PositionFWD)), ErrPositionsNeeded)
+		assert.NoError(t, md.MakePick(bestAt(models.PositionGK)))
+	})
+}
+
+func TestMockDraftService(t *testing.T) {
+	service := NewMockDraftServiceWithRankings(createRankings(40))
+
+	created, err := service.CreateMockDraft(Settings{Teams: 4, Rounds: 2, UserSlot: 2, Seed: 9})
+	assert.NoError(t, err)
+	assert.Len(t, created.Picks, 1)
+
+	updated, err := service.MakePick(created.ID, created.Available[0].PlayerID)
+	assert.NoError(t, err)
+	assert.Len(t, updated.Picks, 6)
+
+	// Earlier snapshots are not changed by later picks
+	assert.Len(t, created.Picks, 1)
+
+	missing, err := service.GetMockDraft(created.ID + 1)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,74 @@
+package player
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RoundPrice rounds a price to the nearest 0.1, the smallest step prices move in
+func RoundPrice(price float64) float64 {
+	return math.Round(price*10) / 10
+}
+
+// SetPrice changes a player's current price and records it in the price history
+func SetPrice(tx *sqlx.Tx, playerID int, price float64, at time.Time) error {
+	price = RoundPrice(price)
+	if price < 0 {
+		return fmt.Errorf("price cannot be negative")
+	}
+
+	result, err := tx.Exec("UPDATE players SET price = $1, updated_at = $2 WHERE id = $3", price, at, playerID)
+	if err != nil {
+		return fmt.Errorf("error updating price: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("player with ID %d not found", playerID)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO player_prices (player_id, price, created_at)
+		VALUES ($1, $2, $3)
+	`, playerID, price, at)
+	if err != nil {
+		return fmt.Errorf("error recording price history: %w", err)
+	}
+	return nil
+}
+
+// UpdatePrice changes a player's current price
+func (s *playerServiceImpl) UpdatePrice(playerID int, price float64) (*models.Player, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := SetPrice(tx, playerID, price, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing price: %w", err)
+	}
+
+	return s.GetPlayer(playerID)
+}
+
+// GetPriceHistory retrieves every price a player has had, oldest first
+func (s *playerServiceImpl) GetPriceHistory(playerID int) ([]*models.PlayerPrice, error) {
+	var prices []*models.PlayerPrice
+	err := s.db.Select(&prices, "SELECT * FROM player_prices WHERE player_id = $1 ORDER BY created_at, id", playerID)
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -20,6 +20,8 @@ type PlayerService interface {
 	GetPlayerStats(playerID int) (*models.PlayerStats, error)
 	ValidatePlayer(player *models.Player) error
 	ValidatePosition(position models.Position) error
+	UpdatePrice(playerID int, price float64) (*models.Player, error)
+	GetPriceHistory(playerID int) ([]*models.PlayerPrice, error)
 }
 
 // PlayerFilter represents the filter criteria for listing players
@@ -63,16 +65,28 @@ func (s *playerServiceImpl) CreatePlayer(player *models.Player) (*models.Player,
 	player.UpdatedAt = now
 
 	// Insert player into database
+	player.Price = RoundPrice(player.Price)
 	var id int
 	err := s.db.QueryRow(`
-		INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO players (team_id, first_name, last_name, position, price, external_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
-	`, player.TeamID, player.FirstName, player.LastName, player.Position, player.CreatedAt, player.UpdatedAt).Scan(&id)
+	`, player.TeamID, player.FirstName, player.LastName, player.Position, player.Price, player.ExternalID, player.CreatedAt, player.UpdatedAt).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
 
+	// Start the price history from the opening price
+	if player.Price > 0 {
+		_, err = s.db.Exec(`
+			INSERT INTO player_prices (player_id, price, created_at)
+			VALUES ($1, $2, $3)
+		`, id, player.Price, player.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Set the ID and return the player
 	player.ID = id
 	return player, nil
@@ -101,9 +115,9 @@ func (s *playerServiceImpl) UpdatePlayer(player *models.Player) (*models.Player,
 	// Update player in database
 	_, err = s.db.Exec(`
 		UPDATE players 
-		SET team_id = $1, first_name = $2, last_name = $3, position = $4, updated_at = $5
-		WHERE id = $6
-	`, player.TeamID, player.FirstName, player.LastName, player.Position, player.UpdatedAt, player.ID)
+		SET team_id = $1, first_name = $2, last_name = $3, position = $4, external_id = $5, updated_at = $6
+		WHERE id = $7
+	`, player.TeamID, player.FirstName, player.LastName, player.Position, player.ExternalID, player.UpdatedAt, player.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -191,6 +205,9 @@ func (s *playerServiceImpl) ValidatePlayer(player *models.Player) error {
 	if player.Position == "" {
 		return fmt.Errorf("position is required")
 	}
+	if player.Price < 0 {
+		return fmt.Errorf("price cannot be negative")
+	}
 	return nil
 }
 
@@ -0,0 +1,174 @@
+package player_sync
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-app/external"
+	"go-app/models"
+	"go-app/services/team_sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PlayerSyncService handles synchronizing players from API-Football squads
+type PlayerSyncService struct {
+	db                *sqlx.DB
+	apiFootballClient external.APIFootballClientInterface
+}
+
+// NewPlayerSyncService creates a new PlayerSyncService instance
+func NewPlayerSyncService(db *sqlx.DB, apiFootballClient external.APIFootballClientInterface) *PlayerSyncService {
+	return &PlayerSyncService{
+		db:                db,
+		apiFootballClient: apiFootballClient,
+	}
+}
+
+// SyncResult counts the players a squad sync created, changed and linked
+type SyncResult struct {
+	Created int
+	Updated int // linked players who changed team or position
+	Linked  int // players added by hand who were matched to a squad player
+	Skipped int // squad players with a position we do not know
+}
+
+// SyncPlayers fetches the squad of every synced team from the API-Football service and creates
+// or updates a player for each squad player, keyed by their API-Football ID. A squad player who
+// is not linked yet is matched to the one unlinked player of their team with the same last name,
+// so players added by hand keep their ID, and created otherwise. Players who have left every
+// squad are left alone, and names are only set when a player is created, so they can be edited.
+// A squad that fails to fetch is logged and skipped.
+func (s *PlayerSyncService) SyncPlayers() (*SyncResult, error) {
+	log.Println("Starting player sync from API-Football")
+
+	teamIDs, err := team_sync.TeamsByExternalID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Squads are fetched before the transaction so it is not held open across requests
+	squads := make(map[int][]*external.SquadPlayer, len(teamIDs))
+	fetched := 0
+	for teamExternalID, teamID := range teamIDs {
+		squad, err := s.apiFootballClient.FetchSquad(teamExternalID)
+		if err != nil {
+			log.Printf("Error fetching squad of team %d: %v", teamExternalID, err)
+			continue
+		}
+		squads[teamID] = squad
+		fetched += len(squad)
+	}
+
+	log.Printf("Fetched %d squad players from API-Football", fetched)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result := &SyncResult{}
+	for teamID, squad := range squads {
+		for _, squadPlayer := range squad {
+			if squadPlayer.Position == "" {
+				log.Printf("Skipping player %d: unknown position", squadPlayer.ExternalID)
+				result.Skipped++
+				continue
+			}
+			if err := syncPlayer(tx, teamID, squadPlayer, result, now); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing players: %w", err)
+	}
+
+	log.Printf("Player sync completed: %d created, %d updated, %d linked, %d skipped",
+		result.Created, result.Updated, result.Linked, result.Skipped)
+	return result, nil
+}
+
+// syncPlayer creates, updates or links the player for a squad player of a team and counts it
+func syncPlayer(tx *sqlx.Tx, teamID int, squadPlayer *external.SquadPlayer, result *SyncResult, now time.Time) error {
+	firstName, lastName := SplitName(squadPlayer.Name)
+
+	player := &models.Player{}
+	err := tx.Get(player, "SELECT * FROM players WHERE external_id = $1 FOR UPDATE", squadPlayer.ExternalID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		if player.TeamID == teamID && player.Position == squadPlayer.Position {
+			return nil
+		}
+		_, err := tx.Exec(`
+			UPDATE players SET team_id = $1, position = $2, updated_at = $3
+			WHERE id = $4
+		`, teamID, squadPlayer.Position, now, player.ID)
+		if err != nil {
+			return fmt.Errorf("error updating player: %w", err)
+		}
+		result.Updated++
+		return nil
+	}
+
+	playerID, err := unlinkedPlayer(tx, teamID, lastName)
+	if err != nil {
+		return err
+	}
+	if playerID != 0 {
+		_, err := tx.Exec(`
+			UPDATE players SET external_id = $1, position = $2, updated_at = $3
+			WHERE id = $4
+		`, squadPlayer.ExternalID, squadPlayer.Position, now, playerID)
+		if err != nil {
+			return fmt.Errorf("error linking player: %w", err)
+		}
+		result.Linked++
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO players (team_id, first_name, last_name, position, external_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`, teamID, firstName, lastName, squadPlayer.Position, squadPlayer.ExternalID, now)
+	if err != nil {
+		return fmt.Errorf("error creating player: %w", err)
+	}
+	result.Created++
+	return nil
+}
+
+// SplitName splits a name as API-Football gives it into a first and last name at the first
+// space, so "Virgil van Dijk" is "Virgil" and "van Dijk". A single name is a last name.
+func SplitName(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	if i := strings.Index(name, " "); i >= 0 {
+		return name[:i], strings.TrimSpace(name[i+1:])
+	}
+	return "", name
+}
+
+// unlinkedPlayer returns the ID of the only player of a team with a last name who is not linked
+// to API-Football, or zero if there is none or more than one
+func unlinkedPlayer(q sqlx.Queryer, teamID int, lastName string) (int, error) {
+	var playerIDs []int
+	err := sqlx.Select(q, &playerIDs, `
+		SELECT id FROM players
+		WHERE team_id = $1 AND LOWER(last_name) = LOWER($2) AND external_id IS NULL
+	`, teamID, lastName)
+	if err != nil {
+		return 0, err
+	}
+	if len(playerIDs) != 1 {
+		return 0, nil
+	}
+	return playerIDs[0], nil
+}
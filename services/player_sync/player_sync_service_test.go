@@ -0,0 +1,125 @@
+package player_sync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/external"
+	"go-app/mocks"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDB *database.TestDB
+
+func TestMain(m *testing.M) {
+	// Initialize test database
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	// Run tests
+	m.Run()
+}
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name      string
+		firstName string
+		lastName  string
+	}{
+		{name: "M. Salah", firstName: "M.", lastName: "Salah"},
+		{name: "Virgil van Dijk", firstName: "Virgil", lastName: "van Dijk"},
+		{name: "Fabinho", firstName: "", lastName: "Fabinho"},
+	}
+	for _, tt := range tests {
+		firstName, lastName := SplitName(tt.name)
+		assert.Equal(t, tt.firstName, firstName, tt.name)
+		assert.Equal(t, tt.lastName, lastName, tt.name)
+	}
+}
+
+func TestPlayerSyncService(t *testing.T) {
+	t.Run("SyncPlayers", func(t *testing.T) {
+		defer testDB.Clear()
+		db := testDB.GetDB()
+		now := time.Now()
+
+		teamIDs := make(map[int]int)
+		for _, externalID := range []int{40, 50} {
+			var teamID int
+			err := db.QueryRow(`
+				INSERT INTO teams (name, external_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $3)
+				RETURNING id
+			`, fmt.Sprintf("Team %d", externalID), externalID, now).Scan(&teamID)
+			assert.NoError(t, err)
+			teamIDs[externalID] = teamID
+		}
+		// Added by hand before the teams were synced
+		var handAddedID int
+		err := db.QueryRow(`
+			INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+			VALUES ($1, 'Mohamed', 'Salah', 'MID', $2, $2)
+			RETURNING id
+		`, teamIDs[40], now).Scan(&handAddedID)
+		assert.NoError(t, err)
+
+		squads := map[int][]*external.SquadPlayer{
+			40: {
+				{ExternalID: 306, TeamExternalID: 40, Name: "M. Salah", Position: models.PositionFWD},
+				{ExternalID: 280, TeamExternalID: 40, Name: "Alisson Becker", Position: models.PositionGK},
+			},
+			50: {
+				{ExternalID: 617, TeamExternalID: 50, Name: "Ederson", Position: models.PositionGK},
+				{ExternalID: 999, TeamExternalID: 50, Name: "Someone", Position: ""},
+			},
+		}
+		client := mocks.NewMockAPIFootballClient(nil, nil).WithSquads(squads)
+		playerSyncService := NewPlayerSyncService(db, client)
+
+		result, err := playerSyncService.SyncPlayers()
+		assert.NoError(t, err)
+		assert.Equal(t, &SyncResult{Created: 2, Linked: 1, Skipped: 1}, result)
+
+		// The player added by hand keeps their ID and name
+		player := &models.Player{}
+		err = db.Get(player, "SELECT * FROM players WHERE external_id = 306")
+		assert.NoError(t, err)
+		assert.Equal(t, handAddedID, player.ID)
+		assert.Equal(t, "Mohamed", player.FirstName)
+		assert.Equal(t, models.PositionFWD, player.Position)
+
+		err = db.Get(player, "SELECT * FROM players WHERE external_id = 617")
+		assert.NoError(t, err)
+		assert.Equal(t, teamIDs[50], player.TeamID)
+		assert.Equal(t, "", player.FirstName)
+		assert.Equal(t, "Ederson", player.LastName)
+
+		// Syncing again without changes leaves the players alone
+		result, err = playerSyncService.SyncPlayers()
+		assert.NoError(t, err)
+		assert.Equal(t, &SyncResult{Skipped: 1}, result)
+
+		// A transfer moves the player to the other team
+		squads[50] = append(squads[50], squads[40][1])
+		squads[40] = squads[40][:1]
+		result, err = playerSyncService.SyncPlayers()
+		assert.NoError(t, err)
+		assert.Equal(t, &SyncResult{Updated: 1, Skipped: 1}, result)
+
+		err = db.Get(player, "SELECT * FROM players WHERE external_id = 280")
+		assert.NoError(t, err)
+		assert.Equal(t, teamIDs[50], player.TeamID)
+	})
+}
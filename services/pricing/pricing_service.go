@@ -0,0 +1,229 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go-app/models"
+	"go-app/services/player"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PriceStep is the smallest amount a price moves by, prices are stored in tenths
+const PriceStep = 0.1
+
+// Settings controls how prices react to transfer activity
+type Settings struct {
+	Threshold     int     // net transfers needed to move a price by one step
+	MaxDailySteps int     // most steps a price can end the day away from where it started it
+	MinPrice      float64 // prices never fall below this
+}
+
+// DefaultSettings returns the settings the nightly price update runs with unless told otherwise
+func DefaultSettings() Settings {
+	return Settings{
+		Threshold:     50,
+		MaxDailySteps: 1,
+		MinPrice:      3.5,
+	}
+}
+
+// PricePrediction is a player along with the transfer activity that drives their next price change
+type PricePrediction struct {
+	models.Player
+	NetTransfers   int     `db:"net_transfers" json:"net_transfers"`
+	Progress       float64 `db:"-" json:"progress"` // net transfers as a share of the threshold, +1 or -1 is a change
+	PredictedPrice float64 `db:"-" json:"predicted_price"`
+}
+
+// PricingService defines the interface for player price changes
+type PricingService interface {
+	PredictChanges() ([]*PricePrediction, error)
+	ApplyChanges() ([]*models.PriceChange, error)
+	ListPriceChanges(since time.Time) ([]*models.PriceChange, error)
+}
+
+// Implementation of the PricingService interface
+type pricingServiceImpl struct {
+	db       *sqlx.DB
+	settings Settings
+}
+
+// NewPricingService creates a new PricingService instance with the default settings
+func NewPricingService(db *sqlx.DB) PricingService {
+	return NewPricingServiceWithSettings(db, DefaultSettings())
+}
+
+// NewPricingServiceWithSettings creates a new PricingService instance with the given settings
+func NewPricingServiceWithSettings(db *sqlx.DB, settings Settings) PricingService {
+	return &pricingServiceImpl{db: db, settings: settings}
+}
+
+// PriceMove returns how far a price should move given the net transfers since it last changed
+// and how far it has already moved today. Partial steps carry over to the next run, as the
+// transfers are only counted from the last change.
+func PriceMove(settings Settings, price float64, netTransfers int, movedToday float64) float64 {
+	if settings.Threshold <= 0 || price <= 0 {
+		return 0
+	}
+
+	// Work in whole steps so rounding never lets a price drift
+	steps := netTransfers / settings.Threshold
+	moved := int(math.Round(movedToday * 10))
+	if steps > 0 {
+		if remaining := settings.MaxDailySteps - moved; steps > remaining {
+			steps = remaining
+		}
+		if steps < 0 {
+			steps = 0
+		}
+	} else if steps < 0 {
+		if remaining := settings.MaxDailySteps + moved; -steps > remaining {
+			steps = -remaining
+		}
+		if steps > 0 {
+			steps = 0
+		}
+		floor := int(math.Round((price - settings.MinPrice) * 10))
+		if floor < 0 {
+			floor = 0
+		}
+		if -steps > floor {
+			steps = -floor
+		}
+	}
+
+	return player.RoundPrice(float64(steps) * PriceStep)
+}
+
+// Predict works out the price changes due now from transfers made in every budget league
+func Predict(q sqlx.Queryer, settings Settings, now time.Time) ([]*PricePrediction, error) {
+	// Only transfers since a player's last price change count towards their next one
+	var predictions []*PricePrediction
+	err := sqlx.Select(q, &predictions, `
+		SELECT p.*, SUM(CASE WHEN t.player_in_id = p.id THEN 1 ELSE -1 END) AS net_transfers
+		FROM players p
+		LEFT JOIN (
+			SELECT player_id, MAX(created_at) AS changed_at
+			FROM player_prices
+			GROUP BY player_id
+		) lc ON lc.player_id = p.id
+		JOIN transfers t ON (t.player_in_id = p.id OR t.player_out_id = p.id)
+			AND (lc.changed_at IS NULL OR t.created_at > lc.changed_at)
+		WHERE p.price > 0
+		GROUP BY p.id
+		ORDER BY p.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var opening []struct {
+		PlayerID int     `db:"player_id"`
+		Price    float64 `db:"price"`
+	}
+	err = sqlx.Select(q, &opening, `
+		SELECT DISTINCT ON (player_id) player_id, price
+		FROM player_prices
+		WHERE created_at < $1
+		ORDER BY player_id, created_at DESC, id DESC
+	`, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	openingPrices := make(map[int]float64, len(opening))
+	for _, row := range opening {
+		openingPrices[row.PlayerID] = row.Price
+	}
+
+	for _, prediction := range predictions {
+		movedToday := 0.0
+		if openingPrice, ok := openingPrices[prediction.ID]; ok {
+			movedToday = prediction.Price - openingPrice
+		}
+		move := PriceMove(settings, prediction.Price, prediction.NetTransfers, movedToday)
+		prediction.PredictedPrice = player.RoundPrice(prediction.Price + move)
+		if settings.Threshold > 0 {
+			prediction.Progress = float64(prediction.NetTransfers) / float64(settings.Threshold)
+		}
+	}
+	return predictions, nil
+}
+
+// PredictChanges works out which players' prices are set to rise or fall, closest to changing first
+func (s *pricingServiceImpl) PredictChanges() ([]*PricePrediction, error) {
+	predictions, err := Predict(s.db, s.settings, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(predictions, func(i, j int) bool {
+		return math.Abs(predictions[i].Progress) > math.Abs(predictions[j].Progress)
+	})
+	return predictions, nil
+}
+
+// ApplyChanges moves every price that is due to change. It is run nightly.
+func (s *pricingServiceImpl) ApplyChanges() ([]*models.PriceChange, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Hold off transfers while prices are being worked out so none are missed
+	if _, err := tx.Exec("LOCK TABLE transfers IN SHARE MODE"); err != nil {
+		return nil, fmt.Errorf("error locking transfers: %w", err)
+	}
+
+	now := time.Now()
+	predictions, err := Predict(tx, s.settings, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*models.PriceChange
+	for _, prediction := range predictions {
+		if prediction.PredictedPrice == prediction.Price {
+			continue
+		}
+		if err := player.SetPrice(tx, prediction.ID, prediction.PredictedPrice, now); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &models.PriceChange{
+			PlayerID:  prediction.ID,
+			OldPrice:  prediction.Price,
+			NewPrice:  prediction.PredictedPrice,
+			ChangedAt: now,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing price changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// ListPriceChanges retrieves every price change made since the given time, latest first
+func (s *pricingServiceImpl) ListPriceChanges(since time.Time) ([]*models.PriceChange, error) {
+	var changes []*models.PriceChange
+	err := s.db.Select(&changes, `
+		SELECT player_id, old_price, price AS new_price, created_at AS changed_at
+		FROM (
+			SELECT player_id, price, created_at,
+				LAG(price) OVER (PARTITION BY player_id ORDER BY created_at, id) AS old_price
+			FROM player_prices
+		) history
+		WHERE old_price IS NOT NULL AND old_price <> price AND created_at >= $1
+		ORDER BY created_at DESC, player_id
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
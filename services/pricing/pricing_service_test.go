@@ -0,0 +1,159 @@
+package pricing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/league"
+	"go-app/services/transfer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB         *database.TestDB
+	pricingService PricingService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	pricingService = NewPricingServiceWithSettings(testDB.GetDB(), Settings{Threshold: 2, MaxDailySteps: 2, MinPrice: 4.0})
+	m.Run()
+}
+
+func TestPriceMove(t *testing.T) {
+	settings := Settings{Threshold: 10, MaxDailySteps: 3, MinPrice: 4.0}
+
+	// Not enough transfers yet
+	assert.Equal(t, 0.0, PriceMove(settings, 6.0, 9, 0))
+	assert.Equal(t, 0.0, PriceMove(settings, 6.0, -9, 0))
+
+	assert.Equal(t, 0.1, PriceMove(settings, 6.0, 10, 0))
+	assert.Equal(t, 0.2, PriceMove(settings, 6.0, 25, 0))
+	assert.Equal(t, -0.2, PriceMove(settings, 6.0, -25, 0))
+
+	// Capped at the daily limit, counting moves already made today
+	assert.Equal(t, 0.3, PriceMove(settings, 6.0, 100, 0))
+	assert.Equal(t, 0.1, PriceMove(settings, 6.2, 100, 0.2))
+	assert.Equal(t, 0.0, PriceMove(settings, 6.3, 100, 0.3))
+	assert.Equal(t, -0.6, PriceMove(settings, 6.3, -100, 0.3))
+	assert.Equal(t, -0.1, PriceMove(settings, 5.8, -100, -0.2))
+
+	// Never below the minimum price
+	assert.Equal(t, -0.1, PriceMove(settings, 4.1, -100, 0))
+	assert.Equal(t, 0.0, PriceMove(settings, 4.0, -100, 0))
+	assert.Equal(t, 0.0, PriceMove(settings, 3.5, -100, 0))
+
+	// Unpriced players never move
+	assert.Equal(t, 0.0, PriceMove(settings, 0, 100, 0))
+}
+
+func TestPricingService(t *testing.T) {
+	db := testDB.GetDB()
+	transferService := transfer.NewTransferService(db)
+	leagueService := league.NewLeagueService(db)
+
+	// createUserTeams inserts a budget league with the given number of user teams
+	createUserTeams := func(count int) []int {
+		now := time.Now()
+		var leagueID, userID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("PRICE%d", now.UnixNano()), "Price League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, "Price", "Manager", fmt.Sprintf("price_%d@example.com", now.UnixNano()), "password", now, now).Scan(&userID)
+		assert.NoError(t, err)
+
+		settings := league.DefaultSettings(leagueID)
+		settings.OwnershipMode = models.OwnershipModeBudget
+		_, err = leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+
+		ids := make([]int, count)
+		for i := range ids {
+			err = db.QueryRow(`
+				INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, userID, leagueID, fmt.Sprintf("Price Team %d", i), now, now).Scan(&ids[i])
+			assert.NoError(t, err)
+		}
+		return ids
+	}
+
+	// createPlayer inserts a forward with a price set a day ago
+	createPlayer := func(price float64) int {
+		now := time.Now()
+		var teamID, playerID int
+		err := db.QueryRow(`
+			INSERT INTO teams (name, external_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, "Price Team", now.UnixNano()%100000, now, now).Scan(&teamID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO players (team_id, first_name, last_name, position, price, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, teamID, "Price", "Player", models.PositionFWD, price, now, now).Scan(&playerID)
+		assert.NoError(t, err)
+		_, err = db.Exec("INSERT INTO player_prices (player_id, price, created_at) VALUES ($1, $2, $3)",
+			playerID, price, now.AddDate(0, 0, -1))
+		assert.NoError(t, err)
+		return playerID
+	}
+
+	t.Run("prices follow net transfers", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamIDs := createUserTeams(5)
+		playerID := createPlayer(6.0)
+		for _, userTeamID := range userTeamIDs {
+			_, err := transferService.MakeTransfers(userTeamID, nil, []int{playerID})
+			assert.NoError(t, err)
+		}
+
+		predictions, err := pricingService.PredictChanges()
+		assert.NoError(t, err)
+		if assert.Len(t, predictions, 1) {
+			assert.Equal(t, 5, predictions[0].NetTransfers)
+			assert.Equal(t, 2.5, predictions[0].Progress)
+			assert.Equal(t, 6.2, predictions[0].PredictedPrice)
+		}
+
+		changes, err := pricingService.ApplyChanges()
+		assert.NoError(t, err)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, 6.0, changes[0].OldPrice)
+			assert.Equal(t, 6.2, changes[0].NewPrice)
+		}
+
+		// Transfers before the change no longer count, and the day's cap has been reached
+		changes, err = pricingService.ApplyChanges()
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+
+		history, err := pricingService.ListPriceChanges(time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+	})
+}
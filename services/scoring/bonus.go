@@ -0,0 +1,88 @@
+package scoring
+
+import (
+	"sort"
+
+	"go-app/models"
+)
+
+// Performance score for each part of a player's match. Goals are worth more the further back a
+// player plays, as in the public games' bonus systems.
+const (
+	shortAppearanceScore  = 3 // for playing less than an hour
+	longAppearanceScore   = 6 // for playing an hour or more
+	longAppearanceMinutes = 60
+	assistScore           = 9
+	cleanSheetScore       = 12 // goalkeepers and defenders only
+	saveScore             = 2
+	penaltySavedScore     = 15
+	penaltyMissedScore    = -6
+	yellowCardScore       = -3
+	redCardScore          = -9
+	ownGoalScore          = -6
+)
+
+var goalScores = map[models.Position]int{
+	models.PositionGK:  12,
+	models.PositionDEF: 12,
+	models.PositionMID: 18,
+	models.PositionFWD: 24,
+}
+
+// PerformanceScore rates how well a player in a position played in a match from their minutes,
+// saves and the incidents they were involved in. It ranks players for bonus points and is not
+// worth any points itself.
+func PerformanceScore(position models.Position, minutes, saves int, incidents []models.IncidentType) int {
+	score := 0
+	if minutes >= longAppearanceMinutes {
+		score += longAppearanceScore
+	} else if minutes > 0 {
+		score += shortAppearanceScore
+	}
+	score += saves * saveScore
+
+	for _, incident := range incidents {
+		switch incident {
+		case models.IncidentTypeGoal, models.IncidentTypePenaltyScored:
+			score += goalScores[position]
+		case models.IncidentTypeAssist:
+			score += assistScore
+		case models.IncidentTypeCleanSheet:
+			if position == models.PositionGK || position == models.PositionDEF {
+				score += cleanSheetScore
+			}
+		case models.IncidentTypePenaltySaved:
+			score += penaltySavedScore
+		case models.IncidentTypePenaltyMissed:
+			score += penaltyMissedScore
+		case models.IncidentTypeYellowCard:
+			score += yellowCardScore
+		case models.IncidentTypeRedCard:
+			score += redCardScore
+		case models.IncidentTypeOwnGoal:
+			score += ownGoalScore
+		}
+	}
+	return score
+}
+
+// RankPerformances orders the performances in a match best first and ranks them. Players level
+// on performance score share the higher rank and the ranks they fill are skipped, so two players
+// tied for first are both ranked 1 and the next player 3. Each player earns the bonus for their
+// rank, so a tie for the last bonus place hands it to everyone tied.
+func RankPerformances(performances []*models.MatchBonus, rules *models.ScoringRules) {
+	sort.Slice(performances, func(i, j int) bool {
+		if performances[i].PerformanceScore != performances[j].PerformanceScore {
+			return performances[i].PerformanceScore > performances[j].PerformanceScore
+		}
+		return performances[i].PlayerID < performances[j].PlayerID
+	})
+	for i, performance := range performances {
+		if i > 0 && performance.PerformanceScore == performances[i-1].PerformanceScore {
+			performance.Rank = performances[i-1].Rank
+		} else {
+			performance.Rank = i + 1
+		}
+		performance.Bonus = rules.BonusFor(performance.Rank)
+	}
+}
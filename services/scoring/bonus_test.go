@@ -0,0 +1,79 @@
+package scoring
+
+import (
+	"testing"
+
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerformanceScore(t *testing.T) {
+	t.Run("goals are worth more the further back a player plays", func(t *testing.T) {
+		goal := []models.IncidentType{models.IncidentTypeGoal}
+		assert.Equal(t, 18, PerformanceScore(models.PositionDEF, 90, 0, goal))
+		assert.Equal(t, 24, PerformanceScore(models.PositionMID, 90, 0, goal))
+		assert.Equal(t, 30, PerformanceScore(models.PositionFWD, 90, 0, goal))
+	})
+
+	t.Run("minutes, saves and discipline count", func(t *testing.T) {
+		assert.Equal(t, 0, PerformanceScore(models.PositionMID, 0, 0, nil))
+		assert.Equal(t, 3, PerformanceScore(models.PositionMID, 30, 0, nil))
+		assert.Equal(t, 6, PerformanceScore(models.PositionMID, 60, 0, nil))
+		assert.Equal(t, 39, PerformanceScore(models.PositionGK, 90, 3, []models.IncidentType{
+			models.IncidentTypeCleanSheet,
+			models.IncidentTypePenaltySaved,
+		}))
+		assert.Equal(t, -12, PerformanceScore(models.PositionFWD, 90, 0, []models.IncidentType{
+			models.IncidentTypeCleanSheet,
+			models.IncidentTypeRedCard,
+			models.IncidentTypePenaltyMissed,
+			models.IncidentTypeYellowCard,
+		}))
+	})
+}
+
+func TestRankPerformances(t *testing.T) {
+	rules := &models.ScoringRules{BonusPoints: []int{3, 2, 1}}
+	rank := func(scores ...int) []*models.MatchBonus {
+		performances := make([]*models.MatchBonus, len(scores))
+		for i, score := range scores {
+			performances[i] = &models.MatchBonus{PlayerID: i + 1, PerformanceScore: score}
+		}
+		RankPerformances(performances, rules)
+		return performances
+	}
+	bonuses := func(performances []*models.MatchBonus) []int {
+		points := make([]int, len(performances))
+		for i, performance := range performances {
+			points[i] = performance.Bonus
+		}
+		return points
+	}
+
+	t.Run("the top three earn the bonus, best first", func(t *testing.T) {
+		performances := rank(10, 30, 20, 40)
+		assert.Equal(t, 4, performances[0].PlayerID)
+		assert.Equal(t, []int{3, 2, 1, 0}, bonuses(performances))
+	})
+
+	t.Run("a tie for first shares the top bonus and skips second", func(t *testing.T) {
+		performances := rank(30, 30, 20, 10)
+		assert.Equal(t, []int{1, 1, 3, 4}, []int{performances[0].Rank, performances[1].Rank, performances[2].Rank, performances[3].Rank})
+		assert.Equal(t, []int{3, 3, 1, 0}, bonuses(performances))
+	})
+
+	t.Run("a tie for second shares the second bonus", func(t *testing.T) {
+		assert.Equal(t, []int{3, 2, 2, 0}, bonuses(rank(30, 20, 20, 10)))
+	})
+
+	t.Run("a tie for third hands everyone tied the last bonus", func(t *testing.T) {
+		assert.Equal(t, []int{3, 2, 1, 1, 1}, bonuses(rank(30, 20, 10, 10, 10)))
+	})
+
+	t.Run("ties are ordered by player", func(t *testing.T) {
+		performances := rank(10, 10)
+		assert.Equal(t, 1, performances[0].PlayerID)
+		assert.Equal(t, 2, performances[1].PlayerID)
+	})
+}
@@ -0,0 +1,65 @@
+package scoring
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-app/models"
+	"go-app/services/league"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LiveScores works out the scores of a league's user teams in a gameweek from its matches as
+// they stand, under the league's rules and with bonus points for matches still being played.
+// Nothing is stored, so the scores follow every new incident. They are provisional until every
+// match of the gameweek has been completed, with postponed matches not holding them up.
+func LiveScores(q sqlx.Queryer, leagueID int, gameweek *models.Gameweek, now time.Time) (*models.LiveScores, error) {
+	rules, err := LoadRules(q, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading scoring rules: %w", err)
+	}
+	settings, err := league.LoadSettings(q, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading league settings: %w", err)
+	}
+
+	live := &models.LiveScores{LeagueID: leagueID, GameweekID: gameweek.ID, Teams: []*models.LiveTeamScore{}}
+	if err := sqlx.Select(q, &live.Matches, "SELECT * FROM matches WHERE gameweek_id = $1 ORDER BY match_date, id", gameweek.ID); err != nil {
+		return nil, err
+	}
+	for _, match := range live.Matches {
+		if match.Status == models.MatchStatusScheduled || match.Status == models.MatchStatusInProgress {
+			live.Provisional = true
+		}
+	}
+
+	data, err := loadMatchData(q, "m.gameweek_id = $1", gameweek.ID)
+	if err != nil {
+		return nil, err
+	}
+	scored := make(map[int]int)
+	for _, p := range data.scorePlayers(rules, gameweek.ID, now) {
+		scored[p.PlayerID] = p.Points
+	}
+	pointsOf := func([]int) (map[int]int, error) {
+		return scored, nil
+	}
+
+	var userTeams []*models.UserTeam
+	if err := sqlx.Select(q, &userTeams, "SELECT * FROM user_teams WHERE league_id = $1 ORDER BY id", leagueID); err != nil {
+		return nil, err
+	}
+	for _, userTeam := range userTeams {
+		score, err := teamScore(q, userTeam.ID, gameweek, settings.CaptainMultiplier, pointsOf)
+		if err != nil {
+			return nil, err
+		}
+		live.Teams = append(live.Teams, &models.LiveTeamScore{Name: userTeam.Name, TeamScore: score})
+	}
+	sort.SliceStable(live.Teams, func(i, j int) bool {
+		return live.Teams[i].Points > live.Teams[j].Points
+	})
+	return live, nil
+}
@@ -0,0 +1,235 @@
+package scoring
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// incidentTypes are the incidents a ruleset can give points for
+var incidentTypes = []models.IncidentType{
+	models.IncidentTypeGoal,
+	models.IncidentTypeAssist,
+	models.IncidentTypeYellowCard,
+	models.IncidentTypeRedCard,
+	models.IncidentTypeSubstitution,
+	models.IncidentTypeCleanSheet,
+	models.IncidentTypePenaltyScored,
+	models.IncidentTypePenaltyMissed,
+	models.IncidentTypePenaltySaved,
+	models.IncidentTypeOwnGoal,
+}
+
+var positions = []models.Position{models.PositionGK, models.PositionDEF, models.PositionMID, models.PositionFWD}
+
+// presetIncidents builds the incident points of a preset from what a goal and a clean sheet
+// are worth in each position, with the other incidents the same for everyone
+func presetIncidents(goals, cleanSheets map[models.Position]int, others map[models.IncidentType]int) map[models.Position]map[models.IncidentType]int {
+	incidents := make(map[models.Position]map[models.IncidentType]int, len(positions))
+	for _, position := range positions {
+		points := map[models.IncidentType]int{
+			models.IncidentTypeGoal:          goals[position],
+			models.IncidentTypePenaltyScored: goals[position],
+			models.IncidentTypeCleanSheet:    cleanSheets[position],
+		}
+		for incident, value := range others {
+			points[incident] = value
+		}
+		incidents[position] = points
+	}
+	return incidents
+}
+
+// Presets returns the built-in rulesets, keyed by name
+func Presets() map[models.ScoringPreset]*models.ScoringRules {
+	others := map[models.IncidentType]int{
+		models.IncidentTypeAssist:        3,
+		models.IncidentTypeYellowCard:    -1,
+		models.IncidentTypeRedCard:       -3,
+		models.IncidentTypePenaltySaved:  5,
+		models.IncidentTypePenaltyMissed: -2,
+		models.IncidentTypeOwnGoal:       -2,
+	}
+	cleanSheets := map[models.Position]int{
+		models.PositionGK:  4,
+		models.PositionDEF: 4,
+		models.PositionMID: 1,
+	}
+	appearances := []models.AppearanceThreshold{{Minutes: 1, Points: 1}, {Minutes: 60, Points: 2}}
+
+	return map[models.ScoringPreset]*models.ScoringRules{
+		// Fantasy Premier League: the three best performers in a match get bonus points
+		models.ScoringPresetFPL: {
+			Preset: models.ScoringPresetFPL,
+			Incidents: presetIncidents(map[models.Position]int{
+				models.PositionGK:  10,
+				models.PositionDEF: 6,
+				models.PositionMID: 5,
+				models.PositionFWD: 4,
+			}, cleanSheets, others),
+			Appearances: appearances,
+			BonusPoints: []int{3, 2, 1},
+		},
+		// UEFA Champions League Fantasy: goalkeepers' goals count as defenders' and only the
+		// player of the match gets a bonus
+		models.ScoringPresetUCL: {
+			Preset: models.ScoringPresetUCL,
+			Incidents: presetIncidents(map[models.Position]int{
+				models.PositionGK:  6,
+				models.PositionDEF: 6,
+				models.PositionMID: 5,
+				models.PositionFWD: 4,
+			}, cleanSheets, others),
+			Appearances: appearances,
+			BonusPoints: []int{3},
+		},
+	}
+}
+
+// Preset returns a copy of a built-in ruleset for a league, or false if there is no such preset
+func Preset(leagueID int, name models.ScoringPreset) (*models.ScoringRules, bool) {
+	rules, ok := Presets()[name]
+	if !ok {
+		return nil, false
+	}
+	rules.LeagueID = leagueID
+	return rules, true
+}
+
+// DefaultRules returns the points used when a league has not set its own, which are those of
+// Fantasy Premier League. Goals and clean sheets are worth more the further back a player plays.
+func DefaultRules(leagueID int) *models.ScoringRules {
+	rules, _ := Preset(leagueID, models.ScoringPresetFPL)
+	return rules
+}
+
+// ValidateRules checks that a ruleset only gives points for known positions and incidents and
+// that its appearance thresholds and bonus points make sense
+func ValidateRules(rules *models.ScoringRules) error {
+	knownPositions := make(map[models.Position]bool, len(positions))
+	for _, position := range positions {
+		knownPositions[position] = true
+	}
+	known := make(map[models.IncidentType]bool, len(incidentTypes))
+	for _, incident := range incidentTypes {
+		known[incident] = true
+	}
+	for position, incidents := range rules.Incidents {
+		if !knownPositions[position] {
+			return fmt.Errorf("unknown position %q", position)
+		}
+		for incident := range incidents {
+			if !known[incident] {
+				return fmt.Errorf("unknown incident type %q", incident)
+			}
+		}
+	}
+
+	seen := make(map[int]bool, len(rules.Appearances))
+	for _, threshold := range rules.Appearances {
+		if threshold.Minutes <= 0 {
+			return fmt.Errorf("appearance thresholds must be at least one minute")
+		}
+		if seen[threshold.Minutes] {
+			return fmt.Errorf("more than one appearance threshold at %d minutes", threshold.Minutes)
+		}
+		seen[threshold.Minutes] = true
+	}
+
+	for i, points := range rules.BonusPoints {
+		if points < 0 {
+			return fmt.Errorf("bonus points cannot be negative")
+		}
+		if i > 0 && points > rules.BonusPoints[i-1] {
+			return fmt.Errorf("bonus points must be given best performer first")
+		}
+	}
+	return nil
+}
+
+// rulesDocument is the part of a ruleset stored as a JSON document
+type rulesDocument struct {
+	Incidents   map[models.Position]map[models.IncidentType]int `json:"incidents"`
+	Appearances []models.AppearanceThreshold                    `json:"appearances"`
+	BonusPoints []int                                           `json:"bonus_points"`
+}
+
+// storedRules is a row of league_scoring_rules
+type storedRules struct {
+	LeagueID  int                  `db:"league_id"`
+	Preset    models.ScoringPreset `db:"preset"`
+	Rules     []byte               `db:"rules"`
+	CreatedAt time.Time            `db:"created_at"`
+	UpdatedAt time.Time            `db:"updated_at"`
+}
+
+// LoadRules retrieves a league's scoring rules, falling back to the defaults if none are stored
+func LoadRules(q sqlx.Queryer, leagueID int) (*models.ScoringRules, error) {
+	stored := &storedRules{}
+	err := sqlx.Get(q, stored, "SELECT * FROM league_scoring_rules WHERE league_id = $1", leagueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultRules(leagueID), nil
+		}
+		return nil, err
+	}
+
+	document := &rulesDocument{}
+	if err := json.Unmarshal(stored.Rules, document); err != nil {
+		return nil, fmt.Errorf("error reading scoring rules: %w", err)
+	}
+	return &models.ScoringRules{
+		LeagueID:    stored.LeagueID,
+		Preset:      stored.Preset,
+		Incidents:   document.Incidents,
+		Appearances: document.Appearances,
+		BonusPoints: document.BonusPoints,
+		UpdatedAt:   stored.UpdatedAt,
+	}, nil
+}
+
+// SeasonStarted reports whether the deadline of the first gameweek has passed, after which
+// leagues can no longer change how they score
+func SeasonStarted(q sqlx.Queryer, now time.Time) (bool, error) {
+	var start sql.NullTime
+	if err := q.QueryRowx("SELECT MIN(deadline) FROM gameweeks").Scan(&start); err != nil {
+		return false, err
+	}
+	return start.Valid && !now.Before(start.Time), nil
+}
+
+// ScoreMatch works out what a player in a position earned in one match from their minutes and
+// the incidents they were involved in. Substitutions and incidents the rules do not know are
+// worth nothing.
+func ScoreMatch(rules *models.ScoringRules, position models.Position, minutes int, incidents []models.IncidentType) *models.PlayerPoints {
+	points := &models.PlayerPoints{MinutesPlayed: minutes}
+	points.Points += rules.AppearancePoints(minutes)
+
+	for _, incident := range incidents {
+		points.Points += rules.IncidentPoints(position, incident)
+		switch incident {
+		case models.IncidentTypeGoal, models.IncidentTypePenaltyScored:
+			points.Goals++
+		case models.IncidentTypeAssist:
+			points.Assists++
+		case models.IncidentTypeCleanSheet:
+			points.CleanSheets++
+		case models.IncidentTypeYellowCard:
+			points.YellowCards++
+		case models.IncidentTypeRedCard:
+			points.RedCards++
+		case models.IncidentTypePenaltySaved:
+			points.PenaltiesSaved++
+		case models.IncidentTypePenaltyMissed:
+			points.PenaltiesMissed++
+		case models.IncidentTypeOwnGoal:
+			points.OwnGoals++
+		}
+	}
+	return points
+}
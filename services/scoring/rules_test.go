@@ -0,0 +1,110 @@
+package scoring
+
+import (
+	"testing"
+
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreMatch(t *testing.T) {
+	rules := DefaultRules(1)
+
+	t.Run("appearance points depend on minutes", func(t *testing.T) {
+		assert.Equal(t, 0, ScoreMatch(rules, models.PositionMID, 0, nil).Points)
+		assert.Equal(t, 1, ScoreMatch(rules, models.PositionMID, 59, nil).Points)
+		assert.Equal(t, 2, ScoreMatch(rules, models.PositionMID, 60, nil).Points)
+	})
+
+	t.Run("goals are worth more the further back a player plays", func(t *testing.T) {
+		goal := []models.IncidentType{models.IncidentTypeGoal}
+		assert.Equal(t, 12, ScoreMatch(rules, models.PositionGK, 90, goal).Points)
+		assert.Equal(t, 8, ScoreMatch(rules, models.PositionDEF, 90, goal).Points)
+		assert.Equal(t, 7, ScoreMatch(rules, models.PositionMID, 90, goal).Points)
+		assert.Equal(t, 6, ScoreMatch(rules, models.PositionFWD, 90, goal).Points)
+	})
+
+	t.Run("every incident type is counted", func(t *testing.T) {
+		points := ScoreMatch(rules, models.PositionDEF, 90, []models.IncidentType{
+			models.IncidentTypePenaltyScored,
+			models.IncidentTypeAssist,
+			models.IncidentTypeCleanSheet,
+			models.IncidentTypeYellowCard,
+			models.IncidentTypeOwnGoal,
+			models.IncidentTypeSubstitution,
+		})
+		// 2 for 90 minutes, 6 for the penalty, 3 for the assist, 4 for the clean sheet, -1 and -2
+		assert.Equal(t, 12, points.Points)
+		assert.Equal(t, 1, points.Goals)
+		assert.Equal(t, 1, points.Assists)
+		assert.Equal(t, 1, points.CleanSheets)
+		assert.Equal(t, 1, points.YellowCards)
+		assert.Equal(t, 1, points.OwnGoals)
+
+		points = ScoreMatch(rules, models.PositionGK, 90, []models.IncidentType{
+			models.IncidentTypePenaltySaved,
+			models.IncidentTypeRedCard,
+		})
+		assert.Equal(t, 4, points.Points)
+		assert.Equal(t, 1, points.PenaltiesSaved)
+		assert.Equal(t, 1, points.RedCards)
+
+		points = ScoreMatch(rules, models.PositionFWD, 20, []models.IncidentType{
+			models.IncidentTypePenaltyMissed,
+			models.IncidentTypeCleanSheet,
+		})
+		assert.Equal(t, -1, points.Points)
+		assert.Equal(t, 1, points.PenaltiesMissed)
+	})
+}
+
+func TestRules(t *testing.T) {
+	t.Run("the highest appearance threshold reached scores", func(t *testing.T) {
+		rules := &models.ScoringRules{Appearances: []models.AppearanceThreshold{
+			{Minutes: 60, Points: 2},
+			{Minutes: 1, Points: 1},
+			{Minutes: 90, Points: 3},
+		}}
+		assert.Equal(t, 0, rules.AppearancePoints(0))
+		assert.Equal(t, 1, rules.AppearancePoints(45))
+		assert.Equal(t, 2, rules.AppearancePoints(89))
+		assert.Equal(t, 3, rules.AppearancePoints(120))
+	})
+
+	t.Run("presets are copies for a league", func(t *testing.T) {
+		fpl, ok := Preset(7, models.ScoringPresetFPL)
+		assert.True(t, ok)
+		assert.Equal(t, 7, fpl.LeagueID)
+		assert.Equal(t, []int{3, 2, 1}, fpl.BonusPoints)
+		fpl.Incidents[models.PositionFWD][models.IncidentTypeGoal] = 100
+
+		again, _ := Preset(8, models.ScoringPresetFPL)
+		assert.Equal(t, 4, again.IncidentPoints(models.PositionFWD, models.IncidentTypeGoal))
+
+		ucl, ok := Preset(7, models.ScoringPresetUCL)
+		assert.True(t, ok)
+		assert.Equal(t, 6, ucl.IncidentPoints(models.PositionGK, models.IncidentTypeGoal))
+
+		_, ok = Preset(7, models.ScoringPresetCustom)
+		assert.False(t, ok)
+		for name, rules := range Presets() {
+			assert.NoError(t, ValidateRules(rules), name)
+		}
+	})
+
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		for name, rules := range map[string]*models.ScoringRules{
+			"unknown position": {Incidents: map[models.Position]map[models.IncidentType]int{"ST": {}}},
+			"unknown incident": {Incidents: map[models.Position]map[models.IncidentType]int{
+				models.PositionFWD: {"hat_trick": 5},
+			}},
+			"zero minute threshold":      {Appearances: []models.AppearanceThreshold{{Minutes: 0, Points: 1}}},
+			"duplicate threshold":        {Appearances: []models.AppearanceThreshold{{Minutes: 60, Points: 1}, {Minutes: 60, Points: 2}}},
+			"negative bonus":             {BonusPoints: []int{3, -1}},
+			"bonus not best first order": {BonusPoints: []int{1, 2, 3}},
+		} {
+			assert.Error(t, ValidateRules(rules), name)
+		}
+	})
+}
@@ -0,0 +1,601 @@
+package scoring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go-app/models"
+	"go-app/services/gameweek"
+	"go-app/services/league"
+	"go-app/services/lineup"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrRulesLocked is returned when a league's scoring rules are changed after the season has started
+	ErrRulesLocked = errors.New("scoring rules are locked as the season has started")
+	// ErrNotCommissioner is returned when a user team other than the commissioner changes the scoring rules
+	ErrNotCommissioner = errors.New("only the commissioner can change the scoring rules")
+	// ErrInvalidRules is returned when a ruleset or preset does not make sense
+	ErrInvalidRules = errors.New("invalid scoring rules")
+	// ErrNoGameweek is returned when live scores are asked for before the first gameweek's deadline
+	ErrNoGameweek = errors.New("no gameweek has started")
+)
+
+// ScoringService defines the interface for turning match data into fantasy points
+type ScoringService interface {
+	ScoreGameweek(gameweekID int) ([]*models.PlayerPoints, error)
+	ListGameweekPoints(leagueID, gameweekID int) ([]*models.PlayerPoints, error)
+	ListPlayerPoints(leagueID, playerID int) ([]*models.PlayerPoints, error)
+	GetTeamScore(userTeamID, gameweekID int) (*models.TeamScore, error)
+	ListMatchBonus(leagueID, matchID int) ([]*models.MatchBonus, error)
+	GetStandings(leagueID int) ([]*models.Standing, error)
+	GetLiveScores(leagueID, gameweekID int) (*models.LiveScores, error)
+	GetRules(leagueID int) (*models.ScoringRules, error)
+	UpdateRules(rules *models.ScoringRules, userTeamID int) (*models.ScoringRules, error)
+	ListPresets() []*models.ScoringRules
+}
+
+// Implementation of the ScoringService interface
+type scoringServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewScoringService creates a new ScoringService instance
+func NewScoringService(db *sqlx.DB) ScoringService {
+	return &scoringServiceImpl{db: db}
+}
+
+// ScoreGameweek works out every player's points in a gameweek from its matches under each
+// league's rules and stores them, replacing any scored before, so it can be rerun as match data
+// comes in
+func (s *scoringServiceImpl) ScoreGameweek(gameweekID int) ([]*models.PlayerPoints, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow("SELECT id FROM gameweeks WHERE id = $1 FOR UPDATE", gameweekID).Scan(&gameweekID); err != nil {
+		return nil, err
+	}
+	points, err := ScoreGameweek(tx, gameweekID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing points: %w", err)
+	}
+
+	return points, nil
+}
+
+// matchKey identifies a player's part in one match
+type matchKey struct {
+	playerID int
+	matchID  int
+}
+
+// performance is what a player did in one match
+type performance struct {
+	minutes   int
+	saves     int
+	incidents []models.IncidentType
+}
+
+// matchData is what the points for a set of matches are worked out from
+type matchData struct {
+	keys         []matchKey
+	performances map[matchKey]*performance
+	statuses     map[int]string
+	positions    map[int]models.Position
+}
+
+// loadMatchData loads the minutes, saves and incidents of every player who played or was
+// involved in an incident in the matches picked out by a condition on m, the matches table
+func loadMatchData(q sqlx.Queryer, condition string, args ...interface{}) (*matchData, error) {
+	var matches []*models.Match
+	if err := sqlx.Select(q, &matches, "SELECT m.* FROM matches m WHERE "+condition, args...); err != nil {
+		return nil, err
+	}
+	var stats []*models.PlayerStats
+	err := sqlx.Select(q, &stats, `
+		SELECT ps.player_id, ps.match_id, ps.minutes_played, ps.saves
+		FROM player_stats ps
+		JOIN matches m ON m.id = ps.match_id
+		WHERE `+condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	var incidents []*models.MatchIncident
+	err = sqlx.Select(q, &incidents, `
+		SELECT mi.id, mi.match_id, mi.player_id, mi.type, mi.minute
+		FROM match_incidents mi
+		JOIN matches m ON m.id = mi.match_id
+		WHERE `+condition+`
+		ORDER BY mi.match_id, mi.minute, mi.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &matchData{
+		performances: make(map[matchKey]*performance),
+		statuses:     make(map[int]string, len(matches)),
+	}
+	for _, match := range matches {
+		data.statuses[match.ID] = match.Status
+	}
+	find := func(key matchKey) *performance {
+		p, ok := data.performances[key]
+		if !ok {
+			p = &performance{}
+			data.performances[key] = p
+			data.keys = append(data.keys, key)
+		}
+		return p
+	}
+	for _, stat := range stats {
+		p := find(matchKey{playerID: stat.PlayerID, matchID: *stat.MatchID})
+		p.minutes += stat.MinutesPlayed
+		p.saves += stat.Saves
+	}
+	for _, incident := range incidents {
+		p := find(matchKey{playerID: incident.PlayerID, matchID: incident.MatchID})
+		p.incidents = append(p.incidents, incident.Type)
+	}
+
+	playerIDs := make([]int, 0, len(data.keys))
+	for _, key := range data.keys {
+		playerIDs = append(playerIDs, key.playerID)
+	}
+	if data.positions, err = playerPositions(q, playerIDs); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// matchBonuses ranks the players who got on the pitch in each match, by match, with the bonus
+// each earns under a league's rules. Bonuses are provisional until their match is completed.
+func (d *matchData) matchBonuses(rules *models.ScoringRules) map[int][]*models.MatchBonus {
+	byMatch := make(map[int][]*models.MatchBonus)
+	for _, key := range d.keys {
+		p := d.performances[key]
+		if p.minutes <= 0 {
+			continue
+		}
+		byMatch[key.matchID] = append(byMatch[key.matchID], &models.MatchBonus{
+			MatchID:          key.matchID,
+			PlayerID:         key.playerID,
+			PerformanceScore: PerformanceScore(d.positions[key.playerID], p.minutes, p.saves, p.incidents),
+			Provisional:      d.statuses[key.matchID] != models.MatchStatusCompleted,
+		})
+	}
+	for _, performances := range byMatch {
+		RankPerformances(performances, rules)
+	}
+	return byMatch
+}
+
+// scorePlayers works out each player's points across the matches under a league's rules,
+// bonus included
+func (d *matchData) scorePlayers(rules *models.ScoringRules, gameweekID int, now time.Time) []*models.PlayerPoints {
+	byPlayer := make(map[int]*models.PlayerPoints)
+	for _, key := range d.keys {
+		p := d.performances[key]
+		scored := ScoreMatch(rules, d.positions[key.playerID], p.minutes, p.incidents)
+		total, ok := byPlayer[key.playerID]
+		if !ok {
+			total = &models.PlayerPoints{LeagueID: rules.LeagueID, PlayerID: key.playerID, GameweekID: gameweekID, UpdatedAt: now}
+			byPlayer[key.playerID] = total
+		}
+		total.Add(scored)
+	}
+	for _, performances := range d.matchBonuses(rules) {
+		for _, bonus := range performances {
+			if bonus.Bonus == 0 {
+				continue
+			}
+			byPlayer[bonus.PlayerID].Add(&models.PlayerPoints{
+				Points:           bonus.Bonus,
+				Bonus:            bonus.Bonus,
+				BonusProvisional: bonus.Provisional,
+			})
+		}
+	}
+
+	points := make([]*models.PlayerPoints, 0, len(byPlayer))
+	for _, total := range byPlayer {
+		points = append(points, total)
+	}
+	sortPoints(points)
+	return points
+}
+
+// ScoreGameweek works out the points of every player who played or was involved in an incident
+// in a gameweek's matches and stores them in place of those scored before. Players are scored
+// for each league with a team under that league's rules. Each match is scored on its own, with
+// bonus points for its best performers, and the matches of a gameweek added up.
+func ScoreGameweek(tx *sqlx.Tx, gameweekID int, now time.Time) ([]*models.PlayerPoints, error) {
+	data, err := loadMatchData(tx, "m.gameweek_id = $1", gameweekID)
+	if err != nil {
+		return nil, err
+	}
+
+	var leagueIDs []int
+	if err := tx.Select(&leagueIDs, "SELECT DISTINCT league_id FROM user_teams ORDER BY league_id"); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM player_points WHERE gameweek_id = $1", gameweekID); err != nil {
+		return nil, fmt.Errorf("error clearing points: %w", err)
+	}
+
+	var points []*models.PlayerPoints
+	for _, leagueID := range leagueIDs {
+		rules, err := LoadRules(tx, leagueID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading scoring rules: %w", err)
+		}
+
+		leaguePoints := data.scorePlayers(rules, gameweekID, now)
+		for _, p := range leaguePoints {
+			_, err := tx.NamedExec(`
+				INSERT INTO player_points (league_id, player_id, gameweek_id, points, minutes_played, goals, assists,
+					clean_sheets, yellow_cards, red_cards, penalties_saved, penalties_missed, own_goals, bonus,
+					bonus_provisional, updated_at)
+				VALUES (:league_id, :player_id, :gameweek_id, :points, :minutes_played, :goals, :assists,
+					:clean_sheets, :yellow_cards, :red_cards, :penalties_saved, :penalties_missed, :own_goals, :bonus,
+					:bonus_provisional, :updated_at)
+			`, p)
+			if err != nil {
+				return nil, fmt.Errorf("error storing points: %w", err)
+			}
+		}
+		points = append(points, leaguePoints...)
+	}
+	return points, nil
+}
+
+// sortPoints puts the highest scorers first
+func sortPoints(points []*models.PlayerPoints) {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Points != points[j].Points {
+			return points[i].Points > points[j].Points
+		}
+		return points[i].PlayerID < points[j].PlayerID
+	})
+}
+
+// playerPositions looks up the position of each of the given players
+func playerPositions(q sqlx.Queryer, playerIDs []int) (map[int]models.Position, error) {
+	positions := make(map[int]models.Position, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return positions, nil
+	}
+	query, args, err := sqlx.In("SELECT * FROM players WHERE id IN (?)", playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	var players []*models.Player
+	if err := sqlx.Select(q, &players, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return nil, err
+	}
+	for _, player := range players {
+		positions[player.ID] = player.Position
+	}
+	return positions, nil
+}
+
+// ListGameweekPoints retrieves the points every player scored in a gameweek under a league's
+// rules, the highest first
+func (s *scoringServiceImpl) ListGameweekPoints(leagueID, gameweekID int) ([]*models.PlayerPoints, error) {
+	var points []*models.PlayerPoints
+	err := s.db.Select(&points, `
+		SELECT * FROM player_points
+		WHERE league_id = $1 AND gameweek_id = $2
+		ORDER BY points DESC, player_id
+	`, leagueID, gameweekID)
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// ListPlayerPoints retrieves the points a player scored in each gameweek under a league's rules,
+// in gameweek order
+func (s *scoringServiceImpl) ListPlayerPoints(leagueID, playerID int) ([]*models.PlayerPoints, error) {
+	var points []*models.PlayerPoints
+	err := s.db.Select(&points, `
+		SELECT pp.*
+		FROM player_points pp
+		JOIN gameweeks g ON g.id = pp.gameweek_id
+		WHERE pp.league_id = $1 AND pp.player_id = $2
+		ORDER BY g.number
+	`, leagueID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// GetTeamScore works out what a user team scored in a gameweek
+func (s *scoringServiceImpl) GetTeamScore(userTeamID, gameweekID int) (*models.TeamScore, error) {
+	gameweek := &models.Gameweek{}
+	if err := s.db.Get(gameweek, "SELECT * FROM gameweeks WHERE id = $1", gameweekID); err != nil {
+		return nil, err
+	}
+	return TeamScore(s.db, userTeamID, gameweek)
+}
+
+// TeamScore works out what a user team scored in a gameweek from its players' stored points.
+// The lineup it fielded after automatic substitutions scores, with the captain, or the
+// vice-captain if the captain did not play, multiplied by the league's captain multiplier and
+// chips taken into account. Points for transfers beyond the free ones are taken off.
+func TeamScore(q sqlx.Queryer, userTeamID int, gameweek *models.Gameweek) (*models.TeamScore, error) {
+	var leagueID int
+	if err := q.QueryRowx("SELECT league_id FROM user_teams WHERE id = $1", userTeamID).Scan(&leagueID); err != nil {
+		return nil, err
+	}
+	settings, err := league.LoadSettings(q, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading league settings: %w", err)
+	}
+
+	return teamScore(q, userTeamID, gameweek, settings.CaptainMultiplier, func(playerIDs []int) (map[int]int, error) {
+		query, args, err := sqlx.In(`
+			SELECT * FROM player_points
+			WHERE league_id = ? AND gameweek_id = ? AND player_id IN (?)
+		`, leagueID, gameweek.ID, playerIDs)
+		if err != nil {
+			return nil, err
+		}
+		var points []*models.PlayerPoints
+		if err := sqlx.Select(q, &points, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+			return nil, err
+		}
+		scored := make(map[int]int, len(points))
+		for _, p := range points {
+			scored[p.PlayerID] = p.Points
+		}
+		return scored, nil
+	})
+}
+
+// teamScore works out what a user team scored in a gameweek, looking up the points of the
+// players who count with pointsOf
+func teamScore(q sqlx.Queryer, userTeamID int, gameweek *models.Gameweek, captainMultiplier float64, pointsOf func(playerIDs []int) (map[int]int, error)) (*models.TeamScore, error) {
+	fielded, err := lineup.FieldedLineup(q, userTeamID, gameweek)
+	if err != nil {
+		return nil, err
+	}
+	multipliers, err := lineup.Multipliers(q, fielded, captainMultiplier)
+	if err != nil {
+		return nil, err
+	}
+
+	score := &models.TeamScore{UserTeamID: userTeamID, GameweekID: gameweek.ID, Players: []*models.ScoredPlayer{}}
+	if len(multipliers) > 0 {
+		playerIDs := make([]int, 0, len(multipliers))
+		for playerID := range multipliers {
+			playerIDs = append(playerIDs, playerID)
+		}
+		scored, err := pointsOf(playerIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, playerID := range append(append([]int{}, fielded.Starters...), fielded.Bench...) {
+			multiplier, ok := multipliers[playerID]
+			if !ok {
+				continue
+			}
+			score.Players = append(score.Players, &models.ScoredPlayer{
+				PlayerID:   playerID,
+				Points:     scored[playerID],
+				Multiplier: multiplier,
+			})
+			score.Points += float64(scored[playerID]) * multiplier
+		}
+	}
+
+	err = q.QueryRowx(`
+		SELECT COALESCE(SUM(cost), 0) FROM transfers
+		WHERE user_team_id = $1 AND gameweek_id = $2
+	`, userTeamID, gameweek.ID).Scan(&score.TransferCost)
+	if err != nil {
+		return nil, err
+	}
+	score.Points = math.Round((score.Points-float64(score.TransferCost))*10) / 10
+	return score, nil
+}
+
+// ListMatchBonus ranks the players in a match by performance score, best first, with the bonus
+// points each earns under a league's rules
+func (s *scoringServiceImpl) ListMatchBonus(leagueID, matchID int) ([]*models.MatchBonus, error) {
+	if err := s.db.QueryRow("SELECT id FROM matches WHERE id = $1", matchID).Scan(&matchID); err != nil {
+		return nil, err
+	}
+	rules, err := LoadRules(s.db, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading scoring rules: %w", err)
+	}
+	data, err := loadMatchData(s.db, "m.id = $1", matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	bonuses := data.matchBonuses(rules)[matchID]
+	if bonuses == nil {
+		bonuses = []*models.MatchBonus{}
+	}
+	return bonuses, nil
+}
+
+// GetStandings ranks the user teams of a league by the points they have scored so far
+func (s *scoringServiceImpl) GetStandings(leagueID int) ([]*models.Standing, error) {
+	return Standings(s.db, leagueID)
+}
+
+// Standings ranks a league's user teams by their total score over every gameweek scored for the
+// league so far, best first. Scores are worked out from the stored points, so they move with any
+// rescoring. Teams level on points share a rank.
+func Standings(q sqlx.Queryer, leagueID int) ([]*models.Standing, error) {
+	var userTeams []*models.UserTeam
+	if err := sqlx.Select(q, &userTeams, "SELECT * FROM user_teams WHERE league_id = $1 ORDER BY id", leagueID); err != nil {
+		return nil, err
+	}
+	var gameweeks []*models.Gameweek
+	err := sqlx.Select(q, &gameweeks, `
+		SELECT g.*
+		FROM gameweeks g
+		WHERE EXISTS (SELECT 1 FROM player_points pp WHERE pp.gameweek_id = g.id AND pp.league_id = $1)
+		ORDER BY g.number
+	`, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := make([]*models.Standing, 0, len(userTeams))
+	for _, userTeam := range userTeams {
+		standing := &models.Standing{UserTeamID: userTeam.ID, Name: userTeam.Name}
+		for _, gameweek := range gameweeks {
+			score, err := TeamScore(q, userTeam.ID, gameweek)
+			if err != nil {
+				return nil, err
+			}
+			standing.Points += score.Points
+		}
+		standing.Points = math.Round(standing.Points*10) / 10
+		standings = append(standings, standing)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Points > standings[j].Points
+	})
+	for i, standing := range standings {
+		if i > 0 && standing.Points == standings[i-1].Points {
+			standing.Rank = standings[i-1].Rank
+		} else {
+			standing.Rank = i + 1
+		}
+	}
+	return standings, nil
+}
+
+// GetLiveScores works out a league's live scores in a gameweek, or in the current gameweek if
+// gameweekID is zero
+func (s *scoringServiceImpl) GetLiveScores(leagueID, gameweekID int) (*models.LiveScores, error) {
+	now := time.Now()
+	var current *models.Gameweek
+	if gameweekID == 0 {
+		var err error
+		if current, err = gameweek.LoadCurrentGameweek(s.db, now); err != nil {
+			return nil, err
+		}
+		if current == nil {
+			return nil, ErrNoGameweek
+		}
+	} else {
+		current = &models.Gameweek{}
+		if err := s.db.Get(current, "SELECT * FROM gameweeks WHERE id = $1", gameweekID); err != nil {
+			return nil, err
+		}
+	}
+	return LiveScores(s.db, leagueID, current, now)
+}
+
+// GetRules retrieves the scoring rules of a league, noting whether they are locked
+func (s *scoringServiceImpl) GetRules(leagueID int) (*models.ScoringRules, error) {
+	rules, err := LoadRules(s.db, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	if rules.Locked, err = SeasonStarted(s.db, time.Now()); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// UpdateRules stores the scoring rules of a league on behalf of one of its user teams, which has
+// to be the commissioner if the league has one. Rules naming a preset and no incident points
+// take the preset's points, while any others are stored as the league's own. The rules cannot
+// change once the season has started.
+func (s *scoringServiceImpl) UpdateRules(rules *models.ScoringRules, userTeamID int) (*models.ScoringRules, error) {
+	settings, err := league.LoadSettings(s.db, rules.LeagueID)
+	if err != nil {
+		return nil, err
+	}
+	var leagueID int
+	if err := s.db.QueryRow("SELECT league_id FROM user_teams WHERE id = $1", userTeamID).Scan(&leagueID); err != nil {
+		return nil, err
+	}
+	if leagueID != rules.LeagueID {
+		return nil, fmt.Errorf("%w: the team is not in the league", ErrNotCommissioner)
+	}
+	if settings.CommissionerTeamID != nil && *settings.CommissionerTeamID != userTeamID {
+		return nil, ErrNotCommissioner
+	}
+
+	now := time.Now()
+	started, err := SeasonStarted(s.db, now)
+	if err != nil {
+		return nil, err
+	}
+	if started {
+		return nil, ErrRulesLocked
+	}
+
+	if rules.Incidents == nil && rules.Preset != models.ScoringPresetCustom {
+		preset, ok := Preset(rules.LeagueID, rules.Preset)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown preset %q", ErrInvalidRules, rules.Preset)
+		}
+		rules = preset
+	} else {
+		rules.Preset = models.ScoringPresetCustom
+	}
+	if err := ValidateRules(rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+
+	document, err := json.Marshal(&rulesDocument{
+		Incidents:   rules.Incidents,
+		Appearances: rules.Appearances,
+		BonusPoints: rules.BonusPoints,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules.UpdatedAt = now
+	_, err = s.db.Exec(`
+		INSERT INTO league_scoring_rules (league_id, preset, rules, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (league_id) DO UPDATE
+		SET preset = EXCLUDED.preset,
+			rules = EXCLUDED.rules,
+			updated_at = EXCLUDED.updated_at
+	`, rules.LeagueID, rules.Preset, string(document), now)
+	if err != nil {
+		return nil, fmt.Errorf("error updating scoring rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// ListPresets retrieves the built-in rulesets a league can copy, in name order
+func (s *scoringServiceImpl) ListPresets() []*models.ScoringRules {
+	presets := make([]*models.ScoringRules, 0)
+	for _, rules := range Presets() {
+		presets = append(presets, rules)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Preset < presets[j].Preset
+	})
+	return presets
+}
@@ -0,0 +1,367 @@
+package scoring
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB         *database.TestDB
+	scoringService ScoringService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	scoringService = NewScoringService(testDB.GetDB())
+	m.Run()
+}
+
+func TestScoringService(t *testing.T) {
+	db := testDB.GetDB()
+
+	createGameweek := func(number int) int {
+		var id int
+		err := db.QueryRow(`
+			INSERT INTO gameweeks (number, deadline, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+			RETURNING id
+		`, number, time.Now().Add(-time.Hour), time.Now()).Scan(&id)
+		assert.NoError(t, err)
+		return id
+	}
+
+	// createPlayers inserts one player per position, all on one team, returning the team and players
+	createPlayers := func(positions ...models.Position) (int, []int) {
+		now := time.Now()
+		var teamID int
+		err := db.QueryRow(`
+			INSERT INTO teams (name, external_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, "Scoring Club", now.UnixNano()%100000, now, now).Scan(&teamID)
+		assert.NoError(t, err)
+
+		ids := make([]int, len(positions))
+		for i, position := range positions {
+			err := db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", i), position, now, now).Scan(&ids[i])
+			assert.NoError(t, err)
+		}
+		return teamID, ids
+	}
+
+	createMatchWithStatus := func(teamID, gameweekID int, status string) int {
+		var id int
+		err := db.QueryRow(`
+			INSERT INTO matches (home_team_id, away_team_id, match_date, status, gameweek_id)
+			VALUES ($1, $1, $2, $3, $4)
+			RETURNING id
+		`, teamID, time.Now(), status, gameweekID).Scan(&id)
+		assert.NoError(t, err)
+		return id
+	}
+
+	createMatch := func(teamID, gameweekID int) int {
+		return createMatchWithStatus(teamID, gameweekID, models.MatchStatusCompleted)
+	}
+
+	played := func(matchID, playerID, minutes int) {
+		_, err := db.Exec("INSERT INTO player_stats (player_id, match_id, minutes_played) VALUES ($1, $2, $3)", playerID, matchID, minutes)
+		assert.NoError(t, err)
+	}
+
+	incident := func(matchID, playerID int, incidentType models.IncidentType) {
+		_, err := db.Exec(`
+			INSERT INTO match_incidents (match_id, player_id, type, minute)
+			VALUES ($1, $2, $3, 30)
+		`, matchID, playerID, incidentType)
+		assert.NoError(t, err)
+	}
+
+	// createLeague inserts a league with one user team, returning both
+	createLeague := func(name string) (int, int) {
+		now := time.Now()
+		var leagueID, userID, userTeamID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("SCORE%d", now.UnixNano()), name, now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, "Scoring", "Manager", fmt.Sprintf("scoring_%d@example.com", now.UnixNano()), "password", now, now).Scan(&userID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, userID, leagueID, name+" Team", now, now).Scan(&userTeamID)
+		assert.NoError(t, err)
+		return leagueID, userTeamID
+	}
+
+	t.Run("players are scored from every match in the gameweek", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, _ := createLeague("Scoring League")
+		gameweekID := createGameweek(1)
+		teamID, playerIDs := createPlayers(models.PositionDEF, models.PositionFWD, models.PositionMID)
+		first := createMatch(teamID, gameweekID)
+		second := createMatch(teamID, gameweekID)
+
+		// The defender plays twice, scoring in the first match and keeping a clean sheet in the second
+		played(first, playerIDs[0], 90)
+		incident(first, playerIDs[0], models.IncidentTypeGoal)
+		played(second, playerIDs[0], 70)
+		incident(second, playerIDs[0], models.IncidentTypeCleanSheet)
+		played(first, playerIDs[1], 90)
+		incident(first, playerIDs[1], models.IncidentTypeGoal)
+		incident(first, playerIDs[1], models.IncidentTypeYellowCard)
+		played(first, playerIDs[2], 0)
+
+		points, err := scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+		if assert.Len(t, points, 3) {
+			assert.Equal(t, leagueID, points[0].LeagueID)
+			assert.Equal(t, playerIDs[0], points[0].PlayerID)
+			// 14 plus the second best performance in the first match and the best in the second
+			assert.Equal(t, 19, points[0].Points)
+			assert.Equal(t, 5, points[0].Bonus)
+			assert.False(t, points[0].BonusProvisional)
+			assert.Equal(t, 160, points[0].MinutesPlayed)
+			assert.Equal(t, 1, points[0].Goals)
+			assert.Equal(t, 1, points[0].CleanSheets)
+			assert.Equal(t, 8, points[1].Points)
+			assert.Equal(t, 0, points[2].Points)
+		}
+
+		// Scoring again replaces what was stored
+		incident(second, playerIDs[2], models.IncidentTypeAssist)
+		_, err = scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+		stored, err := scoringService.ListGameweekPoints(leagueID, gameweekID)
+		assert.NoError(t, err)
+		if assert.Len(t, stored, 3) {
+			assert.Equal(t, playerIDs[2], stored[2].PlayerID)
+			assert.Equal(t, 3, stored[2].Points)
+		}
+
+		history, err := scoringService.ListPlayerPoints(leagueID, playerIDs[0])
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+	})
+
+	t.Run("team scores count the fielded lineup and captain", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, userTeamID := createLeague("Scoring League")
+		gameweekID := createGameweek(1)
+		teamID, playerIDs := createPlayers(models.PositionDEF, models.PositionFWD, models.PositionMID)
+		matchID := createMatch(teamID, gameweekID)
+		played(matchID, playerIDs[0], 90)
+		incident(matchID, playerIDs[0], models.IncidentTypeGoal)
+		played(matchID, playerIDs[1], 90)
+		played(matchID, playerIDs[2], 90)
+		_, err := scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+
+		now := time.Now()
+
+		// The defender captains two starters, with the midfielder on the bench
+		for i, slot := range []int{1, 2, 12} {
+			_, err := db.Exec(`
+				INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $3)
+			`, userTeamID, playerIDs[i], now)
+			assert.NoError(t, err)
+			_, err = db.Exec(`
+				INSERT INTO lineups (user_team_id, gameweek_id, player_id, slot, is_captain, is_vice_captain)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, userTeamID, gameweekID, playerIDs[i], slot, i == 0, i == 1)
+			assert.NoError(t, err)
+		}
+		_, err = db.Exec(`
+			INSERT INTO transfers (user_team_id, player_in_id, gameweek_id, cost, created_at)
+			VALUES ($1, $2, $3, 4, $4)
+		`, userTeamID, playerIDs[1], gameweekID, now)
+		assert.NoError(t, err)
+
+		score, err := scoringService.GetTeamScore(userTeamID, gameweekID)
+		assert.NoError(t, err)
+		assert.Len(t, score.Players, 2)
+		assert.Equal(t, 4, score.TransferCost)
+		// 11 with the bonus doubled for the captain, 4 for the forward tied for second, less the hit
+		assert.Equal(t, float64(22), score.Points)
+	})
+
+	t.Run("each league scores under its own rules until the season starts", func(t *testing.T) {
+		defer testDB.Clear()
+
+		fplLeagueID, _ := createLeague("Preset League")
+		customLeagueID, commissionerID := createLeague("Custom League")
+		_, outsiderID := createLeague("Other League")
+
+		rules, err := scoringService.GetRules(customLeagueID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ScoringPresetFPL, rules.Preset)
+		assert.False(t, rules.Locked)
+
+		// Goals are worth one point whoever scores them, with nothing for turning up
+		custom := &models.ScoringRules{
+			LeagueID: customLeagueID,
+			Incidents: map[models.Position]map[models.IncidentType]int{
+				models.PositionFWD: {models.IncidentTypeGoal: 1},
+			},
+		}
+		_, err = scoringService.UpdateRules(custom, outsiderID)
+		assert.ErrorIs(t, err, ErrNotCommissioner)
+		updated, err := scoringService.UpdateRules(custom, commissionerID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ScoringPresetCustom, updated.Preset)
+
+		_, err = scoringService.UpdateRules(&models.ScoringRules{LeagueID: customLeagueID, Preset: "fantasy"}, commissionerID)
+		assert.ErrorIs(t, err, ErrInvalidRules)
+
+		gameweekID := createGameweek(1)
+		teamID, playerIDs := createPlayers(models.PositionFWD)
+		matchID := createMatch(teamID, gameweekID)
+		played(matchID, playerIDs[0], 90)
+		incident(matchID, playerIDs[0], models.IncidentTypeGoal)
+		_, err = scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+
+		// The preset gives the only player in the match a bonus, while the custom rules have none
+		for leagueID, expected := range map[int]int{fplLeagueID: 9, customLeagueID: 1} {
+			points, err := scoringService.ListGameweekPoints(leagueID, gameweekID)
+			assert.NoError(t, err)
+			if assert.Len(t, points, 1) {
+				assert.Equal(t, expected, points[0].Points)
+			}
+		}
+
+		// The first gameweek's deadline has passed, so the season has started
+		rules, err = scoringService.GetRules(customLeagueID)
+		assert.NoError(t, err)
+		assert.True(t, rules.Locked)
+		assert.Equal(t, 1, rules.IncidentPoints(models.PositionFWD, models.IncidentTypeGoal))
+		_, err = scoringService.UpdateRules(&models.ScoringRules{LeagueID: customLeagueID, Preset: models.ScoringPresetUCL}, commissionerID)
+		assert.ErrorIs(t, err, ErrRulesLocked)
+	})
+
+	t.Run("bonus points are provisional until the match is completed", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, _ := createLeague("Bonus League")
+		gameweekID := createGameweek(1)
+		teamID, playerIDs := createPlayers(models.PositionGK, models.PositionDEF, models.PositionMID, models.PositionFWD)
+		matchID := createMatchWithStatus(teamID, gameweekID, models.MatchStatusInProgress)
+		for _, playerID := range playerIDs {
+			played(matchID, playerID, 90)
+		}
+		_, err := db.Exec("UPDATE player_stats SET saves = 3 WHERE player_id = $1", playerIDs[0])
+		assert.NoError(t, err)
+		incident(matchID, playerIDs[2], models.IncidentTypeAssist)
+		incident(matchID, playerIDs[3], models.IncidentTypeGoal)
+		incident(matchID, playerIDs[3], models.IncidentTypeYellowCard)
+
+		// The forward scores 27, the midfielder 15, the goalkeeper 12 from their saves and the defender 6
+		bonuses, err := scoringService.ListMatchBonus(leagueID, matchID)
+		assert.NoError(t, err)
+		if assert.Len(t, bonuses, 4) {
+			assert.Equal(t, playerIDs[3], bonuses[0].PlayerID)
+			assert.Equal(t, 27, bonuses[0].PerformanceScore)
+			assert.Equal(t, 3, bonuses[0].Bonus)
+			assert.True(t, bonuses[0].Provisional)
+			assert.Equal(t, playerIDs[0], bonuses[2].PlayerID)
+			assert.Equal(t, []int{2, 1, 0}, []int{bonuses[1].Bonus, bonuses[2].Bonus, bonuses[3].Bonus})
+		}
+
+		points, err := scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+		if assert.Len(t, points, 4) {
+			assert.True(t, points[0].BonusProvisional)
+		}
+
+		_, err = db.Exec("UPDATE matches SET status = $1 WHERE id = $2", models.MatchStatusCompleted, matchID)
+		assert.NoError(t, err)
+		_, err = scoringService.ScoreGameweek(gameweekID)
+		assert.NoError(t, err)
+		stored, err := scoringService.ListGameweekPoints(leagueID, gameweekID)
+		assert.NoError(t, err)
+		for _, p := range stored {
+			assert.False(t, p.BonusProvisional)
+		}
+
+		_, err = scoringService.ListMatchBonus(leagueID, matchID+1000)
+		assert.Error(t, err)
+	})
+
+	t.Run("live scores follow a match being played without storing anything", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, userTeamID := createLeague("Live League")
+		gameweekID := createGameweek(1)
+		teamID, playerIDs := createPlayers(models.PositionFWD, models.PositionMID)
+		matchID := createMatchWithStatus(teamID, gameweekID, models.MatchStatusInProgress)
+		now := time.Now()
+		for i, playerID := range playerIDs {
+			played(matchID, playerID, 45)
+			_, err := db.Exec(`
+				INSERT INTO lineups (user_team_id, gameweek_id, player_id, slot, is_captain, is_vice_captain)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, userTeamID, gameweekID, playerID, i+1, i == 0, i == 1)
+			assert.NoError(t, err)
+			_, err = db.Exec(`
+				INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $3)
+			`, userTeamID, playerID, now)
+			assert.NoError(t, err)
+		}
+
+		live, err := scoringService.GetLiveScores(leagueID, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, gameweekID, live.GameweekID)
+		assert.True(t, live.Provisional)
+		assert.Len(t, live.Matches, 1)
+		if assert.Len(t, live.Teams, 1) {
+			// Both on a point for appearing and level on performance, sharing the top bonus
+			assert.Equal(t, "Live League Team", live.Teams[0].Name)
+			assert.Equal(t, float64(12), live.Teams[0].Points)
+		}
+
+		incident(matchID, playerIDs[0], models.IncidentTypeGoal)
+		_, err = db.Exec("UPDATE matches SET status = $1 WHERE id = $2", models.MatchStatusCompleted, matchID)
+		assert.NoError(t, err)
+		live, err = scoringService.GetLiveScores(leagueID, gameweekID)
+		assert.NoError(t, err)
+		assert.False(t, live.Provisional)
+		// The captain's goal and top bonus doubled, with the second bonus for the midfielder
+		assert.Equal(t, float64(19), live.Teams[0].Points)
+
+		stored, err := scoringService.ListGameweekPoints(leagueID, gameweekID)
+		assert.NoError(t, err)
+		assert.Empty(t, stored)
+	})
+}
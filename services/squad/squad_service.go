@@ -0,0 +1,280 @@
+package squad
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Names of the squad rules a roster change can break
+const (
+	RuleSquadSize   = "squad_size"
+	RulePositionMax = "position_max"
+	RulePositionMin = "position_min"
+	RuleTeamMax     = "team_max"
+)
+
+// RuleViolation is returned when a roster change would break one of the league's squad rules
+type RuleViolation struct {
+	Rule     string          `json:"rule"`
+	Position models.Position `json:"position,omitempty"`
+	TeamID   int             `json:"team_id,omitempty"`
+	Limit    int             `json:"limit"`
+	Message  string          `json:"message"`
+}
+
+func (v *RuleViolation) Error() string {
+	return v.Message
+}
+
+// SquadService defines the interface for squad rule operations
+type SquadService interface {
+	GetRules(leagueID int) (*models.SquadRules, error)
+	UpdateRules(rules *models.SquadRules) (*models.SquadRules, error)
+	ValidateRoster(userTeamID int) error
+}
+
+// Implementation of the SquadService interface
+type squadServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewSquadService creates a new SquadService instance
+func NewSquadService(db *sqlx.DB) SquadService {
+	return &squadServiceImpl{db: db}
+}
+
+// DefaultRules returns the squad rules a league uses until they are changed
+func DefaultRules(leagueID int) *models.SquadRules {
+	return &models.SquadRules{
+		LeagueID:   leagueID,
+		SquadSize:  15,
+		MaxPerTeam: 3,
+		MinGK:      2,
+		MaxGK:      2,
+		MinDEF:     5,
+		MaxDEF:     5,
+		MinMID:     5,
+		MaxMID:     5,
+		MinFWD:     3,
+		MaxFWD:     3,
+	}
+}
+
+// LoadRules retrieves a league's squad rules, falling back to the defaults if none are stored
+func LoadRules(q sqlx.Queryer, leagueID int) (*models.SquadRules, error) {
+	rules := &models.SquadRules{}
+	err := sqlx.Get(q, rules, "SELECT * FROM league_squad_rules WHERE league_id = $1", leagueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultRules(leagueID), nil
+		}
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetRules retrieves the squad rules of a league
+func (s *squadServiceImpl) GetRules(leagueID int) (*models.SquadRules, error) {
+	return LoadRules(s.db, leagueID)
+}
+
+// UpdateRules stores the squad rules of a league
+func (s *squadServiceImpl) UpdateRules(rules *models.SquadRules) (*models.SquadRules, error) {
+	if err := ValidateRules(rules); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rules.UpdatedAt = now
+
+	err := s.db.QueryRow(`
+		INSERT INTO league_squad_rules (league_id, squad_size, max_per_team, min_gk, max_gk, min_def, max_def,
+			min_mid, max_mid, min_fwd, max_fwd, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+		ON CONFLICT (league_id) DO UPDATE
+		SET squad_size = EXCLUDED.squad_size,
+			max_per_team = EXCLUDED.max_per_team,
+			min_gk = EXCLUDED.min_gk,
+			max_gk = EXCLUDED.max_gk,
+			min_def = EXCLUDED.min_def,
+			max_def = EXCLUDED.max_def,
+			min_mid = EXCLUDED.min_mid,
+			max_mid = EXCLUDED.max_mid,
+			min_fwd = EXCLUDED.min_fwd,
+			max_fwd = EXCLUDED.max_fwd,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`, rules.LeagueID, rules.SquadSize, rules.MaxPerTeam, rules.MinGK, rules.MaxGK, rules.MinDEF, rules.MaxDEF,
+		rules.MinMID, rules.MaxMID, rules.MinFWD, rules.MaxFWD, now).Scan(&rules.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error updating squad rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// ValidateRules checks that a set of squad rules can be satisfied
+func ValidateRules(rules *models.SquadRules) error {
+	if rules.SquadSize <= 0 {
+		return fmt.Errorf("squad size must be greater than zero")
+	}
+	if rules.MaxPerTeam <= 0 {
+		return fmt.Errorf("max players per team must be greater than zero")
+	}
+
+	minTotal, maxTotal := 0, 0
+	for _, position := range []models.Position{models.PositionGK, models.PositionDEF, models.PositionMID, models.PositionFWD} {
+		min, max := rules.PositionLimits(position)
+		if min < 0 || max < min {
+			return fmt.Errorf("invalid limits for %s: min %d, max %d", position, min, max)
+		}
+		minTotal += min
+		maxTotal += max
+	}
+	if minTotal > rules.SquadSize {
+		return fmt.Errorf("position minimums add up to more than the squad size")
+	}
+	if maxTotal < rules.SquadSize {
+		return fmt.Errorf("position maximums add up to less than the squad size")
+	}
+	return nil
+}
+
+// ValidateRoster checks a user team's current roster against its league's squad rules
+func (s *squadServiceImpl) ValidateRoster(userTeamID int) error {
+	return ValidateRosterChange(s.db, userTeamID, nil, nil)
+}
+
+// ValidateRosterChange checks that a user team's roster would still meet its league's squad
+// rules after adding and dropping the given players. It returns a *RuleViolation naming the
+// first rule that would be broken.
+func ValidateRosterChange(q sqlx.Queryer, userTeamID int, add []int, drop []int) error {
+	rules, roster, err := LoadSquad(q, userTeamID)
+	if err != nil {
+		return err
+	}
+
+	dropped := make(map[int]bool, len(drop))
+	for _, id := range drop {
+		dropped[id] = true
+	}
+	players := make([]*models.Player, 0, len(roster)+len(add))
+	for _, player := range roster {
+		if !dropped[player.ID] {
+			players = append(players, player)
+		}
+	}
+
+	if len(add) > 0 {
+		var added []*models.Player
+		query, args, err := sqlx.In("SELECT * FROM players WHERE id IN (?)", add)
+		if err != nil {
+			return err
+		}
+		if err := sqlx.Select(q, &added, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+			return err
+		}
+		if len(added) != len(add) {
+			return fmt.Errorf("one or more players to add were not found")
+		}
+		players = append(players, added...)
+	}
+
+	if violation := CheckSquad(rules, players); violation != nil {
+		return violation
+	}
+	return nil
+}
+
+// LoadSquad retrieves a user team's current roster along with its league's squad rules
+func LoadSquad(q sqlx.Queryer, userTeamID int) (*models.SquadRules, []*models.Player, error) {
+	var leagueID int
+	if err := q.QueryRowx("SELECT league_id FROM user_teams WHERE id = $1", userTeamID).Scan(&leagueID); err != nil {
+		return nil, nil, err
+	}
+
+	rules, err := LoadRules(q, leagueID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roster []*models.Player
+	err = sqlx.Select(q, &roster, `
+		SELECT p.*
+		FROM players p
+		JOIN user_team_players utp ON utp.player_id = p.id
+		WHERE utp.user_team_id = $1
+		ORDER BY p.id
+	`, userTeamID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rules, roster, nil
+}
+
+// CheckSquad checks a squad against a set of squad rules. A squad still being filled passes
+// the position minimums as long as it has enough empty slots left to reach them.
+func CheckSquad(rules *models.SquadRules, players []*models.Player) *RuleViolation {
+	if len(players) > rules.SquadSize {
+		return &RuleViolation{
+			Rule:    RuleSquadSize,
+			Limit:   rules.SquadSize,
+			Message: fmt.Sprintf("squad cannot have more than %d players", rules.SquadSize),
+		}
+	}
+
+	positionCounts := make(map[models.Position]int)
+	teamCounts := make(map[int]int)
+	for _, player := range players {
+		positionCounts[player.Position]++
+		teamCounts[player.TeamID]++
+	}
+
+	positions := []models.Position{models.PositionGK, models.PositionDEF, models.PositionMID, models.PositionFWD}
+	for _, position := range positions {
+		_, max := rules.PositionLimits(position)
+		if positionCounts[position] > max {
+			return &RuleViolation{
+				Rule:     RulePositionMax,
+				Position: position,
+				Limit:    max,
+				Message:  fmt.Sprintf("squad cannot have more than %d %s players", max, position),
+			}
+		}
+	}
+
+	for _, player := range players {
+		if teamCounts[player.TeamID] > rules.MaxPerTeam {
+			return &RuleViolation{
+				Rule:    RuleTeamMax,
+				TeamID:  player.TeamID,
+				Limit:   rules.MaxPerTeam,
+				Message: fmt.Sprintf("squad cannot have more than %d players from team %d", rules.MaxPerTeam, player.TeamID),
+			}
+		}
+	}
+
+	openSlots := rules.SquadSize - len(players)
+	for _, position := range positions {
+		min, _ := rules.PositionLimits(position)
+		if short := min - positionCounts[position]; short > 0 {
+			openSlots -= short
+			if openSlots < 0 {
+				return &RuleViolation{
+					Rule:     RulePositionMin,
+					Position: position,
+					Limit:    min,
+					Message:  fmt.Sprintf("squad would not have room for at least %d %s players", min, position),
+				}
+			}
+		}
+	}
+
+	return nil
+}
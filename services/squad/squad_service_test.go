@@ -0,0 +1,224 @@
+package squad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB       *database.TestDB
+	squadService SquadService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	squadService = NewSquadService(testDB.GetDB())
+	m.Run()
+}
+
+// squadOf builds a squad with the given number of players at each position, spread across teams
+func squadOf(gk, def, mid, fwd int) []*models.Player {
+	var players []*models.Player
+	add := func(position models.Position, count int) {
+		for i := 0; i < count; i++ {
+			players = append(players, &models.Player{
+				ID:       len(players) + 1,
+				TeamID:   len(players) + 1,
+				Position: position,
+			})
+		}
+	}
+	add(models.PositionGK, gk)
+	add(models.PositionDEF, def)
+	add(models.PositionMID, mid)
+	add(models.PositionFWD, fwd)
+	return players
+}
+
+func TestCheckSquad(t *testing.T) {
+	rules := DefaultRules(1)
+
+	t.Run("full valid squad", func(t *testing.T) {
+		assert.Nil(t, CheckSquad(rules, squadOf(2, 5, 5, 3)))
+	})
+
+	t.Run("partial squad with room to fill", func(t *testing.T) {
+		assert.Nil(t, CheckSquad(rules, squadOf(0, 5, 2, 0)))
+	})
+
+	t.Run("too many players", func(t *testing.T) {
+		violation := CheckSquad(rules, squadOf(2, 5, 5, 4))
+		if assert.NotNil(t, violation) {
+			assert.Equal(t, RuleSquadSize, violation.Rule)
+			assert.Equal(t, 15, violation.Limit)
+		}
+	})
+
+	t.Run("too many at a position", func(t *testing.T) {
+		violation := CheckSquad(rules, squadOf(3, 0, 0, 0))
+		if assert.NotNil(t, violation) {
+			assert.Equal(t, RulePositionMax, violation.Rule)
+			assert.Equal(t, models.PositionGK, violation.Position)
+			assert.Equal(t, 2, violation.Limit)
+		}
+	})
+
+	t.Run("too many from one team", func(t *testing.T) {
+		players := squadOf(0, 4, 0, 0)
+		for _, player := range players {
+			player.TeamID = 7
+		}
+		violation := CheckSquad(rules, players)
+		if assert.NotNil(t, violation) {
+			assert.Equal(t, RuleTeamMax, violation.Rule)
+			assert.Equal(t, 7, violation.TeamID)
+		}
+	})
+
+	t.Run("no room left for a position minimum", func(t *testing.T) {
+		relaxed := *rules
+		relaxed.MaxDEF = 8
+		violation := CheckSquad(&relaxed, squadOf(2, 8, 3, 0))
+		if assert.NotNil(t, violation) {
+			assert.Equal(t, RulePositionMin, violation.Rule)
+		}
+	})
+}
+
+func TestValidateRules(t *testing.T) {
+	assert.NoError(t, ValidateRules(DefaultRules(1)))
+
+	rules := DefaultRules(1)
+	rules.SquadSize = 0
+	assert.Error(t, ValidateRules(rules))
+
+	rules = DefaultRules(1)
+	rules.MinGK = 3
+	assert.Error(t, ValidateRules(rules))
+
+	rules = DefaultRules(1)
+	rules.SquadSize = 20
+	assert.Error(t, ValidateRules(rules))
+}
+
+func TestSquadService(t *testing.T) {
+	db := testDB.GetDB()
+
+	// createUserTeam inserts a league with one user team
+	createUserTeam := func() (int, int) {
+		now := time.Now()
+		var leagueID, userID, userTeamID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("SQUAD%d", now.UnixNano()), "Squad League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, "Squad", "Manager", fmt.Sprintf("squad_%d@example.com", now.UnixNano()), "password", now, now).Scan(&userID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, userID, leagueID, "Squad Team", now, now).Scan(&userTeamID)
+		assert.NoError(t, err)
+		return leagueID, userTeamID
+	}
+
+	// createPlayers inserts players at a position on a single team
+	createPlayers := func(position models.Position, count int) []int {
+		now := time.Now()
+		var teamID int
+		err := db.QueryRow(`
+			INSERT INTO teams (name, external_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, "Squad Team", now.UnixNano()%100000, now, now).Scan(&teamID)
+		assert.NoError(t, err)
+
+		ids := make([]int, count)
+		for i := range ids {
+			err := db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", i), position, now, now).Scan(&ids[i])
+			assert.NoError(t, err)
+		}
+		return ids
+	}
+
+	t.Run("GetRules falls back to the defaults", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, _ := createUserTeam()
+		rules, err := squadService.GetRules(leagueID)
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultRules(leagueID), rules)
+	})
+
+	t.Run("UpdateRules", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, _ := createUserTeam()
+		rules := DefaultRules(leagueID)
+		rules.MaxPerTeam = 2
+
+		_, err := squadService.UpdateRules(rules)
+		assert.NoError(t, err)
+
+		stored, err := squadService.GetRules(leagueID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, stored.MaxPerTeam)
+
+		rules.MinFWD = 4
+		_, err = squadService.UpdateRules(rules)
+		assert.Error(t, err)
+	})
+
+	t.Run("ValidateRosterChange", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, userTeamID := createUserTeam()
+		playerIDs := createPlayers(models.PositionFWD, 4)
+
+		now := time.Now()
+		for _, playerID := range playerIDs[:3] {
+			_, err := db.Exec(`
+				INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $4)
+			`, userTeamID, playerID, now, now)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, squadService.ValidateRoster(userTeamID))
+
+		err := ValidateRosterChange(db, userTeamID, []int{playerIDs[3]}, nil)
+		violation, ok := err.(*RuleViolation)
+		if assert.True(t, ok) {
+			assert.Equal(t, RulePositionMax, violation.Rule)
+		}
+
+		// Swapping one forward for another keeps the squad valid
+		assert.NoError(t, ValidateRosterChange(db, userTeamID, []int{playerIDs[3]}, []int{playerIDs[0]}))
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"go-app/external"
 	"go-app/models"
 	"go-app/services/team"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // TeamSyncService handles synchronizing teams from external sources
@@ -25,6 +27,23 @@ func NewTeamSyncService(teamService team.TeamService, apiFootballClient external
 	}
 }
 
+// TeamsByExternalID maps the API-Football IDs of synced teams to their IDs, for syncs that match
+// what API-Football reports to the teams it is about
+func TeamsByExternalID(q sqlx.Queryer) (map[int]int, error) {
+	var rows []struct {
+		ID         int `db:"id"`
+		ExternalID int `db:"external_id"`
+	}
+	if err := sqlx.Select(q, &rows, "SELECT id, external_id FROM teams WHERE external_id IS NOT NULL"); err != nil {
+		return nil, err
+	}
+	teamIDs := make(map[int]int, len(rows))
+	for _, row := range rows {
+		teamIDs[row.ExternalID] = row.ID
+	}
+	return teamIDs, nil
+}
+
 // SyncTeamsFromExternalAPI fetches teams from the API-Football service and saves them to the database
 func (s *TeamSyncService) SyncTeamsFromExternalAPI() error {
 	log.Println("Starting team sync from API-Football")
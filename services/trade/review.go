@@ -0,0 +1,193 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/league"
+	"go-app/services/squad"
+	"go-app/services/waiver"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// VetoTrade vetoes an accepted trade in review on behalf of a user team. Under commissioner
+// review the commissioner's veto stops the trade straight away. Under a league vote it counts as
+// the team's vote against the trade, which is vetoed once enough of the other managers agree.
+func (s *tradeServiceImpl) VetoTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settings, err := waiver.LockDraftLeague(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	trade, err := lockTrade(tx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if trade.LeagueID != settings.LeagueID {
+		return nil, fmt.Errorf("%w: the team is not in the trade's league", ErrNotTradeParty)
+	}
+
+	now := time.Now()
+	if trade.Status != models.TradeStatusInReview {
+		return nil, fmt.Errorf("%w: the trade is %s", ErrTradeNotInReview, trade.Status)
+	}
+	if trade.ReviewEndsAt == nil || !trade.ReviewEndsAt.After(now) {
+		return nil, fmt.Errorf("%w: the review period has closed", ErrTradeNotInReview)
+	}
+
+	switch settings.TradeReview {
+	case models.TradeReviewCommissioner:
+		if settings.CommissionerTeamID == nil || *settings.CommissionerTeamID != userTeamID {
+			return nil, fmt.Errorf("%w: only the commissioner can veto trades", ErrNotTradeParty)
+		}
+		for _, party := range []int{trade.ProposerTeamID, trade.RecipientTeamID} {
+			party := party
+			err := audit.Record(tx, &models.AuditEntry{
+				LeagueID:        trade.LeagueID,
+				UserTeamID:      &party,
+				ActorUserTeamID: &userTeamID,
+				Action:          models.AuditActionCommissionerOverride,
+				TradeID:         &trade.ID,
+				CreatedAt:       now,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case models.TradeReviewLeagueVote:
+		if userTeamID == trade.ProposerTeamID || userTeamID == trade.RecipientTeamID {
+			return nil, fmt.Errorf("%w: teams cannot vote on their own trade", ErrNotTradeParty)
+		}
+		_, err := tx.Exec(`
+			INSERT INTO trade_votes (trade_id, user_team_id, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (trade_id, user_team_id) DO NOTHING
+		`, trade.ID, userTeamID, now)
+		if err != nil {
+			return nil, fmt.Errorf("error recording veto vote: %w", err)
+		}
+		if err := tx.QueryRow("SELECT COUNT(*) FROM trade_votes WHERE trade_id = $1", trade.ID).Scan(&trade.VetoVotes); err != nil {
+			return nil, err
+		}
+		if trade.VetoVotes < settings.TradeVetoVotes {
+			if err := tx.Commit(); err != nil {
+				return nil, fmt.Errorf("error committing veto vote: %w", err)
+			}
+			return trade, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: the league does not veto trades", ErrNotTradeParty)
+	}
+
+	if err := updateStatus(tx, trade, models.TradeStatusVetoed, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing veto: %w", err)
+	}
+
+	return trade, nil
+}
+
+// ProcessTrades makes the accepted trades in a league whose review period has closed
+func (s *tradeServiceImpl) ProcessTrades(leagueID int) ([]*models.Trade, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	processed, err := ProcessDueTrades(tx, leagueID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing trades: %w", err)
+	}
+
+	return processed, nil
+}
+
+// LeaguesWithDueTrades returns the leagues that have accepted trades whose review period has closed
+func LeaguesWithDueTrades(q sqlx.Queryer, now time.Time) ([]int, error) {
+	var leagueIDs []int
+	err := sqlx.Select(q, &leagueIDs, `
+		SELECT DISTINCT league_id
+		FROM trades
+		WHERE status = $1 AND review_ends_at <= $2
+		ORDER BY league_id
+	`, models.TradeStatusInReview, now)
+	if err != nil {
+		return nil, err
+	}
+	return leagueIDs, nil
+}
+
+// ProcessDueTrades makes every accepted trade in a league whose review period has closed without a
+// veto, in the order they were accepted. A trade that can no longer be made, because an earlier
+// one moved its players or a team would now break a squad rule, is marked invalid instead.
+func ProcessDueTrades(tx *sqlx.Tx, leagueID int, now time.Time) ([]*models.Trade, error) {
+	// Lock the league so no other roster move can land while the trades are made
+	if _, err := tx.Exec("SELECT id FROM leagues WHERE id = $1 FOR UPDATE", leagueID); err != nil {
+		return nil, fmt.Errorf("error locking league: %w", err)
+	}
+
+	var tradeIDs []int
+	err := tx.Select(&tradeIDs, `
+		SELECT id FROM trades
+		WHERE league_id = $1 AND status = $2 AND review_ends_at <= $3
+		ORDER BY review_ends_at, id
+	`, leagueID, models.TradeStatusInReview, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(tradeIDs) == 0 {
+		return nil, nil
+	}
+
+	settings, err := league.LoadSettings(tx, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading league settings: %w", err)
+	}
+
+	var processed []*models.Trade
+	for _, tradeID := range tradeIDs {
+		trade, err := lockTrade(tx, tradeID)
+		if err != nil {
+			return nil, err
+		}
+		if trade.Status != models.TradeStatusInReview {
+			// An earlier trade moved one of its players
+			processed = append(processed, trade)
+			continue
+		}
+
+		if err := updateStatus(tx, trade, models.TradeStatusAccepted, now); err != nil {
+			return nil, err
+		}
+		if err := executeTrade(tx, settings, trade, nil, now); err != nil {
+			var violation *squad.RuleViolation
+			if !errors.As(err, &violation) && !errors.Is(err, ErrInvalidTrade) {
+				return nil, err
+			}
+			if err := updateStatus(tx, trade, models.TradeStatusInvalid, now); err != nil {
+				return nil, err
+			}
+		}
+		processed = append(processed, trade)
+	}
+	return processed, nil
+}
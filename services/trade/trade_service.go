@@ -0,0 +1,641 @@
+package trade
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/lineup"
+	"go-app/services/squad"
+	"go-app/services/waiver"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultExpiry is how long a trade proposal stays open when it is not given a deadline
+const DefaultExpiry = 48 * time.Hour
+
+var (
+	// ErrNotTradeParty is returned when a user team acts on a trade it is not allowed to
+	ErrNotTradeParty = errors.New("user team cannot act on this trade")
+	// ErrTradeNotPending is returned when a trade that has already been settled is responded to
+	ErrTradeNotPending = errors.New("trade is no longer pending")
+	// ErrTradeNotInReview is returned when a trade is vetoed outside of its review period
+	ErrTradeNotInReview = errors.New("trade is not in review")
+	// ErrInvalidTrade is returned when the items in a trade do not make sense for the teams trading them
+	ErrInvalidTrade = errors.New("invalid trade")
+)
+
+// TradeService defines the interface for trades between user teams in draft leagues
+type TradeService interface {
+	ProposeTrade(trade *models.Trade) (*models.Trade, error)
+	GetTrade(id int) (*models.Trade, error)
+	ListTrades(userTeamID int) ([]*models.Trade, error)
+	AcceptTrade(tradeID, userTeamID int) (*models.Trade, error)
+	RejectTrade(tradeID, userTeamID int) (*models.Trade, error)
+	CounterTrade(tradeID int, counter *models.Trade) (*models.Trade, error)
+	CancelTrade(tradeID, userTeamID int) (*models.Trade, error)
+	VetoTrade(tradeID, userTeamID int) (*models.Trade, error)
+	ProcessTrades(leagueID int) ([]*models.Trade, error)
+}
+
+// Implementation of the TradeService interface
+type tradeServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewTradeService creates a new TradeService instance
+func NewTradeService(db *sqlx.DB) TradeService {
+	return &tradeServiceImpl{db: db}
+}
+
+// ExpireTrades marks every pending trade whose deadline has passed as expired
+func ExpireTrades(e sqlx.Execer, now time.Time) error {
+	_, err := e.Exec(`
+		UPDATE trades
+		SET status = $1, updated_at = $2
+		WHERE status = $3 AND expires_at <= $2
+	`, models.TradeStatusExpired, now, models.TradeStatusPending)
+	if err != nil {
+		return fmt.Errorf("error expiring trades: %w", err)
+	}
+	return nil
+}
+
+// ProposeTrade offers a trade from the proposing user team to the recipient. The trade stays
+// open for the recipient to respond until it expires, DefaultExpiry from now unless given.
+func (s *tradeServiceImpl) ProposeTrade(trade *models.Trade) (*models.Trade, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settings, err := waiver.LockDraftLeague(tx, trade.ProposerTeamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := proposeTrade(tx, settings, trade, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing trade: %w", err)
+	}
+
+	return trade, nil
+}
+
+// proposeTrade validates and inserts a trade proposal in a league locked by the caller
+func proposeTrade(tx *sqlx.Tx, settings *models.LeagueSettings, trade *models.Trade, now time.Time) error {
+	trade.LeagueID = settings.LeagueID
+	trade.Status = models.TradeStatusPending
+	if trade.ExpiresAt.IsZero() {
+		trade.ExpiresAt = now.Add(DefaultExpiry)
+	}
+	if !trade.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: the trade has to expire in the future", ErrInvalidTrade)
+	}
+	trade.RespondedAt = nil
+	trade.CreatedAt = now
+	trade.UpdatedAt = now
+
+	if err := validateTrade(tx, settings, trade); err != nil {
+		return err
+	}
+
+	err := tx.QueryRow(`
+		INSERT INTO trades (league_id, proposer_team_id, recipient_team_id, status, counter_of_id, message, expires_at,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, trade.LeagueID, trade.ProposerTeamID, trade.RecipientTeamID, trade.Status, trade.CounterOfID, trade.Message,
+		trade.ExpiresAt, trade.CreatedAt, trade.UpdatedAt).Scan(&trade.ID)
+	if err != nil {
+		return fmt.Errorf("error creating trade: %w", err)
+	}
+
+	for _, item := range trade.Items {
+		item.TradeID = trade.ID
+		err := tx.QueryRow(`
+			INSERT INTO trade_items (trade_id, from_user_team_id, type, player_id, draft_round, pick_original_user_team_id, amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, item.TradeID, item.FromTeamID, item.Type, item.PlayerID, item.DraftRound, item.PickOriginalTeamID,
+			item.Amount).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("error adding trade item: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateTrade checks that everything in a trade belongs to the team giving it up and that both
+// teams would still meet the league's squad rules once the trade is made
+func validateTrade(q sqlx.Queryer, settings *models.LeagueSettings, trade *models.Trade) error {
+	if trade.ProposerTeamID == trade.RecipientTeamID {
+		return fmt.Errorf("%w: a team cannot trade with itself", ErrInvalidTrade)
+	}
+	if err := checkInLeague(q, settings.LeagueID, trade.RecipientTeamID); err != nil {
+		return err
+	}
+	if len(trade.Items) == 0 {
+		return fmt.Errorf("%w: the trade has nothing in it", ErrInvalidTrade)
+	}
+
+	players := make(map[int]bool)
+	picks := make(map[[2]int]bool)
+	faab := make(map[int]bool)
+	add := make(map[int][]int)
+	drop := make(map[int][]int)
+	for _, item := range trade.Items {
+		to := trade.RecipientTeamID
+		switch item.FromTeamID {
+		case trade.ProposerTeamID:
+		case trade.RecipientTeamID:
+			to = trade.ProposerTeamID
+		default:
+			return fmt.Errorf("%w: items can only come from the two teams trading", ErrInvalidTrade)
+		}
+
+		switch item.Type {
+		case models.TradeItemPlayer:
+			if item.PlayerID == nil {
+				return fmt.Errorf("%w: a player item needs a player", ErrInvalidTrade)
+			}
+			if players[*item.PlayerID] {
+				return fmt.Errorf("%w: player %d is in the trade more than once", ErrInvalidTrade, *item.PlayerID)
+			}
+			players[*item.PlayerID] = true
+
+			owner, err := waiver.OwnerOf(q, settings.LeagueID, *item.PlayerID)
+			if err != nil {
+				return err
+			}
+			if owner != item.FromTeamID {
+				return fmt.Errorf("%w: player %d is not on the team's roster", ErrInvalidTrade, *item.PlayerID)
+			}
+			drop[item.FromTeamID] = append(drop[item.FromTeamID], *item.PlayerID)
+			add[to] = append(add[to], *item.PlayerID)
+
+		case models.TradeItemDraftPick:
+			if item.DraftRound == nil || *item.DraftRound < 1 || item.PickOriginalTeamID == nil {
+				return fmt.Errorf("%w: a draft pick needs a round and the team it was dealt to", ErrInvalidTrade)
+			}
+			key := [2]int{*item.DraftRound, *item.PickOriginalTeamID}
+			if picks[key] {
+				return fmt.Errorf("%w: a draft pick is in the trade more than once", ErrInvalidTrade)
+			}
+			picks[key] = true
+
+			if err := checkInLeague(q, settings.LeagueID, *item.PickOriginalTeamID); err != nil {
+				return err
+			}
+			owner, err := PickOwner(q, settings.LeagueID, *item.DraftRound, *item.PickOriginalTeamID)
+			if err != nil {
+				return err
+			}
+			if owner != item.FromTeamID {
+				return fmt.Errorf("%w: the team does not own that round %d pick", ErrInvalidTrade, *item.DraftRound)
+			}
+
+		case models.TradeItemFAAB:
+			if settings.WaiverMode != models.WaiverModeFAAB {
+				return fmt.Errorf("%w: FAAB is only traded in FAAB leagues", ErrInvalidTrade)
+			}
+			if item.Amount <= 0 {
+				return fmt.Errorf("%w: FAAB traded has to be more than zero", ErrInvalidTrade)
+			}
+			if faab[item.FromTeamID] {
+				return fmt.Errorf("%w: each team can only give FAAB once in a trade", ErrInvalidTrade)
+			}
+			faab[item.FromTeamID] = true
+
+			balance, err := waiver.FAABBalance(q, item.FromTeamID, settings.FAABBudget)
+			if err != nil {
+				return err
+			}
+			if item.Amount > balance {
+				return fmt.Errorf("%w: %d FAAB is more than the %d the team has left", ErrInvalidTrade, item.Amount, balance)
+			}
+
+		default:
+			return fmt.Errorf("%w: unknown item type %q", ErrInvalidTrade, item.Type)
+		}
+	}
+
+	for _, userTeamID := range []int{trade.ProposerTeamID, trade.RecipientTeamID} {
+		if len(add[userTeamID]) == 0 && len(drop[userTeamID]) == 0 {
+			continue
+		}
+		if err := squad.ValidateRosterChange(q, userTeamID, add[userTeamID], drop[userTeamID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkInLeague ensures a user team plays in a league
+func checkInLeague(q sqlx.Queryer, leagueID, userTeamID int) error {
+	var count int
+	err := q.QueryRowx("SELECT COUNT(*) FROM user_teams WHERE id = $1 AND league_id = $2", userTeamID, leagueID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: user team %d is not in the league", ErrInvalidTrade, userTeamID)
+	}
+	return nil
+}
+
+// PickOwner returns the user team that owns a pick in a league's next draft, which is the team
+// it was dealt to unless it has been traded
+func PickOwner(q sqlx.Queryer, leagueID, round, originalUserTeamID int) (int, error) {
+	var owner int
+	err := q.QueryRowx(`
+		SELECT owner_user_team_id
+		FROM draft_pick_owners
+		WHERE league_id = $1 AND round = $2 AND original_user_team_id = $3 AND draft_id IS NULL
+	`, leagueID, round, originalUserTeamID).Scan(&owner)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return originalUserTeamID, nil
+		}
+		return 0, err
+	}
+	return owner, nil
+}
+
+// GetTrade retrieves a trade with its items
+func (s *tradeServiceImpl) GetTrade(id int) (*models.Trade, error) {
+	if err := ExpireTrades(s.db, time.Now()); err != nil {
+		return nil, err
+	}
+
+	trade := &models.Trade{}
+	if err := s.db.Get(trade, "SELECT * FROM trades WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+	if err := loadDetails(s.db, trade); err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
+
+// ListTrades retrieves the trades a user team has proposed or been offered, latest first
+func (s *tradeServiceImpl) ListTrades(userTeamID int) ([]*models.Trade, error) {
+	if err := ExpireTrades(s.db, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var trades []*models.Trade
+	err := s.db.Select(&trades, `
+		SELECT * FROM trades
+		WHERE proposer_team_id = $1 OR recipient_team_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	for _, trade := range trades {
+		if err := loadDetails(s.db, trade); err != nil {
+			return nil, err
+		}
+	}
+	return trades, nil
+}
+
+// loadDetails fills in the items of a trade and how many votes there are to veto it
+func loadDetails(q sqlx.Queryer, trade *models.Trade) error {
+	trade.Items = []*models.TradeItem{}
+	if err := sqlx.Select(q, &trade.Items, "SELECT * FROM trade_items WHERE trade_id = $1 ORDER BY id", trade.ID); err != nil {
+		return err
+	}
+	return q.QueryRowx("SELECT COUNT(*) FROM trade_votes WHERE trade_id = $1", trade.ID).Scan(&trade.VetoVotes)
+}
+
+// lockTrade locks a trade for the rest of the transaction and loads its details
+func lockTrade(tx *sqlx.Tx, tradeID int) (*models.Trade, error) {
+	trade := &models.Trade{}
+	if err := tx.Get(trade, "SELECT * FROM trades WHERE id = $1 FOR UPDATE", tradeID); err != nil {
+		return nil, err
+	}
+	if err := loadDetails(tx, trade); err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
+
+// settleTrade moves a pending trade on to the given status
+func settleTrade(tx *sqlx.Tx, trade *models.Trade, status models.TradeStatus, now time.Time) error {
+	if trade.Status != models.TradeStatusPending {
+		return fmt.Errorf("%w: the trade is %s", ErrTradeNotPending, trade.Status)
+	}
+	trade.RespondedAt = &now
+	return updateStatus(tx, trade, status, now)
+}
+
+// updateStatus stores a trade's new status along with when it was responded to and reviewed
+func updateStatus(tx *sqlx.Tx, trade *models.Trade, status models.TradeStatus, now time.Time) error {
+	trade.Status = status
+	trade.UpdatedAt = now
+	_, err := tx.Exec(`
+		UPDATE trades
+		SET status = $1, responded_at = $2, review_ends_at = $3, updated_at = $4
+		WHERE id = $5
+	`, trade.Status, trade.RespondedAt, trade.ReviewEndsAt, trade.UpdatedAt, trade.ID)
+	if err != nil {
+		return fmt.Errorf("error updating trade: %w", err)
+	}
+	return nil
+}
+
+// AcceptTrade accepts a trade offered to a user team. Unless the league reviews trades it is made
+// straight away, swapping the players, picks and FAAB in it in one go, otherwise it is made when
+// the review period closes without a veto. Either way the trade is checked against both teams'
+// rosters again first, as they may have changed since it was proposed.
+func (s *tradeServiceImpl) AcceptTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settings, err := waiver.LockDraftLeague(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := ExpireTrades(tx, now); err != nil {
+		return nil, err
+	}
+
+	trade, err := lockTrade(tx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if trade.RecipientTeamID != userTeamID {
+		return nil, fmt.Errorf("%w: only the team offered a trade can accept it", ErrNotTradeParty)
+	}
+	if settings.TradeReview == models.TradeReviewNone {
+		if err := settleTrade(tx, trade, models.TradeStatusAccepted, now); err != nil {
+			return nil, err
+		}
+		if err := executeTrade(tx, settings, trade, &userTeamID, now); err != nil {
+			return nil, err
+		}
+	} else {
+		if trade.Status != models.TradeStatusPending {
+			return nil, fmt.Errorf("%w: the trade is %s", ErrTradeNotPending, trade.Status)
+		}
+		if err := validateTrade(tx, settings, trade); err != nil {
+			return nil, err
+		}
+		reviewEndsAt := now.Add(time.Duration(settings.TradeReviewHours) * time.Hour)
+		trade.ReviewEndsAt = &reviewEndsAt
+		if err := settleTrade(tx, trade, models.TradeStatusInReview, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing trade: %w", err)
+	}
+
+	return trade, nil
+}
+
+// executeTrade swaps everything in a trade between its two teams and records it in the league's
+// transaction history and audit log, with the team that made it happen as the actor, if any.
+// Other pending trades offering the players that moved are no longer valid.
+func executeTrade(tx *sqlx.Tx, settings *models.LeagueSettings, trade *models.Trade, actor *int, now time.Time) error {
+	if err := validateTrade(tx, settings, trade); err != nil {
+		return err
+	}
+
+	before := make(map[int][]int64)
+	for _, userTeamID := range []int{trade.ProposerTeamID, trade.RecipientTeamID} {
+		// Lineups of gameweeks already under way are kept as they were at the deadline
+		if err := lineup.LockDueLineups(tx, userTeamID, now); err != nil {
+			return err
+		}
+		roster, err := audit.RosterOf(tx, userTeamID)
+		if err != nil {
+			return err
+		}
+		before[userTeamID] = roster
+	}
+
+	playersIn := make(map[int][]int)
+	playersOut := make(map[int][]int)
+	spent := make(map[int]int)
+	for _, item := range trade.Items {
+		to := trade.RecipientTeamID
+		if item.FromTeamID == trade.RecipientTeamID {
+			to = trade.ProposerTeamID
+		}
+
+		switch item.Type {
+		case models.TradeItemPlayer:
+			_, err := tx.Exec(`
+				UPDATE user_team_players
+				SET user_team_id = $1, updated_at = $2
+				WHERE user_team_id = $3 AND player_id = $4
+			`, to, now, item.FromTeamID, *item.PlayerID)
+			if err != nil {
+				return fmt.Errorf("error moving player: %w", err)
+			}
+			playersOut[item.FromTeamID] = append(playersOut[item.FromTeamID], *item.PlayerID)
+			playersIn[to] = append(playersIn[to], *item.PlayerID)
+
+		case models.TradeItemDraftPick:
+			if err := setPickOwner(tx, settings.LeagueID, *item.DraftRound, *item.PickOriginalTeamID, to); err != nil {
+				return err
+			}
+
+		case models.TradeItemFAAB:
+			spent[item.FromTeamID] += item.Amount
+			spent[to] -= item.Amount
+		}
+	}
+
+	for _, userTeamID := range []int{trade.ProposerTeamID, trade.RecipientTeamID} {
+		if err := waiver.InvalidateTrades(tx, userTeamID, playersOut[userTeamID], now); err != nil {
+			return err
+		}
+		if err := recordTrade(tx, trade, userTeamID, playersIn[userTeamID], playersOut[userTeamID], spent[userTeamID], now); err != nil {
+			return err
+		}
+		err := audit.Record(tx, &models.AuditEntry{
+			LeagueID:        trade.LeagueID,
+			UserTeamID:      &userTeamID,
+			ActorUserTeamID: actor,
+			Action:          models.AuditActionTrade,
+			PlayersIn:       audit.PlayerIDs(playersIn[userTeamID]...),
+			PlayersOut:      audit.PlayerIDs(playersOut[userTeamID]...),
+			TradeID:         &trade.ID,
+			RosterBefore:    before[userTeamID],
+			CreatedAt:       now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPickOwner hands a pick in a league's next draft to a new owner
+func setPickOwner(tx *sqlx.Tx, leagueID, round, originalUserTeamID, ownerUserTeamID int) error {
+	result, err := tx.Exec(`
+		UPDATE draft_pick_owners
+		SET owner_user_team_id = $1
+		WHERE league_id = $2 AND round = $3 AND original_user_team_id = $4 AND draft_id IS NULL
+	`, ownerUserTeamID, leagueID, round, originalUserTeamID)
+	if err != nil {
+		return fmt.Errorf("error trading draft pick: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows > 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO draft_pick_owners (league_id, round, original_user_team_id, owner_user_team_id)
+		VALUES ($1, $2, $3, $4)
+	`, leagueID, round, originalUserTeamID, ownerUserTeamID)
+	if err != nil {
+		return fmt.Errorf("error trading draft pick: %w", err)
+	}
+	return nil
+}
+
+// recordTrade adds one side of a trade to the league's transaction history, pairing the players
+// the team received with those it gave up. The FAAB the team spent, or received as a negative
+// amount, goes on the first entry so its balance comes out right.
+func recordTrade(tx *sqlx.Tx, trade *models.Trade, userTeamID int, in, out []int, spent int, now time.Time) error {
+	sort.Ints(in)
+	sort.Ints(out)
+	entries := len(in)
+	if len(out) > entries {
+		entries = len(out)
+	}
+	if entries == 0 {
+		entries = 1
+	}
+
+	for i := 0; i < entries; i++ {
+		transaction := &models.LeagueTransaction{
+			LeagueID:   trade.LeagueID,
+			UserTeamID: userTeamID,
+			Type:       models.LeagueTransactionTrade,
+			TradeID:    &trade.ID,
+			CreatedAt:  now,
+		}
+		if i < len(in) {
+			transaction.PlayerInID = &in[i]
+		}
+		if i < len(out) {
+			transaction.PlayerOutID = &out[i]
+		}
+		if i == 0 {
+			transaction.Amount = spent
+		}
+		if err := waiver.RecordTransaction(tx, transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RejectTrade turns down a trade offered to a user team
+func (s *tradeServiceImpl) RejectTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	return s.respond(tradeID, userTeamID, models.TradeStatusRejected)
+}
+
+// CancelTrade withdraws a trade a user team proposed
+func (s *tradeServiceImpl) CancelTrade(tradeID, userTeamID int) (*models.Trade, error) {
+	return s.respond(tradeID, userTeamID, models.TradeStatusCancelled)
+}
+
+// respond settles a pending trade without making it. Only the proposer can cancel a trade and
+// only the recipient can reject it.
+func (s *tradeServiceImpl) respond(tradeID, userTeamID int, status models.TradeStatus) (*models.Trade, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if err := ExpireTrades(tx, now); err != nil {
+		return nil, err
+	}
+	trade, err := lockTrade(tx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	party := trade.RecipientTeamID
+	if status == models.TradeStatusCancelled {
+		party = trade.ProposerTeamID
+	}
+	if party != userTeamID {
+		return nil, fmt.Errorf("%w: the team cannot mark the trade %s", ErrNotTradeParty, status)
+	}
+	if err := settleTrade(tx, trade, status, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing trade: %w", err)
+	}
+
+	return trade, nil
+}
+
+// CounterTrade answers a trade offered to a user team with a trade of its own, which goes back to
+// the team that proposed the original. The original trade is closed as countered.
+func (s *tradeServiceImpl) CounterTrade(tradeID int, counter *models.Trade) (*models.Trade, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settings, err := waiver.LockDraftLeague(tx, counter.ProposerTeamID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := ExpireTrades(tx, now); err != nil {
+		return nil, err
+	}
+
+	original, err := lockTrade(tx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if original.RecipientTeamID != counter.ProposerTeamID {
+		return nil, fmt.Errorf("%w: only the team offered a trade can counter it", ErrNotTradeParty)
+	}
+	if err := settleTrade(tx, original, models.TradeStatusCountered, now); err != nil {
+		return nil, err
+	}
+
+	counter.RecipientTeamID = original.ProposerTeamID
+	counter.CounterOfID = &original.ID
+	if err := proposeTrade(tx, settings, counter, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing trade: %w", err)
+	}
+
+	return counter, nil
+}
@@ -0,0 +1,492 @@
+package trade
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/league"
+	"go-app/services/waiver"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB       *database.TestDB
+	tradeService TradeService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	tradeService = NewTradeService(testDB.GetDB())
+	m.Run()
+}
+
+func TestTradeService(t *testing.T) {
+	db := testDB.GetDB()
+	leagueService := league.NewLeagueService(db)
+	waiverService := waiver.NewWaiverService(db)
+
+	// createLeague inserts a draft league whose draft is completed, with the given waiver mode.
+	// Trades are made as soon as they are accepted.
+	createLeague := func(teams int, mode models.WaiverMode) (int, []int) {
+		now := time.Now()
+		var leagueID, draftID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("TRADE%d", now.UnixNano()), "Trade League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+
+		settings := league.DefaultSettings(leagueID)
+		settings.WaiverProcessTime = ""
+		settings.WaiverMode = mode
+		settings.TradeReview = models.TradeReviewNone
+		_, err = leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+
+		err = db.QueryRow(`
+			INSERT INTO drafts (league_id, type, status, rounds, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			RETURNING id
+		`, leagueID, models.DraftTypeSnake, models.DraftStatusCompleted, 1, now).Scan(&draftID)
+		assert.NoError(t, err)
+
+		userTeamIDs := make([]int, teams)
+		for i := range userTeamIDs {
+			var userID int
+			err := db.QueryRow(`
+				INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, "Trade", "Manager", fmt.Sprintf("trade_%d_%d@example.com", now.UnixNano(), i), "password", now, now).Scan(&userID)
+			assert.NoError(t, err)
+			err = db.QueryRow(`
+				INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, userID, leagueID, fmt.Sprintf("Trade Team %d", i), now, now).Scan(&userTeamIDs[i])
+			assert.NoError(t, err)
+			_, err = db.Exec("INSERT INTO draft_order (draft_id, user_team_id, position) VALUES ($1, $2, $3)", draftID, userTeamIDs[i], i+1)
+			assert.NoError(t, err)
+		}
+		return leagueID, userTeamIDs
+	}
+
+	// createPlayers inserts forwards on separate teams
+	createPlayers := func(count int) []int {
+		now := time.Now()
+		ids := make([]int, count)
+		for i := range ids {
+			var teamID int
+			err := db.QueryRow(`
+				INSERT INTO teams (name, external_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id
+			`, fmt.Sprintf("Trade Club %d", i), now.UnixNano()%100000+int64(i), now, now).Scan(&teamID)
+			assert.NoError(t, err)
+			err = db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", i), models.PositionFWD, now, now).Scan(&ids[i])
+			assert.NoError(t, err)
+		}
+		return ids
+	}
+
+	addToRoster := func(userTeamID, playerID int) {
+		_, err := db.Exec(`
+			INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+		`, userTeamID, playerID, time.Now())
+		assert.NoError(t, err)
+	}
+
+	rosterOf := func(userTeamID int) []int {
+		var ids []int
+		err := db.Select(&ids, "SELECT player_id FROM user_team_players WHERE user_team_id = $1 ORDER BY player_id", userTeamID)
+		assert.NoError(t, err)
+		return ids
+	}
+
+	player := func(from, playerID int) *models.TradeItem {
+		return &models.TradeItem{FromTeamID: from, Type: models.TradeItemPlayer, PlayerID: &playerID}
+	}
+
+	t.Run("players and picks are swapped when a trade is accepted", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(2, models.WaiverModeRolling)
+		playerIDs := createPlayers(3)
+		addToRoster(teams[0], playerIDs[0])
+		addToRoster(teams[0], playerIDs[1])
+		addToRoster(teams[1], playerIDs[2])
+
+		round := 1
+		proposal, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items: []*models.TradeItem{
+				player(teams[0], playerIDs[0]),
+				player(teams[0], playerIDs[1]),
+				player(teams[1], playerIDs[2]),
+				{FromTeamID: teams[1], Type: models.TradeItemDraftPick, DraftRound: &round, PickOriginalTeamID: &teams[1]},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusPending, proposal.Status)
+		assert.WithinDuration(t, time.Now().Add(DefaultExpiry), proposal.ExpiresAt, time.Minute)
+
+		// Only the recipient can accept
+		_, err = tradeService.AcceptTrade(proposal.ID, teams[0])
+		assert.ErrorIs(t, err, ErrNotTradeParty)
+
+		accepted, err := tradeService.AcceptTrade(proposal.ID, teams[1])
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusAccepted, accepted.Status)
+
+		assert.Equal(t, []int{playerIDs[2]}, rosterOf(teams[0]))
+		assert.Equal(t, []int{playerIDs[0], playerIDs[1]}, rosterOf(teams[1]))
+
+		owner, err := PickOwner(db, leagueID, 1, teams[1])
+		assert.NoError(t, err)
+		assert.Equal(t, teams[0], owner)
+
+		// Team 0 received one player for two, so it has two entries in the history
+		transactions, err := waiverService.ListTransactions(leagueID)
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 4)
+		for _, transaction := range transactions {
+			assert.Equal(t, models.LeagueTransactionTrade, transaction.Type)
+			assert.Equal(t, &proposal.ID, transaction.TradeID)
+		}
+
+		_, err = tradeService.AcceptTrade(proposal.ID, teams[1])
+		assert.ErrorIs(t, err, ErrTradeNotPending)
+	})
+
+	t.Run("trades are invalid once a player leaves the roster", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, teams := createLeague(3, models.WaiverModeRolling)
+		playerIDs := createPlayers(3)
+		addToRoster(teams[0], playerIDs[0])
+		addToRoster(teams[1], playerIDs[1])
+		addToRoster(teams[2], playerIDs[2])
+
+		// Team 0 offers player 0 to both other teams
+		first, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items:           []*models.TradeItem{player(teams[0], playerIDs[0]), player(teams[1], playerIDs[1])},
+		})
+		assert.NoError(t, err)
+		second, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[2],
+			Items:           []*models.TradeItem{player(teams[0], playerIDs[0]), player(teams[2], playerIDs[2])},
+		})
+		assert.NoError(t, err)
+
+		// A player the team does not own cannot be offered
+		_, err = tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items:           []*models.TradeItem{player(teams[0], playerIDs[2])},
+		})
+		assert.ErrorIs(t, err, ErrInvalidTrade)
+
+		_, err = tradeService.AcceptTrade(first.ID, teams[1])
+		assert.NoError(t, err)
+
+		found, err := tradeService.GetTrade(second.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusInvalid, found.Status)
+		_, err = tradeService.AcceptTrade(second.ID, teams[2])
+		assert.ErrorIs(t, err, ErrTradeNotPending)
+
+		// Dropping a player does the same
+		third, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[2],
+			RecipientTeamID: teams[0],
+			Items:           []*models.TradeItem{player(teams[2], playerIDs[2])},
+		})
+		assert.NoError(t, err)
+		_, err = waiverService.DropPlayer(teams[2], playerIDs[2])
+		assert.NoError(t, err)
+		found, err = tradeService.GetTrade(third.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusInvalid, found.Status)
+	})
+
+	t.Run("counters, rejections and expiry", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, teams := createLeague(2, models.WaiverModeFAAB)
+		playerIDs := createPlayers(2)
+		addToRoster(teams[0], playerIDs[0])
+		addToRoster(teams[1], playerIDs[1])
+
+		proposal, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items:           []*models.TradeItem{player(teams[1], playerIDs[1])},
+		})
+		assert.NoError(t, err)
+
+		// FAAB can only be traded up to the team's balance
+		_, err = tradeService.CounterTrade(proposal.ID, &models.Trade{
+			ProposerTeamID: teams[1],
+			Items: []*models.TradeItem{
+				player(teams[1], playerIDs[1]),
+				{FromTeamID: teams[0], Type: models.TradeItemFAAB, Amount: 101},
+			},
+		})
+		assert.ErrorIs(t, err, ErrInvalidTrade)
+
+		counter, err := tradeService.CounterTrade(proposal.ID, &models.Trade{
+			ProposerTeamID: teams[1],
+			Items: []*models.TradeItem{
+				player(teams[1], playerIDs[1]),
+				{FromTeamID: teams[0], Type: models.TradeItemFAAB, Amount: 40},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, teams[0], counter.RecipientTeamID)
+		assert.Equal(t, &proposal.ID, counter.CounterOfID)
+
+		original, err := tradeService.GetTrade(proposal.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusCountered, original.Status)
+
+		_, err = tradeService.AcceptTrade(counter.ID, teams[0])
+		assert.NoError(t, err)
+		assert.Equal(t, []int{playerIDs[0], playerIDs[1]}, rosterOf(teams[0]))
+
+		balance, err := waiverService.GetFAABBalance(teams[0])
+		assert.NoError(t, err)
+		assert.Equal(t, 60, balance)
+		balance, err = waiverService.GetFAABBalance(teams[1])
+		assert.NoError(t, err)
+		assert.Equal(t, 140, balance)
+
+		// Only the recipient can reject and only the proposer can cancel
+		rejected, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[1],
+			RecipientTeamID: teams[0],
+			Items:           []*models.TradeItem{{FromTeamID: teams[1], Type: models.TradeItemFAAB, Amount: 10}},
+		})
+		assert.NoError(t, err)
+		_, err = tradeService.RejectTrade(rejected.ID, teams[1])
+		assert.ErrorIs(t, err, ErrNotTradeParty)
+		rejected, err = tradeService.RejectTrade(rejected.ID, teams[0])
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusRejected, rejected.Status)
+
+		cancelled, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[1],
+			RecipientTeamID: teams[0],
+			Items:           []*models.TradeItem{{FromTeamID: teams[1], Type: models.TradeItemFAAB, Amount: 10}},
+		})
+		assert.NoError(t, err)
+		cancelled, err = tradeService.CancelTrade(cancelled.ID, teams[1])
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusCancelled, cancelled.Status)
+
+		expiring, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[1],
+			RecipientTeamID: teams[0],
+			ExpiresAt:       time.Now().Add(time.Hour),
+			Items:           []*models.TradeItem{{FromTeamID: teams[1], Type: models.TradeItemFAAB, Amount: 10}},
+		})
+		assert.NoError(t, err)
+		_, err = db.Exec("UPDATE trades SET expires_at = $1 WHERE id = $2", time.Now().Add(-time.Minute), expiring.ID)
+		assert.NoError(t, err)
+		_, err = tradeService.AcceptTrade(expiring.ID, teams[0])
+		assert.ErrorIs(t, err, ErrTradeNotPending)
+
+		trades, err := tradeService.ListTrades(teams[0])
+		assert.NoError(t, err)
+		assert.Len(t, trades, 5)
+		assert.Equal(t, models.TradeStatusExpired, trades[0].Status)
+	})
+
+	// process makes the trades in review as if the review period had passed
+	process := func(leagueID int) []*models.Trade {
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+		processed, err := ProcessDueTrades(tx, leagueID, time.Now().Add(48*time.Hour))
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+		return processed
+	}
+
+	t.Run("league votes veto trades in review", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(4, models.WaiverModeRolling)
+		settings := league.DefaultSettings(leagueID)
+		settings.WaiverProcessTime = ""
+		settings.TradeVetoVotes = 2
+		_, err := leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+		playerIDs := createPlayers(4)
+		for i, id := range playerIDs {
+			addToRoster(teams[i], id)
+		}
+
+		vetoed, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items:           []*models.TradeItem{player(teams[0], playerIDs[0]), player(teams[1], playerIDs[1])},
+		})
+		assert.NoError(t, err)
+		vetoed, err = tradeService.AcceptTrade(vetoed.ID, teams[1])
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusInReview, vetoed.Status)
+		if assert.NotNil(t, vetoed.ReviewEndsAt) {
+			assert.WithinDuration(t, time.Now().Add(league.DefaultTradeReviewHours*time.Hour), *vetoed.ReviewEndsAt, time.Minute)
+		}
+		assert.Equal(t, []int{playerIDs[0]}, rosterOf(teams[0]))
+
+		// The teams trading cannot vote, and a team only counts once
+		_, err = tradeService.VetoTrade(vetoed.ID, teams[0])
+		assert.ErrorIs(t, err, ErrNotTradeParty)
+		vetoed, err = tradeService.VetoTrade(vetoed.ID, teams[2])
+		assert.NoError(t, err)
+		assert.Equal(t, 1, vetoed.VetoVotes)
+		vetoed, err = tradeService.VetoTrade(vetoed.ID, teams[2])
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusInReview, vetoed.Status)
+		vetoed, err = tradeService.VetoTrade(vetoed.ID, teams[3])
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusVetoed, vetoed.Status)
+
+		made, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[2],
+			RecipientTeamID: teams[3],
+			Items:           []*models.TradeItem{player(teams[2], playerIDs[2]), player(teams[3], playerIDs[3])},
+		})
+		assert.NoError(t, err)
+		_, err = tradeService.AcceptTrade(made.ID, teams[3])
+		assert.NoError(t, err)
+		_, err = tradeService.VetoTrade(made.ID, teams[0])
+		assert.NoError(t, err)
+
+		// Nothing is made until the review period closes
+		processed, err := tradeService.ProcessTrades(leagueID)
+		assert.NoError(t, err)
+		assert.Empty(t, processed)
+
+		processed = process(leagueID)
+		if assert.Len(t, processed, 1) {
+			assert.Equal(t, models.TradeStatusAccepted, processed[0].Status)
+		}
+		assert.Equal(t, []int{playerIDs[0]}, rosterOf(teams[0]))
+		assert.Equal(t, []int{playerIDs[3]}, rosterOf(teams[2]))
+		assert.Equal(t, []int{playerIDs[2]}, rosterOf(teams[3]))
+	})
+
+	t.Run("the commissioner vetoes trades in review", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(3, models.WaiverModeRolling)
+		settings := league.DefaultSettings(leagueID)
+		settings.WaiverProcessTime = ""
+		settings.TradeReview = models.TradeReviewCommissioner
+		settings.CommissionerTeamID = &teams[2]
+		_, err := leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+		playerIDs := createPlayers(3)
+		addToRoster(teams[0], playerIDs[0])
+		addToRoster(teams[1], playerIDs[1])
+
+		proposal, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items:           []*models.TradeItem{player(teams[0], playerIDs[0])},
+		})
+		assert.NoError(t, err)
+		_, err = tradeService.AcceptTrade(proposal.ID, teams[1])
+		assert.NoError(t, err)
+
+		_, err = tradeService.VetoTrade(proposal.ID, teams[0])
+		assert.ErrorIs(t, err, ErrNotTradeParty)
+		vetoed, err := tradeService.VetoTrade(proposal.ID, teams[2])
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusVetoed, vetoed.Status)
+
+		// The veto is logged against both teams as the commissioner's override
+		page, err := audit.NewAuditService(db).ListEntries(leagueID, &audit.AuditFilter{Action: models.AuditActionCommissionerOverride})
+		assert.NoError(t, err)
+		if assert.Len(t, page.Entries, 2) {
+			assert.Equal(t, &teams[2], page.Entries[0].ActorUserTeamID)
+			assert.Equal(t, &proposal.ID, page.Entries[0].TradeID)
+		}
+
+		// A trade whose player is dropped during review falls through
+		dropped, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items:           []*models.TradeItem{player(teams[0], playerIDs[0])},
+		})
+		assert.NoError(t, err)
+		_, err = tradeService.AcceptTrade(dropped.ID, teams[1])
+		assert.NoError(t, err)
+		_, err = waiverService.DropPlayer(teams[0], playerIDs[0])
+		assert.NoError(t, err)
+
+		assert.Empty(t, process(leagueID))
+		found, err := tradeService.GetTrade(dropped.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.TradeStatusInvalid, found.Status)
+		_, err = tradeService.VetoTrade(dropped.ID, teams[2])
+		assert.ErrorIs(t, err, ErrTradeNotInReview)
+	})
+
+	t.Run("squad rules are checked for both teams", func(t *testing.T) {
+		defer testDB.Clear()
+
+		_, teams := createLeague(2, models.WaiverModeRolling)
+		playerIDs := createPlayers(4)
+		for _, id := range playerIDs[:3] {
+			addToRoster(teams[0], id)
+		}
+		addToRoster(teams[1], playerIDs[3])
+
+		// Team 0 already has three forwards, the most a squad can hold
+		_, err := tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items:           []*models.TradeItem{player(teams[1], playerIDs[3])},
+		})
+		assert.Error(t, err)
+
+		// Only FAAB leagues trade FAAB
+		_, err = tradeService.ProposeTrade(&models.Trade{
+			ProposerTeamID:  teams[0],
+			RecipientTeamID: teams[1],
+			Items: []*models.TradeItem{
+				player(teams[0], playerIDs[0]),
+				{FromTeamID: teams[0], Type: models.TradeItemFAAB, Amount: 5},
+			},
+		})
+		assert.ErrorIs(t, err, ErrInvalidTrade)
+	})
+}
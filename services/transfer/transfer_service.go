@@ -0,0 +1,353 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/gameweek"
+	"go-app/services/league"
+	"go-app/services/lineup"
+	"go-app/services/player"
+	"go-app/services/squad"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// FreeTransfers is how many players a user team can buy in a gameweek without a points hit
+const FreeTransfers = 1
+
+// TransferHitCost is the points deducted for each player bought beyond the free transfers
+const TransferHitCost = 4
+
+var (
+	// ErrNotBudgetLeague is returned when a transfer is made in a league that drafts its players
+	ErrNotBudgetLeague = errors.New("transfers are only made in budget leagues")
+	// ErrOverBudget is returned when transfers would cost more than the team has in the bank
+	ErrOverBudget = errors.New("transfers would take the squad over budget")
+	// ErrInvalidTransfer is returned when the players moved in or out do not make sense for the squad
+	ErrInvalidTransfer = errors.New("invalid transfer")
+)
+
+// TransferService defines the interface for transfer-related operations
+type TransferService interface {
+	MakeTransfers(userTeamID int, out []int, in []int) ([]*models.Transfer, error)
+	ListTransfers(userTeamID int) ([]*models.Transfer, error)
+	GetBank(userTeamID int) (float64, error)
+}
+
+// Implementation of the TransferService interface
+type transferServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewTransferService creates a new TransferService instance
+func NewTransferService(db *sqlx.DB) TransferService {
+	return &transferServiceImpl{db: db}
+}
+
+// Bank returns how much of its budget a user team has left to spend. A squad is bought
+// through transfers, so the bank is the budget less everything bought plus everything sold.
+// Transfers made on a free hit no longer count once the squad has been put back.
+func Bank(q sqlx.Queryer, userTeamID int, budget float64) (float64, error) {
+	var spent float64
+	err := q.QueryRowx(`
+		SELECT COALESCE(SUM(t.price_in - t.price_out), 0)
+		FROM transfers t
+		WHERE t.user_team_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM chips c
+			WHERE c.user_team_id = t.user_team_id AND c.gameweek_id = t.gameweek_id
+				AND c.chip = $2 AND c.reverted_at IS NOT NULL
+		)
+	`, userTeamID, models.ChipFreeHit).Scan(&spent)
+	if err != nil {
+		return 0, err
+	}
+	return player.RoundPrice(budget - spent), nil
+}
+
+// SellingPrice returns what a player bought at purchasePrice sells for now that they cost price.
+// A fall is passed on in full but only half of a rise is, rounded down to the nearest step.
+func SellingPrice(purchasePrice, price float64) float64 {
+	profit := int(math.Round((price - purchasePrice) * 10))
+	if profit <= 0 {
+		return price
+	}
+	return player.RoundPrice(purchasePrice + float64(profit/2)/10)
+}
+
+// loadBudgetSettings retrieves the settings of a user team's league, returning
+// ErrNotBudgetLeague if the league drafts its players
+func loadBudgetSettings(q sqlx.Queryer, userTeamID int, lock bool) (*models.LeagueSettings, error) {
+	query := "SELECT league_id FROM user_teams WHERE id = $1"
+	if lock {
+		query += " FOR UPDATE"
+	}
+
+	var leagueID int
+	if err := q.QueryRowx(query, userTeamID).Scan(&leagueID); err != nil {
+		return nil, err
+	}
+
+	settings, err := league.LoadSettings(q, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading league settings: %w", err)
+	}
+	if settings.OwnershipMode != models.OwnershipModeBudget {
+		return nil, ErrNotBudgetLeague
+	}
+	return settings, nil
+}
+
+// GetBank retrieves how much a user team has left to spend
+func (s *transferServiceImpl) GetBank(userTeamID int) (float64, error) {
+	settings, err := loadBudgetSettings(s.db, userTeamID, false)
+	if err != nil {
+		return 0, err
+	}
+	return Bank(s.db, userTeamID, settings.Budget)
+}
+
+// ListTransfers retrieves every transfer a user team has made, oldest first
+func (s *transferServiceImpl) ListTransfers(userTeamID int) ([]*models.Transfer, error) {
+	var transfers []*models.Transfer
+	err := s.db.Select(&transfers, "SELECT * FROM transfers WHERE user_team_id = $1 ORDER BY created_at, id", userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// validateMoves checks that no player is moved twice and that there is something to move
+func validateMoves(out []int, in []int) error {
+	if len(out) == 0 && len(in) == 0 {
+		return fmt.Errorf("%w: no players to transfer", ErrInvalidTransfer)
+	}
+
+	seen := make(map[int]bool, len(out)+len(in))
+	for _, id := range append(append([]int{}, out...), in...) {
+		if seen[id] {
+			return fmt.Errorf("%w: player %d appears more than once", ErrInvalidTransfer, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// MakeTransfers sells the players in out at their selling prices and buys the players in in at
+// their current prices. Players can be owned by any number of teams in a budget league, so only
+// the team's own squad rules and bank limit what it can buy.
+func (s *transferServiceImpl) MakeTransfers(userTeamID int, out []int, in []int) ([]*models.Transfer, error) {
+	if err := validateMoves(out, in); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the team so concurrent transfers cannot both spend the same bank
+	settings, err := loadBudgetSettings(tx, userTeamID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// A free hit squad from an earlier gameweek has to be put back before transferring from it
+	now := time.Now()
+	if _, err := lineup.RevertDueFreeHits(tx, userTeamID, now); err != nil {
+		return nil, err
+	}
+
+	next, err := gameweek.LoadNextGameweek(tx, now)
+	if err != nil {
+		return nil, err
+	}
+	var gameweekID *int
+	freeLeft := -1
+	if next != nil {
+		gameweekID = &next.ID
+		if freeLeft, err = freeTransfersLeft(tx, userTeamID, next); err != nil {
+			return nil, err
+		}
+	}
+
+	var owned []*models.UserTeamPlayer
+	if err := tx.Select(&owned, "SELECT * FROM user_team_players WHERE user_team_id = $1", userTeamID); err != nil {
+		return nil, err
+	}
+	purchasePrices := make(map[int]float64, len(owned))
+	for _, utp := range owned {
+		purchasePrices[utp.PlayerID] = utp.PurchasePrice
+	}
+	for _, id := range out {
+		if _, ok := purchasePrices[id]; !ok {
+			return nil, fmt.Errorf("%w: player %d is not in the squad", ErrInvalidTransfer, id)
+		}
+	}
+	for _, id := range in {
+		if _, ok := purchasePrices[id]; ok {
+			return nil, fmt.Errorf("%w: player %d is already in the squad", ErrInvalidTransfer, id)
+		}
+	}
+
+	if err := squad.ValidateRosterChange(tx, userTeamID, in, out); err != nil {
+		return nil, err
+	}
+
+	prices, err := currentPrices(tx, append(append([]int{}, out...), in...))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range out {
+		prices[id] = SellingPrice(purchasePrices[id], prices[id])
+	}
+
+	bank, err := Bank(tx, userTeamID, settings.Budget)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range out {
+		bank += prices[id]
+	}
+	for _, id := range in {
+		bank -= prices[id]
+	}
+	if player.RoundPrice(bank) < 0 {
+		return nil, ErrOverBudget
+	}
+
+	before, err := audit.RosterOf(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > 0 {
+		query, args, err := sqlx.In("DELETE FROM user_team_players WHERE user_team_id = ? AND player_id IN (?)", userTeamID, out)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(tx.Rebind(query), args...); err != nil {
+			return nil, fmt.Errorf("error removing players from squad: %w", err)
+		}
+	}
+	for _, id := range in {
+		_, err := tx.Exec(`
+			INSERT INTO user_team_players (user_team_id, player_id, purchase_price, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $4)
+		`, userTeamID, id, prices[id], now)
+		if err != nil {
+			return nil, fmt.Errorf("error adding player to squad: %w", err)
+		}
+	}
+
+	// Players out and in are paired up in order, anything left over is a plain sale or purchase
+	count := len(out)
+	if len(in) > count {
+		count = len(in)
+	}
+	transfers := make([]*models.Transfer, 0, count)
+	for i := 0; i < count; i++ {
+		transfer := &models.Transfer{UserTeamID: userTeamID, GameweekID: gameweekID, CreatedAt: now}
+		if i < len(out) {
+			transfer.PlayerOutID = &out[i]
+			transfer.PriceOut = prices[out[i]]
+		}
+		if i < len(in) {
+			transfer.PlayerInID = &in[i]
+			transfer.PriceIn = prices[in[i]]
+			if freeLeft == 0 {
+				transfer.Cost = TransferHitCost
+			} else if freeLeft > 0 {
+				freeLeft--
+			}
+		}
+
+		err := tx.QueryRow(`
+			INSERT INTO transfers (user_team_id, player_out_id, player_in_id, price_out, price_in, gameweek_id, cost, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`, transfer.UserTeamID, transfer.PlayerOutID, transfer.PlayerInID, transfer.PriceOut, transfer.PriceIn,
+			transfer.GameweekID, transfer.Cost, transfer.CreatedAt).Scan(&transfer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error recording transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+	err = audit.Record(tx, &models.AuditEntry{
+		UserTeamID:      &userTeamID,
+		ActorUserTeamID: &userTeamID,
+		Action:          models.AuditActionTransfer,
+		PlayersIn:       audit.PlayerIDs(in...),
+		PlayersOut:      audit.PlayerIDs(out...),
+		RosterBefore:    before,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// freeTransfersLeft returns how many more players a user team can buy in a gameweek without a
+// points hit, or -1 if there is no limit. There is no limit while a wildcard or free hit is
+// active, or while a team is picking its first squad as it has made no transfers in an
+// earlier gameweek.
+func freeTransfersLeft(q sqlx.Queryer, userTeamID int, gameweek *models.Gameweek) (int, error) {
+	chip, err := lineup.ActiveChip(q, userTeamID, gameweek.ID)
+	if err != nil {
+		return 0, err
+	}
+	if chip == models.ChipWildcard || chip == models.ChipFreeHit {
+		return -1, nil
+	}
+
+	var earlier, made int
+	err = q.QueryRowx(`
+		SELECT
+			COUNT(*) FILTER (WHERE g.number < $2),
+			COUNT(*) FILTER (WHERE t.gameweek_id = $3 AND t.player_in_id IS NOT NULL)
+		FROM transfers t
+		JOIN gameweeks g ON g.id = t.gameweek_id
+		WHERE t.user_team_id = $1
+	`, userTeamID, gameweek.Number, gameweek.ID).Scan(&earlier, &made)
+	if err != nil {
+		return 0, err
+	}
+	if earlier == 0 {
+		return -1, nil
+	}
+	if made >= FreeTransfers {
+		return 0, nil
+	}
+	return FreeTransfers - made, nil
+}
+
+// currentPrices retrieves the current price of each of the given players
+func currentPrices(q sqlx.Queryer, playerIDs []int) (map[int]float64, error) {
+	var rows []struct {
+		ID    int     `db:"id"`
+		Price float64 `db:"price"`
+	}
+	query, args, err := sqlx.In("SELECT id, price FROM players WHERE id IN (?)", playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlx.Select(q, &rows, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[int]float64, len(rows))
+	for _, row := range rows {
+		prices[row.ID] = row.Price
+	}
+	return prices, nil
+}
@@ -0,0 +1,288 @@
+package transfer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/league"
+	"go-app/services/lineup"
+	"go-app/services/squad"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB          *database.TestDB
+	transferService TransferService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	transferService = NewTransferService(testDB.GetDB())
+	m.Run()
+}
+
+func TestValidateMoves(t *testing.T) {
+	assert.NoError(t, validateMoves([]int{1}, []int{2}))
+	assert.NoError(t, validateMoves(nil, []int{2, 3}))
+	assert.ErrorIs(t, validateMoves(nil, nil), ErrInvalidTransfer)
+	assert.ErrorIs(t, validateMoves([]int{1}, []int{1}), ErrInvalidTransfer)
+	assert.ErrorIs(t, validateMoves(nil, []int{2, 2}), ErrInvalidTransfer)
+}
+
+func TestSellingPrice(t *testing.T) {
+	assert.Equal(t, 7.5, SellingPrice(7.5, 7.5))
+	assert.Equal(t, 7.0, SellingPrice(7.5, 7.0))
+	assert.Equal(t, 7.6, SellingPrice(7.5, 7.8))
+	assert.Equal(t, 8.0, SellingPrice(7.5, 8.5))
+	assert.Equal(t, 7.5, SellingPrice(7.5, 7.6))
+}
+
+func TestTransferService(t *testing.T) {
+	db := testDB.GetDB()
+	leagueService := league.NewLeagueService(db)
+	lineupService := lineup.NewLineupService(db)
+
+	// createUserTeam inserts a league using the given ownership mode with one user team
+	createUserTeam := func(mode models.OwnershipMode, budget float64) int {
+		now := time.Now()
+		var leagueID, userID, userTeamID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("XFER%d", now.UnixNano()), "Transfer League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, "Transfer", "Manager", fmt.Sprintf("transfer_%d@example.com", now.UnixNano()), "password", now, now).Scan(&userID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, userID, leagueID, "Transfer Team", now, now).Scan(&userTeamID)
+		assert.NoError(t, err)
+
+		settings := league.DefaultSettings(leagueID)
+		settings.OwnershipMode = mode
+		settings.Budget = budget
+		_, err = leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+		return userTeamID
+	}
+
+	// createPlayers inserts forwards with the given prices on separate teams
+	createPlayers := func(prices ...float64) []int {
+		now := time.Now()
+		ids := make([]int, len(prices))
+		for i, price := range prices {
+			var teamID int
+			err := db.QueryRow(`
+				INSERT INTO teams (name, external_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id
+			`, fmt.Sprintf("Transfer Team %d", i), now.UnixNano()%100000+int64(i), now, now).Scan(&teamID)
+			assert.NoError(t, err)
+			err = db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, price, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", i), models.PositionFWD, price, now, now).Scan(&ids[i])
+			assert.NoError(t, err)
+		}
+		return ids
+	}
+
+	createGameweek := func(number int, deadline time.Time) int {
+		var id int
+		err := db.QueryRow(`
+			INSERT INTO gameweeks (number, deadline, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+			RETURNING id
+		`, number, deadline, time.Now()).Scan(&id)
+		assert.NoError(t, err)
+		return id
+	}
+
+	t.Run("buy and sell at current prices", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID := createUserTeam(models.OwnershipModeBudget, 20.0)
+		playerIDs := createPlayers(8.0, 7.5, 6.0)
+
+		transfers, err := transferService.MakeTransfers(userTeamID, nil, []int{playerIDs[0], playerIDs[1]})
+		assert.NoError(t, err)
+		assert.Len(t, transfers, 2)
+
+		bank, err := transferService.GetBank(userTeamID)
+		assert.NoError(t, err)
+		assert.Equal(t, 4.5, bank)
+
+		// Only half of a rise in price is made on a sale
+		_, err = db.Exec("UPDATE players SET price = 9.0 WHERE id = $1", playerIDs[0])
+		assert.NoError(t, err)
+
+		transfers, err = transferService.MakeTransfers(userTeamID, []int{playerIDs[0]}, []int{playerIDs[2]})
+		assert.NoError(t, err)
+		if assert.Len(t, transfers, 1) {
+			assert.Equal(t, 8.5, transfers[0].PriceOut)
+			assert.Equal(t, 6.0, transfers[0].PriceIn)
+		}
+
+		bank, err = transferService.GetBank(userTeamID)
+		assert.NoError(t, err)
+		assert.Equal(t, 7.0, bank)
+
+		history, err := transferService.ListTransfers(userTeamID)
+		assert.NoError(t, err)
+		assert.Len(t, history, 3)
+	})
+
+	t.Run("free transfers and hits", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID := createUserTeam(models.OwnershipModeBudget, 100.0)
+		playerIDs := createPlayers(5.0, 5.0, 5.0, 5.0)
+		first := createGameweek(1, time.Now().Add(-48*time.Hour))
+		second := createGameweek(2, time.Now().Add(24*time.Hour))
+
+		// Picking a first squad is free however many players are bought
+		transfers, err := transferService.MakeTransfers(userTeamID, nil, playerIDs[:2])
+		assert.NoError(t, err)
+		for _, transfer := range transfers {
+			assert.Equal(t, second, *transfer.GameweekID)
+			assert.Equal(t, 0, transfer.Cost)
+		}
+		_, err = db.Exec("UPDATE transfers SET gameweek_id = $1 WHERE user_team_id = $2", first, userTeamID)
+		assert.NoError(t, err)
+
+		transfers, err = transferService.MakeTransfers(userTeamID, playerIDs[:1], playerIDs[2:3])
+		assert.NoError(t, err)
+		if assert.Len(t, transfers, 1) {
+			assert.Equal(t, 0, transfers[0].Cost)
+		}
+		transfers, err = transferService.MakeTransfers(userTeamID, playerIDs[1:2], playerIDs[3:4])
+		assert.NoError(t, err)
+		if assert.Len(t, transfers, 1) {
+			assert.Equal(t, TransferHitCost, transfers[0].Cost)
+		}
+
+		// A wildcard refunds the hit and makes later transfers free
+		_, err = lineupService.PlayChip(userTeamID, second, models.ChipWildcard)
+		assert.NoError(t, err)
+		transfers, err = transferService.MakeTransfers(userTeamID, playerIDs[2:3], playerIDs[:1])
+		assert.NoError(t, err)
+		if assert.Len(t, transfers, 1) {
+			assert.Equal(t, 0, transfers[0].Cost)
+		}
+
+		var cost int
+		err = db.QueryRow("SELECT SUM(cost) FROM transfers WHERE user_team_id = $1", userTeamID).Scan(&cost)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, cost)
+	})
+
+	t.Run("free hit squad is put back after the deadline", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID := createUserTeam(models.OwnershipModeBudget, 20.0)
+		playerIDs := createPlayers(8.0, 7.5, 6.0)
+		_, err := transferService.MakeTransfers(userTeamID, nil, playerIDs[:2])
+		assert.NoError(t, err)
+
+		gameweekID := createGameweek(1, time.Now().Add(time.Hour))
+		createGameweek(2, time.Now().Add(48*time.Hour))
+		_, err = lineupService.PlayChip(userTeamID, gameweekID, models.ChipFreeHit)
+		assert.NoError(t, err)
+
+		_, err = transferService.MakeTransfers(userTeamID, playerIDs[:1], playerIDs[2:])
+		assert.NoError(t, err)
+		bank, err := transferService.GetBank(userTeamID)
+		assert.NoError(t, err)
+		assert.Equal(t, 6.5, bank)
+
+		_, err = db.Exec("UPDATE gameweeks SET deadline = $1 WHERE id = $2", time.Now().Add(-time.Minute), gameweekID)
+		assert.NoError(t, err)
+		reverted, err := lineupService.RevertFreeHits()
+		assert.NoError(t, err)
+		assert.Len(t, reverted, 1)
+
+		var owned []int
+		err = db.Select(&owned, "SELECT player_id FROM user_team_players WHERE user_team_id = $1 ORDER BY player_id", userTeamID)
+		assert.NoError(t, err)
+		assert.Equal(t, playerIDs[:2], owned)
+
+		bank, err = transferService.GetBank(userTeamID)
+		assert.NoError(t, err)
+		assert.Equal(t, 4.5, bank)
+
+		// Putting the squad back only happens once
+		reverted, err = lineupService.RevertFreeHits()
+		assert.NoError(t, err)
+		assert.Empty(t, reverted)
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID := createUserTeam(models.OwnershipModeBudget, 10.0)
+		playerIDs := createPlayers(6.0, 5.0)
+
+		_, err := transferService.MakeTransfers(userTeamID, nil, playerIDs)
+		assert.ErrorIs(t, err, ErrOverBudget)
+	})
+
+	t.Run("players are shared between teams", func(t *testing.T) {
+		defer testDB.Clear()
+
+		first := createUserTeam(models.OwnershipModeBudget, 100.0)
+		second := createUserTeam(models.OwnershipModeBudget, 100.0)
+		playerIDs := createPlayers(8.0)
+
+		_, err := transferService.MakeTransfers(first, nil, playerIDs)
+		assert.NoError(t, err)
+		_, err = transferService.MakeTransfers(second, nil, playerIDs)
+		assert.NoError(t, err)
+
+		// But a team cannot own the same player twice
+		_, err = transferService.MakeTransfers(first, nil, playerIDs)
+		assert.ErrorIs(t, err, ErrInvalidTransfer)
+	})
+
+	t.Run("squad rules apply", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID := createUserTeam(models.OwnershipModeBudget, 100.0)
+		playerIDs := createPlayers(5.0, 5.0, 5.0, 5.0)
+
+		_, err := transferService.MakeTransfers(userTeamID, nil, playerIDs)
+		var violation *squad.RuleViolation
+		assert.ErrorAs(t, err, &violation)
+	})
+
+	t.Run("draft leagues do not transfer", func(t *testing.T) {
+		defer testDB.Clear()
+
+		userTeamID := createUserTeam(models.OwnershipModeDraft, 100.0)
+		playerIDs := createPlayers(5.0)
+
+		_, err := transferService.MakeTransfers(userTeamID, nil, playerIDs)
+		assert.ErrorIs(t, err, ErrNotBudgetLeague)
+	})
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
 	"time"
@@ -11,9 +13,14 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrInvalidCredentials is returned when an email and password do not match a user
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
 // UserService defines the interface for user-related operations
 type UserService interface {
 	GetUser(id int) (*models.User, error)
+	Authenticate(email, password string) (*models.User, error)
+	OwnsUserTeam(userID, userTeamID int) (bool, error)
 	ListUsers() ([]*models.User, error)
 	CreateUser(user *models.User) (*models.User, error)
 	UpdateUser(user *models.User) (*models.User, error)
@@ -159,6 +166,32 @@ func (s *userServiceImpl) ListUsers() ([]*models.User, error) {
 	return users, nil
 }
 
+// Authenticate retrieves the user with an email and checks their password
+func (s *userServiceImpl) Authenticate(email, password string) (*models.User, error) {
+	user := &models.User{}
+	err := s.db.Get(user, "SELECT * FROM users WHERE email = $1", email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// OwnsUserTeam reports whether a user team belongs to a user
+func (s *userServiceImpl) OwnsUserTeam(userID, userTeamID int) (bool, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM user_teams WHERE id = $1 AND user_id = $2", userTeamID, userID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // ValidateUser validates user data
 func (s *userServiceImpl) ValidateUser(user *models.User) error {
 	if user.FirstName == "" {
@@ -3,6 +3,7 @@ package user
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"go-app/database"
 	"go-app/models"
@@ -232,4 +233,49 @@ func TestUserService(t *testing.T) {
 		err = userService.ValidateUser(invalidUser)
 		assert.Error(t, err)
 	})
+	// Test Authenticate and OwnsUserTeam
+	t.Run("Authenticate", func(t *testing.T) {
+		defer testDB.Clear()
+
+		createdUser, err := userService.CreateUser(&models.User{
+			FirstName: "Sam",
+			LastName:  "Manager",
+			Email:     "sam@example.com",
+			Password:  "password123",
+		})
+		assert.NoError(t, err)
+
+		authenticated, err := userService.Authenticate("sam@example.com", "password123")
+		assert.NoError(t, err)
+		if assert.NotNil(t, authenticated) {
+			assert.Equal(t, createdUser.ID, authenticated.ID)
+		}
+		_, err = userService.Authenticate("sam@example.com", "wrong-password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		_, err = userService.Authenticate("nobody@example.com", "password123")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+		db := testDB.GetDB()
+		now := time.Now()
+		var leagueID, userTeamID int
+		err = db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ('AUTH', 'Auth League', $1, $1)
+			RETURNING id
+		`, now).Scan(&leagueID)
+		assert.NoError(t, err)
+		err = db.QueryRow(`
+			INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+			VALUES ($1, $2, 'Sam Team', $3, $3)
+			RETURNING id
+		`, createdUser.ID, leagueID, now).Scan(&userTeamID)
+		assert.NoError(t, err)
+
+		owns, err := userService.OwnsUserTeam(createdUser.ID, userTeamID)
+		assert.NoError(t, err)
+		assert.True(t, owns)
+		owns, err = userService.OwnsUserTeam(createdUser.ID+1, userTeamID)
+		assert.NoError(t, err)
+		assert.False(t, owns)
+	})
 }
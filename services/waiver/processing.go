@@ -0,0 +1,383 @@
+package waiver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/league"
+	"go-app/services/scoring"
+	"go-app/services/squad"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ProcessWaivers settles the claims on every player whose waivers have cleared in a league
+func (s *waiverServiceImpl) ProcessWaivers(leagueID int) ([]*models.WaiverClaim, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	processed, err := ProcessDueWaivers(tx, leagueID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing waivers: %w", err)
+	}
+
+	return processed, nil
+}
+
+// LeaguesWithDueWaivers returns the leagues that have players whose waivers have cleared
+func LeaguesWithDueWaivers(q sqlx.Queryer, now time.Time) ([]int, error) {
+	var leagueIDs []int
+	err := sqlx.Select(q, &leagueIDs, `
+		SELECT DISTINCT league_id
+		FROM waivers
+		WHERE processed_at IS NULL AND clears_at <= $1
+		ORDER BY league_id
+	`, now)
+	if err != nil {
+		return nil, err
+	}
+	return leagueIDs, nil
+}
+
+// ProcessDueWaivers settles the claims on every player whose waivers have cleared in a league as
+// one batch, by rolling waiver priority or by FAAB bids depending on the league's waiver mode.
+// Players nobody won become free agents.
+func ProcessDueWaivers(tx *sqlx.Tx, leagueID int, now time.Time) ([]*models.WaiverClaim, error) {
+	// Lock the league so no other roster move can land the same player while claims are settled
+	if _, err := tx.Exec("SELECT id FROM leagues WHERE id = $1 FOR UPDATE", leagueID); err != nil {
+		return nil, fmt.Errorf("error locking league: %w", err)
+	}
+
+	var due []*models.Waiver
+	err := tx.Select(&due, `
+		SELECT * FROM waivers
+		WHERE league_id = $1 AND processed_at IS NULL AND clears_at <= $2
+	`, leagueID, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	cleared := make(map[int]bool, len(due))
+	for _, waiver := range due {
+		cleared[waiver.PlayerID] = true
+	}
+
+	settings, err := league.LoadSettings(tx, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading league settings: %w", err)
+	}
+
+	var pending []*models.WaiverClaim
+	err = tx.Select(&pending, `
+		SELECT * FROM waiver_claims
+		WHERE league_id = $1 AND status = $2
+		ORDER BY user_team_id, rank, id
+	`, leagueID, models.WaiverClaimPending)
+	if err != nil {
+		return nil, err
+	}
+	var claims []*models.WaiverClaim
+	for _, claim := range pending {
+		if cleared[claim.PlayerID] {
+			claims = append(claims, claim)
+		}
+	}
+
+	order, err := waiverOrder(tx, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	var processed []*models.WaiverClaim
+	if settings.WaiverMode == models.WaiverModeFAAB {
+		processed, order, err = settleBids(tx, settings, claims, order, now)
+	} else {
+		processed, order, err = settleByPriority(tx, settings, claims, order, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	renumber := make(map[int]bool)
+	for _, claim := range processed {
+		_, err := tx.Exec("UPDATE waiver_claims SET status = $1, processed_at = $2 WHERE id = $3", claim.Status, now, claim.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error updating claim: %w", err)
+		}
+		renumber[claim.UserTeamID] = true
+	}
+	for userTeamID := range renumber {
+		if err := renumberClaims(tx, userTeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := savePriorities(tx, leagueID, order); err != nil {
+		return nil, err
+	}
+
+	for _, waiver := range due {
+		if _, err := tx.Exec("UPDATE waivers SET processed_at = $1 WHERE id = $2", now, waiver.ID); err != nil {
+			return nil, fmt.Errorf("error clearing waivers: %w", err)
+		}
+	}
+
+	return processed, nil
+}
+
+// settleByPriority settles claims by rolling waiver priority. The team with the highest priority
+// and a claim that can still be made gets its most wanted player and goes to the back of the
+// order, then the order is walked again from the top until no claims are left.
+func settleByPriority(tx *sqlx.Tx, settings *models.LeagueSettings, claims []*models.WaiverClaim, order []int, now time.Time) ([]*models.WaiverClaim, []int, error) {
+	byTeam := make(map[int][]*models.WaiverClaim)
+	for _, claim := range claims {
+		byTeam[claim.UserTeamID] = append(byTeam[claim.UserTeamID], claim)
+	}
+
+	var processed []*models.WaiverClaim
+	for {
+		winner := 0
+		for _, userTeamID := range order {
+			for len(byTeam[userTeamID]) > 0 && winner == 0 {
+				claim := byTeam[userTeamID][0]
+				byTeam[userTeamID] = byTeam[userTeamID][1:]
+
+				status, err := settleClaim(tx, settings, claim, now)
+				if err != nil {
+					return nil, nil, err
+				}
+				claim.Status = status
+				claim.ProcessedAt = &now
+				processed = append(processed, claim)
+				if status == models.WaiverClaimWon {
+					winner = userTeamID
+				}
+			}
+			if winner != 0 {
+				break
+			}
+		}
+		if winner == 0 {
+			return processed, order, nil
+		}
+		order = moveToBack(order, winner)
+	}
+}
+
+// settleBids settles sealed FAAB bids, the highest bid first. A team that wins a tie on waiver
+// priority goes to the back of the order, so the next tie goes the other way. Ties settled on the
+// standings leave the waiver order as it is.
+func settleBids(tx *sqlx.Tx, settings *models.LeagueSettings, claims []*models.WaiverClaim, order []int, now time.Time) ([]*models.WaiverClaim, []int, error) {
+	var standings []int
+	if settings.WaiverTieBreak == models.WaiverTieBreakStandings {
+		var err error
+		if standings, err = reverseStandings(tx, settings.LeagueID); err != nil {
+			return nil, nil, fmt.Errorf("error loading standings: %w", err)
+		}
+	}
+
+	remaining := append([]*models.WaiverClaim{}, claims...)
+	var processed []*models.WaiverClaim
+	for len(remaining) > 0 {
+		// The waiver order changes as ties are won, so it is read afresh for every claim
+		ranking := order
+		if standings != nil {
+			ranking = standings
+		}
+		SortBids(remaining, settings.WaiverTieBreak, ranking)
+		claim := remaining[0]
+		remaining = remaining[1:]
+
+		status, err := settleClaim(tx, settings, claim, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		claim.Status = status
+		claim.ProcessedAt = &now
+		processed = append(processed, claim)
+
+		if status == models.WaiverClaimWon && settings.WaiverTieBreak == models.WaiverTieBreakPriority {
+			for _, other := range remaining {
+				if other.PlayerID == claim.PlayerID && other.Bid == claim.Bid && other.UserTeamID != claim.UserTeamID {
+					order = moveToBack(order, claim.UserTeamID)
+					break
+				}
+			}
+		}
+	}
+	return processed, order, nil
+}
+
+// SortBids puts FAAB claims in the order they are settled: highest bid first, with equal bids
+// settled by the tie-break. Under waiver priority and standings the order gives the teams from
+// first to last pick, and a team's own equal bids go by its ranking.
+func SortBids(claims []*models.WaiverClaim, tieBreak models.WaiverTieBreak, order []int) {
+	position := make(map[int]int, len(order))
+	for i, userTeamID := range order {
+		position[userTeamID] = i
+	}
+
+	sort.SliceStable(claims, func(i, j int) bool {
+		a, b := claims[i], claims[j]
+		if a.Bid != b.Bid {
+			return a.Bid > b.Bid
+		}
+		if tieBreak == models.WaiverTieBreakEarliest {
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		} else if position[a.UserTeamID] != position[b.UserTeamID] {
+			return position[a.UserTeamID] < position[b.UserTeamID]
+		}
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		return a.ID < b.ID
+	})
+}
+
+// reverseStandings returns the user teams of a league from the bottom of the standings to the top
+func reverseStandings(q sqlx.Queryer, leagueID int) ([]int, error) {
+	standings, err := scoring.Standings(q, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	order := make([]int, len(standings))
+	for i, standing := range standings {
+		order[len(standings)-1-i] = standing.UserTeamID
+	}
+	return order, nil
+}
+
+// moveToBack moves a user team to the back of a waiver order
+func moveToBack(order []int, userTeamID int) []int {
+	moved := make([]int, 0, len(order))
+	for _, id := range order {
+		if id != userTeamID {
+			moved = append(moved, id)
+		}
+	}
+	return append(moved, userTeamID)
+}
+
+// settleClaim makes a waiver claim if it can still be made, returning how it was settled. A claim
+// on a player someone else has already won is lost, and one that no longer fits the team or its
+// FAAB balance fails.
+func settleClaim(tx *sqlx.Tx, settings *models.LeagueSettings, claim *models.WaiverClaim, now time.Time) (models.WaiverClaimStatus, error) {
+	owner, err := OwnerOf(tx, settings.LeagueID, claim.PlayerID)
+	if err != nil {
+		return "", err
+	}
+	if owner != 0 {
+		return models.WaiverClaimLost, nil
+	}
+
+	if err := checkDrop(tx, settings.LeagueID, claim.UserTeamID, claim.DropPlayerID); err != nil {
+		if errors.Is(err, ErrInvalidClaim) {
+			return models.WaiverClaimFailed, nil
+		}
+		return "", err
+	}
+	if err := validateRosterMove(tx, claim.UserTeamID, claim.PlayerID, claim.DropPlayerID); err != nil {
+		var violation *squad.RuleViolation
+		if errors.As(err, &violation) {
+			return models.WaiverClaimFailed, nil
+		}
+		return "", err
+	}
+
+	if settings.WaiverMode == models.WaiverModeFAAB {
+		balance, err := FAABBalance(tx, claim.UserTeamID, settings.FAABBudget)
+		if err != nil {
+			return "", err
+		}
+		if claim.Bid > balance {
+			return models.WaiverClaimFailed, nil
+		}
+	}
+
+	before, err := audit.RosterOf(tx, claim.UserTeamID)
+	if err != nil {
+		return "", err
+	}
+	if err := moveRoster(tx, settings, claim.UserTeamID, &claim.PlayerID, claim.DropPlayerID, now); err != nil {
+		return "", err
+	}
+	err = RecordTransaction(tx, &models.LeagueTransaction{
+		LeagueID:      settings.LeagueID,
+		UserTeamID:    claim.UserTeamID,
+		Type:          models.LeagueTransactionWaiver,
+		PlayerInID:    &claim.PlayerID,
+		PlayerOutID:   claim.DropPlayerID,
+		WaiverClaimID: &claim.ID,
+		Amount:        claim.Bid,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return "", err
+	}
+	// Claims are settled by the league rather than the manager, so the entry has no actor
+	err = audit.Record(tx, &models.AuditEntry{
+		LeagueID:      settings.LeagueID,
+		UserTeamID:    &claim.UserTeamID,
+		Action:        models.AuditActionWaiverAdd,
+		PlayersIn:     audit.PlayerIDs(claim.PlayerID),
+		PlayersOut:    audit.OptionalPlayer(claim.DropPlayerID),
+		WaiverClaimID: &claim.ID,
+		RosterBefore:  before,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return models.WaiverClaimWon, nil
+}
+
+// waiverOrder returns the user teams of a league in waiver priority order. Until waivers have
+// been processed the order is the reverse of the draft order, so the team that picked last
+// claims first. Teams without a place yet go to the back.
+func waiverOrder(q sqlx.Queryer, leagueID int) ([]int, error) {
+	var order []int
+	err := sqlx.Select(q, &order, `
+		SELECT ut.id
+		FROM user_teams ut
+		LEFT JOIN waiver_priorities wp ON wp.league_id = ut.league_id AND wp.user_team_id = ut.id
+		LEFT JOIN draft_order dro ON dro.user_team_id = ut.id
+			AND dro.draft_id = (SELECT MAX(id) FROM drafts WHERE league_id = ut.league_id)
+		WHERE ut.league_id = $1
+		ORDER BY wp.priority NULLS LAST, dro.position DESC NULLS LAST, ut.id
+	`, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// savePriorities stores a league's waiver order
+func savePriorities(tx *sqlx.Tx, leagueID int, order []int) error {
+	for i, userTeamID := range order {
+		_, err := tx.Exec(`
+			INSERT INTO waiver_priorities (league_id, user_team_id, priority)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (league_id, user_team_id) DO UPDATE
+			SET priority = EXCLUDED.priority
+		`, leagueID, userTeamID, i+1)
+		if err != nil {
+			return fmt.Errorf("error saving waiver priority: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,721 @@
+package waiver
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/league"
+	"go-app/services/lineup"
+	"go-app/services/squad"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrNotDraftLeague is returned when the waiver wire is used in a league that buys its players
+	ErrNotDraftLeague = errors.New("waivers are only used in draft leagues")
+	// ErrDraftNotCompleted is returned when players are added before the league's draft has finished
+	ErrDraftNotCompleted = errors.New("players can only be added once the league's draft is completed")
+	// ErrPlayerOnWaivers is returned when a player on waivers is added as a free agent
+	ErrPlayerOnWaivers = errors.New("player is on waivers and has to be claimed")
+	// ErrPlayerNotOnWaivers is returned when a claim is made on a player who is not on waivers
+	ErrPlayerNotOnWaivers = errors.New("player is not on waivers")
+	// ErrPlayerOwned is returned when a player added already belongs to a team in the league
+	ErrPlayerOwned = errors.New("player already belongs to a team in the league")
+	// ErrNotFAABLeague is returned when a FAAB balance is asked for in a league that does not bid on waivers
+	ErrNotFAABLeague = errors.New("the league does not use FAAB waivers")
+	// ErrInvalidClaim is returned when the players in a claim or roster move do not make sense for the team
+	ErrInvalidClaim = errors.New("invalid waiver claim")
+)
+
+// WaiverService defines the interface for the waiver wire of draft leagues
+type WaiverService interface {
+	ListWaivers(leagueID int) ([]*models.Waiver, error)
+	GetPriorities(leagueID int) ([]*models.WaiverPriority, error)
+	ListTransactions(leagueID int) ([]*models.LeagueTransaction, error)
+	ListClaims(userTeamID int) ([]*models.WaiverClaim, error)
+	SubmitClaim(userTeamID, playerID int, dropPlayerID *int, bid int) (*models.WaiverClaim, error)
+	ReorderClaims(userTeamID int, claimIDs []int) ([]*models.WaiverClaim, error)
+	CancelClaim(userTeamID, claimID int) error
+	GetFAABBalance(userTeamID int) (int, error)
+	AddFreeAgent(userTeamID, playerID int, dropPlayerID *int) (*models.LeagueTransaction, error)
+	DropPlayer(userTeamID, playerID int) (*models.LeagueTransaction, error)
+	ProcessWaivers(leagueID int) ([]*models.WaiverClaim, error)
+}
+
+// Implementation of the WaiverService interface
+type waiverServiceImpl struct {
+	db *sqlx.DB
+}
+
+// NewWaiverService creates a new WaiverService instance
+func NewWaiverService(db *sqlx.DB) WaiverService {
+	return &waiverServiceImpl{db: db}
+}
+
+// WaiverClearTime returns when a player dropped at droppedAt comes off waivers: once the league's
+// waiver period has passed, at the next daily processing time if the league has one
+func WaiverClearTime(settings *models.LeagueSettings, droppedAt time.Time) (time.Time, error) {
+	clears := droppedAt.Add(time.Duration(settings.WaiverPeriodHours) * time.Hour)
+	if settings.WaiverProcessTime == "" {
+		return clears, nil
+	}
+
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone: %s", settings.Timezone)
+	}
+	processAt, err := league.ParseClockTime(settings.WaiverProcessTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	year, month, day := clears.In(loc).Date()
+	run := time.Date(year, month, day, 0, 0, 0, 0, loc).Add(processAt)
+	if run.Before(clears) {
+		run = time.Date(year, month, day+1, 0, 0, 0, 0, loc).Add(processAt)
+	}
+	return run, nil
+}
+
+// PlaceUndraftedOnWaivers puts every player nobody drafted in a league on waivers, so the
+// first pick of them after the draft goes by waiver priority rather than who asks first
+func PlaceUndraftedOnWaivers(tx *sqlx.Tx, leagueID int, now time.Time) error {
+	settings, err := league.LoadSettings(tx, leagueID)
+	if err != nil {
+		return fmt.Errorf("error loading league settings: %w", err)
+	}
+	clearsAt, err := WaiverClearTime(settings, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO waivers (league_id, player_id, clears_at, created_at)
+		SELECT $1, p.id, $2, $3
+		FROM players p
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_team_players utp
+			JOIN user_teams ut ON ut.id = utp.user_team_id
+			WHERE ut.league_id = $1 AND utp.player_id = p.id
+		) AND NOT EXISTS (
+			SELECT 1 FROM waivers w
+			WHERE w.league_id = $1 AND w.player_id = p.id AND w.processed_at IS NULL
+		)
+	`, leagueID, clearsAt, now)
+	if err != nil {
+		return fmt.Errorf("error placing players on waivers: %w", err)
+	}
+	return nil
+}
+
+// placeOnWaivers puts a player dropped in a league on waivers
+func placeOnWaivers(tx *sqlx.Tx, settings *models.LeagueSettings, playerID int, now time.Time) error {
+	clearsAt, err := WaiverClearTime(settings, now)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO waivers (league_id, player_id, clears_at, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, settings.LeagueID, playerID, clearsAt, now)
+	if err != nil {
+		return fmt.Errorf("error placing player on waivers: %w", err)
+	}
+	return nil
+}
+
+// RecordTransaction adds a roster change to its league's transaction history
+func RecordTransaction(tx *sqlx.Tx, transaction *models.LeagueTransaction) error {
+	err := tx.QueryRow(`
+		INSERT INTO league_transactions (league_id, user_team_id, type, player_in_id, player_out_id, waiver_claim_id,
+			trade_id, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, transaction.LeagueID, transaction.UserTeamID, transaction.Type, transaction.PlayerInID, transaction.PlayerOutID,
+		transaction.WaiverClaimID, transaction.TradeID, transaction.Amount, transaction.CreatedAt).Scan(&transaction.ID)
+	if err != nil {
+		return fmt.Errorf("error recording transaction: %w", err)
+	}
+	return nil
+}
+
+// FAABBalance returns how much of its FAAB budget a user team has left to bid. Everything spent
+// is in the league's transaction history, so the balance is the budget less what it records.
+func FAABBalance(q sqlx.Queryer, userTeamID int, budget int) (int, error) {
+	var spent int
+	err := q.QueryRowx("SELECT COALESCE(SUM(amount), 0) FROM league_transactions WHERE user_team_id = $1", userTeamID).Scan(&spent)
+	if err != nil {
+		return 0, err
+	}
+	return budget - spent, nil
+}
+
+// GetFAABBalance retrieves how much of its FAAB budget a user team has left to bid
+func (s *waiverServiceImpl) GetFAABBalance(userTeamID int) (int, error) {
+	var leagueID int
+	if err := s.db.QueryRow("SELECT league_id FROM user_teams WHERE id = $1", userTeamID).Scan(&leagueID); err != nil {
+		return 0, err
+	}
+	settings, err := league.LoadSettings(s.db, leagueID)
+	if err != nil {
+		return 0, fmt.Errorf("error loading league settings: %w", err)
+	}
+	if settings.OwnershipMode != models.OwnershipModeDraft || settings.WaiverMode != models.WaiverModeFAAB {
+		return 0, ErrNotFAABLeague
+	}
+	return FAABBalance(s.db, userTeamID, settings.FAABBudget)
+}
+
+// LockDraftLeague locks the league a user team plays in so its rosters change one move at a time,
+// and returns the league's settings. The league must draft its players and have finished its draft.
+func LockDraftLeague(tx *sqlx.Tx, userTeamID int) (*models.LeagueSettings, error) {
+	var leagueID int
+	if err := tx.QueryRow("SELECT league_id FROM user_teams WHERE id = $1", userTeamID).Scan(&leagueID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("SELECT id FROM leagues WHERE id = $1 FOR UPDATE", leagueID); err != nil {
+		return nil, fmt.Errorf("error locking league: %w", err)
+	}
+
+	settings, err := league.LoadSettings(tx, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading league settings: %w", err)
+	}
+	if settings.OwnershipMode != models.OwnershipModeDraft {
+		return nil, ErrNotDraftLeague
+	}
+
+	var status models.DraftStatus
+	err = tx.QueryRow("SELECT status FROM drafts WHERE league_id = $1 ORDER BY id DESC LIMIT 1", leagueID).Scan(&status)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if status != models.DraftStatusCompleted {
+		return nil, ErrDraftNotCompleted
+	}
+	return settings, nil
+}
+
+// openWaiver returns the waiver a player is on in a league, or nil if they are not on waivers
+func openWaiver(q sqlx.Queryer, leagueID, playerID int) (*models.Waiver, error) {
+	waiver := &models.Waiver{}
+	err := sqlx.Get(q, waiver, `
+		SELECT * FROM waivers
+		WHERE league_id = $1 AND player_id = $2 AND processed_at IS NULL
+	`, leagueID, playerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return waiver, nil
+}
+
+// OwnerOf returns the user team a player belongs to in a league, or zero if they are unowned
+func OwnerOf(q sqlx.Queryer, leagueID, playerID int) (int, error) {
+	var userTeamID int
+	err := q.QueryRowx(`
+		SELECT utp.user_team_id
+		FROM user_team_players utp
+		JOIN user_teams ut ON ut.id = utp.user_team_id
+		WHERE ut.league_id = $1 AND utp.player_id = $2
+	`, leagueID, playerID).Scan(&userTeamID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return userTeamID, nil
+}
+
+// checkDrop ensures the player a user team is dropping is on its roster
+func checkDrop(q sqlx.Queryer, leagueID, userTeamID int, dropPlayerID *int) error {
+	if dropPlayerID == nil {
+		return nil
+	}
+	owner, err := OwnerOf(q, leagueID, *dropPlayerID)
+	if err != nil {
+		return err
+	}
+	if owner != userTeamID {
+		return fmt.Errorf("%w: player %d is not on the team's roster", ErrInvalidClaim, *dropPlayerID)
+	}
+	return nil
+}
+
+// validateRosterMove checks a user team would still meet its league's squad rules after the move
+func validateRosterMove(q sqlx.Queryer, userTeamID int, playerInID int, playerOutID *int) error {
+	var drop []int
+	if playerOutID != nil {
+		drop = []int{*playerOutID}
+	}
+	var add []int
+	if playerInID != 0 {
+		add = []int{playerInID}
+	}
+	return squad.ValidateRosterChange(q, userTeamID, add, drop)
+}
+
+// InvalidateTrades marks every pending trade, or accepted trade still in review, in which a user
+// team gives up any of the given players as invalid, as the players have left its roster
+func InvalidateTrades(tx *sqlx.Tx, userTeamID int, playerIDs []int, now time.Time) error {
+	if len(playerIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`
+		UPDATE trades
+		SET status = ?, updated_at = ?
+		WHERE status IN (?, ?) AND id IN (
+			SELECT trade_id FROM trade_items
+			WHERE from_user_team_id = ? AND player_id IN (?)
+		)
+	`, models.TradeStatusInvalid, now, models.TradeStatusPending, models.TradeStatusInReview, userTeamID, playerIDs)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("error invalidating trades: %w", err)
+	}
+	return nil
+}
+
+// moveRoster adds a player to a user team, dropping one of its players first if given. A dropped
+// player goes on waivers before anyone else can add them, and any trade offering them falls
+// through. Either player can be left out.
+func moveRoster(tx *sqlx.Tx, settings *models.LeagueSettings, userTeamID int, playerInID *int, playerOutID *int, now time.Time) error {
+	// Lineups of gameweeks already under way are kept as they were at the deadline
+	if err := lineup.LockDueLineups(tx, userTeamID, now); err != nil {
+		return err
+	}
+	if playerOutID != nil {
+		_, err := tx.Exec("DELETE FROM user_team_players WHERE user_team_id = $1 AND player_id = $2", userTeamID, *playerOutID)
+		if err != nil {
+			return fmt.Errorf("error removing player from roster: %w", err)
+		}
+		if err := placeOnWaivers(tx, settings, *playerOutID, now); err != nil {
+			return err
+		}
+		if err := InvalidateTrades(tx, userTeamID, []int{*playerOutID}, now); err != nil {
+			return err
+		}
+	}
+	if playerInID != nil {
+		_, err := tx.Exec(`
+			INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+		`, userTeamID, *playerInID, now)
+		if err != nil {
+			return fmt.Errorf("error adding player to roster: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListWaivers retrieves the players on waivers in a league, the first to clear first
+func (s *waiverServiceImpl) ListWaivers(leagueID int) ([]*models.Waiver, error) {
+	var waivers []*models.Waiver
+	err := s.db.Select(&waivers, `
+		SELECT * FROM waivers
+		WHERE league_id = $1 AND processed_at IS NULL
+		ORDER BY clears_at, player_id
+	`, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	return waivers, nil
+}
+
+// GetPriorities retrieves a league's waiver order, the team that claims first first
+func (s *waiverServiceImpl) GetPriorities(leagueID int) ([]*models.WaiverPriority, error) {
+	order, err := waiverOrder(s.db, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := make([]*models.WaiverPriority, len(order))
+	for i, userTeamID := range order {
+		priorities[i] = &models.WaiverPriority{LeagueID: leagueID, UserTeamID: userTeamID, Priority: i + 1}
+	}
+	return priorities, nil
+}
+
+// ListTransactions retrieves a league's transaction history, latest first
+func (s *waiverServiceImpl) ListTransactions(leagueID int) ([]*models.LeagueTransaction, error) {
+	var transactions []*models.LeagueTransaction
+	err := s.db.Select(&transactions, `
+		SELECT * FROM league_transactions
+		WHERE league_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// ListClaims retrieves a user team's pending waiver claims in its order of preference
+func (s *waiverServiceImpl) ListClaims(userTeamID int) ([]*models.WaiverClaim, error) {
+	return pendingClaims(s.db, userTeamID)
+}
+
+// pendingClaims retrieves a user team's pending waiver claims in its order of preference
+func pendingClaims(q sqlx.Queryer, userTeamID int) ([]*models.WaiverClaim, error) {
+	var claims []*models.WaiverClaim
+	err := sqlx.Select(q, &claims, `
+		SELECT * FROM waiver_claims
+		WHERE user_team_id = $1 AND status = $2
+		ORDER BY rank, id
+	`, userTeamID, models.WaiverClaimPending)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// SubmitClaim claims a player on waivers for a user team, optionally dropping one of its players
+// if the claim succeeds. The claim is ranked below the team's other pending claims. In FAAB
+// leagues the claim is a sealed bid, which can be up to the team's whole balance: pending bids
+// are not held back, as each is checked against what is left when it is processed.
+func (s *waiverServiceImpl) SubmitClaim(userTeamID, playerID int, dropPlayerID *int, bid int) (*models.WaiverClaim, error) {
+	if bid < 0 {
+		return nil, fmt.Errorf("%w: bid cannot be negative", ErrInvalidClaim)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settings, err := LockDraftLeague(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	waiver, err := openWaiver(tx, settings.LeagueID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if waiver == nil {
+		return nil, ErrPlayerNotOnWaivers
+	}
+	if err := checkDrop(tx, settings.LeagueID, userTeamID, dropPlayerID); err != nil {
+		return nil, err
+	}
+	if settings.WaiverMode == models.WaiverModeFAAB {
+		balance, err := FAABBalance(tx, userTeamID, settings.FAABBudget)
+		if err != nil {
+			return nil, err
+		}
+		if bid > balance {
+			return nil, fmt.Errorf("%w: bid of %d is more than the %d the team has left", ErrInvalidClaim, bid, balance)
+		}
+	} else if bid != 0 {
+		return nil, fmt.Errorf("%w: bids are only placed in FAAB leagues", ErrInvalidClaim)
+	}
+
+	claims, err := pendingClaims(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	for _, claim := range claims {
+		if claim.PlayerID == playerID && sameDrop(claim.DropPlayerID, dropPlayerID) {
+			return nil, fmt.Errorf("%w: the team has already claimed player %d", ErrInvalidClaim, playerID)
+		}
+	}
+
+	// Rosters can change before the claim is processed, so this is checked again then
+	if err := validateRosterMove(tx, userTeamID, playerID, dropPlayerID); err != nil {
+		return nil, err
+	}
+
+	claim := &models.WaiverClaim{
+		LeagueID:     settings.LeagueID,
+		UserTeamID:   userTeamID,
+		PlayerID:     playerID,
+		DropPlayerID: dropPlayerID,
+		Rank:         len(claims) + 1,
+		Bid:          bid,
+		Status:       models.WaiverClaimPending,
+		CreatedAt:    time.Now(),
+	}
+	err = tx.QueryRow(`
+		INSERT INTO waiver_claims (league_id, user_team_id, player_id, drop_player_id, rank, bid, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, claim.LeagueID, claim.UserTeamID, claim.PlayerID, claim.DropPlayerID, claim.Rank, claim.Bid, claim.Status,
+		claim.CreatedAt).Scan(&claim.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error submitting claim: %w", err)
+	}
+	err = audit.Record(tx, &models.AuditEntry{
+		LeagueID:        settings.LeagueID,
+		UserTeamID:      &userTeamID,
+		ActorUserTeamID: &userTeamID,
+		Action:          models.AuditActionWaiverClaim,
+		PlayersIn:       audit.PlayerIDs(playerID),
+		PlayersOut:      audit.OptionalPlayer(dropPlayerID),
+		WaiverClaimID:   &claim.ID,
+		CreatedAt:       claim.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing claim: %w", err)
+	}
+
+	return claim, nil
+}
+
+// sameDrop reports whether two claims drop the same player
+func sameDrop(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// ReorderClaims ranks a user team's pending waiver claims in the given order, most wanted first.
+// Every pending claim has to be listed.
+func (s *waiverServiceImpl) ReorderClaims(userTeamID int, claimIDs []int) ([]*models.WaiverClaim, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := LockDraftLeague(tx, userTeamID); err != nil {
+		return nil, err
+	}
+
+	claims, err := pendingClaims(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[int]bool, len(claims))
+	for _, claim := range claims {
+		pending[claim.ID] = true
+	}
+	if len(claimIDs) != len(claims) {
+		return nil, fmt.Errorf("%w: every pending claim has to be ranked", ErrInvalidClaim)
+	}
+	for _, id := range claimIDs {
+		if !pending[id] {
+			return nil, fmt.Errorf("%w: claim %d is not one of the team's pending claims", ErrInvalidClaim, id)
+		}
+		delete(pending, id)
+	}
+
+	for i, id := range claimIDs {
+		if _, err := tx.Exec("UPDATE waiver_claims SET rank = $1 WHERE id = $2", i+1, id); err != nil {
+			return nil, fmt.Errorf("error ranking claim: %w", err)
+		}
+	}
+
+	claims, err = pendingClaims(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing claim order: %w", err)
+	}
+
+	return claims, nil
+}
+
+// CancelClaim withdraws one of a user team's pending waiver claims
+func (s *waiverServiceImpl) CancelClaim(userTeamID, claimID int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Claims are settled with the league locked, so this cannot cancel a claim being processed
+	if _, err := LockDraftLeague(tx, userTeamID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE waiver_claims
+		SET status = $1, processed_at = $2
+		WHERE id = $3 AND user_team_id = $4 AND status = $5
+	`, models.WaiverClaimCancelled, time.Now(), claimID, userTeamID, models.WaiverClaimPending)
+	if err != nil {
+		return fmt.Errorf("error cancelling claim: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := renumberClaims(tx, userTeamID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing claim: %w", err)
+	}
+	return nil
+}
+
+// renumberClaims closes the gaps left in a user team's claim ranks by claims that are no longer pending
+func renumberClaims(tx *sqlx.Tx, userTeamID int) error {
+	_, err := tx.Exec(`
+		UPDATE waiver_claims wc
+		SET rank = ranked.new_rank
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY rank, id) AS new_rank
+			FROM waiver_claims
+			WHERE user_team_id = $1 AND status = $2
+		) ranked
+		WHERE wc.id = ranked.id
+	`, userTeamID, models.WaiverClaimPending)
+	if err != nil {
+		return fmt.Errorf("error ranking claims: %w", err)
+	}
+	return nil
+}
+
+// AddFreeAgent adds a player who is neither owned nor on waivers to a user team straight away,
+// optionally dropping one of its players to make room
+func (s *waiverServiceImpl) AddFreeAgent(userTeamID, playerID int, dropPlayerID *int) (*models.LeagueTransaction, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settings, err := LockDraftLeague(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	waiver, err := openWaiver(tx, settings.LeagueID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if waiver != nil {
+		return nil, ErrPlayerOnWaivers
+	}
+	owner, err := OwnerOf(tx, settings.LeagueID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != 0 {
+		return nil, ErrPlayerOwned
+	}
+	if err := checkDrop(tx, settings.LeagueID, userTeamID, dropPlayerID); err != nil {
+		return nil, err
+	}
+	if err := validateRosterMove(tx, userTeamID, playerID, dropPlayerID); err != nil {
+		return nil, err
+	}
+
+	before, err := audit.RosterOf(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := moveRoster(tx, settings, userTeamID, &playerID, dropPlayerID, now); err != nil {
+		return nil, err
+	}
+	transaction := &models.LeagueTransaction{
+		LeagueID:    settings.LeagueID,
+		UserTeamID:  userTeamID,
+		Type:        models.LeagueTransactionFreeAgent,
+		PlayerInID:  &playerID,
+		PlayerOutID: dropPlayerID,
+		CreatedAt:   now,
+	}
+	if err := RecordTransaction(tx, transaction); err != nil {
+		return nil, err
+	}
+	err = audit.Record(tx, &models.AuditEntry{
+		LeagueID:        settings.LeagueID,
+		UserTeamID:      &userTeamID,
+		ActorUserTeamID: &userTeamID,
+		Action:          models.AuditActionAdd,
+		PlayersIn:       audit.PlayerIDs(playerID),
+		PlayersOut:      audit.OptionalPlayer(dropPlayerID),
+		RosterBefore:    before,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing free agent: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// DropPlayer releases a player from a user team onto waivers without adding anyone
+func (s *waiverServiceImpl) DropPlayer(userTeamID, playerID int) (*models.LeagueTransaction, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settings, err := LockDraftLeague(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDrop(tx, settings.LeagueID, userTeamID, &playerID); err != nil {
+		return nil, err
+	}
+	if err := validateRosterMove(tx, userTeamID, 0, &playerID); err != nil {
+		return nil, err
+	}
+
+	before, err := audit.RosterOf(tx, userTeamID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := moveRoster(tx, settings, userTeamID, nil, &playerID, now); err != nil {
+		return nil, err
+	}
+	transaction := &models.LeagueTransaction{
+		LeagueID:    settings.LeagueID,
+		UserTeamID:  userTeamID,
+		Type:        models.LeagueTransactionDrop,
+		PlayerOutID: &playerID,
+		CreatedAt:   now,
+	}
+	if err := RecordTransaction(tx, transaction); err != nil {
+		return nil, err
+	}
+	err = audit.Record(tx, &models.AuditEntry{
+		LeagueID:        settings.LeagueID,
+		UserTeamID:      &userTeamID,
+		ActorUserTeamID: &userTeamID,
+		Action:          models.AuditActionDrop,
+		PlayersOut:      audit.PlayerIDs(playerID),
+		RosterBefore:    before,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing drop: %w", err)
+	}
+
+	return transaction, nil
+}
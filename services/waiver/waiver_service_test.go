@@ -0,0 +1,524 @@
+package waiver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-app/database"
+	"go-app/models"
+	"go-app/services/audit"
+	"go-app/services/league"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testDB        *database.TestDB
+	waiverService WaiverService
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = database.NewTestDB()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create test database: %v", err))
+	}
+	defer func() {
+		if err := testDB.Close(); err != nil {
+			panic(fmt.Sprintf("Failed to close test database: %v", err))
+		}
+	}()
+
+	waiverService = NewWaiverService(testDB.GetDB())
+	m.Run()
+}
+
+func TestWaiverClearTime(t *testing.T) {
+	dropped := time.Date(2024, 8, 10, 20, 0, 0, 0, time.UTC)
+
+	t.Run("no processing time", func(t *testing.T) {
+		clears, err := WaiverClearTime(&models.LeagueSettings{WaiverPeriodHours: 48, Timezone: "UTC"}, dropped)
+		assert.NoError(t, err)
+		assert.Equal(t, dropped.Add(48*time.Hour), clears)
+	})
+
+	t.Run("next processing time after the period", func(t *testing.T) {
+		clears, err := WaiverClearTime(&models.LeagueSettings{
+			WaiverPeriodHours: 48,
+			WaiverProcessTime: "03:00",
+			Timezone:          "UTC",
+		}, dropped)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 8, 13, 3, 0, 0, 0, time.UTC), clears)
+	})
+
+	t.Run("period ends before the processing time", func(t *testing.T) {
+		clears, err := WaiverClearTime(&models.LeagueSettings{
+			WaiverPeriodHours: 24,
+			WaiverProcessTime: "22:00",
+			Timezone:          "UTC",
+		}, dropped)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 8, 11, 22, 0, 0, 0, time.UTC), clears)
+	})
+
+	t.Run("league timezone", func(t *testing.T) {
+		clears, err := WaiverClearTime(&models.LeagueSettings{
+			WaiverPeriodHours: 0,
+			WaiverProcessTime: "03:00",
+			Timezone:          "Europe/London",
+		}, dropped)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 8, 11, 2, 0, 0, 0, time.UTC), clears.UTC())
+	})
+}
+
+func TestSortBids(t *testing.T) {
+	now := time.Now()
+	claims := []*models.WaiverClaim{
+		{ID: 1, UserTeamID: 1, PlayerID: 10, Bid: 5, Rank: 1, CreatedAt: now},
+		{ID: 2, UserTeamID: 2, PlayerID: 10, Bid: 8, Rank: 1, CreatedAt: now.Add(time.Minute)},
+		{ID: 3, UserTeamID: 3, PlayerID: 10, Bid: 8, Rank: 2, CreatedAt: now},
+		{ID: 4, UserTeamID: 3, PlayerID: 11, Bid: 8, Rank: 1, CreatedAt: now.Add(time.Hour)},
+	}
+	ids := func() []int {
+		var ids []int
+		for _, claim := range claims {
+			ids = append(ids, claim.ID)
+		}
+		return ids
+	}
+
+	// Team 2 has the higher waiver priority, and team 3 ranks its claim on player 11 first
+	SortBids(claims, models.WaiverTieBreakPriority, []int{2, 3, 1})
+	assert.Equal(t, []int{2, 4, 3, 1}, ids())
+
+	// Team 3 placed a bid first
+	SortBids(claims, models.WaiverTieBreakEarliest, []int{2, 3, 1})
+	assert.Equal(t, []int{3, 2, 4, 1}, ids())
+
+	// Team 3 is bottom of the standings
+	SortBids(claims, models.WaiverTieBreakStandings, []int{3, 1, 2})
+	assert.Equal(t, []int{4, 3, 2, 1}, ids())
+}
+
+func TestWaiverService(t *testing.T) {
+	db := testDB.GetDB()
+	leagueService := league.NewLeagueService(db)
+
+	// createLeague inserts a draft league whose draft is completed, with teams in the given draft order
+	createLeague := func(teams int) (int, []int) {
+		now := time.Now()
+		var leagueID, draftID int
+		err := db.QueryRow(`
+			INSERT INTO leagues (code, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, fmt.Sprintf("WAIV%d", now.UnixNano()), "Waiver League", now, now).Scan(&leagueID)
+		assert.NoError(t, err)
+
+		settings := league.DefaultSettings(leagueID)
+		settings.WaiverProcessTime = ""
+		_, err = leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+
+		err = db.QueryRow(`
+			INSERT INTO drafts (league_id, type, status, rounds, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			RETURNING id
+		`, leagueID, models.DraftTypeSnake, models.DraftStatusCompleted, 1, now).Scan(&draftID)
+		assert.NoError(t, err)
+
+		userTeamIDs := make([]int, teams)
+		for i := range userTeamIDs {
+			var userID int
+			err := db.QueryRow(`
+				INSERT INTO users (first_name, last_name, email, password, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, "Waiver", "Manager", fmt.Sprintf("waiver_%d_%d@example.com", now.UnixNano(), i), "password", now, now).Scan(&userID)
+			assert.NoError(t, err)
+			err = db.QueryRow(`
+				INSERT INTO user_teams (user_id, league_id, name, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, userID, leagueID, fmt.Sprintf("Waiver Team %d", i), now, now).Scan(&userTeamIDs[i])
+			assert.NoError(t, err)
+			_, err = db.Exec("INSERT INTO draft_order (draft_id, user_team_id, position) VALUES ($1, $2, $3)", draftID, userTeamIDs[i], i+1)
+			assert.NoError(t, err)
+		}
+		return leagueID, userTeamIDs
+	}
+
+	// createPlayers inserts forwards on separate teams
+	createPlayers := func(count int) []int {
+		now := time.Now()
+		ids := make([]int, count)
+		for i := range ids {
+			var teamID int
+			err := db.QueryRow(`
+				INSERT INTO teams (name, external_id, created_at, updated_at)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id
+			`, fmt.Sprintf("Waiver Club %d", i), now.UnixNano()%100000+int64(i), now, now).Scan(&teamID)
+			assert.NoError(t, err)
+			err = db.QueryRow(`
+				INSERT INTO players (team_id, first_name, last_name, position, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			`, teamID, "Player", fmt.Sprintf("%d", i), models.PositionFWD, now, now).Scan(&ids[i])
+			assert.NoError(t, err)
+		}
+		return ids
+	}
+
+	addToRoster := func(userTeamID, playerID int) {
+		_, err := db.Exec(`
+			INSERT INTO user_team_players (user_team_id, player_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+		`, userTeamID, playerID, time.Now())
+		assert.NoError(t, err)
+	}
+
+	// placeUndrafted puts the unowned players on waivers as the end of the draft does
+	placeUndrafted := func(leagueID int) {
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+		assert.NoError(t, PlaceUndraftedOnWaivers(tx, leagueID, time.Now()))
+		assert.NoError(t, tx.Commit())
+	}
+
+	// process settles the claims as if the waiver period had passed
+	process := func(leagueID int) []*models.WaiverClaim {
+		tx, err := db.Beginx()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+		processed, err := ProcessDueWaivers(tx, leagueID, time.Now().Add(72*time.Hour))
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+		return processed
+	}
+
+	rosterOf := func(userTeamID int) []int {
+		var ids []int
+		err := db.Select(&ids, "SELECT player_id FROM user_team_players WHERE user_team_id = $1 ORDER BY player_id", userTeamID)
+		assert.NoError(t, err)
+		return ids
+	}
+
+	t.Run("undrafted players are claimed by rolling priority", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(3)
+		playerIDs := createPlayers(3)
+		placeUndrafted(leagueID)
+
+		waivers, err := waiverService.ListWaivers(leagueID)
+		assert.NoError(t, err)
+		assert.Len(t, waivers, 3)
+
+		// Nobody can add a player on waivers straight away
+		_, err = waiverService.AddFreeAgent(teams[0], playerIDs[0], nil)
+		assert.ErrorIs(t, err, ErrPlayerOnWaivers)
+
+		// The team that picked last in the draft claims first
+		priorities, err := waiverService.GetPriorities(leagueID)
+		assert.NoError(t, err)
+		if assert.Len(t, priorities, 3) {
+			assert.Equal(t, teams[2], priorities[0].UserTeamID)
+		}
+
+		// Team 2 wants players 0 and 1, team 0 wants 0 then 2, team 1 wants 0
+		_, err = waiverService.SubmitClaim(teams[2], playerIDs[0], nil, 0)
+		assert.NoError(t, err)
+		_, err = waiverService.SubmitClaim(teams[2], playerIDs[1], nil, 0)
+		assert.NoError(t, err)
+		_, err = waiverService.SubmitClaim(teams[0], playerIDs[0], nil, 0)
+		assert.NoError(t, err)
+		_, err = waiverService.SubmitClaim(teams[0], playerIDs[2], nil, 0)
+		assert.NoError(t, err)
+		_, err = waiverService.SubmitClaim(teams[1], playerIDs[0], nil, 0)
+		assert.NoError(t, err)
+
+		_, err = waiverService.SubmitClaim(teams[1], playerIDs[0], nil, 0)
+		assert.ErrorIs(t, err, ErrInvalidClaim)
+
+		// Only FAAB leagues bid on players
+		_, err = waiverService.SubmitClaim(teams[1], playerIDs[2], nil, 5)
+		assert.ErrorIs(t, err, ErrInvalidClaim)
+		_, err = waiverService.GetFAABBalance(teams[1])
+		assert.ErrorIs(t, err, ErrNotFAABLeague)
+
+		// Claims on players still on waivers are left alone
+		processed, err := waiverService.ProcessWaivers(leagueID)
+		assert.NoError(t, err)
+		assert.Empty(t, processed)
+
+		processed = process(leagueID)
+		assert.Len(t, processed, 5)
+
+		// Team 2 takes player 0 and drops to the back, team 0 misses out on player 0 but takes
+		// player 2, team 1 misses out on player 0 and team 2 gets player 1 last
+		assert.Equal(t, []int{playerIDs[0], playerIDs[1]}, rosterOf(teams[2]))
+		assert.Equal(t, []int{playerIDs[2]}, rosterOf(teams[0]))
+		assert.Empty(t, rosterOf(teams[1]))
+
+		statuses := make(map[int][]models.WaiverClaimStatus)
+		for _, claim := range processed {
+			statuses[claim.UserTeamID] = append(statuses[claim.UserTeamID], claim.Status)
+		}
+		assert.Equal(t, []models.WaiverClaimStatus{models.WaiverClaimWon, models.WaiverClaimWon}, statuses[teams[2]])
+		assert.Equal(t, []models.WaiverClaimStatus{models.WaiverClaimLost, models.WaiverClaimWon}, statuses[teams[0]])
+		assert.Equal(t, []models.WaiverClaimStatus{models.WaiverClaimLost}, statuses[teams[1]])
+
+		// Team 1 never won a claim so it is now first in line
+		priorities, err = waiverService.GetPriorities(leagueID)
+		assert.NoError(t, err)
+		if assert.Len(t, priorities, 3) {
+			assert.Equal(t, []int{teams[1], teams[0], teams[2]}, []int{
+				priorities[0].UserTeamID, priorities[1].UserTeamID, priorities[2].UserTeamID,
+			})
+		}
+
+		transactions, err := waiverService.ListTransactions(leagueID)
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 3)
+		for _, transaction := range transactions {
+			assert.Equal(t, models.LeagueTransactionWaiver, transaction.Type)
+			assert.NotNil(t, transaction.WaiverClaimID)
+		}
+
+		waivers, err = waiverService.ListWaivers(leagueID)
+		assert.NoError(t, err)
+		assert.Empty(t, waivers)
+	})
+
+	t.Run("free agents and drops", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(2)
+		playerIDs := createPlayers(3)
+		addToRoster(teams[0], playerIDs[0])
+		addToRoster(teams[1], playerIDs[1])
+
+		// Player 2 was never on waivers, so is a free agent
+		transaction, err := waiverService.AddFreeAgent(teams[0], playerIDs[2], &playerIDs[0])
+		assert.NoError(t, err)
+		assert.Equal(t, models.LeagueTransactionFreeAgent, transaction.Type)
+		assert.Equal(t, []int{playerIDs[2]}, rosterOf(teams[0]))
+
+		_, err = waiverService.AddFreeAgent(teams[1], playerIDs[2], nil)
+		assert.ErrorIs(t, err, ErrPlayerOwned)
+
+		// The dropped player goes on waivers
+		_, err = waiverService.AddFreeAgent(teams[1], playerIDs[0], nil)
+		assert.ErrorIs(t, err, ErrPlayerOnWaivers)
+
+		// A claim can only drop a player the team owns
+		_, err = waiverService.SubmitClaim(teams[1], playerIDs[0], &playerIDs[2], 0)
+		assert.ErrorIs(t, err, ErrInvalidClaim)
+
+		_, err = waiverService.DropPlayer(teams[1], playerIDs[1])
+		assert.NoError(t, err)
+		_, err = waiverService.SubmitClaim(teams[0], playerIDs[1], nil, 0)
+		assert.NoError(t, err)
+		claim, err := waiverService.SubmitClaim(teams[0], playerIDs[0], nil, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, claim.Rank)
+
+		claims, err := waiverService.ListClaims(teams[0])
+		assert.NoError(t, err)
+		if assert.Len(t, claims, 2) {
+			claims, err = waiverService.ReorderClaims(teams[0], []int{claims[1].ID, claims[0].ID})
+			assert.NoError(t, err)
+			assert.Equal(t, playerIDs[0], claims[0].PlayerID)
+
+			assert.NoError(t, waiverService.CancelClaim(teams[0], claims[0].ID))
+			claims, err = waiverService.ListClaims(teams[0])
+			assert.NoError(t, err)
+			if assert.Len(t, claims, 1) {
+				assert.Equal(t, playerIDs[1], claims[0].PlayerID)
+				assert.Equal(t, 1, claims[0].Rank)
+			}
+		}
+
+		// Once processed, a player nobody claimed is a free agent again
+		process(leagueID)
+		assert.Equal(t, []int{playerIDs[1], playerIDs[2]}, rosterOf(teams[0]))
+		_, err = waiverService.AddFreeAgent(teams[1], playerIDs[0], nil)
+		assert.NoError(t, err)
+
+		transactions, err := waiverService.ListTransactions(leagueID)
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 4)
+
+		// Every move and claim is in the audit log, with the rosters either side of it
+		page, err := audit.NewAuditService(testDB.GetDB()).ListEntries(leagueID, &audit.AuditFilter{UserTeamID: teams[0]})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, page.Total)
+		if assert.Len(t, page.Entries, 4) {
+			won := page.Entries[0]
+			assert.Equal(t, models.AuditActionWaiverAdd, won.Action)
+			assert.Nil(t, won.ActorUserTeamID)
+			assert.Equal(t, audit.PlayerIDs(playerIDs[2]), won.RosterBefore)
+			assert.Equal(t, audit.PlayerIDs(playerIDs[1], playerIDs[2]), won.RosterAfter)
+
+			assert.Equal(t, models.AuditActionWaiverClaim, page.Entries[1].Action)
+			assert.Equal(t, page.Entries[1].RosterBefore, page.Entries[1].RosterAfter)
+
+			added := page.Entries[3]
+			assert.Equal(t, models.AuditActionAdd, added.Action)
+			assert.Equal(t, &teams[0], added.ActorUserTeamID)
+			assert.Equal(t, audit.PlayerIDs(playerIDs[2]), added.PlayersIn)
+			assert.Equal(t, audit.PlayerIDs(playerIDs[0]), added.PlayersOut)
+			assert.Equal(t, audit.PlayerIDs(playerIDs[0]), added.RosterBefore)
+			assert.Equal(t, audit.PlayerIDs(playerIDs[2]), added.RosterAfter)
+		}
+	})
+
+	t.Run("claims that no longer fit the squad fail", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(1)
+		playerIDs := createPlayers(5)
+		for _, id := range playerIDs[:3] {
+			addToRoster(teams[0], id)
+		}
+		placeUndrafted(leagueID)
+
+		// A squad can only hold three forwards, so the claim has to drop one
+		_, err := waiverService.SubmitClaim(teams[0], playerIDs[3], nil, 0)
+		assert.Error(t, err)
+		_, err = waiverService.SubmitClaim(teams[0], playerIDs[3], &playerIDs[0], 0)
+		assert.NoError(t, err)
+		_, err = waiverService.SubmitClaim(teams[0], playerIDs[4], &playerIDs[0], 0)
+		assert.NoError(t, err)
+
+		processed := process(leagueID)
+		if assert.Len(t, processed, 2) {
+			assert.Equal(t, models.WaiverClaimWon, processed[0].Status)
+			assert.Equal(t, models.WaiverClaimFailed, processed[1].Status)
+		}
+		assert.Equal(t, []int{playerIDs[1], playerIDs[2], playerIDs[3]}, rosterOf(teams[0]))
+	})
+
+	t.Run("FAAB bids", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(2)
+		settings := league.DefaultSettings(leagueID)
+		settings.WaiverProcessTime = ""
+		settings.WaiverMode = models.WaiverModeFAAB
+		_, err := leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+		playerIDs := createPlayers(3)
+		placeUndrafted(leagueID)
+
+		_, err = waiverService.SubmitClaim(teams[0], playerIDs[0], nil, 101)
+		assert.ErrorIs(t, err, ErrInvalidClaim)
+
+		// Pending bids can add up to more than the budget, each is checked when it is settled
+		for _, claim := range []struct {
+			userTeamID, playerID, bid int
+		}{
+			{teams[0], playerIDs[0], 30},
+			{teams[0], playerIDs[1], 80},
+			{teams[0], playerIDs[2], 25},
+			{teams[1], playerIDs[0], 30},
+			{teams[1], playerIDs[1], 75},
+		} {
+			_, err := waiverService.SubmitClaim(claim.userTeamID, claim.playerID, nil, claim.bid)
+			assert.NoError(t, err)
+		}
+
+		// The highest bid goes first, team 1 wins the tie on player 0 on waiver priority and
+		// team 0 cannot afford player 2 after paying 80 for player 1
+		processed := process(leagueID)
+		statuses := make(map[int]models.WaiverClaimStatus)
+		for _, claim := range processed {
+			statuses[claim.UserTeamID*1000+claim.PlayerID] = claim.Status
+		}
+		assert.Equal(t, models.WaiverClaimWon, statuses[teams[0]*1000+playerIDs[1]])
+		assert.Equal(t, models.WaiverClaimLost, statuses[teams[1]*1000+playerIDs[1]])
+		assert.Equal(t, models.WaiverClaimWon, statuses[teams[1]*1000+playerIDs[0]])
+		assert.Equal(t, models.WaiverClaimLost, statuses[teams[0]*1000+playerIDs[0]])
+		assert.Equal(t, models.WaiverClaimFailed, statuses[teams[0]*1000+playerIDs[2]])
+
+		balance, err := waiverService.GetFAABBalance(teams[0])
+		assert.NoError(t, err)
+		assert.Equal(t, 20, balance)
+		balance, err = waiverService.GetFAABBalance(teams[1])
+		assert.NoError(t, err)
+		assert.Equal(t, 70, balance)
+
+		// Winning the tie sent team 1 to the back
+		priorities, err := waiverService.GetPriorities(leagueID)
+		assert.NoError(t, err)
+		if assert.Len(t, priorities, 2) {
+			assert.Equal(t, teams[0], priorities[0].UserTeamID)
+		}
+
+		transactions, err := waiverService.ListTransactions(leagueID)
+		assert.NoError(t, err)
+		amounts := make([]int, 0, len(transactions))
+		for _, transaction := range transactions {
+			amounts = append(amounts, transaction.Amount)
+		}
+		assert.ElementsMatch(t, []int{80, 30}, amounts)
+	})
+
+	t.Run("FAAB ties on waiver priority alternate", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(2)
+		settings := league.DefaultSettings(leagueID)
+		settings.WaiverProcessTime = ""
+		settings.WaiverMode = models.WaiverModeFAAB
+		_, err := leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+		playerIDs := createPlayers(2)
+		placeUndrafted(leagueID)
+
+		priorities, err := waiverService.GetPriorities(leagueID)
+		assert.NoError(t, err)
+		if !assert.Len(t, priorities, 2) {
+			return
+		}
+		first, second := priorities[0].UserTeamID, priorities[1].UserTeamID
+
+		// Both teams bid the same on both players, each wanting player 0 most
+		for _, userTeamID := range teams {
+			for _, playerID := range playerIDs {
+				_, err := waiverService.SubmitClaim(userTeamID, playerID, nil, 10)
+				assert.NoError(t, err)
+			}
+		}
+
+		// The first tie sends its winner to the back, so the second tie goes the other way
+		processed := process(leagueID)
+		statuses := make(map[int]models.WaiverClaimStatus)
+		for _, claim := range processed {
+			statuses[claim.UserTeamID*1000+claim.PlayerID] = claim.Status
+		}
+		assert.Equal(t, models.WaiverClaimWon, statuses[first*1000+playerIDs[0]])
+		assert.Equal(t, models.WaiverClaimWon, statuses[second*1000+playerIDs[1]])
+		assert.Equal(t, []int{playerIDs[0]}, rosterOf(first))
+		assert.Equal(t, []int{playerIDs[1]}, rosterOf(second))
+	})
+
+	t.Run("budget leagues have no waivers", func(t *testing.T) {
+		defer testDB.Clear()
+
+		leagueID, teams := createLeague(1)
+		playerIDs := createPlayers(1)
+		settings := league.DefaultSettings(leagueID)
+		settings.OwnershipMode = models.OwnershipModeBudget
+		_, err := leagueService.UpdateSettings(settings)
+		assert.NoError(t, err)
+
+		_, err = waiverService.AddFreeAgent(teams[0], playerIDs[0], nil)
+		assert.ErrorIs(t, err, ErrNotDraftLeague)
+	})
+}